@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
@@ -15,6 +16,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 
+	"github.com/vctt94/pong-bisonrelay/ponggame"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -25,6 +27,7 @@ type UpdatedMsg struct{}
 
 type PongClientCfg struct {
 	ServerAddr    string      // Address of the Pong server
+	Nick          string      // Bison Relay nick announced to the server
 	GRPCCertPath  string      // Cert to the grpc server
 	Log           slog.Logger // Application's logger
 	ChatClient    types.ChatServiceClient
@@ -67,12 +70,22 @@ type PongClient struct {
 	cancelFunc   context.CancelFunc
 	reconnecting bool
 	reconnectMu  sync.Mutex
+
+	// fairness holds the serve seed commitment and the entropy sent for
+	// each game, to verify the seed revealed when the game ends.
+	fairness map[string]*gameFairness
+}
+
+type gameFairness struct {
+	commitment []byte
+	entropy    []byte
 }
 
 func (pc *PongClient) StartNotifier(ctx context.Context) error {
 	// Creates game start stream so we can notify when the game starts
 	gameStartedStream, err := pc.gc.StartNtfnStream(ctx, &pong.StartNtfnStreamRequest{
 		ClientId: pc.ID,
+		Nick:     pc.cfg.Nick,
 	})
 	if err != nil {
 		return fmt.Errorf("error creating notifier stream: %w", err)
@@ -107,7 +120,53 @@ func (pc *PongClient) StartNotifier(ctx context.Context) error {
 				switch ntfn.NotificationType {
 				case pong.NotificationType_ON_WR_CREATED:
 					pc.ntfns.notifyOnWRCreated(ntfn.Wr, time.Now())
+				case pong.NotificationType_WR_INVITE:
+					pc.ntfns.notifyWRInvite(ntfn.Wr, ntfn.InviteCode, ntfn.PlayerId, time.Now())
+				case pong.NotificationType_CHALLENGE_RECEIVED,
+					pong.NotificationType_CHALLENGE_ACCEPTED,
+					pong.NotificationType_CHALLENGE_DECLINED,
+					pong.NotificationType_CHALLENGE_EXPIRED:
+					pc.ntfns.notifyChallenge(ntfn.NotificationType, ntfn.Challenge, ntfn.Wr, time.Now())
+				case pong.NotificationType_SERIES_UPDATE:
+					msg := ntfn.Message
+					if err := pc.verifyFairness(ntfn.GameId, ntfn.Fairness); err != nil {
+						pc.log.Warnf("Game %s: %v", ntfn.GameId, err)
+						msg += " Warning: " + err.Error()
+					}
+					pc.ntfns.notifySeriesUpdate(ntfn.GameId, msg, ntfn.Series, time.Now())
+				case pong.NotificationType_REMATCH_AVAILABLE,
+					pong.NotificationType_REMATCH_PROPOSED,
+					pong.NotificationType_REMATCH_ACCEPTED,
+					pong.NotificationType_REMATCH_DECLINED,
+					pong.NotificationType_REMATCH_EXPIRED:
+					pc.ntfns.notifyRematch(ntfn.NotificationType, ntfn.Rematch, ntfn.Wr, time.Now())
+				case pong.NotificationType_TOURNAMENT_UPDATE,
+					pong.NotificationType_TOURNAMENT_MATCH_READY,
+					pong.NotificationType_TOURNAMENT_ENDED:
+					pc.ntfns.notifyTournament(ntfn.NotificationType, ntfn.Message, ntfn.Tournament, ntfn.Wr, time.Now())
+				case pong.NotificationType_READY_CHECK,
+					pong.NotificationType_PLAYER_EVICTED,
+					pong.NotificationType_WR_EXPIRED,
+					pong.NotificationType_WR_RESTORED:
+					pc.ntfns.notifyWRTimeout(ntfn.NotificationType, ntfn.Message, ntfn.Wr, time.Now())
+				case pong.NotificationType_PLAYER_KICKED,
+					pong.NotificationType_WR_LOCKED,
+					pong.NotificationType_WR_UNLOCKED,
+					pong.NotificationType_HOST_TRANSFERRED,
+					pong.NotificationType_WR_SETTINGS_UPDATED:
+					pc.ntfns.notifyWRModeration(ntfn.NotificationType, ntfn.Message, ntfn.Wr, time.Now())
+				case pong.NotificationType_WITHDRAWAL_STARTED,
+					pong.NotificationType_PAYOUT_PROGRESS,
+					pong.NotificationType_PAYOUT_COMPLETED:
+					if ntfn.PlayerId == pc.ID && ntfn.Balance != nil {
+						pc.BetAmt = ntfn.BetAmt
+						pc.ntfns.notifyBetAmtChanged(ntfn.PlayerId, ntfn.BetAmt, time.Now())
+					}
+					pc.ntfns.notifyPayout(ntfn.NotificationType, ntfn.Message, ntfn.Balance, time.Now())
 				case pong.NotificationType_MESSAGE:
+					if ntfn.Chat != nil {
+						pc.ntfns.notifyChatMessage(ntfn.Chat, time.Now())
+					}
 				case pong.NotificationType_PLAYER_JOINED_WR:
 					pc.ntfns.notifyPlayerJoinedWR(ntfn.Wr, time.Now())
 				case pong.NotificationType_PLAYER_LEFT_WR:
@@ -117,8 +176,13 @@ func (pc *PongClient) StartNotifier(ctx context.Context) error {
 						pc.ntfns.notifyGameStarted(ntfn.GameId, time.Now())
 					}
 				case pong.NotificationType_GAME_END:
-					pc.ntfns.notifyGameEnded(ntfn.GameId, ntfn.Message, time.Now())
-					pc.log.Infof("%s", ntfn.Message)
+					msg := ntfn.Message
+					if err := pc.verifyFairness(ntfn.GameId, ntfn.Fairness); err != nil {
+						pc.log.Warnf("Game %s: %v", ntfn.GameId, err)
+						msg += " Warning: " + err.Error()
+					}
+					pc.ntfns.notifyGameEnded(ntfn.GameId, msg, time.Now())
+					pc.log.Infof("%s", msg)
 				case pong.NotificationType_OPPONENT_DISCONNECTED:
 				case pong.NotificationType_BET_AMOUNT_UPDATE:
 					if ntfn.PlayerId == pc.ID {
@@ -136,8 +200,15 @@ func (pc *PongClient) StartNotifier(ctx context.Context) error {
 					// Forward countdown updates to UI
 					pc.UpdatesCh <- ntfn
 				case pong.NotificationType_GAME_READY_TO_PLAY:
+					if ntfn.Fairness != nil {
+						pc.Lock()
+						pc.fairness[ntfn.GameId] = &gameFairness{commitment: ntfn.Fairness.ServerSeedHash}
+						pc.Unlock()
+					}
 					// Forward game ready to play notifications to UI
 					pc.UpdatesCh <- ntfn
+				case pong.NotificationType_SESSION_REMINDER:
+					pc.UpdatesCh <- ntfn
 				default:
 				}
 			}
@@ -197,15 +268,26 @@ func (pc *PongClient) SendInput(input string) error {
 }
 
 func (pc *PongClient) GetWaitingRooms() ([]*pong.WaitingRoom, error) {
+	res, err := pc.SearchWaitingRooms(&pong.WaitingRoomsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return res.Wr, nil
+}
+
+// SearchWaitingRooms lists one page of the waiting rooms matching the query.
+// The next page is fetched by setting the query cursor to the returned
+// NextCursor.
+func (pc *PongClient) SearchWaitingRooms(query *pong.WaitingRoomsRequest) (*pong.WaitingRoomsResponse, error) {
 	ctx := context.Background()
 
-	res, err := pc.gc.GetWaitingRooms(ctx, &pong.WaitingRoomsRequest{})
+	res, err := pc.gc.GetWaitingRooms(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("error getting wr: %w", err)
 	}
 	go func() { pc.UpdatesCh <- res.Wr }()
 
-	return res.Wr, nil
+	return res, nil
 }
 
 func (pc *PongClient) GetWRPlayers() ([]*pong.Player, error) {
@@ -242,6 +324,259 @@ func (pc *PongClient) JoinWaitingRoom(roomID string) (*pong.JoinWaitingRoomRespo
 	return res, nil
 }
 
+// CreatePrivateWaitingRoom creates an invite-only waiting room. It returns the
+// room and the invite code other players may use to join it.
+func (pc *PongClient) CreatePrivateWaitingRoom(betAmt int64, invitees []string) (*pong.WaitingRoom, string, error) {
+	ctx := context.Background()
+	res, err := pc.gc.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId:   pc.ID,
+		BetAmt:   betAmt,
+		Private:  true,
+		Invitees: invitees,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating private wr: %w", err)
+	}
+	return res.Wr, res.InviteCode, nil
+}
+
+// JoinPrivateWaitingRoom joins a private waiting room using its invite code.
+func (pc *PongClient) JoinPrivateWaitingRoom(roomID, inviteCode string) (*pong.JoinWaitingRoomResponse, error) {
+	ctx := context.Background()
+	res, err := pc.gc.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		ClientId:   pc.ID,
+		RoomId:     roomID,
+		InviteCode: inviteCode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error joining private wr: %w", err)
+	}
+	return res, nil
+}
+
+// InviteToWaitingRoom invites more players to a private waiting room hosted
+// by this client.
+func (pc *PongClient) InviteToWaitingRoom(roomID string, invitees []string) (string, error) {
+	ctx := context.Background()
+	res, err := pc.gc.InviteToWaitingRoom(ctx, &pong.InviteToWaitingRoomRequest{
+		ClientId: pc.ID,
+		RoomId:   roomID,
+		Invitees: invitees,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error inviting to wr: %w", err)
+	}
+	return res.InviteCode, nil
+}
+
+// KickPlayer removes a player from a waiting room hosted by the client.
+// Banned players can't join the room again.
+func (pc *PongClient) KickPlayer(roomID, playerID string, ban bool) (*pong.WaitingRoom, error) {
+	ctx := context.Background()
+	res, err := pc.gc.KickPlayer(ctx, &pong.KickPlayerRequest{
+		ClientId: pc.ID,
+		RoomId:   roomID,
+		PlayerId: playerID,
+		Ban:      ban,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error kicking player: %w", err)
+	}
+	return res.Wr, nil
+}
+
+// SetRoomLocked locks or unlocks a waiting room hosted by the client.
+func (pc *PongClient) SetRoomLocked(roomID string, locked bool) (*pong.WaitingRoom, error) {
+	ctx := context.Background()
+	req := &pong.LockRoomRequest{
+		ClientId: pc.ID,
+		RoomId:   roomID,
+	}
+	var res *pong.LockRoomResponse
+	var err error
+	if locked {
+		res, err = pc.gc.LockRoom(ctx, req)
+	} else {
+		res, err = pc.gc.UnlockRoom(ctx, req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error locking wr: %w", err)
+	}
+	return res.Wr, nil
+}
+
+// TransferHost hands the host role of a waiting room to another player.
+func (pc *PongClient) TransferHost(roomID, newHostID string) (*pong.WaitingRoom, error) {
+	ctx := context.Background()
+	res, err := pc.gc.TransferHost(ctx, &pong.TransferHostRequest{
+		ClientId:  pc.ID,
+		RoomId:    roomID,
+		NewHostId: newHostID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error transferring host: %w", err)
+	}
+	return res.Wr, nil
+}
+
+// UpdateRoomSettings changes the settings of a waiting room hosted by the
+// client. Nil rules or visibility and a zero bet are left unchanged. It
+// returns the invite code when the room is private.
+func (pc *PongClient) UpdateRoomSettings(roomID string, rules *pong.GameRules, private *bool, betAmt int64) (*pong.WaitingRoom, string, error) {
+	ctx := context.Background()
+	res, err := pc.gc.UpdateRoomSettings(ctx, &pong.UpdateRoomSettingsRequest{
+		ClientId: pc.ID,
+		RoomId:   roomID,
+		Rules:    rules,
+		Private:  private,
+		BetAmt:   betAmt,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("error updating wr settings: %w", err)
+	}
+	return res.Wr, res.InviteCode, nil
+}
+
+// ChallengePlayer challenges the player with the given client id or nick to a
+// game. A zero timeout uses the server default.
+func (pc *PongClient) ChallengePlayer(target string, betAmt int64, rules *pong.GameRules, timeout time.Duration) (*pong.Challenge, error) {
+	ctx := context.Background()
+	res, err := pc.gc.ChallengePlayer(ctx, &pong.ChallengePlayerRequest{
+		ClientId:    pc.ID,
+		Target:      target,
+		BetAmt:      betAmt,
+		Rules:       rules,
+		TimeoutSecs: int64(timeout / time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error challenging player: %w", err)
+	}
+	return res.Challenge, nil
+}
+
+// RespondChallenge accepts or declines a challenge. When accepted, it returns
+// the waiting room holding both players.
+func (pc *PongClient) RespondChallenge(challengeID string, accept bool) (*pong.WaitingRoom, error) {
+	ctx := context.Background()
+	res, err := pc.gc.RespondChallenge(ctx, &pong.RespondChallengeRequest{
+		ClientId:    pc.ID,
+		ChallengeId: challengeID,
+		Accept:      accept,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error responding to challenge: %w", err)
+	}
+	return res.Wr, nil
+}
+
+// ProposeRematch proposes a rematch to the opponent of the last game. A zero
+// bet amount and nil rules keep the ones of the last game.
+func (pc *PongClient) ProposeRematch(betAmt int64, rules *pong.GameRules) (*pong.Rematch, error) {
+	ctx := context.Background()
+	res, err := pc.gc.ProposeRematch(ctx, &pong.ProposeRematchRequest{
+		ClientId: pc.ID,
+		BetAmt:   betAmt,
+		Rules:    rules,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error proposing rematch: %w", err)
+	}
+	return res.Rematch, nil
+}
+
+// RespondRematch accepts or declines the rematch proposed by the opponent.
+// When accepted, it returns the waiting room holding both players.
+func (pc *PongClient) RespondRematch(accept bool) (*pong.WaitingRoom, error) {
+	ctx := context.Background()
+	res, err := pc.gc.RespondRematch(ctx, &pong.RespondRematchRequest{
+		ClientId: pc.ID,
+		Accept:   accept,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error responding to rematch: %w", err)
+	}
+	return res.Wr, nil
+}
+
+// ListTournaments returns the tournaments hosted by the server.
+func (pc *PongClient) ListTournaments() ([]*pong.Tournament, error) {
+	ctx := context.Background()
+	res, err := pc.gc.ListTournaments(ctx, &pong.ListTournamentsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing tournaments: %w", err)
+	}
+	return res.Tournaments, nil
+}
+
+// GetTournament returns a tournament with its bracket.
+func (pc *PongClient) GetTournament(tournamentID string) (*pong.Tournament, error) {
+	ctx := context.Background()
+	res, err := pc.gc.GetTournament(ctx, &pong.GetTournamentRequest{
+		TournamentId: tournamentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting tournament: %w", err)
+	}
+	return res.Tournament, nil
+}
+
+// RegisterTournament registers in a tournament. Its buy-in is reserved from
+// the available balance of the player and held in escrow until the tournament
+// ends.
+func (pc *PongClient) RegisterTournament(tournamentID string) (*pong.Tournament, error) {
+	ctx := context.Background()
+	res, err := pc.gc.RegisterTournament(ctx, &pong.RegisterTournamentRequest{
+		ClientId:     pc.ID,
+		TournamentId: tournamentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error registering in tournament: %w", err)
+	}
+	return res.Tournament, nil
+}
+
+// UnregisterTournament leaves a tournament that hasn't started.
+func (pc *PongClient) UnregisterTournament(tournamentID string) error {
+	ctx := context.Background()
+	_, err := pc.gc.UnregisterTournament(ctx, &pong.UnregisterTournamentRequest{
+		ClientId:     pc.ID,
+		TournamentId: tournamentID,
+	})
+	if err != nil {
+		return fmt.Errorf("error unregistering from tournament: %w", err)
+	}
+	return nil
+}
+
+// SendChatMessage sends a message to the players of the current waiting room
+// or game of the player.
+func (pc *PongClient) SendChatMessage(text string) (*pong.ChatMessage, error) {
+	ctx := context.Background()
+	res, err := pc.gc.SendChatMessage(ctx, &pong.SendChatMessageRequest{
+		ClientId: pc.ID,
+		Text:     text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error sending chat message: %w", err)
+	}
+	return res.Chat, nil
+}
+
+// MutePlayer mutes or unmutes the chat messages of another player. It returns
+// the ids of all muted players.
+func (pc *PongClient) MutePlayer(playerID string, mute bool) ([]string, error) {
+	ctx := context.Background()
+	res, err := pc.gc.MutePlayer(ctx, &pong.MutePlayerRequest{
+		ClientId: pc.ID,
+		PlayerId: playerID,
+		Mute:     mute,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error muting player: %w", err)
+	}
+	return res.Muted, nil
+}
+
 func (pc *PongClient) LeaveWaitingRoom(roomID string) error {
 	ctx := context.Background()
 	res, err := pc.gc.LeaveWaitingRoom(ctx, &pong.LeaveWaitingRoomRequest{
@@ -259,6 +594,87 @@ func (pc *PongClient) LeaveWaitingRoom(roomID string) error {
 	return nil
 }
 
+// GetLeaderboard fetches a ranked leaderboard. A zero season returns the
+// current season.
+func (pc *PongClient) GetLeaderboard(kind pong.LeaderboardKind, window pong.LeaderboardWindow, season uint32, limit int32) (*pong.LeaderboardResponse, error) {
+	ctx := context.Background()
+	res, err := pc.gc.GetLeaderboard(ctx, &pong.LeaderboardRequest{
+		Kind:   kind,
+		Window: window,
+		Season: season,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting leaderboard: %w", err)
+	}
+	return res, nil
+}
+
+// GetBalance returns the available, reserved and pending payout amounts of
+// the player.
+func (pc *PongClient) GetBalance() (*pong.Balance, error) {
+	ctx := context.Background()
+	res, err := pc.gc.GetBalance(ctx, &pong.GetBalanceRequest{ClientId: pc.ID})
+	if err != nil {
+		return nil, fmt.Errorf("error getting balance: %w", err)
+	}
+	return res.Balance, nil
+}
+
+// Withdraw asks the bot to send back an amount in matoms of the available
+// balance, or all of it when amount is 0.
+func (pc *PongClient) Withdraw(amount int64) (*pong.WithdrawResponse, error) {
+	ctx := context.Background()
+	res, err := pc.gc.Withdraw(ctx, &pong.WithdrawRequest{
+		ClientId: pc.ID,
+		Amount:   amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error withdrawing: %w", err)
+	}
+	return res, nil
+}
+
+// GetLimits returns the responsible gaming limits of the player.
+func (pc *PongClient) GetLimits() (*pong.Limits, error) {
+	ctx := context.Background()
+	res, err := pc.gc.GetLimits(ctx, &pong.GetLimitsRequest{ClientId: pc.ID})
+	if err != nil {
+		return nil, fmt.Errorf("error getting limits: %w", err)
+	}
+	return res.Limits, nil
+}
+
+// SetLimits replaces the responsible gaming limits of the player. Amounts
+// are in matoms, and zero disables a limit. Looser limits only apply after
+// a cool-off period.
+func (pc *PongClient) SetLimits(maxStake, maxDailyLoss int64, reminder time.Duration) (*pong.Limits, error) {
+	ctx := context.Background()
+	res, err := pc.gc.SetLimits(ctx, &pong.SetLimitsRequest{
+		ClientId:            pc.ID,
+		MaxStake:            maxStake,
+		MaxDailyLoss:        maxDailyLoss,
+		SessionReminderSecs: int64(reminder / time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error setting limits: %w", err)
+	}
+	return res.Limits, nil
+}
+
+// SelfExclude stops the player from playing for d. It can't be undone.
+func (pc *PongClient) SelfExclude(d time.Duration) (*pong.Limits, error) {
+	ctx := context.Background()
+	res, err := pc.gc.SelfExclude(ctx, &pong.SelfExcludeRequest{
+		ClientId:     pc.ID,
+		DurationSecs: int64(d / time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error self-excluding: %w", err)
+	}
+	return res.Limits, nil
+}
+
 func (pc *PongClient) reconnect() error {
 	pc.reconnectMu.Lock()
 	if pc.reconnecting {
@@ -401,6 +817,7 @@ func NewPongClient(clientID string, cfg *PongClientCfg) (*PongClient, error) {
 		ntfns:      ntfns,
 		ctx:        ctx,
 		cancelFunc: cancel,
+		fairness:   make(map[string]*gameFairness),
 	}
 
 	return pc, nil
@@ -439,13 +856,25 @@ func (pc *PongClient) SignalUnready() error {
 	return nil
 }
 
-// SignalReadyToPlay signals that the player is ready to start playing
+// SignalReadyToPlay signals that the player is ready to start playing. It
+// sends random entropy that is mixed into the serve seed of the game.
 func (pc *PongClient) SignalReadyToPlay(gameID string) error {
 	ctx := context.Background()
 
+	entropy := make([]byte, 32)
+	if _, err := rand.Read(entropy); err != nil {
+		return fmt.Errorf("error generating entropy: %w", err)
+	}
+	pc.Lock()
+	if f := pc.fairness[gameID]; f != nil && f.entropy == nil {
+		f.entropy = entropy
+	}
+	pc.Unlock()
+
 	resp, err := pc.gc.SignalReadyToPlay(ctx, &pong.SignalReadyToPlayRequest{
 		ClientId: pc.ID,
 		GameId:   gameID,
+		Entropy:  entropy,
 	})
 	if err != nil {
 		return fmt.Errorf("error signaling ready to play: %w", err)
@@ -457,3 +886,18 @@ func (pc *PongClient) SignalReadyToPlay(gameID string) error {
 
 	return nil
 }
+
+// verifyFairness verifies the serve seed revealed at the end of a game
+// against the commitment received when it was created. Games created before
+// the client connected can't be verified and are skipped.
+func (pc *PongClient) verifyFairness(gameID string, revealed *pong.Fairness) error {
+	pc.Lock()
+	f := pc.fairness[gameID]
+	delete(pc.fairness, gameID)
+	pc.Unlock()
+	if f == nil {
+		return nil
+	}
+	_, err := ponggame.VerifyFairness(f.commitment, f.entropy, revealed)
+	return err
+}
@@ -56,6 +56,79 @@ type OnGameEndedNtfn func(string, string, time.Time)
 
 func (_ OnGameEndedNtfn) typ() string { return onGameEndedfnType }
 
+const onWRInvitefnType = "onWRInvite"
+
+// OnWRInviteNtfn is the handler for invites to private waiting rooms. It
+// receives the room, the invite code and the id of the inviting host.
+type OnWRInviteNtfn func(*pong.WaitingRoom, string, string, time.Time)
+
+func (_ OnWRInviteNtfn) typ() string { return onWRInvitefnType }
+
+const onChallengefnType = "onChallenge"
+
+// OnChallengeNtfn is the handler for updates on direct challenges. The
+// waiting room is only set once a challenge is accepted.
+type OnChallengeNtfn func(pong.NotificationType, *pong.Challenge, *pong.WaitingRoom, time.Time)
+
+func (_ OnChallengeNtfn) typ() string { return onChallengefnType }
+
+const onSeriesUpdatefnType = "onSeriesUpdate"
+
+// OnSeriesUpdateNtfn is the handler for the end of a game that did not decide
+// its series yet.
+type OnSeriesUpdateNtfn func(string, string, *pong.SeriesState, time.Time)
+
+func (_ OnSeriesUpdateNtfn) typ() string { return onSeriesUpdatefnType }
+
+const onRematchfnType = "onRematch"
+
+// OnRematchNtfn is the handler for updates on rematch offers. The waiting
+// room is only set once a rematch is accepted.
+type OnRematchNtfn func(pong.NotificationType, *pong.Rematch, *pong.WaitingRoom, time.Time)
+
+func (_ OnRematchNtfn) typ() string { return onRematchfnType }
+
+const onTournamentfnType = "onTournament"
+
+// OnTournamentNtfn is the handler for tournament updates. The waiting room is
+// only set when a match of the player is ready to be played.
+type OnTournamentNtfn func(pong.NotificationType, string, *pong.Tournament, *pong.WaitingRoom, time.Time)
+
+func (_ OnTournamentNtfn) typ() string { return onTournamentfnType }
+
+const onWRTimeoutfnType = "onWRTimeout"
+
+// OnWRTimeoutNtfn is the handler for waiting room ready checks, evictions,
+// expirations and restores after a server restart. The waiting room is nil
+// when the player itself was evicted.
+type OnWRTimeoutNtfn func(pong.NotificationType, string, *pong.WaitingRoom, time.Time)
+
+func (_ OnWRTimeoutNtfn) typ() string { return onWRTimeoutfnType }
+
+const onWRModerationfnType = "onWRModeration"
+
+// OnWRModerationNtfn is the handler for actions taken by the host of the
+// waiting room. The waiting room is nil when the player itself was kicked.
+type OnWRModerationNtfn func(pong.NotificationType, string, *pong.WaitingRoom, time.Time)
+
+func (_ OnWRModerationNtfn) typ() string { return onWRModerationfnType }
+
+const onChatMessagefnType = "onChatMessage"
+
+// OnChatMessageNtfn is the handler for chat messages sent in the waiting room
+// or game of the player.
+type OnChatMessageNtfn func(*pong.ChatMessage, time.Time)
+
+func (_ OnChatMessageNtfn) typ() string { return onChatMessagefnType }
+
+const onPayoutfnType = "onPayout"
+
+// OnPayoutNtfn is the handler for the progress of withdrawals and other
+// payouts sent to the player, carrying the updated balance.
+type OnPayoutNtfn func(pong.NotificationType, string, *pong.Balance, time.Time)
+
+func (_ OnPayoutNtfn) typ() string { return onPayoutfnType }
+
 // UINotificationsConfig is the configuration for how UI notifications are
 // emitted.
 type UINotificationsConfig struct {
@@ -64,6 +137,9 @@ type UINotificationsConfig struct {
 
 	WRCreated bool
 
+	// Chat flags whether to emit notifications for chat messages.
+	Chat bool
+
 	// MaxLength is the max length of messages emitted.
 	MaxLength int
 
@@ -93,6 +169,7 @@ type UINotificationType string
 const (
 	UINtfnGameStarted UINotificationType = "gamestarted"
 	UINtfnWRCreated   UINotificationType = "wrcreated"
+	UINtfnChat        UINotificationType = "chat"
 	UINtfnMultiple    UINotificationType = "multiple"
 )
 
@@ -294,7 +371,7 @@ func (nmgr *NotificationManager) waitAndEmitUINtfn(c <-chan time.Time, cancel <-
 		visit(func(h OnUINotification) { h(n) })
 }
 
-func (nmgr *NotificationManager) addUINtfn(from zkidentity.ShortID, typ UINotificationType, msg string, ts time.Time) {
+func (nmgr *NotificationManager) addUINtfn(from zkidentity.ShortID, fromNick string, typ UINotificationType, msg string, ts time.Time) {
 	nmgr.uiMtx.Lock()
 
 	n := &nmgr.uiNextNtfn
@@ -309,7 +386,8 @@ func (nmgr *NotificationManager) addUINtfn(from zkidentity.ShortID, typ UINotifi
 	// })
 
 	switch {
-	case typ == UINtfnWRCreated && !cfg.WRCreated:
+	case typ == UINtfnWRCreated && !cfg.WRCreated,
+		typ == UINtfnChat && !cfg.Chat:
 
 		// Ignore
 		nmgr.uiMtx.Unlock()
@@ -324,6 +402,20 @@ func (nmgr *NotificationManager) addUINtfn(from zkidentity.ShortID, typ UINotifi
 		n.Text = fmt.Sprintf("wr created by %s: %s", from,
 			cfg.clip(msg))
 
+	case typ == UINtfnChat && n.Count == 0:
+		// First chat message.
+		n.Type = typ
+		n.Count = 1
+		n.From = from
+		n.FromNick = fromNick
+		n.Timestamp = ts.Unix()
+		n.Text = fmt.Sprintf("%s: %s", fromNick, cfg.clip(msg))
+
+	case typ == UINtfnChat && n.Type == UINtfnChat && n.fromSame(&from):
+		// Additional chat messages from the same player.
+		n.Count += 1
+		n.Text = fmt.Sprintf("%d messages from %s", n.Count, fromNick)
+
 	default:
 		// Multiple types.
 		n.Type = UINtfnMultiple
@@ -391,9 +483,63 @@ func (nmgr *NotificationManager) notifyPlayerLeftWR(wr *pong.WaitingRoom, player
 		visit(func(h OnPlayerLeftNtfn) { h(wr, playerID, ts) })
 }
 
+func (nmgr *NotificationManager) notifyWRInvite(wr *pong.WaitingRoom, inviteCode, hostID string, ts time.Time) {
+	nmgr.handlers[onWRInvitefnType].(*handlersFor[OnWRInviteNtfn]).
+		visit(func(h OnWRInviteNtfn) { h(wr, inviteCode, hostID, ts) })
+}
+
+func (nmgr *NotificationManager) notifyChallenge(typ pong.NotificationType, c *pong.Challenge, wr *pong.WaitingRoom, ts time.Time) {
+	nmgr.handlers[onChallengefnType].(*handlersFor[OnChallengeNtfn]).
+		visit(func(h OnChallengeNtfn) { h(typ, c, wr, ts) })
+}
+
+func (nmgr *NotificationManager) notifySeriesUpdate(gameID, msg string, series *pong.SeriesState, ts time.Time) {
+	nmgr.handlers[onSeriesUpdatefnType].(*handlersFor[OnSeriesUpdateNtfn]).
+		visit(func(h OnSeriesUpdateNtfn) { h(gameID, msg, series, ts) })
+}
+
+func (nmgr *NotificationManager) notifyRematch(typ pong.NotificationType, r *pong.Rematch, wr *pong.WaitingRoom, ts time.Time) {
+	nmgr.handlers[onRematchfnType].(*handlersFor[OnRematchNtfn]).
+		visit(func(h OnRematchNtfn) { h(typ, r, wr, ts) })
+}
+
+func (nmgr *NotificationManager) notifyWRTimeout(typ pong.NotificationType, msg string, wr *pong.WaitingRoom, ts time.Time) {
+	nmgr.handlers[onWRTimeoutfnType].(*handlersFor[OnWRTimeoutNtfn]).
+		visit(func(h OnWRTimeoutNtfn) { h(typ, msg, wr, ts) })
+}
+
+func (nmgr *NotificationManager) notifyWRModeration(typ pong.NotificationType, msg string, wr *pong.WaitingRoom, ts time.Time) {
+	nmgr.handlers[onWRModerationfnType].(*handlersFor[OnWRModerationNtfn]).
+		visit(func(h OnWRModerationNtfn) { h(typ, msg, wr, ts) })
+}
+
+func (nmgr *NotificationManager) notifyChatMessage(msg *pong.ChatMessage, ts time.Time) {
+	nmgr.handlers[onChatMessagefnType].(*handlersFor[OnChatMessageNtfn]).
+		visit(func(h OnChatMessageNtfn) { h(msg, ts) })
+
+	var id zkidentity.ShortID
+	id.FromString(msg.SenderId)
+	nick := msg.SenderNick
+	if nick == "" {
+		nick = msg.SenderId
+	}
+	nmgr.addUINtfn(id, nick, UINtfnChat, msg.Text, ts)
+}
+
+func (nmgr *NotificationManager) notifyTournament(typ pong.NotificationType, msg string, t *pong.Tournament, wr *pong.WaitingRoom, ts time.Time) {
+	nmgr.handlers[onTournamentfnType].(*handlersFor[OnTournamentNtfn]).
+		visit(func(h OnTournamentNtfn) { h(typ, msg, t, wr, ts) })
+}
+
+func (nmgr *NotificationManager) notifyPayout(typ pong.NotificationType, msg string, balance *pong.Balance, ts time.Time) {
+	nmgr.handlers[onPayoutfnType].(*handlersFor[OnPayoutNtfn]).
+		visit(func(h OnPayoutNtfn) { h(typ, msg, balance, ts) })
+}
+
 func NewNotificationManager() *NotificationManager {
 	nmgr := &NotificationManager{
 		uiConfig: UINotificationsConfig{
+			Chat:         true,
 			MaxLength:    255,
 			EmitInterval: 30 * time.Second,
 		},
@@ -406,6 +552,15 @@ func NewNotificationManager() *NotificationManager {
 			OnPlayerJoinedNtfnType: &handlersFor[OnPlayerJoinedNtfn]{},
 			onGameEndedfnType:      &handlersFor[OnGameEndedNtfn]{},
 			onPlayerLeftNtfnType:   &handlersFor[OnPlayerLeftNtfn]{},
+			onWRInvitefnType:       &handlersFor[OnWRInviteNtfn]{},
+			onChallengefnType:      &handlersFor[OnChallengeNtfn]{},
+			onSeriesUpdatefnType:   &handlersFor[OnSeriesUpdateNtfn]{},
+			onRematchfnType:        &handlersFor[OnRematchNtfn]{},
+			onTournamentfnType:     &handlersFor[OnTournamentNtfn]{},
+			onChatMessagefnType:    &handlersFor[OnChatMessageNtfn]{},
+			onWRTimeoutfnType:      &handlersFor[OnWRTimeoutNtfn]{},
+			onWRModerationfnType:   &handlersFor[OnWRModerationNtfn]{},
+			onPayoutfnType:         &handlersFor[OnPayoutNtfn]{},
 
 			onUINtfnType: &handlersFor[OnUINotification]{},
 		},
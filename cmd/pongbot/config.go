@@ -5,8 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/vctt94/bisonbotkit/config"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 )
 
 type PongBotConfig struct {
@@ -14,10 +17,38 @@ type PongBotConfig struct {
 
 	// Additional pong-specific fields
 	IsF2P     bool
-	MinBetAmt float64
+	MinBetAmt matoms.Amount
 	GRPCHost  string
 	GRPCPort  string
 	HttpPort  string
+
+	// SeasonLength is the length of a leaderboard season.
+	SeasonLength time.Duration
+
+	// ReadyTimeout is how long players of a full waiting room have to get
+	// ready.
+	ReadyTimeout time.Duration
+
+	// RoomTTL is how long an inactive waiting room is kept.
+	RoomTTL time.Duration
+
+	// RestoreGrace is how long players of waiting rooms restored after a
+	// restart have to reconnect.
+	RestoreGrace time.Duration
+
+	// RakePercent, RakeFee and RakeCap configure the commission the house
+	// keeps from the pot of games with a winner.
+	RakePercent float64
+	RakeFee     matoms.Amount
+	RakeCap     matoms.Amount
+
+	// CreditGrant is the balance free-to-play players are topped up to
+	// every day.
+	CreditGrant matoms.Amount
+
+	// AdminTokens maps operator names to the bearer tokens of the admin
+	// HTTP API.
+	AdminTokens map[string]string
 }
 
 // Load config function
@@ -28,7 +59,7 @@ func LoadPongBotConfig(dataDir, configFile string) (*PongBotConfig, error) {
 		return nil, fmt.Errorf("failed to load base config: %w", err)
 	}
 
-	minBetAmt, err := strconv.ParseFloat(baseConfig.ExtraConfig["minbetamt"], 64)
+	minBetAmt, err := matoms.Parse(baseConfig.ExtraConfig["minbetamt"])
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse minbetamt: %w", err)
 	}
@@ -42,6 +73,73 @@ func LoadPongBotConfig(dataDir, configFile string) (*PongBotConfig, error) {
 		HttpPort:  baseConfig.ExtraConfig["httpport"],
 	}
 
+	if v := baseConfig.ExtraConfig["seasondays"]; v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse seasondays: %w", err)
+		}
+		cfg.SeasonLength = time.Duration(days) * 24 * time.Hour
+	}
+
+	if v := baseConfig.ExtraConfig["readytimeoutsecs"]; v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse readytimeoutsecs: %w", err)
+		}
+		cfg.ReadyTimeout = time.Duration(secs) * time.Second
+	}
+
+	if v := baseConfig.ExtraConfig["roomttlmins"]; v != "" {
+		mins, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse roomttlmins: %w", err)
+		}
+		cfg.RoomTTL = time.Duration(mins) * time.Minute
+	}
+
+	if v := baseConfig.ExtraConfig["restoregracesecs"]; v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse restoregracesecs: %w", err)
+		}
+		cfg.RestoreGrace = time.Duration(secs) * time.Second
+	}
+
+	if v := baseConfig.ExtraConfig["rakepercent"]; v != "" {
+		percent, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rakepercent: %w", err)
+		}
+		cfg.RakePercent = percent
+	}
+
+	for key, dst := range map[string]*matoms.Amount{
+		"rakefee":     &cfg.RakeFee,
+		"rakecap":     &cfg.RakeCap,
+		"creditgrant": &cfg.CreditGrant,
+	} {
+		v := baseConfig.ExtraConfig[key]
+		if v == "" {
+			continue
+		}
+		amt, err := matoms.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+		*dst = amt
+	}
+
+	if v := baseConfig.ExtraConfig["admintokens"]; v != "" {
+		cfg.AdminTokens = make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			name, token, ok := strings.Cut(strings.TrimSpace(pair), ":")
+			if !ok || name == "" || token == "" {
+				return nil, fmt.Errorf("failed to parse admintokens: expected name:token pairs")
+			}
+			cfg.AdminTokens[name] = token
+		}
+	}
+
 	// Load the config file if it exists
 	configPath := filepath.Join(dataDir, configFile)
 	if _, err := os.Stat(configPath); err == nil {
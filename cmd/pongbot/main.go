@@ -17,18 +17,20 @@ import (
 	"github.com/vctt94/bisonbotkit"
 	"github.com/vctt94/bisonbotkit/logging"
 	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 	"github.com/vctt94/pong-bisonrelay/server"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 )
 
 var (
 	flagDataDir        = flag.String("datadir", "", "Directory for server data (certificates, keys, etc.)")
 	flagIsF2P          = flag.Bool("isf2p", false, "Enable free-to-play mode")
-	flagMinBetAmt      = flag.Float64("minbetamt", 0, "Minimum bet amount")
+	flagMinBetAmt      = flag.String("minbetamt", "", "Minimum bet amount in DCR")
 	flagRPCURL         = flag.String("rpcurl", "", "URL of the RPC server")
 	flagGRPCHost       = flag.String("grpchost", "", "Host for gRPC server")
 	flagGRPCPort       = flag.String("grpcport", "", "Port for gRPC server")
@@ -57,13 +59,15 @@ func realMain() error {
 	if cfg == nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
-	// Create channels for tip events
+	// Create channels for tip and PM events
 	tipChan := make(chan types.ReceivedTip)
 	tipProgressChan := make(chan types.TipProgressEvent)
+	pmChan := make(chan types.ReceivedPM)
 
 	// Assign channels to the bot config
 	cfg.BotConfig.TipReceivedChan = tipChan
 	cfg.BotConfig.TipProgressChan = tipProgressChan
+	cfg.BotConfig.PMChan = pmChan
 
 	logBackend, err := logging.NewLogBackend(logging.LogConfig{
 		LogFile:        filepath.Join(appdata, "logs", "pongbot.log"),
@@ -83,8 +87,12 @@ func realMain() error {
 	if *flagIsF2P {
 		cfg.IsF2P = *flagIsF2P
 	}
-	if *flagMinBetAmt != 0 {
-		cfg.MinBetAmt = *flagMinBetAmt
+	if *flagMinBetAmt != "" {
+		minBetAmt, err := matoms.Parse(*flagMinBetAmt)
+		if err != nil {
+			return fmt.Errorf("invalid minbetamt: %w", err)
+		}
+		cfg.MinBetAmt = minBetAmt
 	}
 	if *flagRPCURL != "" {
 		cfg.RPCURL = *flagRPCURL
@@ -155,12 +163,21 @@ func realMain() error {
 	copy(zkShortID[:], clientID)
 
 	srv, err := server.NewServer(&zkShortID, server.ServerConfig{
-		Bot:        bot,
-		ServerDir:  cfg.DataDir,
-		IsF2P:      cfg.IsF2P,
-		MinBetAmt:  cfg.MinBetAmt,
-		HTTPPort:   cfg.HttpPort,
-		LogBackend: logBackend,
+		Bot:          bot,
+		ServerDir:    cfg.DataDir,
+		IsF2P:        cfg.IsF2P,
+		MinBetAmt:    cfg.MinBetAmt,
+		HTTPPort:     cfg.HttpPort,
+		LogBackend:   logBackend,
+		SeasonLength: cfg.SeasonLength,
+		ReadyTimeout: cfg.ReadyTimeout,
+		RoomTTL:      cfg.RoomTTL,
+		RestoreGrace: cfg.RestoreGrace,
+		RakePercent:  cfg.RakePercent,
+		RakeFee:      cfg.RakeFee,
+		RakeCap:      cfg.RakeCap,
+		CreditGrant:  cfg.CreditGrant,
+		AdminTokens:  cfg.AdminTokens,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
@@ -194,6 +211,19 @@ func realMain() error {
 		}
 	})
 
+	g.Go(func() error {
+		for {
+			select {
+			case pm := <-pmChan:
+				if err := srv.HandlePM(ctx, &pm); err != nil {
+					log.Errorf("Error processing PM: %v", err)
+				}
+			case <-gctx.Done():
+				return nil
+			}
+		}
+	})
+
 	certPath := filepath.Join(cfg.DataDir, "server.cert")
 	keyPath := filepath.Join(cfg.DataDir, "server.key")
 	if _, err := os.Stat(certPath); os.IsNotExist(err) {
@@ -220,6 +250,7 @@ func realMain() error {
 	)
 
 	pong.RegisterPongGameServer(grpcServer, srv)
+	healthpb.RegisterHealthServer(grpcServer, srv.HealthServer())
 
 	g.Go(func() error {
 		<-gctx.Done()
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/vctt94/pong-bisonrelay/client"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
@@ -43,6 +44,42 @@ const (
 	viewLogs
 )
 
+// roomFilter is the filter of the join view.
+type roomFilter int
+
+const (
+	roomFilterAll roomFilter = iota
+	roomFilterOpen
+	roomFilterAffordable
+	roomFilterCount
+)
+
+func (f roomFilter) String() string {
+	switch f {
+	case roomFilterOpen:
+		return "open seats"
+	case roomFilterAffordable:
+		return "open seats I can afford"
+	default:
+		return "all rooms"
+	}
+}
+
+func sortLabel(order pong.WaitingRoomSort) string {
+	switch order {
+	case pong.WaitingRoomSort_WR_SORT_OLDEST:
+		return "oldest"
+	case pong.WaitingRoomSort_WR_SORT_BET_ASC:
+		return "lowest bet"
+	case pong.WaitingRoomSort_WR_SORT_BET_DESC:
+		return "highest bet"
+	case pong.WaitingRoomSort_WR_SORT_HOST_RATING:
+		return "host rating"
+	default:
+		return "newest"
+	}
+}
+
 var (
 	// serverAddr = flag.String("server_addr", "104.131.180.29:50051", "The server address in the format of host:port")
 	serverAddr         = flag.String("server_addr", "", "The server address in the format of host:port")
@@ -54,6 +91,7 @@ var (
 	rpcUser            = flag.String("rpcuser", "", "RPC user for basic authentication")
 	rpcPass            = flag.String("rpcpass", "", "RPC password for basic authentication")
 	grpcServerCert     = flag.String("grpcservercert", "", "Path to grpc server.cert file")
+	flagStake          = flag.String("stake", "", "DCR to stake in the rooms you create, empty for your whole balance")
 )
 
 type appstate struct {
@@ -77,12 +115,39 @@ type appstate struct {
 	players       []*pong.Player
 
 	// player current bet amt
-	betAmount float64
+	betAmount matoms.Amount
 
 	currentWR *pong.WaitingRoom
 
 	waitingRooms []*pong.WaitingRoom
 
+	// join view search state. roomCursors holds the cursor of every page up
+	// to the current one.
+	roomFilter     roomFilter
+	roomSort       pong.WaitingRoomSort
+	roomCursors    []string
+	roomNextCursor string
+	roomTotal      int32
+
+	// last invite received to a private waiting room
+	inviteRoomID string
+	inviteCode   string
+
+	// pending challenge received from another player
+	challengeID string
+
+	// rematch state after a game ends
+	rematchAvailable bool
+	rematchProposed  bool
+
+	// tournament the player registered in
+	tournament *pong.Tournament
+
+	// chat pane of the current room or game
+	chatting  bool
+	chatInput string
+	chatLog   []string
+
 	notification string
 
 	logBuffer   []string
@@ -156,7 +221,8 @@ func (m *appstate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case pong.NotificationType_GAME_READY_TO_PLAY:
 			m.notification = "=== GAME CREATED! === Press 'r' or SPACE to signal you're ready to play!"
 			m.currentGameId = msg.GameId
-		case pong.NotificationType_COUNTDOWN_UPDATE:
+		case pong.NotificationType_COUNTDOWN_UPDATE,
+			pong.NotificationType_SESSION_REMINDER:
 			m.notification = msg.Message
 		case pong.NotificationType_ON_PLAYER_READY:
 			if msg.PlayerId != m.pc.ID {
@@ -171,10 +237,20 @@ func (m *appstate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		if m.chatting {
+			m.handleChatInput(msg)
+			return m, nil
+		}
+
+		if m.mode == joinRoom && m.handleJoinRoomKey(msg.String()) {
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "l":
 			// Switch to list rooms mode
 			m.mode = listRooms
+			m.roomCursors = nil
 			m.listWaitingRooms()
 			return m, nil
 		case "c":
@@ -188,10 +264,101 @@ func (m *appstate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.notification = "Bet amount must be > 0 to create a room."
 			}
+		case "p":
+			// Create an invite-only room
+			if m.betAmount > 0 || isF2p {
+				err := m.createPrivateRoom()
+				if err != nil {
+					m.notification = fmt.Sprintf("Error creating private room: %v", err)
+				}
+				return m, nil
+			} else {
+				m.notification = "Bet amount must be > 0 to create a room."
+			}
+		case "i":
+			// Accept the last private room invite
+			if m.inviteRoomID != "" && m.currentWR == nil {
+				err := m.acceptInvite()
+				if err != nil {
+					m.notification = fmt.Sprintf("Error joining private room: %v", err)
+				}
+				return m, nil
+			}
+		case "a", "d":
+			// Accept or decline the pending challenge
+			if m.challengeID != "" && m.currentWR == nil {
+				err := m.respondChallenge(msg.String() == "a")
+				if err != nil {
+					m.notification = fmt.Sprintf("Error responding to challenge: %v", err)
+				}
+				return m, nil
+			}
+		case "m":
+			// Propose a rematch with the same stake and rules
+			if m.rematchAvailable && m.currentWR == nil {
+				if _, err := m.pc.ProposeRematch(0, nil); err != nil {
+					m.notification = fmt.Sprintf("Error proposing rematch: %v", err)
+				} else {
+					m.notification = "Rematch proposed, waiting for your opponent"
+				}
+				return m, nil
+			}
+		case "y", "n":
+			// Accept or decline the rematch proposed by the opponent
+			if m.rematchProposed && m.currentWR == nil {
+				err := m.respondRematch(msg.String() == "y")
+				if err != nil {
+					m.notification = fmt.Sprintf("Error responding to rematch: %v", err)
+				}
+				return m, nil
+			}
+		case "/":
+			// Start typing a chat message to the room or game
+			if m.currentWR != nil || m.isGameRunning {
+				m.chatting = true
+				m.chatInput = ""
+				return m, nil
+			}
+		case "k":
+			// Kick the other players out of the hosted room
+			if m.isHost() && !m.isGameRunning {
+				if err := m.kickGuests(); err != nil {
+					m.notification = fmt.Sprintf("Error kicking player: %v", err)
+				}
+				return m, nil
+			}
+		case "o":
+			// Lock or unlock the hosted room
+			if m.isHost() && !m.isGameRunning {
+				wr, err := m.pc.SetRoomLocked(m.currentWR.Id, !m.currentWR.Locked)
+				if err != nil {
+					m.notification = fmt.Sprintf("Error locking room: %v", err)
+				} else {
+					m.currentWR = wr
+				}
+				return m, nil
+			}
+		case "t":
+			// Register in the next tournament open for registration
+			if m.tournament == nil && m.currentWR == nil {
+				if err := m.registerTournament(); err != nil {
+					m.notification = fmt.Sprintf("Error registering in tournament: %v", err)
+				}
+				return m, nil
+			}
+		case "$":
+			// Withdraw the available balance
+			if m.mode == gameIdle && m.currentWR == nil && !m.isGameRunning {
+				if err := m.withdraw(); err != nil {
+					m.notification = fmt.Sprintf("Error withdrawing: %v", err)
+				}
+				return m, nil
+			}
 		case "j":
 			// Switch to join room mode
 			m.mode = joinRoom
 			m.selectedRoomIndex = 0
+			m.roomCursors = nil
 			m.listWaitingRooms()
 			return m, nil
 		case "w", "up":
@@ -337,18 +504,88 @@ func (m *appstate) waitForMsg() tea.Cmd {
 }
 
 func (m *appstate) listWaitingRooms() error {
-	wr, err := m.pc.GetWaitingRooms()
+	query := &pong.WaitingRoomsRequest{
+		Sort: m.roomSort,
+	}
+	if len(m.roomCursors) > 0 {
+		query.Cursor = m.roomCursors[len(m.roomCursors)-1]
+	}
+	switch m.roomFilter {
+	case roomFilterOpen:
+		query.OpenSeats = true
+	case roomFilterAffordable:
+		query.OpenSeats = true
+		query.MaxBet = m.pc.BetAmt
+	}
+
+	res, err := m.pc.SearchWaitingRooms(query)
 	if err != nil {
 		m.log.Errorf("Failed to get waiting rooms: %v", err)
 		return err
 	}
-	m.waitingRooms = wr
+	m.waitingRooms = res.Wr
+	m.roomNextCursor = res.NextCursor
+	m.roomTotal = res.Total
+	if m.selectedRoomIndex >= len(m.waitingRooms) {
+		m.selectedRoomIndex = 0
+	}
 	return nil
 }
 
+// handleJoinRoomKey handles the search keys of the join view. It returns
+// false for keys it doesn't handle.
+func (m *appstate) handleJoinRoomKey(key string) bool {
+	switch key {
+	case "f":
+		m.roomFilter = (m.roomFilter + 1) % roomFilterCount
+	case "o":
+		m.roomSort = (m.roomSort + 1) % pong.WaitingRoomSort(len(pong.WaitingRoomSort_name))
+	case "n":
+		if m.roomNextCursor == "" {
+			return true
+		}
+		m.roomCursors = append(m.roomCursors, m.roomNextCursor)
+		m.selectedRoomIndex = 0
+		if err := m.listWaitingRooms(); err != nil {
+			m.notification = fmt.Sprintf("Error listing rooms: %v", err)
+		}
+		return true
+	case "b":
+		if len(m.roomCursors) == 0 {
+			return true
+		}
+		m.roomCursors = m.roomCursors[:len(m.roomCursors)-1]
+		m.selectedRoomIndex = 0
+		if err := m.listWaitingRooms(); err != nil {
+			m.notification = fmt.Sprintf("Error listing rooms: %v", err)
+		}
+		return true
+	default:
+		return false
+	}
+
+	// Filters and sort order apply from the first page.
+	m.roomCursors = nil
+	m.selectedRoomIndex = 0
+	if err := m.listWaitingRooms(); err != nil {
+		m.notification = fmt.Sprintf("Error listing rooms: %v", err)
+	}
+	return true
+}
+
+// stake returns the amount to stake in new rooms: the -stake flag, capped to
+// the balance, or the whole balance.
+func (m *appstate) stake() int64 {
+	stake, err := matoms.Parse(*flagStake)
+	if err != nil || stake <= 0 || int64(stake) > m.pc.BetAmt {
+		return m.pc.BetAmt
+	}
+	return int64(stake)
+}
+
 func (m *appstate) createRoom() error {
 	var err error
-	_, err = m.pc.CreateWaitingRoom(m.pc.ID, m.pc.BetAmt)
+	_, err = m.pc.CreateWaitingRoom(m.pc.ID, m.stake())
 	if err != nil {
 		m.log.Errorf("Error creating room: %v", err)
 		return err
@@ -357,6 +594,178 @@ func (m *appstate) createRoom() error {
 	return nil
 }
 
+func (m *appstate) withdraw() error {
+	res, err := m.pc.Withdraw(0)
+	if err != nil {
+		m.log.Errorf("Error withdrawing: %v", err)
+		return err
+	}
+	m.notification = fmt.Sprintf("Withdrawing %s. Reserved: %s, pending payouts: %s",
+		matoms.Amount(res.Amount), matoms.Amount(res.Balance.Reserved),
+		matoms.Amount(res.Balance.PendingPayout))
+	return nil
+}
+
+func (m *appstate) createPrivateRoom() error {
+	wr, code, err := m.pc.CreatePrivateWaitingRoom(m.stake(), nil)
+	if err != nil {
+		m.log.Errorf("Error creating private room: %v", err)
+		return err
+	}
+	m.currentWR = wr
+	m.mode = gameMode
+	m.notification = fmt.Sprintf("Private room %s created. Invite code: %s", wr.Id, code)
+	return nil
+}
+
+func (m *appstate) acceptInvite() error {
+	res, err := m.pc.JoinPrivateWaitingRoom(m.inviteRoomID, m.inviteCode)
+	if err != nil {
+		m.log.Errorf("Failed to join private room %s: %v", m.inviteRoomID, err)
+		return err
+	}
+	m.currentWR = res.Wr
+	m.inviteRoomID, m.inviteCode = "", ""
+	m.mode = gameMode
+	return nil
+}
+
+func (m *appstate) respondChallenge(accept bool) error {
+	challengeID := m.challengeID
+	m.challengeID = ""
+	wr, err := m.pc.RespondChallenge(challengeID, accept)
+	if err != nil {
+		m.log.Errorf("Failed to respond to challenge %s: %v", challengeID, err)
+		return err
+	}
+	if !accept {
+		m.notification = "Challenge declined"
+		return nil
+	}
+	m.currentWR = wr
+	m.mode = gameMode
+	m.notification = "Challenge accepted! Press SPACE to signal you're ready"
+	return nil
+}
+
+func (m *appstate) respondRematch(accept bool) error {
+	m.rematchProposed = false
+	wr, err := m.pc.RespondRematch(accept)
+	if err != nil {
+		m.log.Errorf("Failed to respond to rematch: %v", err)
+		return err
+	}
+	if !accept {
+		m.rematchAvailable = false
+		m.notification = "Rematch declined"
+		return nil
+	}
+	m.currentWR = wr
+	m.mode = gameMode
+	return nil
+}
+
+// maxChatLog is the number of chat messages kept in the chat pane.
+const maxChatLog = 10
+
+func (m *appstate) handleChatInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.chatting = false
+		m.chatInput = ""
+	case tea.KeyEnter:
+		m.chatting = false
+		text := m.chatInput
+		m.chatInput = ""
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		if _, err := m.pc.SendChatMessage(text); err != nil {
+			m.notification = fmt.Sprintf("Error sending message: %v", err)
+		}
+	case tea.KeyBackspace:
+		if r := []rune(m.chatInput); len(r) > 0 {
+			m.chatInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.chatInput += " "
+	case tea.KeyRunes:
+		m.chatInput += string(msg.Runes)
+	}
+}
+
+func (m *appstate) addChatMessage(chat *pong.ChatMessage) {
+	nick := chat.SenderNick
+	if nick == "" {
+		nick = chat.SenderId
+	}
+	line := fmt.Sprintf("[%s] %s: %s", time.Unix(chat.Timestamp, 0).Format("15:04"), nick, chat.Text)
+	m.chatLog = append(m.chatLog, line)
+	if len(m.chatLog) > maxChatLog {
+		m.chatLog = m.chatLog[len(m.chatLog)-maxChatLog:]
+	}
+}
+
+func (m *appstate) registerTournament() error {
+	tournaments, err := m.pc.ListTournaments()
+	if err != nil {
+		m.log.Errorf("Failed to list tournaments: %v", err)
+		return err
+	}
+	for _, t := range tournaments {
+		if t.State != pong.TournamentState_TOURNAMENT_REGISTRATION {
+			continue
+		}
+		t, err = m.pc.RegisterTournament(t.Id)
+		if err != nil {
+			m.log.Errorf("Failed to register in tournament: %v", err)
+			return err
+		}
+		m.tournament = t
+		m.notification = fmt.Sprintf("Registered in %s, starting at %s", t.Name,
+			time.Unix(t.StartsAt, 0).Format(time.Kitchen))
+		return nil
+	}
+	return fmt.Errorf("no tournament open for registration")
+}
+
+// formatBracket renders the matches of a tournament round by round.
+func formatBracket(t *pong.Tournament) string {
+	nicks := make(map[string]string, len(t.Players))
+	for _, p := range t.Players {
+		nicks[p.PlayerId] = p.Nick
+		if p.Nick == "" {
+			nicks[p.PlayerId] = p.PlayerId[:8]
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🏆 %s (%s) - %s DCR pool\n", t.Name,
+		strings.ToLower(t.State.String()), matoms.Amount(t.PrizePool)))
+	round := int32(0)
+	for _, m := range t.Matches {
+		if m.Round != round {
+			round = m.Round
+			b.WriteString(fmt.Sprintf("Round %d\n", round))
+		}
+		names := make([]string, 0, len(m.PlayerIds))
+		for _, id := range m.PlayerIds {
+			names = append(names, nicks[id])
+		}
+		line := strings.Join(names, " vs ")
+		switch {
+		case m.Bye:
+			line += " (bye)"
+		case m.WinnerId != "" && m.Forfeit:
+			line += fmt.Sprintf(" -> %s (no-show)", nicks[m.WinnerId])
+		case m.WinnerId != "":
+			line += fmt.Sprintf(" -> %s", nicks[m.WinnerId])
+		}
+		b.WriteString(fmt.Sprintf("  %s %s\n", m.Id, line))
+	}
+	return b.String()
+}
+
 func (m *appstate) joinRoom(roomID string) error {
 	res, err := m.pc.JoinWaitingRoom(roomID)
 	if err != nil {
@@ -503,6 +912,31 @@ func (m *appstate) leaveRoom() error {
 	return nil
 }
 
+// isHost returns whether the player hosts the current waiting room.
+func (m *appstate) isHost() bool {
+	return m.currentWR != nil && m.currentWR.HostId == m.pc.ID
+}
+
+// kickGuests removes every other player from the hosted waiting room.
+func (m *appstate) kickGuests() error {
+	kicked := 0
+	for _, p := range m.currentWR.Players {
+		if p.Uid == m.pc.ID {
+			continue
+		}
+		wr, err := m.pc.KickPlayer(m.currentWR.Id, p.Uid, false)
+		if err != nil {
+			return err
+		}
+		m.currentWR = wr
+		kicked++
+	}
+	if kicked == 0 {
+		return fmt.Errorf("no other players in the room")
+	}
+	return nil
+}
+
 func (m *appstate) signalReadyToPlay() error {
 	if !m.isGameRunning {
 		return fmt.Errorf("no active game to signal readiness")
@@ -536,7 +970,10 @@ func (m *appstate) View() string {
 		}
 
 		b.WriteString(fmt.Sprintf("👤 Player ID: %s\n", m.pc.ID))
-		b.WriteString(fmt.Sprintf("💵 Bet Amount: %.8f\n", m.betAmount))
+		b.WriteString(fmt.Sprintf("💵 Bet Amount: %s\n", m.betAmount))
+		if m.currentWR == nil && *flagStake != "" {
+			b.WriteString(fmt.Sprintf("🎯 Stake: %s\n", matoms.Amount(m.stake())))
+		}
 		b.WriteString(fmt.Sprintf("✅ Status Ready: %t\n", m.pc.IsReady))
 
 		// Display the current room or show a placeholder if not in a room
@@ -551,12 +988,57 @@ func (m *appstate) View() string {
 		b.WriteString("Use the following keys to navigate:\n")
 		b.WriteString("[L] - List rooms\n")
 		b.WriteString("[C] - Create room\n")
+		b.WriteString("[P] - Create private room\n")
 		b.WriteString("[J] - Join room\n")
+		if m.inviteRoomID != "" {
+			b.WriteString("[I] - Accept private room invite\n")
+		}
+		if m.challengeID != "" {
+			b.WriteString("[A]/[D] - Accept/decline challenge\n")
+		}
+		if m.rematchProposed {
+			b.WriteString("[Y]/[N] - Accept/decline rematch\n")
+		} else if m.rematchAvailable {
+			b.WriteString("[M] - Propose rematch\n")
+		}
+		if m.tournament == nil {
+			b.WriteString("[T] - Register in tournament\n")
+		}
+		if m.currentWR != nil {
+			b.WriteString("[/] - Chat with the room\n")
+		}
+		if m.isHost() {
+			b.WriteString("[K] - Kick other players\n")
+			if m.currentWR.Locked {
+				b.WriteString("[O] - Unlock room\n")
+			} else {
+				b.WriteString("[O] - Lock room\n")
+			}
+		}
+		if m.currentWR == nil {
+			b.WriteString("[$] - Withdraw available balance\n")
+		}
 		b.WriteString("[Q] - Leave current room\n")
 		b.WriteString("[V] - View logs\n")
 		b.WriteString("[Ctrl+C] - Exit\n")
 		b.WriteString("====================\n\n")
 
+		if m.tournament != nil {
+			b.WriteString(formatBracket(m.tournament))
+			b.WriteString("\n")
+		}
+
+		if len(m.chatLog) > 0 || m.chatting {
+			b.WriteString("===== Chat =====\n")
+			for _, line := range m.chatLog {
+				b.WriteString(line + "\n")
+			}
+			if m.chatting {
+				b.WriteString(fmt.Sprintf("> %s_ (Enter to send, Esc to cancel)\n", m.chatInput))
+			}
+			b.WriteString("\n")
+		}
+
 		if !m.isGameRunning && m.currentWR != nil {
 			if m.pc.IsReady {
 				b.WriteString("[Space] - Toggle ready status (currently READY)\n")
@@ -578,7 +1060,18 @@ func (m *appstate) View() string {
 		b.WriteString("\n[Game Mode]\n")
 		b.WriteString("Press 'Esc' to return to the main menu.\n")
 		b.WriteString("Use W/S or Arrow Keys to move.\n")
-		b.WriteString(fmt.Sprintf("Use +/- to adjust key release delay (current: %d ms).\n\n", m.keyReleaseDelay/time.Millisecond))
+		b.WriteString(fmt.Sprintf("Use +/- to adjust key release delay (current: %d ms).\n", m.keyReleaseDelay/time.Millisecond))
+		if m.isGameRunning {
+			switch {
+			case m.chatting:
+				b.WriteString(fmt.Sprintf("> %s_\n", m.chatInput))
+			case len(m.chatLog) > 0:
+				b.WriteString(m.chatLog[len(m.chatLog)-1] + "\n")
+			default:
+				b.WriteString("Press '/' to chat.\n")
+			}
+		}
+		b.WriteString("\n")
 
 		if m.gameState != nil {
 			var gameView strings.Builder
@@ -686,7 +1179,7 @@ func (m *appstate) View() string {
 		b.WriteString("\n[List Rooms Mode]\n")
 		if len(m.waitingRooms) > 0 {
 			for i, room := range m.waitingRooms {
-				b.WriteString(fmt.Sprintf("%d: Room ID %s - Bet Price: %.8f\n", i+1, room.Id, float64(room.BetAmt)/1e11))
+				b.WriteString(fmt.Sprintf("%d: Room ID %s - Bet Price: %s\n", i+1, room.Id, matoms.Amount(room.BetAmt)))
 			}
 		} else {
 			b.WriteString("No rooms available.\n")
@@ -700,7 +1193,10 @@ func (m *appstate) View() string {
 	case joinRoom:
 		b.WriteString("\n[Join Room Mode]\n")
 		b.WriteString("Select a room to join. Use [up]/[down] to navigate and [enter] to join.\n")
+		b.WriteString("[F] filter, [O] sort, [N]/[B] next/previous page.\n")
 		b.WriteString("Press [esc] to go back to the main menu.\n")
+		b.WriteString(fmt.Sprintf("Filter: %s | Sort: %s | Page %d | %d rooms\n",
+			m.roomFilter, sortLabel(m.roomSort), len(m.roomCursors)+1, m.roomTotal))
 
 		if len(m.waitingRooms) > 0 {
 			for i, room := range m.waitingRooms {
@@ -708,7 +1204,17 @@ func (m *appstate) View() string {
 				if i == m.selectedRoomIndex {
 					indicator = ">" // Mark the selected room
 				}
-				b.WriteString(fmt.Sprintf("%s %d: Room ID %s - Bet Price: %.8f\n", indicator, i+1, room.Id, float64(room.BetAmt)/1e11))
+				locked := ""
+				if room.Locked {
+					locked = " [locked]"
+				}
+				rake := ""
+				if room.Rake > 0 {
+					rake = fmt.Sprintf(" - Rake: %s", matoms.Amount(room.Rake))
+				}
+				b.WriteString(fmt.Sprintf("%s %d: Room ID %s - Bet Price: %s%s - Players: %d - First to %d - Host rating: %.0f%s\n",
+					indicator, i+1, room.Id, matoms.Amount(room.BetAmt), rake, len(room.Players),
+					room.Rules.GetMaxScore(), room.HostRating, locked))
 			}
 		} else {
 			b.WriteString("No rooms available.\n")
@@ -820,7 +1326,7 @@ func realMain() error {
 		for _, p := range as.players {
 			if p.Uid == clientID {
 				as.currentWR = wr
-				as.betAmount = float64(wr.BetAmt) / 1e11
+				as.betAmount = matoms.Amount(wr.BetAmt)
 				as.mode = gameMode
 			}
 		}
@@ -840,7 +1346,7 @@ func realMain() error {
 		// Update bet amount for the player in the local state (e.g., as.Players).
 		if clientID == playerID {
 			as.notification = "bet amount updated"
-			as.betAmount = float64(betAmt) / 1e11
+			as.betAmount = matoms.Amount(betAmt)
 			as.msgCh <- client.UpdatedMsg{}
 		}
 		for i, p := range as.players {
@@ -877,6 +1383,165 @@ func realMain() error {
 		}()
 	}))
 
+	ntfns.Register(client.OnWRInviteNtfn(func(wr *pong.WaitingRoom, code, hostID string, ts time.Time) {
+		as.Lock()
+		as.inviteRoomID = wr.Id
+		as.inviteCode = code
+		as.Unlock()
+		as.notification = fmt.Sprintf("%s invited you to private room %s. Press [I] to join", hostID, wr.Id)
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnChallengeNtfn(func(typ pong.NotificationType, c *pong.Challenge, wr *pong.WaitingRoom, ts time.Time) {
+		as.Lock()
+		switch typ {
+		case pong.NotificationType_CHALLENGE_RECEIVED:
+			from := c.ChallengerNick
+			if from == "" {
+				from = c.ChallengerId
+			}
+			as.challengeID = c.Id
+			as.notification = fmt.Sprintf("%s challenged you for %s DCR (first to %d). Press [A] to accept or [D] to decline",
+				from, matoms.Amount(c.BetAmt), c.Rules.GetMaxScore())
+		case pong.NotificationType_CHALLENGE_ACCEPTED:
+			as.currentWR = wr
+			as.mode = gameMode
+			as.notification = "Challenge accepted! Press SPACE to signal you're ready"
+		case pong.NotificationType_CHALLENGE_DECLINED:
+			as.notification = "Your challenge was declined"
+		case pong.NotificationType_CHALLENGE_EXPIRED:
+			if as.challengeID == c.Id {
+				as.challengeID = ""
+			}
+			as.notification = "Challenge expired"
+		}
+		as.Unlock()
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnSeriesUpdateNtfn(func(gameID, msg string, series *pong.SeriesState, ts time.Time) {
+		as.notification = fmt.Sprintf("game %s ended (best of %d)\n%s", gameID, series.GetBestOf(), msg)
+		as.isGameRunning = false
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnRematchNtfn(func(typ pong.NotificationType, r *pong.Rematch, wr *pong.WaitingRoom, ts time.Time) {
+		as.Lock()
+		switch typ {
+		case pong.NotificationType_REMATCH_AVAILABLE:
+			as.rematchAvailable = true
+			as.rematchProposed = false
+		case pong.NotificationType_REMATCH_PROPOSED:
+			as.rematchProposed = true
+			as.notification = fmt.Sprintf("Opponent proposed a rematch for %s DCR (best of %d). Press [Y] to accept or [N] to decline",
+				matoms.Amount(r.BetAmt), r.Rules.GetBestOf())
+		case pong.NotificationType_REMATCH_ACCEPTED:
+			as.rematchAvailable = false
+			as.rematchProposed = false
+			as.currentWR = wr
+			as.mode = gameMode
+			as.notification = "Rematch accepted! Starting a new game"
+		case pong.NotificationType_REMATCH_DECLINED, pong.NotificationType_REMATCH_EXPIRED:
+			as.rematchAvailable = false
+			as.rematchProposed = false
+			as.notification = "Rematch is no longer available"
+		}
+		as.Unlock()
+
+		// Rematches go straight to the game without waiting in the room.
+		if typ == pong.NotificationType_REMATCH_ACCEPTED {
+			if err := as.makeClientReady(); err != nil {
+				as.notification = fmt.Sprintf("Error signaling readiness: %v", err)
+			}
+		}
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnTournamentNtfn(func(typ pong.NotificationType, msg string, t *pong.Tournament, wr *pong.WaitingRoom, ts time.Time) {
+		as.Lock()
+		as.tournament = t
+		as.notification = msg
+		switch typ {
+		case pong.NotificationType_TOURNAMENT_MATCH_READY:
+			as.currentWR = wr
+			as.mode = gameMode
+			as.notification = msg + "\nPress SPACE to signal you're ready"
+		case pong.NotificationType_TOURNAMENT_ENDED:
+			as.tournament = nil
+			as.notification = msg + "\n" + formatBracket(t)
+		}
+		as.Unlock()
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnWRTimeoutNtfn(func(typ pong.NotificationType, msg string, wr *pong.WaitingRoom, ts time.Time) {
+		as.Lock()
+		as.notification = msg
+		switch {
+		case typ == pong.NotificationType_READY_CHECK:
+			as.currentWR = wr
+			as.notification = fmt.Sprintf("%s Deadline: %s", msg,
+				time.Unix(wr.ReadyDeadline, 0).Format(time.Kitchen))
+		case typ == pong.NotificationType_WR_EXPIRED,
+			typ == pong.NotificationType_PLAYER_EVICTED && wr == nil:
+			as.currentWR = nil
+			as.mode = gameIdle
+		case typ == pong.NotificationType_WR_RESTORED:
+			as.currentWR = wr
+			as.betAmount = matoms.Amount(wr.BetAmt)
+			as.mode = gameMode
+		default:
+			as.currentWR = wr
+		}
+		as.Unlock()
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnWRModerationNtfn(func(typ pong.NotificationType, msg string, wr *pong.WaitingRoom, ts time.Time) {
+		as.Lock()
+		as.notification = msg
+		if typ == pong.NotificationType_PLAYER_KICKED && wr == nil {
+			as.currentWR = nil
+			as.mode = gameIdle
+		} else {
+			as.currentWR = wr
+		}
+		as.Unlock()
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnPayoutNtfn(func(typ pong.NotificationType, msg string, balance *pong.Balance, ts time.Time) {
+		as.Lock()
+		as.notification = msg
+		as.Unlock()
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
+	ntfns.Register(client.OnChatMessageNtfn(func(chat *pong.ChatMessage, ts time.Time) {
+		as.Lock()
+		as.addChatMessage(chat)
+		as.Unlock()
+		go func() {
+			as.msgCh <- client.UpdatedMsg{}
+		}()
+	}))
+
 	ntfns.Register(client.OnGameEndedNtfn(func(gameID, msg string, ts time.Time) {
 		as.notification = fmt.Sprintf("game %s ended\n%s", gameID, msg)
 		as.betAmount = 0
@@ -902,6 +1567,7 @@ func realMain() error {
 
 	pc, err := client.NewPongClient(clientID, &client.PongClientCfg{
 		ServerAddr:    cfg.ServerAddr,
+		Nick:          publicIdentity.Nick,
 		Notifications: ntfns,
 		Log:           log,
 		GRPCCertPath:  cfg.GRPCServerCert,
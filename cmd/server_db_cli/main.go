@@ -4,14 +4,19 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/vctt94/pong-bisonrelay/matoms"
 )
 
 var (
-	serverURL = flag.String("serverURL", "http://localhost:8888", "URL of the HTTP server")
+	serverURL  = flag.String("serverURL", "http://localhost:8888", "URL of the HTTP server")
+	adminToken = flag.String("admintoken", "", "token of the admin API")
 )
 
 type Tip struct {
@@ -40,6 +45,8 @@ func main() {
 		fmt.Println("  getsendprogress <clientID>")
 		fmt.Println("  getreceived <clientID>")
 		fmt.Println("  getall")
+		fmt.Println("  export [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-format csv|json] [-out file]")
+		fmt.Println("  reconcile")
 		os.Exit(1)
 	}
 
@@ -58,6 +65,10 @@ func main() {
 		fetchReceivedTips(args[1])
 	case "getall":
 		fetchAllUnprocessedTips()
+	case "export":
+		exportLedger(args[1:])
+	case "reconcile":
+		fetchReconciliation()
 	default:
 		fmt.Println("Unknown command:", args[0])
 		os.Exit(1)
@@ -66,7 +77,7 @@ func main() {
 
 func fetchReceivedTips(clientID string) {
 	url := fmt.Sprintf("%s/received?clientID=%s", *serverURL, clientID)
-	resp, err := http.Get(url)
+	resp, err := adminGet(url)
 	if err != nil {
 		fmt.Printf("Error fetching received tips: %v\n", err)
 		return
@@ -93,7 +104,7 @@ func fetchReceivedTips(clientID string) {
 
 func fetchAllUnprocessedTips() {
 	url := fmt.Sprintf("%s/fetchAllUnprocessedTips", *serverURL)
-	resp, err := http.Get(url)
+	resp, err := adminGet(url)
 	if err != nil {
 		fmt.Printf("Error fetching all unprocessed tips: %v\n", err)
 		return
@@ -129,7 +140,7 @@ func fetchAllUnprocessedTips() {
 
 func fetchSendProgress(clientID string) {
 	url := fmt.Sprintf("%s/tipprogress?clientID=%s", *serverURL, clientID)
-	resp, err := http.Get(url)
+	resp, err := adminGet(url)
 	if err != nil {
 		fmt.Printf("Error fetching send progress: %v\n", err)
 		return
@@ -179,9 +190,138 @@ func fetchSendProgress(clientID string) {
 	}
 }
 
-func formatMatoms(matoms int64) string {
-	btc := float64(matoms) / 1e11 // Convert matoms to BTC
-	return fmt.Sprintf("%.2f", btc)
+// adminGet makes a GET request to an endpoint of the admin API.
+func adminGet(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+*adminToken)
+	return http.DefaultClient.Do(req)
+}
+
+func exportLedger(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	from := fs.String("from", "", "first day to export (YYYY-MM-DD, UTC)")
+	to := fs.String("to", "", "last day to export (YYYY-MM-DD, UTC)")
+	format := fs.String("format", "csv", "output format (csv or json)")
+	out := fs.String("out", "", "file to write the export to instead of stdout")
+	fs.Parse(args)
+
+	query := url.Values{"format": {*format}}
+	if *from != "" {
+		day, err := time.Parse(time.DateOnly, *from)
+		if err != nil {
+			fmt.Printf("Invalid from date: %v\n", err)
+			os.Exit(1)
+		}
+		query.Set("from", fmt.Sprint(day.Unix()))
+	}
+	if *to != "" {
+		day, err := time.Parse(time.DateOnly, *to)
+		if err != nil {
+			fmt.Printf("Invalid to date: %v\n", err)
+			os.Exit(1)
+		}
+		// Include the whole last day.
+		query.Set("to", fmt.Sprint(day.AddDate(0, 0, 1).Unix()))
+	}
+
+	resp, err := adminGet(fmt.Sprintf("%s/audit/export?%s", *serverURL, query.Encode()))
+	if err != nil {
+		fmt.Printf("Error fetching export: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Server returned error: %s\n", resp.Status)
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Printf("Error creating %s: %v\n", *out, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		fmt.Printf("Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func fetchReconciliation() {
+	resp, err := adminGet(fmt.Sprintf("%s/audit/reconcile", *serverURL))
+	if err != nil {
+		fmt.Printf("Error fetching reconciliation: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("Server returned error: %s\n", resp.Status)
+		os.Exit(1)
+	}
+
+	var report struct {
+		CheckedAt   time.Time `json:"checked_at"`
+		LedgerError string    `json:"ledger_error"`
+		StuckTips   []struct {
+			UID        string `json:"uid"`
+			SequenceID uint64 `json:"sequence_id"`
+			Amount     int64  `json:"amount"`
+			Reason     string `json:"reason"`
+		} `json:"stuck_tips"`
+		OrphanedPayouts []struct {
+			ID     uint64 `json:"id"`
+			UID    string `json:"uid"`
+			Status string `json:"status"`
+			Amount int64  `json:"amount"`
+			Reason string `json:"reason"`
+		} `json:"orphaned_payouts"`
+		BalanceMismatches []struct {
+			UID    string `json:"uid"`
+			Ledger int64  `json:"ledger"`
+			Tips   int64  `json:"tips"`
+		} `json:"balance_mismatches"`
+		OK bool `json:"ok"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		fmt.Printf("Error decoding response: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Reconciliation at %s\n\n", report.CheckedAt.Format("2006-01-02 15:04:05"))
+	if report.LedgerError != "" {
+		fmt.Printf("Ledger: %s\n\n", report.LedgerError)
+	}
+	fmt.Printf("Tips stuck in sending: %d\n", len(report.StuckTips))
+	for _, tip := range report.StuckTips {
+		fmt.Printf("  - ID: %-12d | %8s DCR | UID: %s | %s\n", tip.SequenceID,
+			formatMatoms(tip.Amount), truncateUID(tip.UID), tip.Reason)
+	}
+	fmt.Printf("Orphaned payouts: %d\n", len(report.OrphanedPayouts))
+	for _, p := range report.OrphanedPayouts {
+		fmt.Printf("  - #%-6d %-8s | %8s DCR | UID: %s | %s\n", p.ID, strings.ToUpper(p.Status),
+			formatMatoms(p.Amount), truncateUID(p.UID), p.Reason)
+	}
+	fmt.Printf("Balance mismatches: %d\n", len(report.BalanceMismatches))
+	for _, m := range report.BalanceMismatches {
+		fmt.Printf("  - UID: %s | ledger %s DCR | tips %s DCR\n", truncateUID(m.UID),
+			formatMatoms(m.Ledger), formatMatoms(m.Tips))
+	}
+	if !report.OK {
+		os.Exit(2)
+	}
+}
+
+func formatMatoms(amount int64) string {
+	return matoms.Amount(amount).String()
 }
 
 func truncateUID(uid string) string {
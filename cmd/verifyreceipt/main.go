@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vctt94/pong-bisonrelay/receipt"
+)
+
+var (
+	flagPubKey = flag.String("pubkey", "", "Hex public key of the bot, as logged at startup. If empty, the key in the receipt is trusted")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-pubkey <hex>] [receipt file]\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Verifies a match receipt sent by the pong bot. The receipt is read from")
+		fmt.Fprintln(os.Stderr, "the file, or stdin if none is given, and may include the text of the PM.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var data []byte
+	var err error
+	switch flag.NArg() {
+	case 0:
+		data, err = io.ReadAll(os.Stdin)
+	case 1:
+		data, err = os.ReadFile(flag.Arg(0))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read receipt: %w", err)
+	}
+
+	var pub ed25519.PublicKey
+	if *flagPubKey != "" {
+		pub, err = hex.DecodeString(*flagPubKey)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid public key %q", *flagPubKey)
+		}
+	}
+
+	// Skip the text of the PM before the receipt.
+	if i := bytes.IndexByte(data, '{'); i > 0 {
+		data = data[i:]
+	}
+	var signed receipt.Signed
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return fmt.Errorf("failed to decode receipt: %w", err)
+	}
+	r, err := signed.Verify(pub)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Receipt signature is valid.")
+	if pub == nil {
+		fmt.Printf("Warning: signed by %x, pass -pubkey to check it's the bot's key.\n", signed.PublicKey)
+	}
+	fmt.Printf("Game:        %s\n", r.GameID)
+	if r.SeriesID != "" {
+		fmt.Printf("Series:      %s\n", r.SeriesID)
+	}
+	for _, p := range r.Players {
+		fmt.Printf("Player:      %s (%s) - score %d, stake %s DCR\n", p.Nick, p.UID, p.Score, p.Stake)
+	}
+	winner := "draw"
+	if r.Winner != "" {
+		winner = r.Winner
+	}
+	fmt.Printf("Winner:      %s\n", winner)
+	fmt.Printf("Pot:         %s DCR\n", r.Pot)
+	fmt.Printf("Rake:        %s DCR\n", r.Rake)
+	fmt.Printf("Payout:      %s DCR\n", r.Payout)
+	fmt.Printf("Replay hash: %x\n", r.ReplayHash)
+	for i, g := range r.Games {
+		winner := "draw"
+		if g.Winner != "" {
+			winner = g.Winner
+		}
+		fmt.Printf("Game %d:      %s - winner %s, replay hash %x\n", i+1, g.ID, winner, g.ReplayHash)
+	}
+	fmt.Printf("Started:     %s\n", r.StartedAt)
+	fmt.Printf("Ended:       %s\n", r.EndedAt)
+	return nil
+}
@@ -14,6 +14,7 @@ require (
 	github.com/jrick/logrotate v1.1.2
 	github.com/ndabAP/ping-pong v0.0.0-20231119080825-15ed4850b548
 	github.com/pbnjay/memory v0.0.0-20210728143218-7b4eea64cf58
+	github.com/prometheus/client_golang v1.15.0
 	github.com/prometheus/procfs v0.12.0
 	github.com/stretchr/testify v1.10.0
 	github.com/vctt94/bisonbotkit v0.0.0-20250326213544-b2c61baabfaa
@@ -123,7 +124,6 @@ require (
 	github.com/muesli/cancelreader v0.2.2 // indirect
 	github.com/muesli/termenv v0.15.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/prometheus/client_golang v1.15.0 // indirect
 	github.com/prometheus/client_model v0.3.0 // indirect
 	github.com/prometheus/common v0.42.0 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
@@ -0,0 +1,106 @@
+// Package matoms provides the integer milli-atom amount used for every bet,
+// balance and payout, with exact conversions to atoms and DCR.
+package matoms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+)
+
+// Amount is an amount of DCR in milli-atoms, the unit Bison Relay tips are
+// received in.
+type Amount int64
+
+const (
+	// PerAtom is the number of milli-atoms in an atom.
+	PerAtom Amount = 1000
+
+	// PerDCR is the number of milli-atoms in one DCR.
+	PerDCR Amount = PerAtom * dcrutil.AtomsPerCoin
+
+	// decimals is the number of decimal places of a DCR amount in
+	// milli-atoms, and minDecimals the number always shown.
+	decimals    = 11
+	minDecimals = 8
+)
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// FromAtoms converts an amount in atoms to milli-atoms.
+func FromAtoms(atoms dcrutil.Amount) Amount {
+	return Amount(atoms) * PerAtom
+}
+
+// FromDCR converts an amount in DCR, as found in configs, to the nearest
+// milli-atom.
+func FromDCR(dcr float64) (Amount, error) {
+	if math.IsNaN(dcr) || math.IsInf(dcr, 0) {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, dcr)
+	}
+	return Parse(strconv.FormatFloat(dcr, 'f', decimals, 64))
+}
+
+// Parse parses a decimal amount in DCR with at most 11 decimal places, such
+// as the one returned by String.
+func Parse(s string) (Amount, error) {
+	str := strings.TrimSpace(s)
+	neg := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "+")
+	whole, frac, _ := strings.Cut(str, ".")
+	if whole == "" && frac == "" || len(frac) > decimals {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+
+	var m uint64
+	for _, c := range whole + frac {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+		if m > (math.MaxUint64-9)/10 {
+			return 0, fmt.Errorf("%w: %q out of range", ErrInvalidAmount, s)
+		}
+		m = m*10 + uint64(c-'0')
+	}
+	switch {
+	case neg && m <= 1<<63:
+		return Amount(-m), nil
+	case !neg && m < 1<<63:
+		return Amount(m), nil
+	}
+	return 0, fmt.Errorf("%w: %q out of range", ErrInvalidAmount, s)
+}
+
+// Atoms returns the whole atoms of the amount. Milli-atoms can't be sent, so
+// this is what a payout of the amount transfers.
+func (a Amount) Atoms() dcrutil.Amount {
+	return dcrutil.Amount(a / PerAtom)
+}
+
+// DCR returns the amount in DCR. It is only meant for display: amounts
+// above 90071 DCR can't be represented exactly.
+func (a Amount) DCR() float64 {
+	return float64(a) / float64(PerDCR)
+}
+
+// String formats the amount in DCR with as many decimal places as needed to
+// be exact, and at least 8.
+func (a Amount) String() string {
+	m := uint64(a)
+	sign := ""
+	if a < 0 {
+		m = -m
+		sign = "-"
+	}
+	frac := fmt.Sprintf("%011d", m%uint64(PerDCR))
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) < minDecimals {
+		frac += strings.Repeat("0", minDecimals-len(frac))
+	}
+	return fmt.Sprintf("%s%d.%s", sign, m/uint64(PerDCR), frac)
+}
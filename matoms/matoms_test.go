@@ -0,0 +1,120 @@
+package matoms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{0, "0.00000000"},
+		{PerDCR, "1.00000000"},
+		{PerAtom, "0.00000001"},
+		{1, "0.00000000001"},
+		{12345678901234, "123.45678901234"},
+		{-5 * PerDCR / 2, "-2.50000000"},
+		{math.MaxInt64, "92233720.36854775807"},
+		{math.MinInt64, "-92233720.36854775808"},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.want, tc.amount.String())
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Amount
+	}{
+		{"1", PerDCR},
+		{"0.1", PerDCR / 10},
+		{".5", PerDCR / 2},
+		{"2.", 2 * PerDCR},
+		{"+0.00000001", PerAtom},
+		{" -0.00000000001 ", -1},
+		{"92233720.36854775807", math.MaxInt64},
+		{"-92233720.36854775808", math.MinInt64},
+	}
+	for _, tc := range tests {
+		got, err := Parse(tc.s)
+		require.NoError(t, err, tc.s)
+		require.Equal(t, tc.want, got, tc.s)
+	}
+
+	for _, s := range []string{"", ".", "-", "1.2.3", "abc", "1e8", "0.000000000001",
+		"92233720.36854775808", "-92233720.36854775809", "99999999999999999999"} {
+		_, err := Parse(s)
+		require.True(t, errors.Is(err, ErrInvalidAmount), "%q: %v", s, err)
+	}
+}
+
+func TestFromDCR(t *testing.T) {
+	for dcr, want := range map[float64]Amount{
+		0.1:          PerDCR / 10,
+		0.3:          3 * PerDCR / 10,
+		1.23456789:   123456789 * PerAtom,
+		21e6:         21e6 * PerDCR,
+		-0.00000001:  -PerAtom,
+		1e-12:        0,
+		0.5000000001: PerDCR/2 + 10,
+	} {
+		got, err := FromDCR(dcr)
+		require.NoError(t, err)
+		require.Equal(t, want, got, "%v", dcr)
+	}
+	for _, dcr := range []float64{math.NaN(), math.Inf(1), 1e30} {
+		_, err := FromDCR(dcr)
+		require.Error(t, err)
+	}
+}
+
+// The properties below check that no conversion loses precision.
+
+func TestParseStringRoundTrip(t *testing.T) {
+	f := func(a int64) bool {
+		got, err := Parse(Amount(a).String())
+		return err == nil && got == Amount(a)
+	}
+	require.NoError(t, quick.Check(f, nil))
+}
+
+func TestAtomsRoundTrip(t *testing.T) {
+	f := func(atoms int64) bool {
+		atoms %= int64(math.MaxInt64 / PerAtom)
+		return FromAtoms(dcrutil.Amount(atoms)).Atoms() == dcrutil.Amount(atoms)
+	}
+	require.NoError(t, quick.Check(f, nil))
+
+	// Splitting an amount into atoms keeps the milli-atoms left over.
+	g := func(a int64) bool {
+		amount := Amount(a)
+		return FromAtoms(amount.Atoms())+amount%PerAtom == amount
+	}
+	require.NoError(t, quick.Check(g, nil))
+}
+
+func TestDCRRoundTrip(t *testing.T) {
+	// Amounts up to 10000 DCR survive a trip through float64 DCR.
+	f := func(a int64) bool {
+		amount := Amount(a % int64(10000*PerDCR))
+		got, err := FromDCR(amount.DCR())
+		return err == nil && got == amount
+	}
+	require.NoError(t, quick.Check(f, nil))
+
+	// Whole atoms format like the %.8f DCR amounts shown before.
+	g := func(atoms int32) bool {
+		amount := FromAtoms(dcrutil.Amount(atoms))
+		return amount.String() == fmt.Sprintf("%.8f", amount.DCR())
+	}
+	require.NoError(t, quick.Check(g, nil))
+}
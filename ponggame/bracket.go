@@ -0,0 +1,293 @@
+package ponggame
+
+import (
+	"fmt"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+// Bracket runs single or double elimination between a set of players.
+//
+// Players are grouped by their number of losses: the winners bracket holds
+// players without losses and, in double elimination, the losers bracket holds
+// players with one. Rounds are paired inside each bracket, so a winners
+// bracket player losing the grand final naturally plays a bracket reset.
+type Bracket struct {
+	Format    pong.TournamentFormat
+	MaxLosses int32
+	Entrants  []*BracketEntrant
+	Matches   []*BracketMatch
+	Round     int32
+
+	// eliminated lists players in the order they were knocked out.
+	eliminated []zkidentity.ShortID
+}
+
+// BracketEntrant is a player of a bracket.
+type BracketEntrant struct {
+	ID     zkidentity.ShortID
+	Seed   int32
+	Losses int32
+	Byes   int32
+}
+
+// BracketMatch is a match between two entrants. A bye has a single player
+// who advances without playing.
+type BracketMatch struct {
+	ID      string
+	Round   int32
+	Pool    int32 // losses of the players when the match was paired
+	Players []zkidentity.ShortID
+	Winner  *zkidentity.ShortID
+	Forfeit bool
+}
+
+// Bye returns whether the match is a bye.
+func (m *BracketMatch) Bye() bool {
+	return len(m.Players) == 1
+}
+
+// Done returns whether the match has a winner.
+func (m *BracketMatch) Done() bool {
+	return m.Winner != nil
+}
+
+// NewBracket creates a bracket seeded in the order of the given players and
+// pairs its first round.
+func NewBracket(format pong.TournamentFormat, players []zkidentity.ShortID) (*Bracket, error) {
+	if len(players) < 2 {
+		return nil, fmt.Errorf("bracket needs at least 2 players, got %d", len(players))
+	}
+
+	var maxLosses int32
+	switch format {
+	case pong.TournamentFormat_SINGLE_ELIMINATION:
+		maxLosses = 1
+	case pong.TournamentFormat_DOUBLE_ELIMINATION:
+		maxLosses = 2
+	default:
+		return nil, fmt.Errorf("unknown tournament format %v", format)
+	}
+
+	b := &Bracket{
+		Format:    format,
+		MaxLosses: maxLosses,
+	}
+	seen := make(map[zkidentity.ShortID]struct{}, len(players))
+	for i, id := range players {
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("player %s entered twice", id)
+		}
+		seen[id] = struct{}{}
+		b.Entrants = append(b.Entrants, &BracketEntrant{ID: id, Seed: int32(i + 1)})
+	}
+	b.advance()
+	return b, nil
+}
+
+// Entrant returns the entrant with the given id.
+func (b *Bracket) Entrant(id zkidentity.ShortID) *BracketEntrant {
+	for _, e := range b.Entrants {
+		if e.ID == id {
+			return e
+		}
+	}
+	return nil
+}
+
+// Match returns the match with the given id.
+func (b *Bracket) Match(id string) *BracketMatch {
+	for _, m := range b.Matches {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// PendingMatches returns the matches of the current round still to be played.
+func (b *Bracket) PendingMatches() []*BracketMatch {
+	var pending []*BracketMatch
+	for _, m := range b.Matches {
+		if !m.Done() {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// Champion returns the winner of the bracket once it is over.
+func (b *Bracket) Champion() *zkidentity.ShortID {
+	alive := b.alive()
+	if len(alive) != 1 {
+		return nil
+	}
+	return &alive[0].ID
+}
+
+// Done returns whether the bracket has a champion.
+func (b *Bracket) Done() bool {
+	return b.Champion() != nil
+}
+
+// Placements returns the final ranking of the players, champion first. Players
+// knocked out later rank higher.
+func (b *Bracket) Placements() []zkidentity.ShortID {
+	champion := b.Champion()
+	if champion == nil {
+		return nil
+	}
+	placements := []zkidentity.ShortID{*champion}
+	for i := len(b.eliminated) - 1; i >= 0; i-- {
+		placements = append(placements, b.eliminated[i])
+	}
+	return placements
+}
+
+// ReportResult records the winner of a match and pairs the next round once
+// the current one is over.
+func (b *Bracket) ReportResult(matchID string, winner zkidentity.ShortID, forfeit bool) error {
+	m := b.Match(matchID)
+	if m == nil {
+		return fmt.Errorf("match %s not found", matchID)
+	}
+	if m.Done() {
+		return fmt.Errorf("match %s already decided", matchID)
+	}
+	found := false
+	for _, id := range m.Players {
+		if id == winner {
+			found = true
+			continue
+		}
+		loser := b.Entrant(id)
+		loser.Losses++
+		if loser.Losses >= b.MaxLosses {
+			b.eliminated = append(b.eliminated, id)
+		}
+	}
+	if !found {
+		return fmt.Errorf("player %s is not in match %s", winner, matchID)
+	}
+	m.Winner = &winner
+	m.Forfeit = forfeit
+
+	if len(b.PendingMatches()) == 0 {
+		b.advance()
+	}
+	return nil
+}
+
+// alive returns the players not yet knocked out, in seed order.
+func (b *Bracket) alive() []*BracketEntrant {
+	var alive []*BracketEntrant
+	for _, e := range b.Entrants {
+		if e.Losses < b.MaxLosses {
+			alive = append(alive, e)
+		}
+	}
+	return alive
+}
+
+// advance pairs the next round. Each bracket is paired on its own and an odd
+// player out gets a bye. When every bracket is down to a single player, they
+// meet in the final.
+func (b *Bracket) advance() {
+	alive := b.alive()
+	if len(alive) < 2 {
+		return
+	}
+
+	pools := make([][]*BracketEntrant, b.MaxLosses)
+	for _, e := range alive {
+		pools[e.Losses] = append(pools[e.Losses], e)
+	}
+
+	var pairings [][]*BracketEntrant
+	var byes []*BracketEntrant
+	for _, pool := range pools {
+		if len(pool) < 2 {
+			byes = append(byes, pool...)
+			continue
+		}
+		if len(pool)%2 == 1 {
+			bye := pickBye(pool)
+			byes = append(byes, bye)
+			pool = removeEntrant(pool, bye)
+		}
+		// Top seeds meet the bottom ones.
+		for i := 0; i < len(pool)/2; i++ {
+			pairings = append(pairings, []*BracketEntrant{pool[i], pool[len(pool)-1-i]})
+		}
+	}
+	if len(pairings) == 0 {
+		// Only lone players are left in each bracket: the final.
+		pairings = append(pairings, byes)
+		byes = nil
+	}
+
+	b.Round++
+	for i, pair := range pairings {
+		b.Matches = append(b.Matches, &BracketMatch{
+			ID:      fmt.Sprintf("R%d-%d", b.Round, i+1),
+			Round:   b.Round,
+			Pool:    pair[0].Losses,
+			Players: []zkidentity.ShortID{pair[0].ID, pair[1].ID},
+		})
+	}
+	for i, e := range byes {
+		e.Byes++
+		winner := e.ID
+		b.Matches = append(b.Matches, &BracketMatch{
+			ID:      fmt.Sprintf("R%d-B%d", b.Round, i+1),
+			Round:   b.Round,
+			Pool:    e.Losses,
+			Players: []zkidentity.ShortID{e.ID},
+			Winner:  &winner,
+		})
+	}
+}
+
+// pickBye returns the lowest seeded player with the fewest byes so far.
+func pickBye(pool []*BracketEntrant) *BracketEntrant {
+	bye := pool[len(pool)-1]
+	for i := len(pool) - 1; i >= 0; i-- {
+		if pool[i].Byes < bye.Byes {
+			bye = pool[i]
+		}
+	}
+	return bye
+}
+
+func removeEntrant(pool []*BracketEntrant, e *BracketEntrant) []*BracketEntrant {
+	res := make([]*BracketEntrant, 0, len(pool)-1)
+	for _, p := range pool {
+		if p != e {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// Marshal converts the bracket matches to their proto representation.
+func (b *Bracket) Marshal() []*pong.TournamentMatch {
+	matches := make([]*pong.TournamentMatch, 0, len(b.Matches))
+	for _, m := range b.Matches {
+		pm := &pong.TournamentMatch{
+			Id:      m.ID,
+			Round:   m.Round,
+			Pool:    m.Pool,
+			Bye:     m.Bye(),
+			Forfeit: m.Forfeit,
+		}
+		for _, id := range m.Players {
+			pm.PlayerIds = append(pm.PlayerIds, id.String())
+		}
+		if m.Winner != nil {
+			pm.WinnerId = m.Winner.String()
+		}
+		matches = append(matches, pm)
+	}
+	return matches
+}
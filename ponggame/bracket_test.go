@@ -0,0 +1,115 @@
+package ponggame
+
+import (
+	"testing"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func bracketPlayers(n int) []zkidentity.ShortID {
+	ids := make([]zkidentity.ShortID, n)
+	for i := range ids {
+		ids[i] = zkidentity.ShortID{byte(i + 1)}
+	}
+	return ids
+}
+
+// playBracket plays every pending match with the given winner until the
+// bracket is over. It returns the number of games played.
+func playBracket(t *testing.T, b *Bracket, winner func(*BracketMatch) zkidentity.ShortID) int {
+	t.Helper()
+	games := 0
+	for !b.Done() {
+		pending := b.PendingMatches()
+		require.NotEmpty(t, pending, "bracket stalled")
+		for _, m := range pending {
+			require.Len(t, m.Players, 2)
+			require.NoError(t, b.ReportResult(m.ID, winner(m), false))
+			games++
+		}
+	}
+	return games
+}
+
+func topSeed(b *Bracket) func(*BracketMatch) zkidentity.ShortID {
+	return func(m *BracketMatch) zkidentity.ShortID {
+		if b.Entrant(m.Players[0]).Seed < b.Entrant(m.Players[1]).Seed {
+			return m.Players[0]
+		}
+		return m.Players[1]
+	}
+}
+
+func TestBracket_SingleElimination(t *testing.T) {
+	for _, n := range []int{2, 3, 5, 8} {
+		players := bracketPlayers(n)
+		b, err := NewBracket(pong.TournamentFormat_SINGLE_ELIMINATION, players)
+		require.NoError(t, err)
+
+		games := playBracket(t, b, topSeed(b))
+		assert.Equal(t, n-1, games, "players %d", n)
+		assert.Equal(t, players[0], *b.Champion())
+
+		placements := b.Placements()
+		require.Len(t, placements, n)
+		assert.Equal(t, players[0], placements[0])
+	}
+}
+
+func TestBracket_DoubleElimination(t *testing.T) {
+	players := bracketPlayers(8)
+	b, err := NewBracket(pong.TournamentFormat_DOUBLE_ELIMINATION, players)
+	require.NoError(t, err)
+
+	games := playBracket(t, b, topSeed(b))
+	// Without a bracket reset a double elimination takes 2n-2 games.
+	assert.Equal(t, 14, games)
+	assert.Equal(t, players[0], *b.Champion())
+	assert.Len(t, b.Placements(), 8)
+	for _, e := range b.Entrants {
+		if e.ID != players[0] {
+			assert.Equal(t, int32(2), e.Losses)
+		}
+	}
+}
+
+func TestBracket_DoubleEliminationReset(t *testing.T) {
+	players := bracketPlayers(2)
+	b, err := NewBracket(pong.TournamentFormat_DOUBLE_ELIMINATION, players)
+	require.NoError(t, err)
+
+	// The second seed loses the first match, then wins the final and the
+	// reset.
+	m := b.PendingMatches()[0]
+	require.NoError(t, b.ReportResult(m.ID, players[0], false))
+	m = b.PendingMatches()[0]
+	require.NoError(t, b.ReportResult(m.ID, players[1], false))
+	require.False(t, b.Done())
+	m = b.PendingMatches()[0]
+	require.NoError(t, b.ReportResult(m.ID, players[1], true))
+
+	require.True(t, b.Done())
+	assert.Equal(t, players[1], *b.Champion())
+	assert.Equal(t, []zkidentity.ShortID{players[1], players[0]}, b.Placements())
+	assert.True(t, b.Match(m.ID).Forfeit)
+}
+
+func TestBracket_Errors(t *testing.T) {
+	_, err := NewBracket(pong.TournamentFormat_SINGLE_ELIMINATION, bracketPlayers(1))
+	require.Error(t, err)
+
+	players := bracketPlayers(2)
+	_, err = NewBracket(pong.TournamentFormat_SINGLE_ELIMINATION, []zkidentity.ShortID{players[0], players[0]})
+	require.Error(t, err)
+
+	b, err := NewBracket(pong.TournamentFormat_SINGLE_ELIMINATION, players)
+	require.NoError(t, err)
+	m := b.PendingMatches()[0]
+	require.Error(t, b.ReportResult(m.ID, zkidentity.ShortID{9}, false))
+	require.Error(t, b.ReportResult("nope", players[0], false))
+	require.NoError(t, b.ReportResult(m.ID, players[0], false))
+	require.Error(t, b.ReportResult(m.ID, players[0], false))
+}
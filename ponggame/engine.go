@@ -3,6 +3,7 @@ package ponggame
 import (
 	"context"
 	"errors"
+	"math/rand/v2"
 	"sync"
 	"time"
 
@@ -28,10 +29,19 @@ func New(g engine.Game) *CanvasEngine {
 	e.FPS = DEFAULT_FPS
 	e.TPS = 1000.0 / e.FPS
 	e.VelocityIncrease = DEFAULT_VEL_INCR
+	e.rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
 
 	return e
 }
 
+// SetSeed seeds the RNG the serves are drawn from. See CombineSeeds.
+func (e *CanvasEngine) SetSeed(seed [32]byte) *CanvasEngine {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rng = rand.New(rand.NewChaCha8(seed))
+	return e
+}
+
 // SetDebug sets the Canvas engines debug state
 func (e *CanvasEngine) SetLogger(log slog.Logger) *CanvasEngine {
 	e.log = log
@@ -49,6 +59,14 @@ func (e *CanvasEngine) SetFPS(fps uint) *CanvasEngine {
 	return e
 }
 
+// wait blocks until the round loop exited. The context the rounds were
+// started with must be done, so no round starts afterwards.
+func (e *CanvasEngine) wait() {
+	e.roundsMtx.Lock()
+	e.roundsMtx.Unlock()
+	e.rounds.Wait()
+}
+
 // Error returns the Canvas engines error
 func (e *CanvasEngine) Error() error {
 	return e.Err
@@ -58,10 +76,17 @@ func (e *CanvasEngine) Error() error {
 // a frames channel to write into and input channel to read from
 func (e *CanvasEngine) NewRound(ctx context.Context, framesch chan<- []byte, inputch <-chan []byte, roundResult chan<- int32) {
 	time.Sleep(time.Second)
+	e.roundsMtx.Lock()
+	defer e.roundsMtx.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
 	e.reset()
 
 	// Calculates and writes frames
+	e.rounds.Add(1)
 	go func() {
+		defer e.rounds.Done()
 		frameTimer := time.NewTicker(time.Duration(1000.0/e.FPS) * time.Millisecond)
 		defer frameTimer.Stop()
 
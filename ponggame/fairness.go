@@ -0,0 +1,78 @@
+package ponggame
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+// MaxEntropySize is the most entropy a player may contribute to the serve
+// seed of a game.
+const MaxEntropySize = 64
+
+// ErrUnfair is returned when the revealed serve randomness of a game doesn't
+// match what was committed to.
+var ErrUnfair = errors.New("serve randomness could not be verified")
+
+// serveSeed is the commit-reveal state of the serve randomness of a game. The
+// server seed is committed to when the game is created and only revealed once
+// it ends, so neither the server nor a player can pick the serves alone.
+type serveSeed struct {
+	server  [32]byte
+	entropy map[string][]byte // by client ID
+}
+
+func newServeSeed() (*serveSeed, error) {
+	s := &serveSeed{entropy: make(map[string][]byte)}
+	if _, err := rand.Read(s.server[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate serve seed: %w", err)
+	}
+	return s, nil
+}
+
+func (s *serveSeed) commitment() []byte {
+	hash := sha256.Sum256(s.server[:])
+	return hash[:]
+}
+
+// CombineSeeds derives the seed of the engine RNG from the server seed and the
+// entropy of the players in player number order. Every part is hashed on its
+// own so bytes can't be moved from one part into another.
+//
+// The serve of every round is drawn with IntN(2) from a ChaCha8 generator
+// seeded with the result: 0 serves towards player 1, 1 towards player 2.
+func CombineSeeds(serverSeed []byte, entropy ...[]byte) [32]byte {
+	h := sha256.New()
+	for _, part := range append([][]byte{serverSeed}, entropy...) {
+		hash := sha256.Sum256(part)
+		h.Write(hash[:])
+	}
+	var seed [32]byte
+	copy(seed[:], h.Sum(nil))
+	return seed
+}
+
+// VerifyFairness checks the fairness revealed at the end of a game against
+// the commitment received when it was created, and that the entropy sent by
+// the player was used. It returns the seed the serves were drawn from.
+func VerifyFairness(commitment, entropy []byte, f *pong.Fairness) ([32]byte, error) {
+	if f == nil || len(f.ServerSeed) == 0 {
+		return [32]byte{}, fmt.Errorf("%w: server seed not revealed", ErrUnfair)
+	}
+	hash := sha256.Sum256(f.ServerSeed)
+	if !bytes.Equal(hash[:], commitment) {
+		return [32]byte{}, fmt.Errorf("%w: server seed doesn't match its commitment", ErrUnfair)
+	}
+	used := len(entropy) == 0
+	for _, e := range f.PlayerEntropy {
+		used = used || bytes.Equal(e, entropy)
+	}
+	if !used {
+		return [32]byte{}, fmt.Errorf("%w: player entropy was not used", ErrUnfair)
+	}
+	return CombineSeeds(f.ServerSeed, f.PlayerEntropy...), nil
+}
@@ -0,0 +1,80 @@
+package ponggame
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/ndabAP/ping-pong/engine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func TestFairness(t *testing.T) {
+	gm := createTestGameManager()
+	players := createTestPlayers()
+	game, err := gm.StartGameWithRules(context.Background(), players, DefaultGameRules(), 100)
+	require.NoError(t, err)
+	defer game.cancel()
+
+	// Only the commitment is known before the game ends.
+	committed := game.Fairness(false)
+	require.Len(t, committed.ServerSeedHash, sha256.Size)
+	assert.Empty(t, committed.ServerSeed)
+
+	// Wagered games need entropy, and only the first signal counts.
+	p1, p2 := players[0].ID.String(), players[1].ID.String()
+	require.Error(t, game.SetPlayerReady(p1, nil))
+	require.Error(t, game.SetPlayerReady(p1, make([]byte, MaxEntropySize+1)))
+	require.NoError(t, game.SetPlayerReady(p2, []byte("p2")))
+	require.NoError(t, game.SetPlayerReady(p1, []byte("p1")))
+	require.NoError(t, game.SetPlayerReady(p1, []byte("again")))
+
+	revealed := game.Fairness(true)
+	assert.Equal(t, [][]byte{[]byte("p1"), []byte("p2")}, revealed.PlayerEntropy)
+	seed, err := VerifyFairness(committed.ServerSeedHash, []byte("p2"), revealed)
+	require.NoError(t, err)
+	assert.Equal(t, game.combinedSeed(), seed)
+
+	// Tampered reveals are caught.
+	_, err = VerifyFairness(committed.ServerSeedHash, []byte("again"), revealed)
+	assert.True(t, errors.Is(err, ErrUnfair))
+	forged := &pong.Fairness{ServerSeed: make([]byte, 32), PlayerEntropy: revealed.PlayerEntropy}
+	_, err = VerifyFairness(committed.ServerSeedHash, []byte("p2"), forged)
+	assert.True(t, errors.Is(err, ErrUnfair))
+	_, err = VerifyFairness(committed.ServerSeedHash, nil, committed)
+	assert.True(t, errors.Is(err, ErrUnfair))
+
+	// Unwagered games don't need entropy, whatever the players' balances.
+	unwagered, err := gm.StartGame(context.Background(), players)
+	require.NoError(t, err)
+	defer unwagered.cancel()
+	require.NoError(t, unwagered.SetPlayerReady(p1, nil))
+}
+
+func TestCombineSeeds(t *testing.T) {
+	seed := CombineSeeds([]byte("server"), []byte("a"), []byte("b"))
+	assert.Equal(t, seed, CombineSeeds([]byte("server"), []byte("a"), []byte("b")))
+	assert.NotEqual(t, seed, CombineSeeds([]byte("server"), []byte("b"), []byte("a")))
+	assert.NotEqual(t, seed, CombineSeeds([]byte("server"), []byte("ab"), nil))
+}
+
+func TestSeededServes(t *testing.T) {
+	serves := func(seed [32]byte) []float64 {
+		e := New(engine.NewGame(800, 600, engine.NewPlayer(10, 75),
+			engine.NewPlayer(10, 75), engine.NewBall(15, 15)))
+		e.SetSeed(seed)
+		var dirs []float64
+		for i := 0; i < 32; i++ {
+			e.resetBall()
+			dirs = append(dirs, e.BallVel.X)
+		}
+		return dirs
+	}
+
+	seed := CombineSeeds([]byte("server"), []byte("a"), []byte("b"))
+	assert.Equal(t, serves(seed), serves(seed))
+	assert.NotEqual(t, serves(seed), serves(CombineSeeds([]byte("server"))))
+}
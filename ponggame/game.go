@@ -1,7 +1,10 @@
 package ponggame
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"time"
 
@@ -9,6 +12,7 @@ import (
 	"github.com/decred/slog"
 	"github.com/ndabAP/ping-pong/engine"
 	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 	"google.golang.org/protobuf/proto"
 )
@@ -103,7 +107,11 @@ func (gm *GameManager) HandlePlayerInput(clientID zkidentity.ShortID, req *pong.
 		return nil, fmt.Errorf("failed to serialize input: %w", err)
 	}
 
-	if !game.Running {
+	// Inputs are sent under the game lock, so Cleanup doesn't close the
+	// channel under them.
+	game.RLock()
+	defer game.RUnlock()
+	if !game.Running || game.cleanedUp {
 		return nil, fmt.Errorf("game has ended for client ID %s", clientID)
 	}
 
@@ -188,30 +196,42 @@ func (gm *GameManager) GetPlayerGame(clientID zkidentity.ShortID) *GameInstance
 }
 
 func (s *GameManager) StartGame(ctx context.Context, players []*Player) (*GameInstance, error) {
+	return s.StartGameWithRules(ctx, players, DefaultGameRules(), 0)
+}
+
+// StartGameWithRules starts a game between the players using the given rules.
+// The stake is the amount each player wagered on it, zero for unwagered games.
+func (s *GameManager) StartGameWithRules(ctx context.Context, players []*Player, rules GameRules, stake matoms.Amount) (*GameInstance, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
 	s.Lock()
 	defer s.Unlock()
 	gameID, err := utils.GenerateRandomString(16)
 	if err != nil {
 		return nil, err
 	}
+	seed, err := newServeSeed()
+	if err != nil {
+		return nil, err
+	}
 
-	newGameInstance := s.startNewGame(ctx, players, gameID)
+	newGameInstance := s.startNewGame(ctx, players, gameID, seed, stake)
+	newGameInstance.Rules = rules
 	s.Games[gameID] = newGameInstance
 
 	return newGameInstance, nil
 }
 
-func (gm *GameManager) startNewGame(ctx context.Context, players []*Player, id string) *GameInstance {
+func (gm *GameManager) startNewGame(ctx context.Context, players []*Player, id string, seed *serveSeed, stake matoms.Amount) *GameInstance {
 	framesch := make(chan []byte, INPUT_BUF_SIZE)
 	inputch := make(chan []byte, INPUT_BUF_SIZE)
 	roundResult := make(chan int32)
 	ctx, cancel := context.WithCancel(ctx)
 
-	// sum of all bets
-	betAmt := int64(0)
 	for _, player := range players {
 		player.Score = 0
-		betAmt += player.BetAmt
 		// Create individual frame buffer for each player with frame dropping capability
 		player.FrameCh = make(chan []byte, INPUT_BUF_SIZE/4) // Smaller buffer per player
 	}
@@ -225,9 +245,13 @@ func (gm *GameManager) startNewGame(ctx context.Context, players []*Player, id s
 		ctx:         ctx,
 		cancel:      cancel,
 		Players:     players,
-		betAmt:      betAmt,
+		stake:       stake,
+		seed:        seed,
+		StartedAt:   time.Now(),
 		log:         gm.Log,
 
+		onFrameDropped: gm.OnFrameDropped,
+
 		// Initialize the ready to play fields
 		PlayersReady:     make(map[string]bool),
 		CountdownStarted: false,
@@ -242,6 +266,7 @@ func (gm *GameManager) startNewGame(ctx context.Context, players []*Player, id s
 	newGame.engine = NewEngine(swidth, sheight, players, gm.Log)
 
 	// Start frame distributor goroutine to distribute frames to individual player channels
+	newGame.loops.Add(1)
 	go newGame.distributeFrames()
 
 	// Update PlayerSessions with the correct player numbers after NewEngine assigns them
@@ -300,6 +325,7 @@ func (gm *GameManager) startNewGame(ctx context.Context, players []*Player, id s
 				Started:          true,
 				GameId:           id,
 				PlayerNumber:     player.PlayerNumber,
+				Fairness:         newGame.Fairness(false),
 			})
 		}
 	}
@@ -308,7 +334,9 @@ func (gm *GameManager) startNewGame(ctx context.Context, players []*Player, id s
 }
 
 func (g *GameInstance) Run() {
+	g.Lock()
 	g.Running = true
+	g.Unlock()
 
 	// Wait for players to be ready before starting the actual game
 	go func() {
@@ -329,6 +357,9 @@ func (g *GameInstance) Run() {
 				// If all players are ready and countdown hasn't started yet, start countdown
 				if allPlayersReady && !g.CountdownStarted && !g.GameReady {
 					g.CountdownStarted = true
+					if g.seed != nil {
+						g.engine.SetSeed(g.combinedSeed())
+					}
 					g.Unlock()
 
 					// Start the countdown
@@ -358,8 +389,12 @@ func (g *GameInstance) Run() {
 
 							// Check if the game should continue or end
 							if g.shouldEndGame() {
-								// clean up the game after ending
-								g.Cleanup()
+								// Whoever runs the game cleans it up once
+								// its loops exited.
+								g.Lock()
+								g.Running = false
+								g.Unlock()
+								g.cancel()
 								break
 							} else {
 								g.engine.NewRound(g.ctx, g.Framesch, g.Inputch, g.roundResult)
@@ -374,6 +409,75 @@ func (g *GameInstance) Run() {
 	}()
 }
 
+// SetPlayerReady marks a player as ready to play, contributing its entropy
+// to the serve seed. Wagered games require entropy. Only the entropy of the
+// first ready signal is used.
+func (g *GameInstance) SetPlayerReady(clientID string, entropy []byte) error {
+	if len(entropy) > MaxEntropySize {
+		return fmt.Errorf("entropy is longer than %d bytes", MaxEntropySize)
+	}
+	if g.stake > 0 && len(entropy) == 0 {
+		return fmt.Errorf("entropy is required for wagered games")
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	if g.PlayersReady[clientID] {
+		return nil
+	}
+	g.PlayersReady[clientID] = true
+	if g.seed != nil {
+		g.seed.entropy[clientID] = bytes.Clone(entropy)
+	}
+	return nil
+}
+
+// playerEntropy returns the entropy of the players in player number order.
+func (g *GameInstance) playerEntropy() [][]byte {
+	entropy := make([][]byte, 0, len(g.Players))
+	for _, player := range g.Players {
+		entropy = append(entropy, g.seed.entropy[player.ID.String()])
+	}
+	return entropy
+}
+
+func (g *GameInstance) combinedSeed() [32]byte {
+	return CombineSeeds(g.seed.server[:], g.playerEntropy()...)
+}
+
+// Fairness returns the commitment to the serve seed of the game, along with
+// the seed and player entropy if reveal is set. The seed must only be
+// revealed once the game is over.
+func (g *GameInstance) Fairness(reveal bool) *pong.Fairness {
+	if g.seed == nil {
+		return nil
+	}
+	f := &pong.Fairness{ServerSeedHash: g.seed.commitment()}
+	if reveal {
+		g.RLock()
+		f.ServerSeed = bytes.Clone(g.seed.server[:])
+		f.PlayerEntropy = g.playerEntropy()
+		g.RUnlock()
+	}
+	return f
+}
+
+// ReplayHash commits to the serve seed of the game and the player number
+// winning each round, in order.
+func (g *GameInstance) ReplayHash() []byte {
+	g.RLock()
+	defer g.RUnlock()
+	h := sha256.New()
+	if g.seed != nil {
+		seed := g.combinedSeed()
+		h.Write(seed[:])
+	}
+	for _, winner := range g.rounds {
+		binary.Write(h, binary.BigEndian, winner)
+	}
+	return h.Sum(nil)
+}
+
 // startCountdown initiates and manages the countdown before the game starts
 func (g *GameInstance) startCountdown() {
 	countdownTicker := time.NewTicker(1 * time.Second)
@@ -455,6 +559,10 @@ func (g *GameInstance) startCountdown() {
 }
 
 func (g *GameInstance) handleRoundResult(winner int32) {
+	g.Lock()
+	g.rounds = append(g.rounds, winner)
+	g.Unlock()
+
 	// update player score
 	for _, player := range g.Players {
 		if player.PlayerNumber == winner {
@@ -463,9 +571,45 @@ func (g *GameInstance) handleRoundResult(winner int32) {
 	}
 }
 
+// Stop ends a game that is still running with the given winner, which may be
+// nil to end it without one. It only cancels the game: whoever runs it
+// cleans it up once its players stopped receiving frames.
+func (g *GameInstance) Stop(winner *zkidentity.ShortID) error {
+	g.Lock()
+	defer g.Unlock()
+	if g.cleanedUp || !g.Running {
+		return fmt.Errorf("game %s already ended", g.Id)
+	}
+	g.Winner = winner
+	g.Running = false
+	g.Stopped = true
+	g.cancel()
+	return nil
+}
+
+// Done returns a channel closed once the game ended.
+func (g *GameInstance) Done() <-chan struct{} {
+	return g.ctx.Done()
+}
+
+// Cleanup ends the game and closes its channels once the engine and the
+// frame distributor exited.
 func (g *GameInstance) Cleanup() {
+	// Games can be cleaned up by whoever runs them and by a shutdown at
+	// once.
+	g.Lock()
+	if g.cleanedUp {
+		g.Unlock()
+		return
+	}
 	g.cleanedUp = true
+	g.Unlock()
+
 	g.cancel()
+	if g.engine != nil {
+		g.engine.wait()
+	}
+	g.loops.Wait()
 	close(g.Framesch)
 	close(g.Inputch)
 	close(g.roundResult)
@@ -482,7 +626,7 @@ func (g *GameInstance) Cleanup() {
 func (g *GameInstance) shouldEndGame() bool {
 	for _, player := range g.Players {
 		// Check if any player has reached the max score
-		if player.Score >= maxScore {
+		if int32(player.Score) >= g.maxScore() {
 			g.log.Infof("Game ending: Player %s reached the maximum score of %d", player.ID, player.Score)
 			g.Winner = player.ID
 			g.Running = false
@@ -500,6 +644,15 @@ func (g *GameInstance) shouldEndGame() bool {
 	return false
 }
 
+// maxScore returns the score that wins the game, falling back to the default
+// for games created without rules.
+func (g *GameInstance) maxScore() int32 {
+	if g.Rules.MaxScore <= 0 {
+		return maxScore
+	}
+	return g.Rules.MaxScore
+}
+
 // isTimeout checks if the game duration has exceeded a set limit
 func (g *GameInstance) isTimeout() bool {
 	// For example, a simple time limit check
@@ -532,6 +685,7 @@ func NewEngine(width, height float64, players []*Player, log slog.Logger) *Canva
 // distributeFrames distributes frames from the main channel to individual player channels
 // This prevents one slow client from affecting others by implementing frame dropping
 func (g *GameInstance) distributeFrames() {
+	defer g.loops.Done()
 	for {
 		select {
 		case <-g.ctx.Done():
@@ -555,6 +709,9 @@ func (g *GameInstance) distributeFrames() {
 						// Frame sent successfully
 					default:
 						// Player's buffer is full, drop oldest frame and try again
+						if g.onFrameDropped != nil {
+							g.onFrameDropped()
+						}
 						select {
 						case <-player.FrameCh:
 							// Dropped oldest frame
@@ -57,7 +57,7 @@ func TestGameManager_StartGame(t *testing.T) {
 	assert.NotNil(t, game)
 	assert.True(t, game.Running)
 	assert.Equal(t, len(players), len(game.Players))
-	assert.Equal(t, int64(250), game.betAmt) // 100 + 150
+	assert.Zero(t, game.stake)
 
 	// Verify game is tracked in manager
 	assert.Equal(t, 1, len(gm.Games))
@@ -2,13 +2,16 @@ package ponggame
 
 import (
 	"context"
+	"math/rand/v2"
 	"sync"
+	"time"
 
 	"github.com/companyzero/bisonrelay/client/clientintf"
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/decred/slog"
 	"github.com/ndabAP/ping-pong/engine"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 )
 
@@ -36,7 +39,7 @@ type Player struct {
 	ID *zkidentity.ShortID
 
 	Nick           string
-	BetAmt         int64
+	BetAmt         matoms.Amount
 	PlayerNumber   int32 // 1 for player 1, 2 for player 2
 	Score          int
 	GameStream     pong.PongGame_StartGameStreamServer
@@ -74,10 +77,30 @@ type GameInstance struct {
 	Running     bool
 	ctx         context.Context
 	cancel      context.CancelFunc
-	Winner      *zkidentity.ShortID
+	// loops tracks the frame distributor, which must exit before the
+	// channels of the game are closed.
+	loops  sync.WaitGroup
+	Winner *zkidentity.ShortID
 
-	// betAmt sum of total bets
-	betAmt int64
+	// Stopped is set when the game was ended by Stop instead of being
+	// played out.
+	Stopped bool
+
+	onFrameDropped func()
+
+	// stake is the amount each player wagered on the game.
+	stake matoms.Amount
+
+	// Rules the game is played with.
+	Rules GameRules
+
+	// seed is the committed serve randomness of the game.
+	seed *serveSeed
+
+	// rounds holds the player number winning each round.
+	rounds []int32
+
+	StartedAt time.Time
 
 	// Ready to play state
 	PlayersReady     map[string]bool
@@ -95,8 +118,31 @@ type WaitingRoom struct {
 	ID           string
 	HostID       *clientintf.UserID
 	Players      []*Player
-	BetAmount    int64
+	BetAmount    matoms.Amount
 	ReservedTips []*types.ReceivedTip
+	Rules        GameRules
+
+	// Rake is what the house keeps from the pot if the game has a winner.
+	Rake matoms.Amount
+
+	// Private rooms are hidden from listings and can only be joined by
+	// invited players or with the invite code.
+	Private    bool
+	InviteCode string
+	Invited    map[zkidentity.ShortID]struct{}
+
+	CreatedAt time.Time
+
+	// LastActivity is when players last joined, left or changed their
+	// ready status. ReadyDeadline is set once the room is full and is when
+	// its players have to be ready.
+	LastActivity  time.Time
+	ReadyDeadline time.Time
+
+	// Locked rooms can't be joined. Banned players were kicked by the host
+	// and can't join again.
+	Locked bool
+	Banned map[zkidentity.ShortID]struct{}
 }
 
 type GameManager struct {
@@ -112,6 +158,10 @@ type GameManager struct {
 
 	// Callback for waiting room removal notifications
 	OnWaitingRoomRemoved func(*pong.WaitingRoom)
+
+	// OnFrameDropped is called when a frame of a game is dropped because
+	// a player's frame buffer is full.
+	OnFrameDropped func()
 }
 
 // CanvasEngine is a ping-pong engine for browsers with Canvas support
@@ -133,6 +183,10 @@ type CanvasEngine struct {
 	VelocityMultiplier float64
 	VelocityIncrease   float64
 
+	// rng draws the serves. It is seeded with the combined serve seed once
+	// all players are ready.
+	rng *rand.Rand
+
 	// Error of the current tick
 	Err error
 
@@ -140,6 +194,11 @@ type CanvasEngine struct {
 	log slog.Logger
 
 	mu sync.RWMutex
+
+	// rounds tracks the running round loop, which is only started under
+	// roundsMtx while the context of the game isn't done.
+	roundsMtx sync.Mutex
+	rounds    sync.WaitGroup
 }
 
 // StartGameStreamRequest encapsulates the data needed to start a game stream.
@@ -2,7 +2,6 @@ package ponggame
 
 import (
 	"math"
-	"math/rand"
 
 	"github.com/ndabAP/ping-pong/engine"
 )
@@ -242,12 +241,11 @@ func (e *CanvasEngine) resetBall() *CanvasEngine {
 	// Reset velocity multiplier to 1.0 at the start of each round
 	e.VelocityMultiplier = 1.0
 
-	// Random direction
+	// Random direction, drawn from the seeded engine RNG
 	xVel := initial_ball_x_vel * e.Game.Width
-	yVel := initial_ball_y_vel*e.Game.Height +
-		rand.Float64()*((initial_ball_y_vel*e.Game.Height)-(initial_ball_y_vel*e.Game.Height))
+	yVel := initial_ball_y_vel * e.Game.Height
 
-	if rand.Intn(10) < 5 {
+	if e.rng.IntN(2) == 0 {
 		e.BallVel = Vec2{-xVel, -yVel}
 	} else {
 		e.BallVel = Vec2{xVel, yVel}
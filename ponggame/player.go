@@ -2,9 +2,11 @@ package ponggame
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 )
 
@@ -20,7 +22,7 @@ func (p *Player) Marshal() (*pong.Player, error) {
 	return &pong.Player{
 		Uid:    p.ID.String(),
 		Nick:   p.Nick,
-		BetAmt: p.BetAmt,
+		BetAmt: int64(p.BetAmt),
 		Number: p.PlayerNumber,
 		Score:  int32(p.Score),
 		Ready:  p.Ready,
@@ -38,7 +40,7 @@ func (p *Player) Unmarshal(proto *pong.Player) error {
 
 	p.ID = &id
 	p.Nick = proto.GetNick()
-	p.BetAmt = proto.GetBetAmt()
+	p.BetAmt = matoms.Amount(proto.GetBetAmt())
 	p.PlayerNumber = proto.GetNumber()
 	p.Score = int(proto.GetScore())
 	p.Ready = proto.GetReady()
@@ -64,6 +66,20 @@ func (ps *PlayerSessions) GetPlayer(clientID zkidentity.ShortID) *Player {
 	return player
 }
 
+// GetPlayersByNick returns the connected players with the given nick,
+// ignoring case. Nicks are chosen by the players, so several may share one.
+func (ps *PlayerSessions) GetPlayersByNick(nick string) []*Player {
+	ps.RLock()
+	defer ps.RUnlock()
+	var players []*Player
+	for _, player := range ps.Sessions {
+		if player.Nick != "" && strings.EqualFold(player.Nick, nick) {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
 func (ps *PlayerSessions) CreateSession(clientID zkidentity.ShortID) *Player {
 	ps.Lock()
 	defer ps.Unlock()
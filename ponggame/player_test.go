@@ -6,6 +6,7 @@ import (
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 )
 
@@ -107,7 +108,7 @@ func TestPlayer_Unmarshal(t *testing.T) {
 		proto     *pong.Player
 		wantErr   bool
 		wantNick  string
-		wantBet   int64
+		wantBet   matoms.Amount
 		wantNum   int32
 		wantScore int
 		wantReady bool
@@ -179,7 +180,7 @@ func TestPlayer_ResetPlayer(t *testing.T) {
 	// Verify all fields are reset except ID and Nick
 	assert.Equal(t, &shortID, player.ID)       // ID should remain
 	assert.Equal(t, "TestPlayer", player.Nick) // Nick should remain
-	assert.Equal(t, int64(0), player.BetAmt)
+	assert.Equal(t, matoms.Amount(0), player.BetAmt)
 	assert.Equal(t, int32(0), player.PlayerNumber)
 	assert.Equal(t, 0, player.Score)
 	assert.False(t, player.Ready)
@@ -0,0 +1,66 @@
+package ponggame
+
+import (
+	"fmt"
+
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+const (
+	// maxAllowedScore bounds the score players may ask a game to be played to.
+	maxAllowedScore = 21
+
+	// maxBestOf bounds the number of games of a series.
+	maxBestOf = 9
+)
+
+// GameRules are the per-game settings agreed on by the players.
+type GameRules struct {
+	// MaxScore is the number of points needed to win a game.
+	MaxScore int32
+
+	// BestOf is the number of games of the series the stake is played
+	// for. A single game is a best-of-1.
+	BestOf int32
+}
+
+// DefaultGameRules returns the rules used when players did not ask for
+// anything else.
+func DefaultGameRules() GameRules {
+	return GameRules{
+		MaxScore: maxScore,
+		BestOf:   1,
+	}
+}
+
+// Validate returns an error if the rules can't be played.
+func (r GameRules) Validate() error {
+	if r.MaxScore < 1 || r.MaxScore > maxAllowedScore {
+		return fmt.Errorf("max score must be between 1 and %d", maxAllowedScore)
+	}
+	if r.BestOf < 1 || r.BestOf > maxBestOf || r.BestOf%2 == 0 {
+		return fmt.Errorf("best of must be an odd number between 1 and %d", maxBestOf)
+	}
+	return nil
+}
+
+// Marshal converts the rules to their proto representation.
+func (r GameRules) Marshal() *pong.GameRules {
+	return &pong.GameRules{
+		MaxScore: r.MaxScore,
+		BestOf:   r.BestOf,
+	}
+}
+
+// GameRulesFromProto converts requested rules, filling unset fields with the
+// defaults.
+func GameRulesFromProto(proto *pong.GameRules) GameRules {
+	rules := DefaultGameRules()
+	if proto.GetMaxScore() != 0 {
+		rules.MaxScore = proto.GetMaxScore()
+	}
+	if proto.GetBestOf() != 0 {
+		rules.BestOf = proto.GetBestOf()
+	}
+	return rules
+}
@@ -0,0 +1,126 @@
+package ponggame
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+// Series tracks a best-of-N set of games played for a single stake.
+type Series struct {
+	ID          string
+	BestOf      int32
+	Players     []*Player
+	Wins        map[zkidentity.ShortID]int32
+	GamesPlayed int32
+	Winner      *zkidentity.ShortID
+
+	// Games are the games played in the series in order, including draws.
+	Games []SeriesGame
+
+	// NextGameAt is when the next game starts during a break.
+	NextGameAt time.Time
+}
+
+// SeriesGame is the result of a game played in a series.
+type SeriesGame struct {
+	ID         string
+	Winner     *zkidentity.ShortID
+	ReplayHash []byte
+	StartedAt  time.Time
+}
+
+// NewSeries creates a series between the players.
+func NewSeries(players []*Player, bestOf int32) (*Series, error) {
+	if len(players) != 2 {
+		return nil, fmt.Errorf("series needs 2 players, got %d", len(players))
+	}
+	if bestOf < 1 {
+		bestOf = 1
+	}
+	id, err := utils.GenerateRandomString(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate series ID: %w", err)
+	}
+	return &Series{
+		ID:      id,
+		BestOf:  bestOf,
+		Players: players,
+		Wins:    make(map[zkidentity.ShortID]int32, len(players)),
+	}, nil
+}
+
+// WinsNeeded returns the number of games needed to take the series.
+func (s *Series) WinsNeeded() int32 {
+	return s.BestOf/2 + 1
+}
+
+// Decided returns whether the series has a winner.
+func (s *Series) Decided() bool {
+	return s.Winner != nil
+}
+
+// NextPlayers returns the players in the order of the next game. Players swap
+// sides after every game.
+func (s *Series) NextPlayers() []*Player {
+	if s.GamesPlayed%2 == 0 {
+		return []*Player{s.Players[0], s.Players[1]}
+	}
+	return []*Player{s.Players[1], s.Players[0]}
+}
+
+// RecordGame adds the result of a game to the series and returns whether the
+// series is now decided. Games without a winner don't count towards the
+// series.
+func (s *Series) RecordGame(winner *zkidentity.ShortID) bool {
+	if s.Decided() || winner == nil {
+		return s.Decided()
+	}
+	s.GamesPlayed++
+	s.Wins[*winner]++
+	if s.Wins[*winner] >= s.WinsNeeded() {
+		id := *winner
+		s.Winner = &id
+	}
+	return s.Decided()
+}
+
+// AddGame adds a finished game to the games played in the series.
+func (s *Series) AddGame(g *GameInstance) {
+	s.Games = append(s.Games, SeriesGame{
+		ID:         g.Id,
+		Winner:     g.Winner,
+		ReplayHash: g.ReplayHash(),
+		StartedAt:  g.StartedAt,
+	})
+}
+
+// Forfeit ends the series in favor of the given player.
+func (s *Series) Forfeit(winner zkidentity.ShortID) {
+	s.Winner = &winner
+}
+
+// Marshal converts the series to its proto representation.
+func (s *Series) Marshal() *pong.SeriesState {
+	state := &pong.SeriesState{
+		Id:          s.ID,
+		BestOf:      s.BestOf,
+		GamesPlayed: s.GamesPlayed,
+	}
+	for _, p := range s.Players {
+		state.Scores = append(state.Scores, &pong.SeriesScore{
+			PlayerId: p.ID.String(),
+			Wins:     s.Wins[*p.ID],
+		})
+	}
+	if s.Winner != nil {
+		state.WinnerId = s.Winner.String()
+	}
+	if !s.NextGameAt.IsZero() {
+		state.NextGameAt = s.NextGameAt.Unix()
+	}
+	return state
+}
@@ -0,0 +1,58 @@
+package ponggame
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeries_BestOfThree(t *testing.T) {
+	players := createTestPlayers()
+	p1, p2 := *players[0].ID, *players[1].ID
+
+	series, err := NewSeries(players, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), series.WinsNeeded())
+
+	// Sides swap after every game.
+	assert.Equal(t, players[0], series.NextPlayers()[0])
+	assert.False(t, series.RecordGame(&p1))
+	assert.Equal(t, players[1], series.NextPlayers()[0])
+
+	// Draws don't count.
+	assert.False(t, series.RecordGame(nil))
+	assert.Equal(t, int32(1), series.GamesPlayed)
+
+	assert.False(t, series.RecordGame(&p2))
+	assert.True(t, series.RecordGame(&p2))
+	require.NotNil(t, series.Winner)
+	assert.Equal(t, p2, *series.Winner)
+
+	// Games after the series is decided are ignored.
+	assert.True(t, series.RecordGame(&p1))
+	assert.Equal(t, int32(3), series.GamesPlayed)
+
+	state := series.Marshal()
+	assert.Equal(t, p2.String(), state.WinnerId)
+	require.Len(t, state.Scores, 2)
+	assert.Equal(t, int32(1), state.Scores[0].Wins)
+	assert.Equal(t, int32(2), state.Scores[1].Wins)
+}
+
+func TestSeries_Forfeit(t *testing.T) {
+	players := createTestPlayers()
+	series, err := NewSeries(players, 5)
+	require.NoError(t, err)
+
+	series.Forfeit(*players[1].ID)
+	assert.True(t, series.Decided())
+	assert.Equal(t, *players[1].ID, *series.Winner)
+}
+
+func TestGameRules_Validate(t *testing.T) {
+	require.NoError(t, DefaultGameRules().Validate())
+	require.Error(t, GameRules{MaxScore: 3, BestOf: 2}.Validate())
+	require.Error(t, GameRules{MaxScore: 0, BestOf: 1}.Validate())
+	require.NoError(t, GameRulesFromProto(nil).Validate())
+}
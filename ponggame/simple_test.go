@@ -8,6 +8,7 @@ import (
 	"github.com/decred/slog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 )
 
 // Basic working tests for core functionality
@@ -73,7 +74,7 @@ func TestBasic_PlayerReset(t *testing.T) {
 	player.ResetPlayer()
 
 	// Check that fields are reset
-	assert.Equal(t, int64(0), player.BetAmt)
+	assert.Equal(t, matoms.Amount(0), player.BetAmt)
 	assert.Equal(t, int32(0), player.PlayerNumber)
 	assert.Equal(t, 0, player.Score)
 	assert.False(t, player.Ready)
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 )
 
@@ -26,12 +28,23 @@ func (wr *WaitingRoom) Marshal() (*pong.WaitingRoom, error) {
 		players[i] = protoPlayer
 	}
 
-	return &pong.WaitingRoom{
+	pwr := &pong.WaitingRoom{
 		Id:      wr.ID,
 		HostId:  wr.HostID.String(),
 		Players: players,
-		BetAmt:  wr.BetAmount,
-	}, nil
+		BetAmt:  int64(wr.BetAmount),
+		Private: wr.Private,
+		Rules:   wr.Rules.Marshal(),
+		Locked:  wr.Locked,
+		Rake:    int64(wr.Rake),
+	}
+	if !wr.ReadyDeadline.IsZero() {
+		pwr.ReadyDeadline = wr.ReadyDeadline.Unix()
+	}
+	if !wr.CreatedAt.IsZero() {
+		pwr.CreatedAt = wr.CreatedAt.Unix()
+	}
+	return pwr, nil
 }
 
 // Unmarshal converts a WaitingRoomProto to a WaitingRoom struct.
@@ -54,13 +67,157 @@ func (wr *WaitingRoom) Unmarshal(proto *pong.WaitingRoom) error {
 
 	wr.HostID = &hostID
 	wr.Players = players
-	wr.BetAmount = proto.GetBetAmt()
+	wr.BetAmount = matoms.Amount(proto.GetBetAmt())
+	wr.Private = proto.GetPrivate()
+	wr.Locked = proto.GetLocked()
+	wr.Rake = matoms.Amount(proto.GetRake())
+	if proto.GetCreatedAt() != 0 {
+		wr.CreatedAt = time.Unix(proto.GetCreatedAt(), 0)
+	}
+	wr.Rules = GameRulesFromProto(proto.GetRules())
 	return nil
 }
 
+// MakePrivate turns the room into an invite-only room and returns its invite
+// code.
+func (wr *WaitingRoom) MakePrivate() (string, error) {
+	code, err := utils.GenerateRandomString(8)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate invite code: %w", err)
+	}
+
+	wr.Lock()
+	defer wr.Unlock()
+	wr.Private = true
+	wr.InviteCode = code
+	if wr.Invited == nil {
+		wr.Invited = make(map[zkidentity.ShortID]struct{})
+	}
+	return code, nil
+}
+
+// MakePublic lists the room again and drops its invite code.
+func (wr *WaitingRoom) MakePublic() {
+	wr.Lock()
+	defer wr.Unlock()
+	wr.Private = false
+	wr.InviteCode = ""
+}
+
+// Invite allows the given players to join a private room without the invite
+// code.
+func (wr *WaitingRoom) Invite(ids ...zkidentity.ShortID) {
+	wr.Lock()
+	defer wr.Unlock()
+	if wr.Invited == nil {
+		wr.Invited = make(map[zkidentity.ShortID]struct{})
+	}
+	for _, id := range ids {
+		wr.Invited[id] = struct{}{}
+	}
+}
+
+// IsInvited returns whether the player was explicitly invited to the room.
+func (wr *WaitingRoom) IsInvited(id zkidentity.ShortID) bool {
+	wr.RLock()
+	defer wr.RUnlock()
+	_, ok := wr.Invited[id]
+	return ok
+}
+
+// CanJoin returns whether the player may join the room. Public rooms are open
+// to everyone, private rooms require an invite or the matching invite code.
+func (wr *WaitingRoom) CanJoin(id zkidentity.ShortID, inviteCode string) bool {
+	wr.RLock()
+	defer wr.RUnlock()
+	if !wr.Private {
+		return true
+	}
+	if _, ok := wr.Invited[id]; ok {
+		return true
+	}
+	return inviteCode != "" && inviteCode == wr.InviteCode
+}
+
+// SetLocked locks or unlocks the room for new players.
+func (wr *WaitingRoom) SetLocked(locked bool) {
+	wr.Lock()
+	defer wr.Unlock()
+	wr.Locked = locked
+}
+
+// IsLocked returns whether the room is locked.
+func (wr *WaitingRoom) IsLocked() bool {
+	wr.RLock()
+	defer wr.RUnlock()
+	return wr.Locked
+}
+
+// Ban keeps the player from joining the room again.
+func (wr *WaitingRoom) Ban(id zkidentity.ShortID) {
+	wr.Lock()
+	defer wr.Unlock()
+	if wr.Banned == nil {
+		wr.Banned = make(map[zkidentity.ShortID]struct{})
+	}
+	wr.Banned[id] = struct{}{}
+	delete(wr.Invited, id)
+}
+
+// IsBanned returns whether the player was banned from the room.
+func (wr *WaitingRoom) IsBanned(id zkidentity.ShortID) bool {
+	wr.RLock()
+	defer wr.RUnlock()
+	_, ok := wr.Banned[id]
+	return ok
+}
+
+// IsHost returns whether the player is the host of the room.
+func (wr *WaitingRoom) IsHost(id zkidentity.ShortID) bool {
+	wr.RLock()
+	defer wr.RUnlock()
+	return wr.HostID != nil && *wr.HostID == id
+}
+
+// TransferHost makes another player of the room its host.
+func (wr *WaitingRoom) TransferHost(id zkidentity.ShortID) error {
+	wr.Lock()
+	defer wr.Unlock()
+	for _, p := range wr.Players {
+		if *p.ID == id {
+			wr.HostID = p.ID
+			wr.LastActivity = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("player %s is not in waiting room %s", id, wr.ID)
+}
+
+// NextHost returns the player that should take over the room when the host
+// leaves: the longest waiting player that is still connected, or the longest
+// waiting one if nobody is. It returns nil if the room has no other players.
+func (wr *WaitingRoom) NextHost() *Player {
+	wr.RLock()
+	defer wr.RUnlock()
+	var next *Player
+	for _, p := range wr.Players {
+		if wr.HostID != nil && *p.ID == *wr.HostID {
+			continue
+		}
+		if p.NotifierStream != nil {
+			return p
+		}
+		if next == nil {
+			next = p
+		}
+	}
+	return next
+}
+
 func (wr *WaitingRoom) AddPlayer(player *Player) {
 	wr.Lock()
 	defer wr.Unlock()
+	wr.LastActivity = time.Now()
 	for _, p := range wr.Players {
 		// don't add repeated players
 		if p.ID == player.ID {
@@ -70,6 +227,34 @@ func (wr *WaitingRoom) AddPlayer(player *Player) {
 	wr.Players = append(wr.Players, player)
 }
 
+// IsFull returns whether all the seats of the room are taken.
+func (wr *WaitingRoom) IsFull(seats int) bool {
+	wr.RLock()
+	defer wr.RUnlock()
+	return len(wr.Players) >= seats
+}
+
+// TakeSeat adds a player that reserved betAmt to the room unless all its
+// seats are taken or its bet changed since.
+func (wr *WaitingRoom) TakeSeat(player *Player, seats int, betAmt matoms.Amount) error {
+	wr.Lock()
+	defer wr.Unlock()
+	for _, p := range wr.Players {
+		if p.ID == player.ID {
+			return nil
+		}
+	}
+	if len(wr.Players) >= seats {
+		return fmt.Errorf("waiting room %s is full", wr.ID)
+	}
+	if wr.BetAmount != betAmt {
+		return fmt.Errorf("the bet of waiting room %s changed to %s", wr.ID, wr.BetAmount)
+	}
+	wr.LastActivity = time.Now()
+	wr.Players = append(wr.Players, player)
+	return nil
+}
+
 func (wr *WaitingRoom) ReadyPlayers() ([]*Player, bool) {
 	wr.Lock()
 	defer wr.Unlock()
@@ -112,6 +297,7 @@ func (wr *WaitingRoom) GetPlayers() []*Player {
 func (wr *WaitingRoom) RemovePlayer(clientID zkidentity.ShortID) {
 	wr.Lock()
 	defer wr.Unlock()
+	wr.LastActivity = time.Now()
 
 	// Remove player from Players slice
 	for i, player := range wr.Players {
@@ -131,6 +317,26 @@ func (wr *WaitingRoom) RemovePlayer(clientID zkidentity.ShortID) {
 	wr.ReservedTips = filteredTips
 }
 
+// Touch records activity in the room.
+func (wr *WaitingRoom) Touch() {
+	wr.Lock()
+	wr.LastActivity = time.Now()
+	wr.Unlock()
+}
+
+// UnreadyPlayers returns the players of the room that are not ready.
+func (wr *WaitingRoom) UnreadyPlayers() []*Player {
+	wr.RLock()
+	defer wr.RUnlock()
+	var unready []*Player
+	for _, player := range wr.Players {
+		if !player.Ready {
+			unready = append(unready, player)
+		}
+	}
+	return unready
+}
+
 func (wr *WaitingRoom) length() int {
 	wr.RLock()
 	defer wr.RUnlock()
@@ -138,19 +344,23 @@ func (wr *WaitingRoom) length() int {
 }
 
 // NewWaitingRoom creates and initializes a new waiting room.
-func NewWaitingRoom(hostPlayer *Player, betAmount int64) (*WaitingRoom, error) {
+func NewWaitingRoom(hostPlayer *Player, betAmount matoms.Amount) (*WaitingRoom, error) {
 	id, err := utils.GenerateRandomString(16)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate waiting room ID: %w", err)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
 	return &WaitingRoom{
-		Ctx:       ctx,
-		Cancel:    cancel,
-		ID:        id,
-		HostID:    hostPlayer.ID,
-		BetAmount: betAmount,
-		Players:   []*Player{hostPlayer},
+		Ctx:          ctx,
+		Cancel:       cancel,
+		ID:           id,
+		HostID:       hostPlayer.ID,
+		BetAmount:    betAmount,
+		Players:      []*Player{hostPlayer},
+		Rules:        DefaultGameRules(),
+		CreatedAt:    now,
+		LastActivity: now,
 	}, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 )
 
 func createTestWaitingRoom() *WaitingRoom {
@@ -91,7 +92,7 @@ func TestWaitingRoom_Marshal(t *testing.T) {
 	pongWR, err := wr.Marshal()
 	require.NoError(t, err)
 	assert.Equal(t, wr.ID, pongWR.Id)
-	assert.Equal(t, wr.BetAmount, pongWR.BetAmt)
+	assert.Equal(t, int64(wr.BetAmount), pongWR.BetAmt)
 	assert.Equal(t, 0, len(pongWR.Players))
 
 	// Add players and test marshaling
@@ -101,17 +102,40 @@ func TestWaitingRoom_Marshal(t *testing.T) {
 	pongWR, err = wr.Marshal()
 	require.NoError(t, err)
 	assert.Equal(t, wr.ID, pongWR.Id)
-	assert.Equal(t, wr.BetAmount, pongWR.BetAmt)
+	assert.Equal(t, int64(wr.BetAmount), pongWR.BetAmt)
 	assert.Equal(t, 2, len(pongWR.Players))
 
 	// Verify player data is correctly marshaled
 	for i, player := range pongWR.Players {
 		assert.Equal(t, players[i].Nick, player.Nick)
-		assert.Equal(t, players[i].BetAmt, player.BetAmt)
+		assert.Equal(t, int64(players[i].BetAmt), player.BetAmt)
 		assert.Equal(t, players[i].Ready, player.Ready)
 	}
 }
 
+func TestWaitingRoom_CanJoin(t *testing.T) {
+	wr := createTestWaitingRoom()
+	invited := zkidentity.ShortID{1}
+	stranger := zkidentity.ShortID{2}
+
+	// Public rooms are open to everyone.
+	assert.True(t, wr.CanJoin(stranger, ""))
+
+	code, err := wr.MakePrivate()
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	wr.Invite(invited)
+
+	assert.True(t, wr.CanJoin(invited, ""))
+	assert.False(t, wr.CanJoin(stranger, ""))
+	assert.False(t, wr.CanJoin(stranger, "wrong"))
+	assert.True(t, wr.CanJoin(stranger, code))
+
+	pongWR, err := wr.Marshal()
+	require.NoError(t, err)
+	assert.True(t, pongWR.Private)
+}
+
 func TestWaitingRoom_ReadyPlayers(t *testing.T) {
 	wr := createTestWaitingRoom()
 	players := createTestPlayers()
@@ -144,6 +168,56 @@ func TestWaitingRoom_ReadyPlayers(t *testing.T) {
 	assert.Equal(t, 2, len(readyPlayers))
 }
 
+func TestWaitingRoom_UnreadyPlayers(t *testing.T) {
+	wr := createTestWaitingRoom()
+	players := createTestPlayers()
+	wr.AddPlayer(players[0])
+	wr.AddPlayer(players[1])
+	require.False(t, wr.LastActivity.IsZero())
+
+	players[0].Ready = true
+	players[1].Ready = false
+	assert.Equal(t, []*Player{players[1]}, wr.UnreadyPlayers())
+
+	players[1].Ready = true
+	assert.Empty(t, wr.UnreadyPlayers())
+}
+
+func TestWaitingRoom_TransferHost(t *testing.T) {
+	wr := createTestWaitingRoom()
+	players := createTestPlayers()
+	wr.HostID = players[0].ID
+	wr.AddPlayer(players[0])
+	wr.AddPlayer(players[1])
+
+	require.True(t, wr.IsHost(*players[0].ID))
+	assert.Equal(t, players[1], wr.NextHost())
+
+	require.NoError(t, wr.TransferHost(*players[1].ID))
+	assert.True(t, wr.IsHost(*players[1].ID))
+	assert.False(t, wr.IsHost(*players[0].ID))
+
+	outsider := zkidentity.ShortID{9}
+	assert.Error(t, wr.TransferHost(outsider))
+	assert.True(t, wr.IsHost(*players[1].ID))
+}
+
+func TestWaitingRoom_LockAndBan(t *testing.T) {
+	wr := createTestWaitingRoom()
+	id := zkidentity.ShortID{3}
+
+	assert.False(t, wr.IsLocked())
+	wr.SetLocked(true)
+	assert.True(t, wr.IsLocked())
+	wr.SetLocked(false)
+	assert.False(t, wr.IsLocked())
+
+	wr.Invite(id)
+	wr.Ban(id)
+	assert.True(t, wr.IsBanned(id))
+	assert.False(t, wr.IsInvited(id))
+}
+
 func TestWaitingRoom_GetPlayers(t *testing.T) {
 	wr := createTestWaitingRoom()
 	players := createTestPlayers()
@@ -273,14 +347,14 @@ func TestWaitingRoom_BetAmountCalculations(t *testing.T) {
 	wr.AddPlayer(players[1])
 
 	// Test that waiting room tracks bet amount correctly
-	assert.Equal(t, int64(100), wr.BetAmount) // Initial bet amount
+	assert.Equal(t, matoms.Amount(100), wr.BetAmount) // Initial bet amount
 
 	// Test total bet calculation
-	totalBets := int64(0)
+	totalBets := matoms.Amount(0)
 	for _, player := range wr.Players {
 		totalBets += player.BetAmt
 	}
-	assert.Equal(t, int64(300), totalBets)
+	assert.Equal(t, matoms.Amount(300), totalBets)
 }
 
 func TestWaitingRoom_StateConsistency(t *testing.T) {
@@ -291,7 +365,7 @@ func TestWaitingRoom_StateConsistency(t *testing.T) {
 	assert.Equal(t, "test-room-id", wr.ID)
 	assert.NotNil(t, wr.Ctx)
 	assert.NotNil(t, wr.Cancel)
-	assert.Equal(t, int64(100), wr.BetAmount)
+	assert.Equal(t, matoms.Amount(100), wr.BetAmount)
 
 	// Add players and verify state
 	wr.AddPlayer(players[0])
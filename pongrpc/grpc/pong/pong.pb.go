@@ -25,19 +25,46 @@ const (
 type NotificationType int32
 
 const (
-	NotificationType_UNKNOWN               NotificationType = 0
-	NotificationType_MESSAGE               NotificationType = 1
-	NotificationType_GAME_START            NotificationType = 2
-	NotificationType_GAME_END              NotificationType = 3
-	NotificationType_OPPONENT_DISCONNECTED NotificationType = 4
-	NotificationType_BET_AMOUNT_UPDATE     NotificationType = 5
-	NotificationType_PLAYER_JOINED_WR      NotificationType = 6
-	NotificationType_ON_WR_CREATED         NotificationType = 7
-	NotificationType_ON_PLAYER_READY       NotificationType = 8
-	NotificationType_ON_WR_REMOVED         NotificationType = 9
-	NotificationType_PLAYER_LEFT_WR        NotificationType = 10
-	NotificationType_COUNTDOWN_UPDATE      NotificationType = 11
-	NotificationType_GAME_READY_TO_PLAY    NotificationType = 12
+	NotificationType_UNKNOWN                NotificationType = 0
+	NotificationType_MESSAGE                NotificationType = 1
+	NotificationType_GAME_START             NotificationType = 2
+	NotificationType_GAME_END               NotificationType = 3
+	NotificationType_OPPONENT_DISCONNECTED  NotificationType = 4
+	NotificationType_BET_AMOUNT_UPDATE      NotificationType = 5
+	NotificationType_PLAYER_JOINED_WR       NotificationType = 6
+	NotificationType_ON_WR_CREATED          NotificationType = 7
+	NotificationType_ON_PLAYER_READY        NotificationType = 8
+	NotificationType_ON_WR_REMOVED          NotificationType = 9
+	NotificationType_PLAYER_LEFT_WR         NotificationType = 10
+	NotificationType_COUNTDOWN_UPDATE       NotificationType = 11
+	NotificationType_GAME_READY_TO_PLAY     NotificationType = 12
+	NotificationType_WR_INVITE              NotificationType = 13
+	NotificationType_CHALLENGE_RECEIVED     NotificationType = 14
+	NotificationType_CHALLENGE_ACCEPTED     NotificationType = 15
+	NotificationType_CHALLENGE_DECLINED     NotificationType = 16
+	NotificationType_CHALLENGE_EXPIRED      NotificationType = 17
+	NotificationType_SERIES_UPDATE          NotificationType = 18
+	NotificationType_REMATCH_AVAILABLE      NotificationType = 19
+	NotificationType_REMATCH_PROPOSED       NotificationType = 20
+	NotificationType_REMATCH_ACCEPTED       NotificationType = 21
+	NotificationType_REMATCH_DECLINED       NotificationType = 22
+	NotificationType_REMATCH_EXPIRED        NotificationType = 23
+	NotificationType_TOURNAMENT_UPDATE      NotificationType = 24
+	NotificationType_TOURNAMENT_MATCH_READY NotificationType = 25
+	NotificationType_TOURNAMENT_ENDED       NotificationType = 26
+	NotificationType_READY_CHECK            NotificationType = 27
+	NotificationType_PLAYER_EVICTED         NotificationType = 28
+	NotificationType_WR_EXPIRED             NotificationType = 29
+	NotificationType_PLAYER_KICKED          NotificationType = 30
+	NotificationType_WR_LOCKED              NotificationType = 31
+	NotificationType_WR_UNLOCKED            NotificationType = 32
+	NotificationType_HOST_TRANSFERRED       NotificationType = 33
+	NotificationType_WR_SETTINGS_UPDATED    NotificationType = 34
+	NotificationType_WR_RESTORED            NotificationType = 35
+	NotificationType_WITHDRAWAL_STARTED     NotificationType = 36
+	NotificationType_PAYOUT_PROGRESS        NotificationType = 37
+	NotificationType_PAYOUT_COMPLETED       NotificationType = 38
+	NotificationType_SESSION_REMINDER       NotificationType = 39
 )
 
 // Enum value maps for NotificationType.
@@ -56,21 +83,75 @@ var (
 		10: "PLAYER_LEFT_WR",
 		11: "COUNTDOWN_UPDATE",
 		12: "GAME_READY_TO_PLAY",
+		13: "WR_INVITE",
+		14: "CHALLENGE_RECEIVED",
+		15: "CHALLENGE_ACCEPTED",
+		16: "CHALLENGE_DECLINED",
+		17: "CHALLENGE_EXPIRED",
+		18: "SERIES_UPDATE",
+		19: "REMATCH_AVAILABLE",
+		20: "REMATCH_PROPOSED",
+		21: "REMATCH_ACCEPTED",
+		22: "REMATCH_DECLINED",
+		23: "REMATCH_EXPIRED",
+		24: "TOURNAMENT_UPDATE",
+		25: "TOURNAMENT_MATCH_READY",
+		26: "TOURNAMENT_ENDED",
+		27: "READY_CHECK",
+		28: "PLAYER_EVICTED",
+		29: "WR_EXPIRED",
+		30: "PLAYER_KICKED",
+		31: "WR_LOCKED",
+		32: "WR_UNLOCKED",
+		33: "HOST_TRANSFERRED",
+		34: "WR_SETTINGS_UPDATED",
+		35: "WR_RESTORED",
+		36: "WITHDRAWAL_STARTED",
+		37: "PAYOUT_PROGRESS",
+		38: "PAYOUT_COMPLETED",
+		39: "SESSION_REMINDER",
 	}
 	NotificationType_value = map[string]int32{
-		"UNKNOWN":               0,
-		"MESSAGE":               1,
-		"GAME_START":            2,
-		"GAME_END":              3,
-		"OPPONENT_DISCONNECTED": 4,
-		"BET_AMOUNT_UPDATE":     5,
-		"PLAYER_JOINED_WR":      6,
-		"ON_WR_CREATED":         7,
-		"ON_PLAYER_READY":       8,
-		"ON_WR_REMOVED":         9,
-		"PLAYER_LEFT_WR":        10,
-		"COUNTDOWN_UPDATE":      11,
-		"GAME_READY_TO_PLAY":    12,
+		"UNKNOWN":                0,
+		"MESSAGE":                1,
+		"GAME_START":             2,
+		"GAME_END":               3,
+		"OPPONENT_DISCONNECTED":  4,
+		"BET_AMOUNT_UPDATE":      5,
+		"PLAYER_JOINED_WR":       6,
+		"ON_WR_CREATED":          7,
+		"ON_PLAYER_READY":        8,
+		"ON_WR_REMOVED":          9,
+		"PLAYER_LEFT_WR":         10,
+		"COUNTDOWN_UPDATE":       11,
+		"GAME_READY_TO_PLAY":     12,
+		"WR_INVITE":              13,
+		"CHALLENGE_RECEIVED":     14,
+		"CHALLENGE_ACCEPTED":     15,
+		"CHALLENGE_DECLINED":     16,
+		"CHALLENGE_EXPIRED":      17,
+		"SERIES_UPDATE":          18,
+		"REMATCH_AVAILABLE":      19,
+		"REMATCH_PROPOSED":       20,
+		"REMATCH_ACCEPTED":       21,
+		"REMATCH_DECLINED":       22,
+		"REMATCH_EXPIRED":        23,
+		"TOURNAMENT_UPDATE":      24,
+		"TOURNAMENT_MATCH_READY": 25,
+		"TOURNAMENT_ENDED":       26,
+		"READY_CHECK":            27,
+		"PLAYER_EVICTED":         28,
+		"WR_EXPIRED":             29,
+		"PLAYER_KICKED":          30,
+		"WR_LOCKED":              31,
+		"WR_UNLOCKED":            32,
+		"HOST_TRANSFERRED":       33,
+		"WR_SETTINGS_UPDATED":    34,
+		"WR_RESTORED":            35,
+		"WITHDRAWAL_STARTED":     36,
+		"PAYOUT_PROGRESS":        37,
+		"PAYOUT_COMPLETED":       38,
+		"SESSION_REMINDER":       39,
 	}
 )
 
@@ -101,6 +182,261 @@ func (NotificationType) EnumDescriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{0}
 }
 
+type WaitingRoomSort int32
+
+const (
+	WaitingRoomSort_WR_SORT_NEWEST      WaitingRoomSort = 0
+	WaitingRoomSort_WR_SORT_OLDEST      WaitingRoomSort = 1
+	WaitingRoomSort_WR_SORT_BET_ASC     WaitingRoomSort = 2
+	WaitingRoomSort_WR_SORT_BET_DESC    WaitingRoomSort = 3
+	WaitingRoomSort_WR_SORT_HOST_RATING WaitingRoomSort = 4 // highest rated hosts first
+)
+
+// Enum value maps for WaitingRoomSort.
+var (
+	WaitingRoomSort_name = map[int32]string{
+		0: "WR_SORT_NEWEST",
+		1: "WR_SORT_OLDEST",
+		2: "WR_SORT_BET_ASC",
+		3: "WR_SORT_BET_DESC",
+		4: "WR_SORT_HOST_RATING",
+	}
+	WaitingRoomSort_value = map[string]int32{
+		"WR_SORT_NEWEST":      0,
+		"WR_SORT_OLDEST":      1,
+		"WR_SORT_BET_ASC":     2,
+		"WR_SORT_BET_DESC":    3,
+		"WR_SORT_HOST_RATING": 4,
+	}
+)
+
+func (x WaitingRoomSort) Enum() *WaitingRoomSort {
+	p := new(WaitingRoomSort)
+	*p = x
+	return p
+}
+
+func (x WaitingRoomSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WaitingRoomSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_pong_proto_enumTypes[1].Descriptor()
+}
+
+func (WaitingRoomSort) Type() protoreflect.EnumType {
+	return &file_pong_proto_enumTypes[1]
+}
+
+func (x WaitingRoomSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WaitingRoomSort.Descriptor instead.
+func (WaitingRoomSort) EnumDescriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{1}
+}
+
+// Leaderboard Messages
+type LeaderboardKind int32
+
+const (
+	LeaderboardKind_BY_RATING       LeaderboardKind = 0
+	LeaderboardKind_BY_NET_WINNINGS LeaderboardKind = 1
+	LeaderboardKind_BY_WIN_STREAK   LeaderboardKind = 2
+	LeaderboardKind_BY_CREDITS      LeaderboardKind = 3 // credits held on free-to-play servers, for any window
+)
+
+// Enum value maps for LeaderboardKind.
+var (
+	LeaderboardKind_name = map[int32]string{
+		0: "BY_RATING",
+		1: "BY_NET_WINNINGS",
+		2: "BY_WIN_STREAK",
+		3: "BY_CREDITS",
+	}
+	LeaderboardKind_value = map[string]int32{
+		"BY_RATING":       0,
+		"BY_NET_WINNINGS": 1,
+		"BY_WIN_STREAK":   2,
+		"BY_CREDITS":      3,
+	}
+)
+
+func (x LeaderboardKind) Enum() *LeaderboardKind {
+	p := new(LeaderboardKind)
+	*p = x
+	return p
+}
+
+func (x LeaderboardKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LeaderboardKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_pong_proto_enumTypes[2].Descriptor()
+}
+
+func (LeaderboardKind) Type() protoreflect.EnumType {
+	return &file_pong_proto_enumTypes[2]
+}
+
+func (x LeaderboardKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LeaderboardKind.Descriptor instead.
+func (LeaderboardKind) EnumDescriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{2}
+}
+
+type LeaderboardWindow int32
+
+const (
+	LeaderboardWindow_SEASON LeaderboardWindow = 0
+	LeaderboardWindow_DAILY  LeaderboardWindow = 1
+	LeaderboardWindow_WEEKLY LeaderboardWindow = 2
+)
+
+// Enum value maps for LeaderboardWindow.
+var (
+	LeaderboardWindow_name = map[int32]string{
+		0: "SEASON",
+		1: "DAILY",
+		2: "WEEKLY",
+	}
+	LeaderboardWindow_value = map[string]int32{
+		"SEASON": 0,
+		"DAILY":  1,
+		"WEEKLY": 2,
+	}
+)
+
+func (x LeaderboardWindow) Enum() *LeaderboardWindow {
+	p := new(LeaderboardWindow)
+	*p = x
+	return p
+}
+
+func (x LeaderboardWindow) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LeaderboardWindow) Descriptor() protoreflect.EnumDescriptor {
+	return file_pong_proto_enumTypes[3].Descriptor()
+}
+
+func (LeaderboardWindow) Type() protoreflect.EnumType {
+	return &file_pong_proto_enumTypes[3]
+}
+
+func (x LeaderboardWindow) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LeaderboardWindow.Descriptor instead.
+func (LeaderboardWindow) EnumDescriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{3}
+}
+
+type TournamentFormat int32
+
+const (
+	TournamentFormat_SINGLE_ELIMINATION TournamentFormat = 0
+	TournamentFormat_DOUBLE_ELIMINATION TournamentFormat = 1
+)
+
+// Enum value maps for TournamentFormat.
+var (
+	TournamentFormat_name = map[int32]string{
+		0: "SINGLE_ELIMINATION",
+		1: "DOUBLE_ELIMINATION",
+	}
+	TournamentFormat_value = map[string]int32{
+		"SINGLE_ELIMINATION": 0,
+		"DOUBLE_ELIMINATION": 1,
+	}
+)
+
+func (x TournamentFormat) Enum() *TournamentFormat {
+	p := new(TournamentFormat)
+	*p = x
+	return p
+}
+
+func (x TournamentFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TournamentFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_pong_proto_enumTypes[4].Descriptor()
+}
+
+func (TournamentFormat) Type() protoreflect.EnumType {
+	return &file_pong_proto_enumTypes[4]
+}
+
+func (x TournamentFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TournamentFormat.Descriptor instead.
+func (TournamentFormat) EnumDescriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{4}
+}
+
+type TournamentState int32
+
+const (
+	TournamentState_TOURNAMENT_REGISTRATION TournamentState = 0
+	TournamentState_TOURNAMENT_RUNNING      TournamentState = 1
+	TournamentState_TOURNAMENT_FINISHED     TournamentState = 2
+	TournamentState_TOURNAMENT_CANCELLED    TournamentState = 3
+)
+
+// Enum value maps for TournamentState.
+var (
+	TournamentState_name = map[int32]string{
+		0: "TOURNAMENT_REGISTRATION",
+		1: "TOURNAMENT_RUNNING",
+		2: "TOURNAMENT_FINISHED",
+		3: "TOURNAMENT_CANCELLED",
+	}
+	TournamentState_value = map[string]int32{
+		"TOURNAMENT_REGISTRATION": 0,
+		"TOURNAMENT_RUNNING":      1,
+		"TOURNAMENT_FINISHED":     2,
+		"TOURNAMENT_CANCELLED":    3,
+	}
+)
+
+func (x TournamentState) Enum() *TournamentState {
+	p := new(TournamentState)
+	*p = x
+	return p
+}
+
+func (x TournamentState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TournamentState) Descriptor() protoreflect.EnumDescriptor {
+	return file_pong_proto_enumTypes[5].Descriptor()
+}
+
+func (TournamentState) Type() protoreflect.EnumType {
+	return &file_pong_proto_enumTypes[5]
+}
+
+func (x TournamentState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TournamentState.Descriptor instead.
+func (TournamentState) EnumDescriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{5}
+}
+
 type UnreadyGameStreamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
@@ -184,6 +520,7 @@ func (*UnreadyGameStreamResponse) Descriptor() ([]byte, []int) {
 type StartNtfnStreamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Nick          string                 `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"` // bisonrelay nick, used to find players by name
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -225,6 +562,13 @@ func (x *StartNtfnStreamRequest) GetClientId() string {
 	return ""
 }
 
+func (x *StartNtfnStreamRequest) GetNick() string {
+	if x != nil {
+		return x.Nick
+	}
+	return ""
+}
+
 type NtfnStreamResponse struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	NotificationType NotificationType       `protobuf:"varint,1,opt,name=notification_type,json=notificationType,proto3,enum=pong.NotificationType" json:"notification_type,omitempty"` // Type of the notification
@@ -237,6 +581,15 @@ type NtfnStreamResponse struct {
 	RoomId           string                 `protobuf:"bytes,8,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
 	Wr               *WaitingRoom           `protobuf:"bytes,9,opt,name=wr,proto3" json:"wr,omitempty"`
 	Ready            bool                   `protobuf:"varint,10,opt,name=ready,proto3" json:"ready,omitempty"`
+	InviteCode       string                 `protobuf:"bytes,11,opt,name=invite_code,json=inviteCode,proto3" json:"invite_code,omitempty"`
+	Challenge        *Challenge             `protobuf:"bytes,12,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Series           *SeriesState           `protobuf:"bytes,13,opt,name=series,proto3" json:"series,omitempty"`
+	Rematch          *Rematch               `protobuf:"bytes,14,opt,name=rematch,proto3" json:"rematch,omitempty"`
+	Tournament       *Tournament            `protobuf:"bytes,15,opt,name=tournament,proto3" json:"tournament,omitempty"`
+	Chat             *ChatMessage           `protobuf:"bytes,16,opt,name=chat,proto3" json:"chat,omitempty"`
+	Balance          *Balance               `protobuf:"bytes,17,opt,name=balance,proto3" json:"balance,omitempty"`
+	Fairness         *Fairness              `protobuf:"bytes,18,opt,name=fairness,proto3" json:"fairness,omitempty"` // seed commitment on GAME_READY_TO_PLAY, revealed on GAME_END
+	Receipt          *MatchReceipt          `protobuf:"bytes,19,opt,name=receipt,proto3" json:"receipt,omitempty"`   // on GAME_END
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -341,10 +694,84 @@ func (x *NtfnStreamResponse) GetReady() bool {
 	return false
 }
 
+func (x *NtfnStreamResponse) GetInviteCode() string {
+	if x != nil {
+		return x.InviteCode
+	}
+	return ""
+}
+
+func (x *NtfnStreamResponse) GetChallenge() *Challenge {
+	if x != nil {
+		return x.Challenge
+	}
+	return nil
+}
+
+func (x *NtfnStreamResponse) GetSeries() *SeriesState {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *NtfnStreamResponse) GetRematch() *Rematch {
+	if x != nil {
+		return x.Rematch
+	}
+	return nil
+}
+
+func (x *NtfnStreamResponse) GetTournament() *Tournament {
+	if x != nil {
+		return x.Tournament
+	}
+	return nil
+}
+
+func (x *NtfnStreamResponse) GetChat() *ChatMessage {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+func (x *NtfnStreamResponse) GetBalance() *Balance {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+func (x *NtfnStreamResponse) GetFairness() *Fairness {
+	if x != nil {
+		return x.Fairness
+	}
+	return nil
+}
+
+func (x *NtfnStreamResponse) GetReceipt() *MatchReceipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
 // Waiting Room Messages
+// WaitingRoomsRequest lists the public waiting rooms. All filters are
+// optional; unset ones match every room.
 type WaitingRoomsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`           // only this room
+	MinBet        int64                  `protobuf:"varint,2,opt,name=min_bet,json=minBet,proto3" json:"min_bet,omitempty"`          // matoms
+	MaxBet        int64                  `protobuf:"varint,3,opt,name=max_bet,json=maxBet,proto3" json:"max_bet,omitempty"`          // matoms, 0 for no limit
+	OpenSeats     bool                   `protobuf:"varint,4,opt,name=open_seats,json=openSeats,proto3" json:"open_seats,omitempty"` // only unlocked rooms with a free seat
+	Rules         *GameRules             `protobuf:"bytes,5,opt,name=rules,proto3" json:"rules,omitempty"`                           // only rooms with these rules, unset fields match any
+	MinHostRating float64                `protobuf:"fixed64,6,opt,name=min_host_rating,json=minHostRating,proto3" json:"min_host_rating,omitempty"`
+	MaxHostRating float64                `protobuf:"fixed64,7,opt,name=max_host_rating,json=maxHostRating,proto3" json:"max_host_rating,omitempty"` // 0 for no limit
+	Sort          WaitingRoomSort        `protobuf:"varint,8,opt,name=sort,proto3,enum=pong.WaitingRoomSort" json:"sort,omitempty"`
+	Cursor        string                 `protobuf:"bytes,9,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor of the previous page
+	Limit         int32                  `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"` // page size, 0 for the server default
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -386,9 +813,74 @@ func (x *WaitingRoomsRequest) GetRoomId() string {
 	return ""
 }
 
+func (x *WaitingRoomsRequest) GetMinBet() int64 {
+	if x != nil {
+		return x.MinBet
+	}
+	return 0
+}
+
+func (x *WaitingRoomsRequest) GetMaxBet() int64 {
+	if x != nil {
+		return x.MaxBet
+	}
+	return 0
+}
+
+func (x *WaitingRoomsRequest) GetOpenSeats() bool {
+	if x != nil {
+		return x.OpenSeats
+	}
+	return false
+}
+
+func (x *WaitingRoomsRequest) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *WaitingRoomsRequest) GetMinHostRating() float64 {
+	if x != nil {
+		return x.MinHostRating
+	}
+	return 0
+}
+
+func (x *WaitingRoomsRequest) GetMaxHostRating() float64 {
+	if x != nil {
+		return x.MaxHostRating
+	}
+	return 0
+}
+
+func (x *WaitingRoomsRequest) GetSort() WaitingRoomSort {
+	if x != nil {
+		return x.Sort
+	}
+	return WaitingRoomSort_WR_SORT_NEWEST
+}
+
+func (x *WaitingRoomsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *WaitingRoomsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
 type WaitingRoomsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Wr            []*WaitingRoom         `protobuf:"bytes,1,rep,name=wr,proto3" json:"wr,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // empty on the last page
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`                            // rooms matching the filters
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -430,10 +922,25 @@ func (x *WaitingRoomsResponse) GetWr() []*WaitingRoom {
 	return nil
 }
 
+func (x *WaitingRoomsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *WaitingRoomsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 type JoinWaitingRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
 	ClientId      string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	InviteCode    string                 `protobuf:"bytes,3,opt,name=invite_code,json=inviteCode,proto3" json:"invite_code,omitempty"` // required to join private rooms without an invite
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -482,6 +989,13 @@ func (x *JoinWaitingRoomRequest) GetClientId() string {
 	return ""
 }
 
+func (x *JoinWaitingRoomRequest) GetInviteCode() string {
+	if x != nil {
+		return x.InviteCode
+	}
+	return ""
+}
+
 type JoinWaitingRoomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"`
@@ -530,6 +1044,9 @@ type CreateWaitingRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
 	BetAmt        int64                  `protobuf:"varint,2,opt,name=betAmt,proto3" json:"betAmt,omitempty"`
+	Private       bool                   `protobuf:"varint,3,opt,name=private,proto3" json:"private,omitempty"`  // hidden from listings, joinable by invite only
+	Invitees      []string               `protobuf:"bytes,4,rep,name=invitees,proto3" json:"invitees,omitempty"` // client ids invited to a private room
+	Rules         *GameRules             `protobuf:"bytes,5,opt,name=rules,proto3" json:"rules,omitempty"`       // defaults are used when unset
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -578,9 +1095,31 @@ func (x *CreateWaitingRoomRequest) GetBetAmt() int64 {
 	return 0
 }
 
+func (x *CreateWaitingRoomRequest) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *CreateWaitingRoomRequest) GetInvitees() []string {
+	if x != nil {
+		return x.Invitees
+	}
+	return nil
+}
+
+func (x *CreateWaitingRoomRequest) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
 type CreateWaitingRoomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"`
+	InviteCode    string                 `protobuf:"bytes,2,opt,name=invite_code,json=inviteCode,proto3" json:"invite_code,omitempty"` // only set for private rooms
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -622,12 +1161,26 @@ func (x *CreateWaitingRoomResponse) GetWr() *WaitingRoom {
 	return nil
 }
 
+func (x *CreateWaitingRoomResponse) GetInviteCode() string {
+	if x != nil {
+		return x.InviteCode
+	}
+	return ""
+}
+
 type WaitingRoom struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	HostId        string                 `protobuf:"bytes,2,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
 	Players       []*Player              `protobuf:"bytes,3,rep,name=players,proto3" json:"players,omitempty"`
 	BetAmt        int64                  `protobuf:"varint,4,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"`
+	Private       bool                   `protobuf:"varint,5,opt,name=private,proto3" json:"private,omitempty"`
+	Rules         *GameRules             `protobuf:"bytes,6,opt,name=rules,proto3" json:"rules,omitempty"`
+	ReadyDeadline int64                  `protobuf:"varint,7,opt,name=ready_deadline,json=readyDeadline,proto3" json:"ready_deadline,omitempty"` // unix seconds players have to get ready once the room is full
+	Locked        bool                   `protobuf:"varint,8,opt,name=locked,proto3" json:"locked,omitempty"`                                    // locked rooms can't be joined
+	CreatedAt     int64                  `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`             // unix seconds
+	HostRating    float64                `protobuf:"fixed64,10,opt,name=host_rating,json=hostRating,proto3" json:"host_rating,omitempty"`        // only set in listings
+	Rake          int64                  `protobuf:"varint,11,opt,name=rake,proto3" json:"rake,omitempty"`                                       // matoms the house keeps from the pot if the game has a winner
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -690,26 +1243,77 @@ func (x *WaitingRoom) GetBetAmt() int64 {
 	return 0
 }
 
-type WaitingRoomRequest struct {
+func (x *WaitingRoom) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *WaitingRoom) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *WaitingRoom) GetReadyDeadline() int64 {
+	if x != nil {
+		return x.ReadyDeadline
+	}
+	return 0
+}
+
+func (x *WaitingRoom) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+func (x *WaitingRoom) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *WaitingRoom) GetHostRating() float64 {
+	if x != nil {
+		return x.HostRating
+	}
+	return 0
+}
+
+func (x *WaitingRoom) GetRake() int64 {
+	if x != nil {
+		return x.Rake
+	}
+	return 0
+}
+
+type GameRules struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxScore      int32                  `protobuf:"varint,1,opt,name=max_score,json=maxScore,proto3" json:"max_score,omitempty"` // points needed to win a game
+	BestOf        int32                  `protobuf:"varint,2,opt,name=best_of,json=bestOf,proto3" json:"best_of,omitempty"`       // games in the series, must be odd
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WaitingRoomRequest) Reset() {
-	*x = WaitingRoomRequest{}
+func (x *GameRules) Reset() {
+	*x = GameRules{}
 	mi := &file_pong_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WaitingRoomRequest) String() string {
+func (x *GameRules) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WaitingRoomRequest) ProtoMessage() {}
+func (*GameRules) ProtoMessage() {}
 
-func (x *WaitingRoomRequest) ProtoReflect() protoreflect.Message {
+func (x *GameRules) ProtoReflect() protoreflect.Message {
 	mi := &file_pong_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -721,32 +1325,47 @@ func (x *WaitingRoomRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WaitingRoomRequest.ProtoReflect.Descriptor instead.
-func (*WaitingRoomRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GameRules.ProtoReflect.Descriptor instead.
+func (*GameRules) Descriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{11}
 }
 
-type WaitingRoomResponse struct {
+func (x *GameRules) GetMaxScore() int32 {
+	if x != nil {
+		return x.MaxScore
+	}
+	return 0
+}
+
+func (x *GameRules) GetBestOf() int32 {
+	if x != nil {
+		return x.BestOf
+	}
+	return 0
+}
+
+type SeriesScore struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Players       []*Player              `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Wins          int32                  `protobuf:"varint,2,opt,name=wins,proto3" json:"wins,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WaitingRoomResponse) Reset() {
-	*x = WaitingRoomResponse{}
+func (x *SeriesScore) Reset() {
+	*x = SeriesScore{}
 	mi := &file_pong_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WaitingRoomResponse) String() string {
+func (x *SeriesScore) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WaitingRoomResponse) ProtoMessage() {}
+func (*SeriesScore) ProtoMessage() {}
 
-func (x *WaitingRoomResponse) ProtoReflect() protoreflect.Message {
+func (x *SeriesScore) ProtoReflect() protoreflect.Message {
 	mi := &file_pong_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -758,45 +1377,51 @@ func (x *WaitingRoomResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WaitingRoomResponse.ProtoReflect.Descriptor instead.
-func (*WaitingRoomResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SeriesScore.ProtoReflect.Descriptor instead.
+func (*SeriesScore) Descriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *WaitingRoomResponse) GetPlayers() []*Player {
+func (x *SeriesScore) GetPlayerId() string {
 	if x != nil {
-		return x.Players
+		return x.PlayerId
 	}
-	return nil
+	return ""
 }
 
-// Game Messages
-type Player struct {
+func (x *SeriesScore) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+type SeriesState struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Uid           string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
-	Nick          string                 `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"`
-	BetAmt        int64                  `protobuf:"varint,3,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"`
-	Number        int32                  `protobuf:"varint,4,opt,name=number,proto3" json:"number,omitempty"`
-	Score         int32                  `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
-	Ready         bool                   `protobuf:"varint,6,opt,name=ready,proto3" json:"ready,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BestOf        int32                  `protobuf:"varint,2,opt,name=best_of,json=bestOf,proto3" json:"best_of,omitempty"`
+	GamesPlayed   int32                  `protobuf:"varint,3,opt,name=games_played,json=gamesPlayed,proto3" json:"games_played,omitempty"`
+	Scores        []*SeriesScore         `protobuf:"bytes,4,rep,name=scores,proto3" json:"scores,omitempty"`
+	WinnerId      string                 `protobuf:"bytes,5,opt,name=winner_id,json=winnerId,proto3" json:"winner_id,omitempty"`          // set once the series is decided
+	NextGameAt    int64                  `protobuf:"varint,6,opt,name=next_game_at,json=nextGameAt,proto3" json:"next_game_at,omitempty"` // unix seconds, set during breaks
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Player) Reset() {
-	*x = Player{}
+func (x *SeriesState) Reset() {
+	*x = SeriesState{}
 	mi := &file_pong_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Player) String() string {
+func (x *SeriesState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Player) ProtoMessage() {}
+func (*SeriesState) ProtoMessage() {}
 
-func (x *Player) ProtoReflect() protoreflect.Message {
+func (x *SeriesState) ProtoReflect() protoreflect.Message {
 	mi := &file_pong_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -808,75 +1433,76 @@ func (x *Player) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Player.ProtoReflect.Descriptor instead.
-func (*Player) Descriptor() ([]byte, []int) {
+// Deprecated: Use SeriesState.ProtoReflect.Descriptor instead.
+func (*SeriesState) Descriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *Player) GetUid() string {
+func (x *SeriesState) GetId() string {
 	if x != nil {
-		return x.Uid
+		return x.Id
 	}
 	return ""
 }
 
-func (x *Player) GetNick() string {
+func (x *SeriesState) GetBestOf() int32 {
 	if x != nil {
-		return x.Nick
+		return x.BestOf
 	}
-	return ""
+	return 0
 }
 
-func (x *Player) GetBetAmt() int64 {
+func (x *SeriesState) GetGamesPlayed() int32 {
 	if x != nil {
-		return x.BetAmt
+		return x.GamesPlayed
 	}
 	return 0
 }
 
-func (x *Player) GetNumber() int32 {
+func (x *SeriesState) GetScores() []*SeriesScore {
 	if x != nil {
-		return x.Number
+		return x.Scores
 	}
-	return 0
+	return nil
 }
 
-func (x *Player) GetScore() int32 {
+func (x *SeriesState) GetWinnerId() string {
 	if x != nil {
-		return x.Score
+		return x.WinnerId
 	}
-	return 0
+	return ""
 }
 
-func (x *Player) GetReady() bool {
+func (x *SeriesState) GetNextGameAt() int64 {
 	if x != nil {
-		return x.Ready
+		return x.NextGameAt
 	}
-	return false
+	return 0
 }
 
-// SignalReadyRequest contains information about the client signaling readiness
-type StartGameStreamRequest struct {
+type InviteToWaitingRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"` // must be the room host
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Invitees      []string               `protobuf:"bytes,3,rep,name=invitees,proto3" json:"invitees,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StartGameStreamRequest) Reset() {
-	*x = StartGameStreamRequest{}
+func (x *InviteToWaitingRoomRequest) Reset() {
+	*x = InviteToWaitingRoomRequest{}
 	mi := &file_pong_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartGameStreamRequest) String() string {
+func (x *InviteToWaitingRoomRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartGameStreamRequest) ProtoMessage() {}
+func (*InviteToWaitingRoomRequest) ProtoMessage() {}
 
-func (x *StartGameStreamRequest) ProtoReflect() protoreflect.Message {
+func (x *InviteToWaitingRoomRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pong_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -888,39 +1514,53 @@ func (x *StartGameStreamRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartGameStreamRequest.ProtoReflect.Descriptor instead.
-func (*StartGameStreamRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use InviteToWaitingRoomRequest.ProtoReflect.Descriptor instead.
+func (*InviteToWaitingRoomRequest) Descriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *StartGameStreamRequest) GetClientId() string {
+func (x *InviteToWaitingRoomRequest) GetClientId() string {
 	if x != nil {
 		return x.ClientId
 	}
 	return ""
 }
 
-type GameUpdateBytes struct {
+func (x *InviteToWaitingRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *InviteToWaitingRoomRequest) GetInvitees() []string {
+	if x != nil {
+		return x.Invitees
+	}
+	return nil
+}
+
+type InviteToWaitingRoomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	InviteCode    string                 `protobuf:"bytes,1,opt,name=invite_code,json=inviteCode,proto3" json:"invite_code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GameUpdateBytes) Reset() {
-	*x = GameUpdateBytes{}
+func (x *InviteToWaitingRoomResponse) Reset() {
+	*x = InviteToWaitingRoomResponse{}
 	mi := &file_pong_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GameUpdateBytes) String() string {
+func (x *InviteToWaitingRoomResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GameUpdateBytes) ProtoMessage() {}
+func (*InviteToWaitingRoomResponse) ProtoMessage() {}
 
-func (x *GameUpdateBytes) ProtoReflect() protoreflect.Message {
+func (x *InviteToWaitingRoomResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pong_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -932,41 +1572,38 @@ func (x *GameUpdateBytes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GameUpdateBytes.ProtoReflect.Descriptor instead.
-func (*GameUpdateBytes) Descriptor() ([]byte, []int) {
+// Deprecated: Use InviteToWaitingRoomResponse.ProtoReflect.Descriptor instead.
+func (*InviteToWaitingRoomResponse) Descriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *GameUpdateBytes) GetData() []byte {
+func (x *InviteToWaitingRoomResponse) GetInviteCode() string {
 	if x != nil {
-		return x.Data
+		return x.InviteCode
 	}
-	return nil
+	return ""
 }
 
-type PlayerInput struct {
+type WaitingRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
-	Input         string                 `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`                                    // e.g., "ArrowUp", "ArrowDown"
-	PlayerNumber  int32                  `protobuf:"varint,3,opt,name=player_number,json=playerNumber,proto3" json:"player_number,omitempty"` // player 1 or player 2.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PlayerInput) Reset() {
-	*x = PlayerInput{}
+func (x *WaitingRoomRequest) Reset() {
+	*x = WaitingRoomRequest{}
 	mi := &file_pong_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlayerInput) String() string {
+func (x *WaitingRoomRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlayerInput) ProtoMessage() {}
+func (*WaitingRoomRequest) ProtoMessage() {}
 
-func (x *PlayerInput) ProtoReflect() protoreflect.Message {
+func (x *WaitingRoomRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_pong_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -978,77 +1615,32 @@ func (x *PlayerInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlayerInput.ProtoReflect.Descriptor instead.
-func (*PlayerInput) Descriptor() ([]byte, []int) {
+// Deprecated: Use WaitingRoomRequest.ProtoReflect.Descriptor instead.
+func (*WaitingRoomRequest) Descriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *PlayerInput) GetPlayerId() string {
-	if x != nil {
-		return x.PlayerId
-	}
-	return ""
-}
-
-func (x *PlayerInput) GetInput() string {
-	if x != nil {
-		return x.Input
-	}
-	return ""
-}
-
-func (x *PlayerInput) GetPlayerNumber() int32 {
-	if x != nil {
-		return x.PlayerNumber
-	}
-	return 0
-}
-
-type GameUpdate struct {
+type WaitingRoomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	GameWidth     float64                `protobuf:"fixed64,13,opt,name=gameWidth,proto3" json:"gameWidth,omitempty"`
-	GameHeight    float64                `protobuf:"fixed64,14,opt,name=gameHeight,proto3" json:"gameHeight,omitempty"`
-	P1Width       float64                `protobuf:"fixed64,15,opt,name=p1Width,proto3" json:"p1Width,omitempty"`
-	P1Height      float64                `protobuf:"fixed64,16,opt,name=p1Height,proto3" json:"p1Height,omitempty"`
-	P2Width       float64                `protobuf:"fixed64,17,opt,name=p2Width,proto3" json:"p2Width,omitempty"`
-	P2Height      float64                `protobuf:"fixed64,18,opt,name=p2Height,proto3" json:"p2Height,omitempty"`
-	BallWidth     float64                `protobuf:"fixed64,19,opt,name=ballWidth,proto3" json:"ballWidth,omitempty"`
-	BallHeight    float64                `protobuf:"fixed64,20,opt,name=ballHeight,proto3" json:"ballHeight,omitempty"`
-	P1Score       int32                  `protobuf:"varint,21,opt,name=p1Score,proto3" json:"p1Score,omitempty"`
-	P2Score       int32                  `protobuf:"varint,22,opt,name=p2Score,proto3" json:"p2Score,omitempty"`
-	BallX         float64                `protobuf:"fixed64,1,opt,name=ballX,proto3" json:"ballX,omitempty"`
-	BallY         float64                `protobuf:"fixed64,2,opt,name=ballY,proto3" json:"ballY,omitempty"`
-	P1X           float64                `protobuf:"fixed64,3,opt,name=p1X,proto3" json:"p1X,omitempty"`
-	P1Y           float64                `protobuf:"fixed64,4,opt,name=p1Y,proto3" json:"p1Y,omitempty"`
-	P2X           float64                `protobuf:"fixed64,5,opt,name=p2X,proto3" json:"p2X,omitempty"`
-	P2Y           float64                `protobuf:"fixed64,6,opt,name=p2Y,proto3" json:"p2Y,omitempty"`
-	P1YVelocity   float64                `protobuf:"fixed64,7,opt,name=p1YVelocity,proto3" json:"p1YVelocity,omitempty"`
-	P2YVelocity   float64                `protobuf:"fixed64,8,opt,name=p2YVelocity,proto3" json:"p2YVelocity,omitempty"`
-	BallXVelocity float64                `protobuf:"fixed64,9,opt,name=ballXVelocity,proto3" json:"ballXVelocity,omitempty"`
-	BallYVelocity float64                `protobuf:"fixed64,10,opt,name=ballYVelocity,proto3" json:"ballYVelocity,omitempty"`
-	Fps           float64                `protobuf:"fixed64,11,opt,name=fps,proto3" json:"fps,omitempty"`
-	Tps           float64                `protobuf:"fixed64,12,opt,name=tps,proto3" json:"tps,omitempty"`
-	// Optional: if you want to send error messages or debug information
-	Error         string `protobuf:"bytes,23,opt,name=error,proto3" json:"error,omitempty"`
-	Debug         bool   `protobuf:"varint,24,opt,name=debug,proto3" json:"debug,omitempty"`
+	Players       []*Player              `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GameUpdate) Reset() {
-	*x = GameUpdate{}
+func (x *WaitingRoomResponse) Reset() {
+	*x = WaitingRoomResponse{}
 	mi := &file_pong_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GameUpdate) String() string {
+func (x *WaitingRoomResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GameUpdate) ProtoMessage() {}
+func (*WaitingRoomResponse) ProtoMessage() {}
 
-func (x *GameUpdate) ProtoReflect() protoreflect.Message {
+func (x *WaitingRoomResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pong_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1060,202 +1652,3560 @@ func (x *GameUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GameUpdate.ProtoReflect.Descriptor instead.
-func (*GameUpdate) Descriptor() ([]byte, []int) {
+// Deprecated: Use WaitingRoomResponse.ProtoReflect.Descriptor instead.
+func (*WaitingRoomResponse) Descriptor() ([]byte, []int) {
 	return file_pong_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *GameUpdate) GetGameWidth() float64 {
+func (x *WaitingRoomResponse) GetPlayers() []*Player {
 	if x != nil {
-		return x.GameWidth
+		return x.Players
 	}
-	return 0
+	return nil
 }
 
-func (x *GameUpdate) GetGameHeight() float64 {
-	if x != nil {
-		return x.GameHeight
-	}
-	return 0
+// Game Messages
+type Player struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Nick          string                 `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"`
+	BetAmt        int64                  `protobuf:"varint,3,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"`
+	Number        int32                  `protobuf:"varint,4,opt,name=number,proto3" json:"number,omitempty"`
+	Score         int32                  `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
+	Ready         bool                   `protobuf:"varint,6,opt,name=ready,proto3" json:"ready,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GameUpdate) GetP1Width() float64 {
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_pong_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Player) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Player) GetNick() string {
+	if x != nil {
+		return x.Nick
+	}
+	return ""
+}
+
+func (x *Player) GetBetAmt() int64 {
+	if x != nil {
+		return x.BetAmt
+	}
+	return 0
+}
+
+func (x *Player) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Player) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Player) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+// SignalReadyRequest contains information about the client signaling readiness
+type StartGameStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartGameStreamRequest) Reset() {
+	*x = StartGameStreamRequest{}
+	mi := &file_pong_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartGameStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartGameStreamRequest) ProtoMessage() {}
+
+func (x *StartGameStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartGameStreamRequest.ProtoReflect.Descriptor instead.
+func (*StartGameStreamRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StartGameStreamRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type GameUpdateBytes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameUpdateBytes) Reset() {
+	*x = GameUpdateBytes{}
+	mi := &file_pong_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameUpdateBytes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameUpdateBytes) ProtoMessage() {}
+
+func (x *GameUpdateBytes) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameUpdateBytes.ProtoReflect.Descriptor instead.
+func (*GameUpdateBytes) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GameUpdateBytes) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type PlayerInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Input         string                 `protobuf:"bytes,2,opt,name=input,proto3" json:"input,omitempty"`                                    // e.g., "ArrowUp", "ArrowDown"
+	PlayerNumber  int32                  `protobuf:"varint,3,opt,name=player_number,json=playerNumber,proto3" json:"player_number,omitempty"` // player 1 or player 2.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerInput) Reset() {
+	*x = PlayerInput{}
+	mi := &file_pong_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerInput) ProtoMessage() {}
+
+func (x *PlayerInput) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerInput.ProtoReflect.Descriptor instead.
+func (*PlayerInput) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PlayerInput) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *PlayerInput) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *PlayerInput) GetPlayerNumber() int32 {
+	if x != nil {
+		return x.PlayerNumber
+	}
+	return 0
+}
+
+type GameUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameWidth     float64                `protobuf:"fixed64,13,opt,name=gameWidth,proto3" json:"gameWidth,omitempty"`
+	GameHeight    float64                `protobuf:"fixed64,14,opt,name=gameHeight,proto3" json:"gameHeight,omitempty"`
+	P1Width       float64                `protobuf:"fixed64,15,opt,name=p1Width,proto3" json:"p1Width,omitempty"`
+	P1Height      float64                `protobuf:"fixed64,16,opt,name=p1Height,proto3" json:"p1Height,omitempty"`
+	P2Width       float64                `protobuf:"fixed64,17,opt,name=p2Width,proto3" json:"p2Width,omitempty"`
+	P2Height      float64                `protobuf:"fixed64,18,opt,name=p2Height,proto3" json:"p2Height,omitempty"`
+	BallWidth     float64                `protobuf:"fixed64,19,opt,name=ballWidth,proto3" json:"ballWidth,omitempty"`
+	BallHeight    float64                `protobuf:"fixed64,20,opt,name=ballHeight,proto3" json:"ballHeight,omitempty"`
+	P1Score       int32                  `protobuf:"varint,21,opt,name=p1Score,proto3" json:"p1Score,omitempty"`
+	P2Score       int32                  `protobuf:"varint,22,opt,name=p2Score,proto3" json:"p2Score,omitempty"`
+	BallX         float64                `protobuf:"fixed64,1,opt,name=ballX,proto3" json:"ballX,omitempty"`
+	BallY         float64                `protobuf:"fixed64,2,opt,name=ballY,proto3" json:"ballY,omitempty"`
+	P1X           float64                `protobuf:"fixed64,3,opt,name=p1X,proto3" json:"p1X,omitempty"`
+	P1Y           float64                `protobuf:"fixed64,4,opt,name=p1Y,proto3" json:"p1Y,omitempty"`
+	P2X           float64                `protobuf:"fixed64,5,opt,name=p2X,proto3" json:"p2X,omitempty"`
+	P2Y           float64                `protobuf:"fixed64,6,opt,name=p2Y,proto3" json:"p2Y,omitempty"`
+	P1YVelocity   float64                `protobuf:"fixed64,7,opt,name=p1YVelocity,proto3" json:"p1YVelocity,omitempty"`
+	P2YVelocity   float64                `protobuf:"fixed64,8,opt,name=p2YVelocity,proto3" json:"p2YVelocity,omitempty"`
+	BallXVelocity float64                `protobuf:"fixed64,9,opt,name=ballXVelocity,proto3" json:"ballXVelocity,omitempty"`
+	BallYVelocity float64                `protobuf:"fixed64,10,opt,name=ballYVelocity,proto3" json:"ballYVelocity,omitempty"`
+	Fps           float64                `protobuf:"fixed64,11,opt,name=fps,proto3" json:"fps,omitempty"`
+	Tps           float64                `protobuf:"fixed64,12,opt,name=tps,proto3" json:"tps,omitempty"`
+	// Optional: if you want to send error messages or debug information
+	Error         string `protobuf:"bytes,23,opt,name=error,proto3" json:"error,omitempty"`
+	Debug         bool   `protobuf:"varint,24,opt,name=debug,proto3" json:"debug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameUpdate) Reset() {
+	*x = GameUpdate{}
+	mi := &file_pong_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameUpdate) ProtoMessage() {}
+
+func (x *GameUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameUpdate.ProtoReflect.Descriptor instead.
+func (*GameUpdate) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GameUpdate) GetGameWidth() float64 {
+	if x != nil {
+		return x.GameWidth
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetGameHeight() float64 {
+	if x != nil {
+		return x.GameHeight
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP1Width() float64 {
+	if x != nil {
+		return x.P1Width
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP1Height() float64 {
+	if x != nil {
+		return x.P1Height
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP2Width() float64 {
+	if x != nil {
+		return x.P2Width
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP2Height() float64 {
+	if x != nil {
+		return x.P2Height
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetBallWidth() float64 {
+	if x != nil {
+		return x.BallWidth
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetBallHeight() float64 {
+	if x != nil {
+		return x.BallHeight
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP1Score() int32 {
+	if x != nil {
+		return x.P1Score
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP2Score() int32 {
+	if x != nil {
+		return x.P2Score
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetBallX() float64 {
+	if x != nil {
+		return x.BallX
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetBallY() float64 {
+	if x != nil {
+		return x.BallY
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP1X() float64 {
+	if x != nil {
+		return x.P1X
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP1Y() float64 {
+	if x != nil {
+		return x.P1Y
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP2X() float64 {
+	if x != nil {
+		return x.P2X
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP2Y() float64 {
+	if x != nil {
+		return x.P2Y
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP1YVelocity() float64 {
+	if x != nil {
+		return x.P1YVelocity
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetP2YVelocity() float64 {
+	if x != nil {
+		return x.P2YVelocity
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetBallXVelocity() float64 {
+	if x != nil {
+		return x.BallXVelocity
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetBallYVelocity() float64 {
+	if x != nil {
+		return x.BallYVelocity
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetFps() float64 {
+	if x != nil {
+		return x.Fps
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetTps() float64 {
+	if x != nil {
+		return x.Tps
+	}
+	return 0
+}
+
+func (x *GameUpdate) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GameUpdate) GetDebug() bool {
+	if x != nil {
+		return x.Debug
+	}
+	return false
+}
+
+type LeaveWaitingRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveWaitingRoomRequest) Reset() {
+	*x = LeaveWaitingRoomRequest{}
+	mi := &file_pong_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveWaitingRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveWaitingRoomRequest) ProtoMessage() {}
+
+func (x *LeaveWaitingRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveWaitingRoomRequest.ProtoReflect.Descriptor instead.
+func (*LeaveWaitingRoomRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LeaveWaitingRoomRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *LeaveWaitingRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type LeaveWaitingRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveWaitingRoomResponse) Reset() {
+	*x = LeaveWaitingRoomResponse{}
+	mi := &file_pong_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveWaitingRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveWaitingRoomResponse) ProtoMessage() {}
+
+func (x *LeaveWaitingRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveWaitingRoomResponse.ProtoReflect.Descriptor instead.
+func (*LeaveWaitingRoomResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LeaveWaitingRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *LeaveWaitingRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// SignalReadyToPlayRequest contains information about the client signaling readiness
+type SignalReadyToPlayRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	GameId        string                 `protobuf:"bytes,2,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Entropy       []byte                 `protobuf:"bytes,3,opt,name=entropy,proto3" json:"entropy,omitempty"` // random bytes mixed into the serve seed, required for wagered games
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignalReadyToPlayRequest) Reset() {
+	*x = SignalReadyToPlayRequest{}
+	mi := &file_pong_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignalReadyToPlayRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignalReadyToPlayRequest) ProtoMessage() {}
+
+func (x *SignalReadyToPlayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignalReadyToPlayRequest.ProtoReflect.Descriptor instead.
+func (*SignalReadyToPlayRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SignalReadyToPlayRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *SignalReadyToPlayRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *SignalReadyToPlayRequest) GetEntropy() []byte {
+	if x != nil {
+		return x.Entropy
+	}
+	return nil
+}
+
+// SignalReadyToPlayResponse contains the result of the ready signal
+type SignalReadyToPlayResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignalReadyToPlayResponse) Reset() {
+	*x = SignalReadyToPlayResponse{}
+	mi := &file_pong_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignalReadyToPlayResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignalReadyToPlayResponse) ProtoMessage() {}
+
+func (x *SignalReadyToPlayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignalReadyToPlayResponse.ProtoReflect.Descriptor instead.
+func (*SignalReadyToPlayResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SignalReadyToPlayResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SignalReadyToPlayResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type LeaderboardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          LeaderboardKind        `protobuf:"varint,1,opt,name=kind,proto3,enum=pong.LeaderboardKind" json:"kind,omitempty"`
+	Window        LeaderboardWindow      `protobuf:"varint,2,opt,name=window,proto3,enum=pong.LeaderboardWindow" json:"window,omitempty"`
+	Season        uint32                 `protobuf:"varint,3,opt,name=season,proto3" json:"season,omitempty"` // 0 for the current season, otherwise an archived season
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaderboardRequest) Reset() {
+	*x = LeaderboardRequest{}
+	mi := &file_pong_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaderboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardRequest) ProtoMessage() {}
+
+func (x *LeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*LeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *LeaderboardRequest) GetKind() LeaderboardKind {
+	if x != nil {
+		return x.Kind
+	}
+	return LeaderboardKind_BY_RATING
+}
+
+func (x *LeaderboardRequest) GetWindow() LeaderboardWindow {
+	if x != nil {
+		return x.Window
+	}
+	return LeaderboardWindow_SEASON
+}
+
+func (x *LeaderboardRequest) GetSeason() uint32 {
+	if x != nil {
+		return x.Season
+	}
+	return 0
+}
+
+func (x *LeaderboardRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type LeaderboardEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Uid           string                 `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Nick          string                 `protobuf:"bytes,3,opt,name=nick,proto3" json:"nick,omitempty"`
+	Rating        float64                `protobuf:"fixed64,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	NetWinnings   int64                  `protobuf:"varint,5,opt,name=net_winnings,json=netWinnings,proto3" json:"net_winnings,omitempty"` // in matoms
+	BestStreak    int32                  `protobuf:"varint,6,opt,name=best_streak,json=bestStreak,proto3" json:"best_streak,omitempty"`
+	CurrentStreak int32                  `protobuf:"varint,7,opt,name=current_streak,json=currentStreak,proto3" json:"current_streak,omitempty"`
+	Wins          int32                  `protobuf:"varint,8,opt,name=wins,proto3" json:"wins,omitempty"`
+	Losses        int32                  `protobuf:"varint,9,opt,name=losses,proto3" json:"losses,omitempty"`
+	Credits       int64                  `protobuf:"varint,10,opt,name=credits,proto3" json:"credits,omitempty"` // in matoms, only on BY_CREDITS leaderboards
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaderboardEntry) Reset() {
+	*x = LeaderboardEntry{}
+	mi := &file_pong_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaderboardEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardEntry) ProtoMessage() {}
+
+func (x *LeaderboardEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardEntry.ProtoReflect.Descriptor instead.
+func (*LeaderboardEntry) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *LeaderboardEntry) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *LeaderboardEntry) GetNick() string {
+	if x != nil {
+		return x.Nick
+	}
+	return ""
+}
+
+func (x *LeaderboardEntry) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetNetWinnings() int64 {
+	if x != nil {
+		return x.NetWinnings
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetBestStreak() int32 {
+	if x != nil {
+		return x.BestStreak
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetCurrentStreak() int32 {
+	if x != nil {
+		return x.CurrentStreak
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
+	}
+	return 0
+}
+
+func (x *LeaderboardEntry) GetCredits() int64 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+type LeaderboardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          LeaderboardKind        `protobuf:"varint,1,opt,name=kind,proto3,enum=pong.LeaderboardKind" json:"kind,omitempty"`
+	Window        LeaderboardWindow      `protobuf:"varint,2,opt,name=window,proto3,enum=pong.LeaderboardWindow" json:"window,omitempty"`
+	Season        uint32                 `protobuf:"varint,3,opt,name=season,proto3" json:"season,omitempty"`
+	WindowStart   int64                  `protobuf:"varint,4,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"` // unix seconds
+	Entries       []*LeaderboardEntry    `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaderboardResponse) Reset() {
+	*x = LeaderboardResponse{}
+	mi := &file_pong_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaderboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaderboardResponse) ProtoMessage() {}
+
+func (x *LeaderboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaderboardResponse.ProtoReflect.Descriptor instead.
+func (*LeaderboardResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *LeaderboardResponse) GetKind() LeaderboardKind {
+	if x != nil {
+		return x.Kind
+	}
+	return LeaderboardKind_BY_RATING
+}
+
+func (x *LeaderboardResponse) GetWindow() LeaderboardWindow {
+	if x != nil {
+		return x.Window
+	}
+	return LeaderboardWindow_SEASON
+}
+
+func (x *LeaderboardResponse) GetSeason() uint32 {
+	if x != nil {
+		return x.Season
+	}
+	return 0
+}
+
+func (x *LeaderboardResponse) GetWindowStart() int64 {
+	if x != nil {
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *LeaderboardResponse) GetEntries() []*LeaderboardEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type Challenge struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChallengerId   string                 `protobuf:"bytes,2,opt,name=challenger_id,json=challengerId,proto3" json:"challenger_id,omitempty"`
+	ChallengerNick string                 `protobuf:"bytes,3,opt,name=challenger_nick,json=challengerNick,proto3" json:"challenger_nick,omitempty"`
+	TargetId       string                 `protobuf:"bytes,4,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	BetAmt         int64                  `protobuf:"varint,5,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"`
+	Rules          *GameRules             `protobuf:"bytes,6,opt,name=rules,proto3" json:"rules,omitempty"`
+	ExpiresAt      int64                  `protobuf:"varint,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // unix seconds
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Challenge) Reset() {
+	*x = Challenge{}
+	mi := &file_pong_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Challenge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Challenge) ProtoMessage() {}
+
+func (x *Challenge) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Challenge.ProtoReflect.Descriptor instead.
+func (*Challenge) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Challenge) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Challenge) GetChallengerId() string {
+	if x != nil {
+		return x.ChallengerId
+	}
+	return ""
+}
+
+func (x *Challenge) GetChallengerNick() string {
+	if x != nil {
+		return x.ChallengerNick
+	}
+	return ""
+}
+
+func (x *Challenge) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *Challenge) GetBetAmt() int64 {
+	if x != nil {
+		return x.BetAmt
+	}
+	return 0
+}
+
+func (x *Challenge) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *Challenge) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type ChallengePlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"` // client id or nick of the challenged player
+	BetAmt        int64                  `protobuf:"varint,3,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"`
+	Rules         *GameRules             `protobuf:"bytes,4,opt,name=rules,proto3" json:"rules,omitempty"`
+	TimeoutSecs   int64                  `protobuf:"varint,5,opt,name=timeout_secs,json=timeoutSecs,proto3" json:"timeout_secs,omitempty"` // time to answer, server default when zero
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChallengePlayerRequest) Reset() {
+	*x = ChallengePlayerRequest{}
+	mi := &file_pong_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChallengePlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengePlayerRequest) ProtoMessage() {}
+
+func (x *ChallengePlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengePlayerRequest.ProtoReflect.Descriptor instead.
+func (*ChallengePlayerRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ChallengePlayerRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ChallengePlayerRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *ChallengePlayerRequest) GetBetAmt() int64 {
+	if x != nil {
+		return x.BetAmt
+	}
+	return 0
+}
+
+func (x *ChallengePlayerRequest) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *ChallengePlayerRequest) GetTimeoutSecs() int64 {
+	if x != nil {
+		return x.TimeoutSecs
+	}
+	return 0
+}
+
+type ChallengePlayerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Challenge     *Challenge             `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChallengePlayerResponse) Reset() {
+	*x = ChallengePlayerResponse{}
+	mi := &file_pong_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChallengePlayerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChallengePlayerResponse) ProtoMessage() {}
+
+func (x *ChallengePlayerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChallengePlayerResponse.ProtoReflect.Descriptor instead.
+func (*ChallengePlayerResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ChallengePlayerResponse) GetChallenge() *Challenge {
+	if x != nil {
+		return x.Challenge
+	}
+	return nil
+}
+
+type RespondChallengeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ChallengeId   string                 `protobuf:"bytes,2,opt,name=challenge_id,json=challengeId,proto3" json:"challenge_id,omitempty"`
+	Accept        bool                   `protobuf:"varint,3,opt,name=accept,proto3" json:"accept,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondChallengeRequest) Reset() {
+	*x = RespondChallengeRequest{}
+	mi := &file_pong_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondChallengeRequest) ProtoMessage() {}
+
+func (x *RespondChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondChallengeRequest.ProtoReflect.Descriptor instead.
+func (*RespondChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RespondChallengeRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *RespondChallengeRequest) GetChallengeId() string {
+	if x != nil {
+		return x.ChallengeId
+	}
+	return ""
+}
+
+func (x *RespondChallengeRequest) GetAccept() bool {
+	if x != nil {
+		return x.Accept
+	}
+	return false
+}
+
+type RespondChallengeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"` // set when the challenge was accepted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondChallengeResponse) Reset() {
+	*x = RespondChallengeResponse{}
+	mi := &file_pong_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondChallengeResponse) ProtoMessage() {}
+
+func (x *RespondChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondChallengeResponse.ProtoReflect.Descriptor instead.
+func (*RespondChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RespondChallengeResponse) GetWr() *WaitingRoom {
+	if x != nil {
+		return x.Wr
+	}
+	return nil
+}
+
+type Rematch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlayerIds     []string               `protobuf:"bytes,2,rep,name=player_ids,json=playerIds,proto3" json:"player_ids,omitempty"`
+	BetAmt        int64                  `protobuf:"varint,3,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"` // stake of the last game, or the proposed one
+	Rules         *GameRules             `protobuf:"bytes,4,opt,name=rules,proto3" json:"rules,omitempty"`
+	ProposerId    string                 `protobuf:"bytes,5,opt,name=proposer_id,json=proposerId,proto3" json:"proposer_id,omitempty"` // empty until a player proposes
+	ExpiresAt     int64                  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`   // unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rematch) Reset() {
+	*x = Rematch{}
+	mi := &file_pong_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rematch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rematch) ProtoMessage() {}
+
+func (x *Rematch) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rematch.ProtoReflect.Descriptor instead.
+func (*Rematch) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Rematch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Rematch) GetPlayerIds() []string {
+	if x != nil {
+		return x.PlayerIds
+	}
+	return nil
+}
+
+func (x *Rematch) GetBetAmt() int64 {
+	if x != nil {
+		return x.BetAmt
+	}
+	return 0
+}
+
+func (x *Rematch) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *Rematch) GetProposerId() string {
+	if x != nil {
+		return x.ProposerId
+	}
+	return ""
+}
+
+func (x *Rematch) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type ProposeRematchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	BetAmt        int64                  `protobuf:"varint,2,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"` // zero keeps the stake of the last game
+	Rules         *GameRules             `protobuf:"bytes,3,opt,name=rules,proto3" json:"rules,omitempty"`                  // unset keeps the rules of the last game
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposeRematchRequest) Reset() {
+	*x = ProposeRematchRequest{}
+	mi := &file_pong_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposeRematchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposeRematchRequest) ProtoMessage() {}
+
+func (x *ProposeRematchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposeRematchRequest.ProtoReflect.Descriptor instead.
+func (*ProposeRematchRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ProposeRematchRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ProposeRematchRequest) GetBetAmt() int64 {
+	if x != nil {
+		return x.BetAmt
+	}
+	return 0
+}
+
+func (x *ProposeRematchRequest) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ProposeRematchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rematch       *Rematch               `protobuf:"bytes,1,opt,name=rematch,proto3" json:"rematch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposeRematchResponse) Reset() {
+	*x = ProposeRematchResponse{}
+	mi := &file_pong_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposeRematchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposeRematchResponse) ProtoMessage() {}
+
+func (x *ProposeRematchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposeRematchResponse.ProtoReflect.Descriptor instead.
+func (*ProposeRematchResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ProposeRematchResponse) GetRematch() *Rematch {
+	if x != nil {
+		return x.Rematch
+	}
+	return nil
+}
+
+type RespondRematchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Accept        bool                   `protobuf:"varint,2,opt,name=accept,proto3" json:"accept,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondRematchRequest) Reset() {
+	*x = RespondRematchRequest{}
+	mi := &file_pong_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondRematchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondRematchRequest) ProtoMessage() {}
+
+func (x *RespondRematchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondRematchRequest.ProtoReflect.Descriptor instead.
+func (*RespondRematchRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RespondRematchRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *RespondRematchRequest) GetAccept() bool {
+	if x != nil {
+		return x.Accept
+	}
+	return false
+}
+
+type RespondRematchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"` // set when the rematch was accepted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondRematchResponse) Reset() {
+	*x = RespondRematchResponse{}
+	mi := &file_pong_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondRematchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondRematchResponse) ProtoMessage() {}
+
+func (x *RespondRematchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondRematchResponse.ProtoReflect.Descriptor instead.
+func (*RespondRematchResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RespondRematchResponse) GetWr() *WaitingRoom {
+	if x != nil {
+		return x.Wr
+	}
+	return nil
+}
+
+type TournamentPlayer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Nick          string                 `protobuf:"bytes,2,opt,name=nick,proto3" json:"nick,omitempty"`
+	Seed          int32                  `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	Losses        int32                  `protobuf:"varint,4,opt,name=losses,proto3" json:"losses,omitempty"`
+	Eliminated    bool                   `protobuf:"varint,5,opt,name=eliminated,proto3" json:"eliminated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TournamentPlayer) Reset() {
+	*x = TournamentPlayer{}
+	mi := &file_pong_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TournamentPlayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TournamentPlayer) ProtoMessage() {}
+
+func (x *TournamentPlayer) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TournamentPlayer.ProtoReflect.Descriptor instead.
+func (*TournamentPlayer) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *TournamentPlayer) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *TournamentPlayer) GetNick() string {
+	if x != nil {
+		return x.Nick
+	}
+	return ""
+}
+
+func (x *TournamentPlayer) GetSeed() int32 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *TournamentPlayer) GetLosses() int32 {
+	if x != nil {
+		return x.Losses
+	}
+	return 0
+}
+
+func (x *TournamentPlayer) GetEliminated() bool {
+	if x != nil {
+		return x.Eliminated
+	}
+	return false
+}
+
+type TournamentMatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Round         int32                  `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	Pool          int32                  `protobuf:"varint,3,opt,name=pool,proto3" json:"pool,omitempty"` // losses of its players: 0 winners bracket, 1 losers bracket
+	PlayerIds     []string               `protobuf:"bytes,4,rep,name=player_ids,json=playerIds,proto3" json:"player_ids,omitempty"`
+	WinnerId      string                 `protobuf:"bytes,5,opt,name=winner_id,json=winnerId,proto3" json:"winner_id,omitempty"`
+	Bye           bool                   `protobuf:"varint,6,opt,name=bye,proto3" json:"bye,omitempty"`
+	Forfeit       bool                   `protobuf:"varint,7,opt,name=forfeit,proto3" json:"forfeit,omitempty"`            // decided by a no-show
+	RoomId        string                 `protobuf:"bytes,8,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // waiting room of a scheduled match
+	Deadline      int64                  `protobuf:"varint,9,opt,name=deadline,proto3" json:"deadline,omitempty"`          // unix seconds players have to show up
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TournamentMatch) Reset() {
+	*x = TournamentMatch{}
+	mi := &file_pong_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TournamentMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TournamentMatch) ProtoMessage() {}
+
+func (x *TournamentMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TournamentMatch.ProtoReflect.Descriptor instead.
+func (*TournamentMatch) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *TournamentMatch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TournamentMatch) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *TournamentMatch) GetPool() int32 {
+	if x != nil {
+		return x.Pool
+	}
+	return 0
+}
+
+func (x *TournamentMatch) GetPlayerIds() []string {
+	if x != nil {
+		return x.PlayerIds
+	}
+	return nil
+}
+
+func (x *TournamentMatch) GetWinnerId() string {
+	if x != nil {
+		return x.WinnerId
+	}
+	return ""
+}
+
+func (x *TournamentMatch) GetBye() bool {
+	if x != nil {
+		return x.Bye
+	}
+	return false
+}
+
+func (x *TournamentMatch) GetForfeit() bool {
+	if x != nil {
+		return x.Forfeit
+	}
+	return false
+}
+
+func (x *TournamentMatch) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *TournamentMatch) GetDeadline() int64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+type Tournament struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Format        TournamentFormat       `protobuf:"varint,3,opt,name=format,proto3,enum=pong.TournamentFormat" json:"format,omitempty"`
+	State         TournamentState        `protobuf:"varint,4,opt,name=state,proto3,enum=pong.TournamentState" json:"state,omitempty"`
+	BuyIn         int64                  `protobuf:"varint,5,opt,name=buy_in,json=buyIn,proto3" json:"buy_in,omitempty"`
+	MaxPlayers    int32                  `protobuf:"varint,6,opt,name=max_players,json=maxPlayers,proto3" json:"max_players,omitempty"`
+	StartsAt      int64                  `protobuf:"varint,7,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`              // unix seconds
+	PrizeSplit    []uint32               `protobuf:"varint,8,rep,packed,name=prize_split,json=prizeSplit,proto3" json:"prize_split,omitempty"` // percent of the pool per placement
+	Rules         *GameRules             `protobuf:"bytes,9,opt,name=rules,proto3" json:"rules,omitempty"`
+	PrizePool     int64                  `protobuf:"varint,10,opt,name=prize_pool,json=prizePool,proto3" json:"prize_pool,omitempty"`
+	Players       []*TournamentPlayer    `protobuf:"bytes,11,rep,name=players,proto3" json:"players,omitempty"`
+	Matches       []*TournamentMatch     `protobuf:"bytes,12,rep,name=matches,proto3" json:"matches,omitempty"`
+	Placements    []string               `protobuf:"bytes,13,rep,name=placements,proto3" json:"placements,omitempty"` // player ids, champion first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tournament) Reset() {
+	*x = Tournament{}
+	mi := &file_pong_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tournament) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tournament) ProtoMessage() {}
+
+func (x *Tournament) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tournament.ProtoReflect.Descriptor instead.
+func (*Tournament) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *Tournament) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tournament) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tournament) GetFormat() TournamentFormat {
+	if x != nil {
+		return x.Format
+	}
+	return TournamentFormat_SINGLE_ELIMINATION
+}
+
+func (x *Tournament) GetState() TournamentState {
+	if x != nil {
+		return x.State
+	}
+	return TournamentState_TOURNAMENT_REGISTRATION
+}
+
+func (x *Tournament) GetBuyIn() int64 {
+	if x != nil {
+		return x.BuyIn
+	}
+	return 0
+}
+
+func (x *Tournament) GetMaxPlayers() int32 {
+	if x != nil {
+		return x.MaxPlayers
+	}
+	return 0
+}
+
+func (x *Tournament) GetStartsAt() int64 {
+	if x != nil {
+		return x.StartsAt
+	}
+	return 0
+}
+
+func (x *Tournament) GetPrizeSplit() []uint32 {
+	if x != nil {
+		return x.PrizeSplit
+	}
+	return nil
+}
+
+func (x *Tournament) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *Tournament) GetPrizePool() int64 {
+	if x != nil {
+		return x.PrizePool
+	}
+	return 0
+}
+
+func (x *Tournament) GetPlayers() []*TournamentPlayer {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *Tournament) GetMatches() []*TournamentMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *Tournament) GetPlacements() []string {
+	if x != nil {
+		return x.Placements
+	}
+	return nil
+}
+
+type ListTournamentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTournamentsRequest) Reset() {
+	*x = ListTournamentsRequest{}
+	mi := &file_pong_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTournamentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTournamentsRequest) ProtoMessage() {}
+
+func (x *ListTournamentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTournamentsRequest.ProtoReflect.Descriptor instead.
+func (*ListTournamentsRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{43}
+}
+
+type ListTournamentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tournaments   []*Tournament          `protobuf:"bytes,1,rep,name=tournaments,proto3" json:"tournaments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTournamentsResponse) Reset() {
+	*x = ListTournamentsResponse{}
+	mi := &file_pong_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTournamentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTournamentsResponse) ProtoMessage() {}
+
+func (x *ListTournamentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTournamentsResponse.ProtoReflect.Descriptor instead.
+func (*ListTournamentsResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListTournamentsResponse) GetTournaments() []*Tournament {
+	if x != nil {
+		return x.Tournaments
+	}
+	return nil
+}
+
+type GetTournamentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TournamentId  string                 `protobuf:"bytes,1,opt,name=tournament_id,json=tournamentId,proto3" json:"tournament_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTournamentRequest) Reset() {
+	*x = GetTournamentRequest{}
+	mi := &file_pong_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTournamentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTournamentRequest) ProtoMessage() {}
+
+func (x *GetTournamentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTournamentRequest.ProtoReflect.Descriptor instead.
+func (*GetTournamentRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetTournamentRequest) GetTournamentId() string {
+	if x != nil {
+		return x.TournamentId
+	}
+	return ""
+}
+
+type GetTournamentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tournament    *Tournament            `protobuf:"bytes,1,opt,name=tournament,proto3" json:"tournament,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTournamentResponse) Reset() {
+	*x = GetTournamentResponse{}
+	mi := &file_pong_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTournamentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTournamentResponse) ProtoMessage() {}
+
+func (x *GetTournamentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTournamentResponse.ProtoReflect.Descriptor instead.
+func (*GetTournamentResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetTournamentResponse) GetTournament() *Tournament {
+	if x != nil {
+		return x.Tournament
+	}
+	return nil
+}
+
+type RegisterTournamentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	TournamentId  string                 `protobuf:"bytes,2,opt,name=tournament_id,json=tournamentId,proto3" json:"tournament_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterTournamentRequest) Reset() {
+	*x = RegisterTournamentRequest{}
+	mi := &file_pong_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterTournamentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterTournamentRequest) ProtoMessage() {}
+
+func (x *RegisterTournamentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterTournamentRequest.ProtoReflect.Descriptor instead.
+func (*RegisterTournamentRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RegisterTournamentRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *RegisterTournamentRequest) GetTournamentId() string {
+	if x != nil {
+		return x.TournamentId
+	}
+	return ""
+}
+
+type RegisterTournamentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tournament    *Tournament            `protobuf:"bytes,1,opt,name=tournament,proto3" json:"tournament,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterTournamentResponse) Reset() {
+	*x = RegisterTournamentResponse{}
+	mi := &file_pong_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterTournamentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterTournamentResponse) ProtoMessage() {}
+
+func (x *RegisterTournamentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterTournamentResponse.ProtoReflect.Descriptor instead.
+func (*RegisterTournamentResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *RegisterTournamentResponse) GetTournament() *Tournament {
+	if x != nil {
+		return x.Tournament
+	}
+	return nil
+}
+
+type UnregisterTournamentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	TournamentId  string                 `protobuf:"bytes,2,opt,name=tournament_id,json=tournamentId,proto3" json:"tournament_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterTournamentRequest) Reset() {
+	*x = UnregisterTournamentRequest{}
+	mi := &file_pong_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterTournamentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterTournamentRequest) ProtoMessage() {}
+
+func (x *UnregisterTournamentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterTournamentRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterTournamentRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *UnregisterTournamentRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *UnregisterTournamentRequest) GetTournamentId() string {
+	if x != nil {
+		return x.TournamentId
+	}
+	return ""
+}
+
+type UnregisterTournamentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterTournamentResponse) Reset() {
+	*x = UnregisterTournamentResponse{}
+	mi := &file_pong_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterTournamentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterTournamentResponse) ProtoMessage() {}
+
+func (x *UnregisterTournamentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterTournamentResponse.ProtoReflect.Descriptor instead.
+func (*UnregisterTournamentResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{50}
+}
+
+type ChatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SenderId      string                 `protobuf:"bytes,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	SenderNick    string                 `protobuf:"bytes,2,opt,name=sender_nick,json=senderNick,proto3" json:"sender_nick,omitempty"`
+	RoomId        string                 `protobuf:"bytes,3,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // set for waiting room chat
+	GameId        string                 `protobuf:"bytes,4,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"` // set for in-game chat
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // unix seconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_pong_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ChatMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetSenderNick() string {
+	if x != nil {
+		return x.SenderNick
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// SendChatMessageRequest sends a message to the players of a waiting room or
+// game. When both ids are empty it goes to the sender's current room or game.
+type SendChatMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	GameId        string                 `protobuf:"bytes,3,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendChatMessageRequest) Reset() {
+	*x = SendChatMessageRequest{}
+	mi := &file_pong_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendChatMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendChatMessageRequest) ProtoMessage() {}
+
+func (x *SendChatMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendChatMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendChatMessageRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SendChatMessageRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *SendChatMessageRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SendChatMessageRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *SendChatMessageRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type SendChatMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chat          *ChatMessage           `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendChatMessageResponse) Reset() {
+	*x = SendChatMessageResponse{}
+	mi := &file_pong_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendChatMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendChatMessageResponse) ProtoMessage() {}
+
+func (x *SendChatMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendChatMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendChatMessageResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SendChatMessageResponse) GetChat() *ChatMessage {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+type MutePlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Mute          bool                   `protobuf:"varint,3,opt,name=mute,proto3" json:"mute,omitempty"` // false unmutes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MutePlayerRequest) Reset() {
+	*x = MutePlayerRequest{}
+	mi := &file_pong_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MutePlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MutePlayerRequest) ProtoMessage() {}
+
+func (x *MutePlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MutePlayerRequest.ProtoReflect.Descriptor instead.
+func (*MutePlayerRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *MutePlayerRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *MutePlayerRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *MutePlayerRequest) GetMute() bool {
+	if x != nil {
+		return x.Mute
+	}
+	return false
+}
+
+type MutePlayerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Muted         []string               `protobuf:"bytes,1,rep,name=muted,proto3" json:"muted,omitempty"` // ids muted by the client
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MutePlayerResponse) Reset() {
+	*x = MutePlayerResponse{}
+	mi := &file_pong_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MutePlayerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MutePlayerResponse) ProtoMessage() {}
+
+func (x *MutePlayerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MutePlayerResponse.ProtoReflect.Descriptor instead.
+func (*MutePlayerResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *MutePlayerResponse) GetMuted() []string {
+	if x != nil {
+		return x.Muted
+	}
+	return nil
+}
+
+type KickPlayerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,3,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Ban           bool                   `protobuf:"varint,4,opt,name=ban,proto3" json:"ban,omitempty"` // keep the player from joining again
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickPlayerRequest) Reset() {
+	*x = KickPlayerRequest{}
+	mi := &file_pong_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickPlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickPlayerRequest) ProtoMessage() {}
+
+func (x *KickPlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickPlayerRequest.ProtoReflect.Descriptor instead.
+func (*KickPlayerRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *KickPlayerRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *KickPlayerRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *KickPlayerRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *KickPlayerRequest) GetBan() bool {
+	if x != nil {
+		return x.Ban
+	}
+	return false
+}
+
+type KickPlayerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickPlayerResponse) Reset() {
+	*x = KickPlayerResponse{}
+	mi := &file_pong_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickPlayerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickPlayerResponse) ProtoMessage() {}
+
+func (x *KickPlayerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickPlayerResponse.ProtoReflect.Descriptor instead.
+func (*KickPlayerResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *KickPlayerResponse) GetWr() *WaitingRoom {
+	if x != nil {
+		return x.Wr
+	}
+	return nil
+}
+
+type LockRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockRoomRequest) Reset() {
+	*x = LockRoomRequest{}
+	mi := &file_pong_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockRoomRequest) ProtoMessage() {}
+
+func (x *LockRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockRoomRequest.ProtoReflect.Descriptor instead.
+func (*LockRoomRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *LockRoomRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *LockRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type LockRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockRoomResponse) Reset() {
+	*x = LockRoomResponse{}
+	mi := &file_pong_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockRoomResponse) ProtoMessage() {}
+
+func (x *LockRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockRoomResponse.ProtoReflect.Descriptor instead.
+func (*LockRoomResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *LockRoomResponse) GetWr() *WaitingRoom {
+	if x != nil {
+		return x.Wr
+	}
+	return nil
+}
+
+type TransferHostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	NewHostId     string                 `protobuf:"bytes,3,opt,name=new_host_id,json=newHostId,proto3" json:"new_host_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferHostRequest) Reset() {
+	*x = TransferHostRequest{}
+	mi := &file_pong_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferHostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferHostRequest) ProtoMessage() {}
+
+func (x *TransferHostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferHostRequest.ProtoReflect.Descriptor instead.
+func (*TransferHostRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *TransferHostRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *TransferHostRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *TransferHostRequest) GetNewHostId() string {
+	if x != nil {
+		return x.NewHostId
+	}
+	return ""
+}
+
+type TransferHostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferHostResponse) Reset() {
+	*x = TransferHostResponse{}
+	mi := &file_pong_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferHostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferHostResponse) ProtoMessage() {}
+
+func (x *TransferHostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferHostResponse.ProtoReflect.Descriptor instead.
+func (*TransferHostResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *TransferHostResponse) GetWr() *WaitingRoom {
+	if x != nil {
+		return x.Wr
+	}
+	return nil
+}
+
+// UpdateRoomSettingsRequest changes the settings of a waiting room. Unset
+// fields are left unchanged.
+type UpdateRoomSettingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Rules         *GameRules             `protobuf:"bytes,3,opt,name=rules,proto3" json:"rules,omitempty"`
+	Private       *bool                  `protobuf:"varint,4,opt,name=private,proto3,oneof" json:"private,omitempty"`
+	BetAmt        int64                  `protobuf:"varint,5,opt,name=bet_amt,json=betAmt,proto3" json:"bet_amt,omitempty"` // only while the host is alone in the room
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoomSettingsRequest) Reset() {
+	*x = UpdateRoomSettingsRequest{}
+	mi := &file_pong_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoomSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoomSettingsRequest) ProtoMessage() {}
+
+func (x *UpdateRoomSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoomSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoomSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *UpdateRoomSettingsRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *UpdateRoomSettingsRequest) GetRoomId() string {
 	if x != nil {
-		return x.P1Width
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *UpdateRoomSettingsRequest) GetRules() *GameRules {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *UpdateRoomSettingsRequest) GetPrivate() bool {
+	if x != nil && x.Private != nil {
+		return *x.Private
+	}
+	return false
+}
+
+func (x *UpdateRoomSettingsRequest) GetBetAmt() int64 {
+	if x != nil {
+		return x.BetAmt
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetP1Height() float64 {
+type UpdateRoomSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wr            *WaitingRoom           `protobuf:"bytes,1,opt,name=wr,proto3" json:"wr,omitempty"`
+	InviteCode    string                 `protobuf:"bytes,2,opt,name=invite_code,json=inviteCode,proto3" json:"invite_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoomSettingsResponse) Reset() {
+	*x = UpdateRoomSettingsResponse{}
+	mi := &file_pong_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoomSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoomSettingsResponse) ProtoMessage() {}
+
+func (x *UpdateRoomSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[63]
 	if x != nil {
-		return x.P1Height
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoomSettingsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRoomSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *UpdateRoomSettingsResponse) GetWr() *WaitingRoom {
+	if x != nil {
+		return x.Wr
+	}
+	return nil
+}
+
+func (x *UpdateRoomSettingsResponse) GetInviteCode() string {
+	if x != nil {
+		return x.InviteCode
+	}
+	return ""
+}
+
+// Balance is the money a player holds with the bot, in matoms.
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Available     int64                  `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`                              // free to stake or withdraw
+	Reserved      int64                  `protobuf:"varint,2,opt,name=reserved,proto3" json:"reserved,omitempty"`                                // staked in waiting rooms, games and tournaments
+	PendingPayout int64                  `protobuf:"varint,3,opt,name=pending_payout,json=pendingPayout,proto3" json:"pending_payout,omitempty"` // sent to the player but not confirmed yet
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_pong_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *Balance) GetAvailable() int64 {
+	if x != nil {
+		return x.Available
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetP2Width() float64 {
+func (x *Balance) GetReserved() int64 {
 	if x != nil {
-		return x.P2Width
+		return x.Reserved
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetP2Height() float64 {
+func (x *Balance) GetPendingPayout() int64 {
 	if x != nil {
-		return x.P2Height
+		return x.PendingPayout
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetBallWidth() float64 {
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_pong_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[65]
 	if x != nil {
-		return x.BallWidth
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetBalanceRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type GetBalanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       *Balance               `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceResponse) Reset() {
+	*x = GetBalanceResponse{}
+	mi := &file_pong_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceResponse) ProtoMessage() {}
+
+func (x *GetBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceResponse.ProtoReflect.Descriptor instead.
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *GetBalanceResponse) GetBalance() *Balance {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+type WithdrawRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"` // matoms, 0 for the whole available balance
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawRequest) Reset() {
+	*x = WithdrawRequest{}
+	mi := &file_pong_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawRequest) ProtoMessage() {}
+
+func (x *WithdrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawRequest.ProtoReflect.Descriptor instead.
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *WithdrawRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetBallHeight() float64 {
+type WithdrawResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`  // matoms being sent
+	Balance       *Balance               `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"` // balance after the withdrawal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawResponse) Reset() {
+	*x = WithdrawResponse{}
+	mi := &file_pong_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawResponse) ProtoMessage() {}
+
+func (x *WithdrawResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[68]
 	if x != nil {
-		return x.BallHeight
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawResponse.ProtoReflect.Descriptor instead.
+func (*WithdrawResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *WithdrawResponse) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetP1Score() int32 {
+func (x *WithdrawResponse) GetBalance() *Balance {
 	if x != nil {
-		return x.P1Score
+		return x.Balance
+	}
+	return nil
+}
+
+// Limits are the responsible gaming limits a player set on themselves.
+// Looser limits only apply after a cool-off, stricter ones at once.
+type Limits struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	MaxStake            int64                  `protobuf:"varint,1,opt,name=max_stake,json=maxStake,proto3" json:"max_stake,omitempty"`                                    // matoms per game or tournament, 0 for no limit
+	MaxDailyLoss        int64                  `protobuf:"varint,2,opt,name=max_daily_loss,json=maxDailyLoss,proto3" json:"max_daily_loss,omitempty"`                      // matoms lost in any 24 hours, 0 for no limit
+	SessionReminderSecs int64                  `protobuf:"varint,3,opt,name=session_reminder_secs,json=sessionReminderSecs,proto3" json:"session_reminder_secs,omitempty"` // remind after this long connected, 0 for never
+	ExcludedUntil       int64                  `protobuf:"varint,4,opt,name=excluded_until,json=excludedUntil,proto3" json:"excluded_until,omitempty"`                     // unix seconds, 0 if not self-excluded
+	PendingMaxStake     int64                  `protobuf:"varint,5,opt,name=pending_max_stake,json=pendingMaxStake,proto3" json:"pending_max_stake,omitempty"`             // looser limits waiting for the cool-off
+	PendingMaxDailyLoss int64                  `protobuf:"varint,6,opt,name=pending_max_daily_loss,json=pendingMaxDailyLoss,proto3" json:"pending_max_daily_loss,omitempty"`
+	PendingAt           int64                  `protobuf:"varint,7,opt,name=pending_at,json=pendingAt,proto3" json:"pending_at,omitempty"` // unix seconds the pending limits apply, 0 if none
+	DailyLoss           int64                  `protobuf:"varint,8,opt,name=daily_loss,json=dailyLoss,proto3" json:"daily_loss,omitempty"` // matoms lost in the last 24 hours
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Limits) Reset() {
+	*x = Limits{}
+	mi := &file_pong_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Limits) ProtoMessage() {}
+
+func (x *Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Limits.ProtoReflect.Descriptor instead.
+func (*Limits) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *Limits) GetMaxStake() int64 {
+	if x != nil {
+		return x.MaxStake
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetP2Score() int32 {
+func (x *Limits) GetMaxDailyLoss() int64 {
+	if x != nil {
+		return x.MaxDailyLoss
+	}
+	return 0
+}
+
+func (x *Limits) GetSessionReminderSecs() int64 {
+	if x != nil {
+		return x.SessionReminderSecs
+	}
+	return 0
+}
+
+func (x *Limits) GetExcludedUntil() int64 {
+	if x != nil {
+		return x.ExcludedUntil
+	}
+	return 0
+}
+
+func (x *Limits) GetPendingMaxStake() int64 {
 	if x != nil {
-		return x.P2Score
+		return x.PendingMaxStake
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetBallX() float64 {
+func (x *Limits) GetPendingMaxDailyLoss() int64 {
 	if x != nil {
-		return x.BallX
+		return x.PendingMaxDailyLoss
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetBallY() float64 {
+func (x *Limits) GetPendingAt() int64 {
 	if x != nil {
-		return x.BallY
+		return x.PendingAt
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetP1X() float64 {
+func (x *Limits) GetDailyLoss() int64 {
 	if x != nil {
-		return x.P1X
+		return x.DailyLoss
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetP1Y() float64 {
+type GetLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLimitsRequest) Reset() {
+	*x = GetLimitsRequest{}
+	mi := &file_pong_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLimitsRequest) ProtoMessage() {}
+
+func (x *GetLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[70]
 	if x != nil {
-		return x.P1Y
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GameUpdate) GetP2X() float64 {
+// Deprecated: Use GetLimitsRequest.ProtoReflect.Descriptor instead.
+func (*GetLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GetLimitsRequest) GetClientId() string {
 	if x != nil {
-		return x.P2X
+		return x.ClientId
 	}
-	return 0
+	return ""
 }
 
-func (x *GameUpdate) GetP2Y() float64 {
+type GetLimitsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limits        *Limits                `protobuf:"bytes,1,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLimitsResponse) Reset() {
+	*x = GetLimitsResponse{}
+	mi := &file_pong_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLimitsResponse) ProtoMessage() {}
+
+func (x *GetLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[71]
 	if x != nil {
-		return x.P2Y
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GameUpdate) GetP1YVelocity() float64 {
+// Deprecated: Use GetLimitsResponse.ProtoReflect.Descriptor instead.
+func (*GetLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetLimitsResponse) GetLimits() *Limits {
 	if x != nil {
-		return x.P1YVelocity
+		return x.Limits
 	}
-	return 0
+	return nil
 }
 
-func (x *GameUpdate) GetP2YVelocity() float64 {
+// SetLimitsRequest replaces the limits of the player.
+type SetLimitsRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ClientId            string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	MaxStake            int64                  `protobuf:"varint,2,opt,name=max_stake,json=maxStake,proto3" json:"max_stake,omitempty"`
+	MaxDailyLoss        int64                  `protobuf:"varint,3,opt,name=max_daily_loss,json=maxDailyLoss,proto3" json:"max_daily_loss,omitempty"`
+	SessionReminderSecs int64                  `protobuf:"varint,4,opt,name=session_reminder_secs,json=sessionReminderSecs,proto3" json:"session_reminder_secs,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SetLimitsRequest) Reset() {
+	*x = SetLimitsRequest{}
+	mi := &file_pong_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLimitsRequest) ProtoMessage() {}
+
+func (x *SetLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[72]
 	if x != nil {
-		return x.P2YVelocity
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GameUpdate) GetBallXVelocity() float64 {
+// Deprecated: Use SetLimitsRequest.ProtoReflect.Descriptor instead.
+func (*SetLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *SetLimitsRequest) GetClientId() string {
 	if x != nil {
-		return x.BallXVelocity
+		return x.ClientId
 	}
-	return 0
+	return ""
 }
 
-func (x *GameUpdate) GetBallYVelocity() float64 {
+func (x *SetLimitsRequest) GetMaxStake() int64 {
 	if x != nil {
-		return x.BallYVelocity
+		return x.MaxStake
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetFps() float64 {
+func (x *SetLimitsRequest) GetMaxDailyLoss() int64 {
 	if x != nil {
-		return x.Fps
+		return x.MaxDailyLoss
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetTps() float64 {
+func (x *SetLimitsRequest) GetSessionReminderSecs() int64 {
 	if x != nil {
-		return x.Tps
+		return x.SessionReminderSecs
 	}
 	return 0
 }
 
-func (x *GameUpdate) GetError() string {
+type SetLimitsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limits        *Limits                `protobuf:"bytes,1,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLimitsResponse) Reset() {
+	*x = SetLimitsResponse{}
+	mi := &file_pong_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLimitsResponse) ProtoMessage() {}
+
+func (x *SetLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[73]
 	if x != nil {
-		return x.Error
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *GameUpdate) GetDebug() bool {
+// Deprecated: Use SetLimitsResponse.ProtoReflect.Descriptor instead.
+func (*SetLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *SetLimitsResponse) GetLimits() *Limits {
 	if x != nil {
-		return x.Debug
+		return x.Limits
 	}
-	return false
+	return nil
 }
 
-type LeaveWaitingRoomRequest struct {
+// SelfExcludeRequest keeps the player from playing for a while. It can't be
+// shortened once set.
+type SelfExcludeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
-	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	DurationSecs  int64                  `protobuf:"varint,2,opt,name=duration_secs,json=durationSecs,proto3" json:"duration_secs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LeaveWaitingRoomRequest) Reset() {
-	*x = LeaveWaitingRoomRequest{}
-	mi := &file_pong_proto_msgTypes[18]
+func (x *SelfExcludeRequest) Reset() {
+	*x = SelfExcludeRequest{}
+	mi := &file_pong_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LeaveWaitingRoomRequest) String() string {
+func (x *SelfExcludeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LeaveWaitingRoomRequest) ProtoMessage() {}
+func (*SelfExcludeRequest) ProtoMessage() {}
 
-func (x *LeaveWaitingRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pong_proto_msgTypes[18]
+func (x *SelfExcludeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1266,48 +5216,47 @@ func (x *LeaveWaitingRoomRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LeaveWaitingRoomRequest.ProtoReflect.Descriptor instead.
-func (*LeaveWaitingRoomRequest) Descriptor() ([]byte, []int) {
-	return file_pong_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use SelfExcludeRequest.ProtoReflect.Descriptor instead.
+func (*SelfExcludeRequest) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *LeaveWaitingRoomRequest) GetClientId() string {
+func (x *SelfExcludeRequest) GetClientId() string {
 	if x != nil {
 		return x.ClientId
 	}
 	return ""
 }
 
-func (x *LeaveWaitingRoomRequest) GetRoomId() string {
+func (x *SelfExcludeRequest) GetDurationSecs() int64 {
 	if x != nil {
-		return x.RoomId
+		return x.DurationSecs
 	}
-	return ""
+	return 0
 }
 
-type LeaveWaitingRoomResponse struct {
+type SelfExcludeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Limits        *Limits                `protobuf:"bytes,1,opt,name=limits,proto3" json:"limits,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LeaveWaitingRoomResponse) Reset() {
-	*x = LeaveWaitingRoomResponse{}
-	mi := &file_pong_proto_msgTypes[19]
+func (x *SelfExcludeResponse) Reset() {
+	*x = SelfExcludeResponse{}
+	mi := &file_pong_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LeaveWaitingRoomResponse) String() string {
+func (x *SelfExcludeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LeaveWaitingRoomResponse) ProtoMessage() {}
+func (*SelfExcludeResponse) ProtoMessage() {}
 
-func (x *LeaveWaitingRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pong_proto_msgTypes[19]
+func (x *SelfExcludeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1318,49 +5267,45 @@ func (x *LeaveWaitingRoomResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LeaveWaitingRoomResponse.ProtoReflect.Descriptor instead.
-func (*LeaveWaitingRoomResponse) Descriptor() ([]byte, []int) {
-	return file_pong_proto_rawDescGZIP(), []int{19}
-}
-
-func (x *LeaveWaitingRoomResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Deprecated: Use SelfExcludeResponse.ProtoReflect.Descriptor instead.
+func (*SelfExcludeResponse) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *LeaveWaitingRoomResponse) GetMessage() string {
+func (x *SelfExcludeResponse) GetLimits() *Limits {
 	if x != nil {
-		return x.Message
+		return x.Limits
 	}
-	return ""
+	return nil
 }
 
-// SignalReadyToPlayRequest contains information about the client signaling readiness
-type SignalReadyToPlayRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
-	GameId        string                 `protobuf:"bytes,2,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Fairness lets players verify the serve randomness of a game. The server
+// commits to server_seed_hash when the game is created, and reveals
+// server_seed and the entropy each player sent when it ends.
+type Fairness struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerSeedHash []byte                 `protobuf:"bytes,1,opt,name=server_seed_hash,json=serverSeedHash,proto3" json:"server_seed_hash,omitempty"` // sha256 of server_seed
+	ServerSeed     []byte                 `protobuf:"bytes,2,opt,name=server_seed,json=serverSeed,proto3" json:"server_seed,omitempty"`
+	PlayerEntropy  [][]byte               `protobuf:"bytes,3,rep,name=player_entropy,json=playerEntropy,proto3" json:"player_entropy,omitempty"` // in player number order
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *SignalReadyToPlayRequest) Reset() {
-	*x = SignalReadyToPlayRequest{}
-	mi := &file_pong_proto_msgTypes[20]
+func (x *Fairness) Reset() {
+	*x = Fairness{}
+	mi := &file_pong_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SignalReadyToPlayRequest) String() string {
+func (x *Fairness) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignalReadyToPlayRequest) ProtoMessage() {}
+func (*Fairness) ProtoMessage() {}
 
-func (x *SignalReadyToPlayRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pong_proto_msgTypes[20]
+func (x *Fairness) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1371,49 +5316,58 @@ func (x *SignalReadyToPlayRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignalReadyToPlayRequest.ProtoReflect.Descriptor instead.
-func (*SignalReadyToPlayRequest) Descriptor() ([]byte, []int) {
-	return file_pong_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use Fairness.ProtoReflect.Descriptor instead.
+func (*Fairness) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *SignalReadyToPlayRequest) GetClientId() string {
+func (x *Fairness) GetServerSeedHash() []byte {
 	if x != nil {
-		return x.ClientId
+		return x.ServerSeedHash
 	}
-	return ""
+	return nil
 }
 
-func (x *SignalReadyToPlayRequest) GetGameId() string {
+func (x *Fairness) GetServerSeed() []byte {
 	if x != nil {
-		return x.GameId
+		return x.ServerSeed
 	}
-	return ""
+	return nil
 }
 
-// SignalReadyToPlayResponse contains the result of the ready signal
-type SignalReadyToPlayResponse struct {
+func (x *Fairness) GetPlayerEntropy() [][]byte {
+	if x != nil {
+		return x.PlayerEntropy
+	}
+	return nil
+}
+
+// MatchReceipt is the result of a match signed by the bot. receipt holds the
+// exact JSON that was signed with ed25519.
+type MatchReceipt struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Receipt       []byte                 `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	Signature     []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	PublicKey     []byte                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SignalReadyToPlayResponse) Reset() {
-	*x = SignalReadyToPlayResponse{}
-	mi := &file_pong_proto_msgTypes[21]
+func (x *MatchReceipt) Reset() {
+	*x = MatchReceipt{}
+	mi := &file_pong_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SignalReadyToPlayResponse) String() string {
+func (x *MatchReceipt) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SignalReadyToPlayResponse) ProtoMessage() {}
+func (*MatchReceipt) ProtoMessage() {}
 
-func (x *SignalReadyToPlayResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pong_proto_msgTypes[21]
+func (x *MatchReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_pong_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1424,23 +5378,30 @@ func (x *SignalReadyToPlayResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SignalReadyToPlayResponse.ProtoReflect.Descriptor instead.
-func (*SignalReadyToPlayResponse) Descriptor() ([]byte, []int) {
-	return file_pong_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use MatchReceipt.ProtoReflect.Descriptor instead.
+func (*MatchReceipt) Descriptor() ([]byte, []int) {
+	return file_pong_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *SignalReadyToPlayResponse) GetSuccess() bool {
+func (x *MatchReceipt) GetReceipt() []byte {
 	if x != nil {
-		return x.Success
+		return x.Receipt
 	}
-	return false
+	return nil
 }
 
-func (x *SignalReadyToPlayResponse) GetMessage() string {
+func (x *MatchReceipt) GetSignature() []byte {
 	if x != nil {
-		return x.Message
+		return x.Signature
 	}
-	return ""
+	return nil
+}
+
+func (x *MatchReceipt) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
 }
 
 var File_pong_proto protoreflect.FileDescriptor
@@ -1451,9 +5412,10 @@ const file_pong_proto_rawDesc = "" +
 	"pong.proto\x12\x04pong\"7\n" +
 	"\x18UnreadyGameStreamRequest\x12\x1b\n" +
 	"\tclient_id\x18\x01 \x01(\tR\bclientId\"\x1b\n" +
-	"\x19UnreadyGameStreamResponse\"5\n" +
+	"\x19UnreadyGameStreamResponse\"I\n" +
 	"\x16StartNtfnStreamRequest\x12\x1b\n" +
-	"\tclient_id\x18\x01 \x01(\tR\bclientId\"\xd2\x02\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x12\n" +
+	"\x04nick\x18\x02 \x01(\tR\x04nick\"\xd2\x05\n" +
 	"\x12NtfnStreamResponse\x12C\n" +
 	"\x11notification_type\x18\x01 \x01(\x0e2\x16.pong.NotificationTypeR\x10notificationType\x12\x18\n" +
 	"\astarted\x18\x02 \x01(\bR\astarted\x12\x17\n" +
@@ -1465,26 +5427,90 @@ const file_pong_proto_rawDesc = "" +
 	"\aroom_id\x18\b \x01(\tR\x06roomId\x12!\n" +
 	"\x02wr\x18\t \x01(\v2\x11.pong.WaitingRoomR\x02wr\x12\x14\n" +
 	"\x05ready\x18\n" +
-	" \x01(\bR\x05ready\".\n" +
+	" \x01(\bR\x05ready\x12\x1f\n" +
+	"\vinvite_code\x18\v \x01(\tR\n" +
+	"inviteCode\x12-\n" +
+	"\tchallenge\x18\f \x01(\v2\x0f.pong.ChallengeR\tchallenge\x12)\n" +
+	"\x06series\x18\r \x01(\v2\x11.pong.SeriesStateR\x06series\x12'\n" +
+	"\arematch\x18\x0e \x01(\v2\r.pong.RematchR\arematch\x120\n" +
+	"\n" +
+	"tournament\x18\x0f \x01(\v2\x10.pong.TournamentR\n" +
+	"tournament\x12%\n" +
+	"\x04chat\x18\x10 \x01(\v2\x11.pong.ChatMessageR\x04chat\x12'\n" +
+	"\abalance\x18\x11 \x01(\v2\r.pong.BalanceR\abalance\x12*\n" +
+	"\bfairness\x18\x12 \x01(\v2\x0e.pong.FairnessR\bfairness\x12,\n" +
+	"\areceipt\x18\x13 \x01(\v2\x12.pong.MatchReceiptR\areceipt\"\xcf\x02\n" +
 	"\x13WaitingRoomsRequest\x12\x17\n" +
-	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"9\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\amin_bet\x18\x02 \x01(\x03R\x06minBet\x12\x17\n" +
+	"\amax_bet\x18\x03 \x01(\x03R\x06maxBet\x12\x1d\n" +
+	"\n" +
+	"open_seats\x18\x04 \x01(\bR\topenSeats\x12%\n" +
+	"\x05rules\x18\x05 \x01(\v2\x0f.pong.GameRulesR\x05rules\x12&\n" +
+	"\x0fmin_host_rating\x18\x06 \x01(\x01R\rminHostRating\x12&\n" +
+	"\x0fmax_host_rating\x18\a \x01(\x01R\rmaxHostRating\x12)\n" +
+	"\x04sort\x18\b \x01(\x0e2\x15.pong.WaitingRoomSortR\x04sort\x12\x16\n" +
+	"\x06cursor\x18\t \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\x05R\x05limit\"p\n" +
 	"\x14WaitingRoomsResponse\x12!\n" +
-	"\x02wr\x18\x01 \x03(\v2\x11.pong.WaitingRoomR\x02wr\"N\n" +
+	"\x02wr\x18\x01 \x03(\v2\x11.pong.WaitingRoomR\x02wr\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\"o\n" +
 	"\x16JoinWaitingRoomRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
-	"\tclient_id\x18\x02 \x01(\tR\bclientId\"<\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12\x1f\n" +
+	"\vinvite_code\x18\x03 \x01(\tR\n" +
+	"inviteCode\"<\n" +
 	"\x17JoinWaitingRoomResponse\x12!\n" +
-	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"K\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"\xa8\x01\n" +
 	"\x18CreateWaitingRoomRequest\x12\x17\n" +
 	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12\x16\n" +
-	"\x06betAmt\x18\x02 \x01(\x03R\x06betAmt\">\n" +
+	"\x06betAmt\x18\x02 \x01(\x03R\x06betAmt\x12\x18\n" +
+	"\aprivate\x18\x03 \x01(\bR\aprivate\x12\x1a\n" +
+	"\binvitees\x18\x04 \x03(\tR\binvitees\x12%\n" +
+	"\x05rules\x18\x05 \x01(\v2\x0f.pong.GameRulesR\x05rules\"_\n" +
 	"\x19CreateWaitingRoomResponse\x12!\n" +
-	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"w\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\x12\x1f\n" +
+	"\vinvite_code\x18\x02 \x01(\tR\n" +
+	"inviteCode\"\xcb\x02\n" +
 	"\vWaitingRoom\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\ahost_id\x18\x02 \x01(\tR\x06hostId\x12&\n" +
 	"\aplayers\x18\x03 \x03(\v2\f.pong.PlayerR\aplayers\x12\x17\n" +
-	"\abet_amt\x18\x04 \x01(\x03R\x06betAmt\"\x14\n" +
+	"\abet_amt\x18\x04 \x01(\x03R\x06betAmt\x12\x18\n" +
+	"\aprivate\x18\x05 \x01(\bR\aprivate\x12%\n" +
+	"\x05rules\x18\x06 \x01(\v2\x0f.pong.GameRulesR\x05rules\x12%\n" +
+	"\x0eready_deadline\x18\a \x01(\x03R\rreadyDeadline\x12\x16\n" +
+	"\x06locked\x18\b \x01(\bR\x06locked\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\x03R\tcreatedAt\x12\x1f\n" +
+	"\vhost_rating\x18\n" +
+	" \x01(\x01R\n" +
+	"hostRating\x12\x12\n" +
+	"\x04rake\x18\v \x01(\x03R\x04rake\"A\n" +
+	"\tGameRules\x12\x1b\n" +
+	"\tmax_score\x18\x01 \x01(\x05R\bmaxScore\x12\x17\n" +
+	"\abest_of\x18\x02 \x01(\x05R\x06bestOf\">\n" +
+	"\vSeriesScore\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04wins\x18\x02 \x01(\x05R\x04wins\"\xc3\x01\n" +
+	"\vSeriesState\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\abest_of\x18\x02 \x01(\x05R\x06bestOf\x12!\n" +
+	"\fgames_played\x18\x03 \x01(\x05R\vgamesPlayed\x12)\n" +
+	"\x06scores\x18\x04 \x03(\v2\x11.pong.SeriesScoreR\x06scores\x12\x1b\n" +
+	"\twinner_id\x18\x05 \x01(\tR\bwinnerId\x12 \n" +
+	"\fnext_game_at\x18\x06 \x01(\x03R\n" +
+	"nextGameAt\"n\n" +
+	"\x1aInviteToWaitingRoomRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1a\n" +
+	"\binvitees\x18\x03 \x03(\tR\binvitees\">\n" +
+	"\x1bInviteToWaitingRoomResponse\x12\x1f\n" +
+	"\vinvite_code\x18\x01 \x01(\tR\n" +
+	"inviteCode\"\x14\n" +
 	"\x12WaitingRoomRequest\"=\n" +
 	"\x13WaitingRoomResponse\x12&\n" +
 	"\aplayers\x18\x01 \x03(\v2\f.pong.PlayerR\aplayers\"\x8b\x01\n" +
@@ -1539,13 +5565,244 @@ const file_pong_proto_rawDesc = "" +
 	"\aroom_id\x18\x02 \x01(\tR\x06roomId\"N\n" +
 	"\x18LeaveWaitingRoomResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"P\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"j\n" +
 	"\x18SignalReadyToPlayRequest\x12\x1b\n" +
 	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
-	"\agame_id\x18\x02 \x01(\tR\x06gameId\"O\n" +
+	"\agame_id\x18\x02 \x01(\tR\x06gameId\x12\x18\n" +
+	"\aentropy\x18\x03 \x01(\fR\aentropy\"O\n" +
 	"\x19SignalReadyToPlayResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage*\x8f\x02\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9e\x01\n" +
+	"\x12LeaderboardRequest\x12)\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x15.pong.LeaderboardKindR\x04kind\x12/\n" +
+	"\x06window\x18\x02 \x01(\x0e2\x17.pong.LeaderboardWindowR\x06window\x12\x16\n" +
+	"\x06season\x18\x03 \x01(\rR\x06season\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\x95\x02\n" +
+	"\x10LeaderboardEntry\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x10\n" +
+	"\x03uid\x18\x02 \x01(\tR\x03uid\x12\x12\n" +
+	"\x04nick\x18\x03 \x01(\tR\x04nick\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x01R\x06rating\x12!\n" +
+	"\fnet_winnings\x18\x05 \x01(\x03R\vnetWinnings\x12\x1f\n" +
+	"\vbest_streak\x18\x06 \x01(\x05R\n" +
+	"bestStreak\x12%\n" +
+	"\x0ecurrent_streak\x18\a \x01(\x05R\rcurrentStreak\x12\x12\n" +
+	"\x04wins\x18\b \x01(\x05R\x04wins\x12\x16\n" +
+	"\x06losses\x18\t \x01(\x05R\x06losses\x12\x18\n" +
+	"\acredits\x18\n" +
+	" \x01(\x03R\acredits\"\xde\x01\n" +
+	"\x13LeaderboardResponse\x12)\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x15.pong.LeaderboardKindR\x04kind\x12/\n" +
+	"\x06window\x18\x02 \x01(\x0e2\x17.pong.LeaderboardWindowR\x06window\x12\x16\n" +
+	"\x06season\x18\x03 \x01(\rR\x06season\x12!\n" +
+	"\fwindow_start\x18\x04 \x01(\x03R\vwindowStart\x120\n" +
+	"\aentries\x18\x05 \x03(\v2\x16.pong.LeaderboardEntryR\aentries\"\xe5\x01\n" +
+	"\tChallenge\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rchallenger_id\x18\x02 \x01(\tR\fchallengerId\x12'\n" +
+	"\x0fchallenger_nick\x18\x03 \x01(\tR\x0echallengerNick\x12\x1b\n" +
+	"\ttarget_id\x18\x04 \x01(\tR\btargetId\x12\x17\n" +
+	"\abet_amt\x18\x05 \x01(\x03R\x06betAmt\x12%\n" +
+	"\x05rules\x18\x06 \x01(\v2\x0f.pong.GameRulesR\x05rules\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\x03R\texpiresAt\"\xb0\x01\n" +
+	"\x16ChallengePlayerRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x17\n" +
+	"\abet_amt\x18\x03 \x01(\x03R\x06betAmt\x12%\n" +
+	"\x05rules\x18\x04 \x01(\v2\x0f.pong.GameRulesR\x05rules\x12!\n" +
+	"\ftimeout_secs\x18\x05 \x01(\x03R\vtimeoutSecs\"H\n" +
+	"\x17ChallengePlayerResponse\x12-\n" +
+	"\tchallenge\x18\x01 \x01(\v2\x0f.pong.ChallengeR\tchallenge\"q\n" +
+	"\x17RespondChallengeRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12!\n" +
+	"\fchallenge_id\x18\x02 \x01(\tR\vchallengeId\x12\x16\n" +
+	"\x06accept\x18\x03 \x01(\bR\x06accept\"=\n" +
+	"\x18RespondChallengeResponse\x12!\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"\xb8\x01\n" +
+	"\aRematch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"player_ids\x18\x02 \x03(\tR\tplayerIds\x12\x17\n" +
+	"\abet_amt\x18\x03 \x01(\x03R\x06betAmt\x12%\n" +
+	"\x05rules\x18\x04 \x01(\v2\x0f.pong.GameRulesR\x05rules\x12\x1f\n" +
+	"\vproposer_id\x18\x05 \x01(\tR\n" +
+	"proposerId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt\"t\n" +
+	"\x15ProposeRematchRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
+	"\abet_amt\x18\x02 \x01(\x03R\x06betAmt\x12%\n" +
+	"\x05rules\x18\x03 \x01(\v2\x0f.pong.GameRulesR\x05rules\"A\n" +
+	"\x16ProposeRematchResponse\x12'\n" +
+	"\arematch\x18\x01 \x01(\v2\r.pong.RematchR\arematch\"L\n" +
+	"\x15RespondRematchRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x16\n" +
+	"\x06accept\x18\x02 \x01(\bR\x06accept\";\n" +
+	"\x16RespondRematchResponse\x12!\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"\x8f\x01\n" +
+	"\x10TournamentPlayer\x12\x1b\n" +
+	"\tplayer_id\x18\x01 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04nick\x18\x02 \x01(\tR\x04nick\x12\x12\n" +
+	"\x04seed\x18\x03 \x01(\x05R\x04seed\x12\x16\n" +
+	"\x06losses\x18\x04 \x01(\x05R\x06losses\x12\x1e\n" +
+	"\n" +
+	"eliminated\x18\x05 \x01(\bR\n" +
+	"eliminated\"\xe8\x01\n" +
+	"\x0fTournamentMatch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05round\x18\x02 \x01(\x05R\x05round\x12\x12\n" +
+	"\x04pool\x18\x03 \x01(\x05R\x04pool\x12\x1d\n" +
+	"\n" +
+	"player_ids\x18\x04 \x03(\tR\tplayerIds\x12\x1b\n" +
+	"\twinner_id\x18\x05 \x01(\tR\bwinnerId\x12\x10\n" +
+	"\x03bye\x18\x06 \x01(\bR\x03bye\x12\x18\n" +
+	"\aforfeit\x18\a \x01(\bR\aforfeit\x12\x17\n" +
+	"\aroom_id\x18\b \x01(\tR\x06roomId\x12\x1a\n" +
+	"\bdeadline\x18\t \x01(\x03R\bdeadline\"\xcc\x03\n" +
+	"\n" +
+	"Tournament\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12.\n" +
+	"\x06format\x18\x03 \x01(\x0e2\x16.pong.TournamentFormatR\x06format\x12+\n" +
+	"\x05state\x18\x04 \x01(\x0e2\x15.pong.TournamentStateR\x05state\x12\x15\n" +
+	"\x06buy_in\x18\x05 \x01(\x03R\x05buyIn\x12\x1f\n" +
+	"\vmax_players\x18\x06 \x01(\x05R\n" +
+	"maxPlayers\x12\x1b\n" +
+	"\tstarts_at\x18\a \x01(\x03R\bstartsAt\x12\x1f\n" +
+	"\vprize_split\x18\b \x03(\rR\n" +
+	"prizeSplit\x12%\n" +
+	"\x05rules\x18\t \x01(\v2\x0f.pong.GameRulesR\x05rules\x12\x1d\n" +
+	"\n" +
+	"prize_pool\x18\n" +
+	" \x01(\x03R\tprizePool\x120\n" +
+	"\aplayers\x18\v \x03(\v2\x16.pong.TournamentPlayerR\aplayers\x12/\n" +
+	"\amatches\x18\f \x03(\v2\x15.pong.TournamentMatchR\amatches\x12\x1e\n" +
+	"\n" +
+	"placements\x18\r \x03(\tR\n" +
+	"placements\"\x18\n" +
+	"\x16ListTournamentsRequest\"M\n" +
+	"\x17ListTournamentsResponse\x122\n" +
+	"\vtournaments\x18\x01 \x03(\v2\x10.pong.TournamentR\vtournaments\";\n" +
+	"\x14GetTournamentRequest\x12#\n" +
+	"\rtournament_id\x18\x01 \x01(\tR\ftournamentId\"I\n" +
+	"\x15GetTournamentResponse\x120\n" +
+	"\n" +
+	"tournament\x18\x01 \x01(\v2\x10.pong.TournamentR\n" +
+	"tournament\"]\n" +
+	"\x19RegisterTournamentRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12#\n" +
+	"\rtournament_id\x18\x02 \x01(\tR\ftournamentId\"N\n" +
+	"\x1aRegisterTournamentResponse\x120\n" +
+	"\n" +
+	"tournament\x18\x01 \x01(\v2\x10.pong.TournamentR\n" +
+	"tournament\"_\n" +
+	"\x1bUnregisterTournamentRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12#\n" +
+	"\rtournament_id\x18\x02 \x01(\tR\ftournamentId\"\x1e\n" +
+	"\x1cUnregisterTournamentResponse\"\xaf\x01\n" +
+	"\vChatMessage\x12\x1b\n" +
+	"\tsender_id\x18\x01 \x01(\tR\bsenderId\x12\x1f\n" +
+	"\vsender_nick\x18\x02 \x01(\tR\n" +
+	"senderNick\x12\x17\n" +
+	"\aroom_id\x18\x03 \x01(\tR\x06roomId\x12\x17\n" +
+	"\agame_id\x18\x04 \x01(\tR\x06gameId\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"{\n" +
+	"\x16SendChatMessageRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x17\n" +
+	"\agame_id\x18\x03 \x01(\tR\x06gameId\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\"@\n" +
+	"\x17SendChatMessageResponse\x12%\n" +
+	"\x04chat\x18\x01 \x01(\v2\x11.pong.ChatMessageR\x04chat\"a\n" +
+	"\x11MutePlayerRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x12\n" +
+	"\x04mute\x18\x03 \x01(\bR\x04mute\"*\n" +
+	"\x12MutePlayerResponse\x12\x14\n" +
+	"\x05muted\x18\x01 \x03(\tR\x05muted\"x\n" +
+	"\x11KickPlayerRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\tplayer_id\x18\x03 \x01(\tR\bplayerId\x12\x10\n" +
+	"\x03ban\x18\x04 \x01(\bR\x03ban\"7\n" +
+	"\x12KickPlayerResponse\x12!\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"G\n" +
+	"\x0fLockRoomRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\"5\n" +
+	"\x10LockRoomResponse\x12!\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"k\n" +
+	"\x13TransferHostRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1e\n" +
+	"\vnew_host_id\x18\x03 \x01(\tR\tnewHostId\"9\n" +
+	"\x14TransferHostResponse\x12!\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\"\xbc\x01\n" +
+	"\x19UpdateRoomSettingsRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12%\n" +
+	"\x05rules\x18\x03 \x01(\v2\x0f.pong.GameRulesR\x05rules\x12\x1d\n" +
+	"\aprivate\x18\x04 \x01(\bH\x00R\aprivate\x88\x01\x01\x12\x17\n" +
+	"\abet_amt\x18\x05 \x01(\x03R\x06betAmtB\n" +
+	"\n" +
+	"\b_private\"`\n" +
+	"\x1aUpdateRoomSettingsResponse\x12!\n" +
+	"\x02wr\x18\x01 \x01(\v2\x11.pong.WaitingRoomR\x02wr\x12\x1f\n" +
+	"\vinvite_code\x18\x02 \x01(\tR\n" +
+	"inviteCode\"j\n" +
+	"\aBalance\x12\x1c\n" +
+	"\tavailable\x18\x01 \x01(\x03R\tavailable\x12\x1a\n" +
+	"\breserved\x18\x02 \x01(\x03R\breserved\x12%\n" +
+	"\x0epending_payout\x18\x03 \x01(\x03R\rpendingPayout\"0\n" +
+	"\x11GetBalanceRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\"=\n" +
+	"\x12GetBalanceResponse\x12'\n" +
+	"\abalance\x18\x01 \x01(\v2\r.pong.BalanceR\abalance\"F\n" +
+	"\x0fWithdrawRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"S\n" +
+	"\x10WithdrawResponse\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12'\n" +
+	"\abalance\x18\x02 \x01(\v2\r.pong.BalanceR\abalance\"\xc5\x02\n" +
+	"\x06Limits\x12\x1b\n" +
+	"\tmax_stake\x18\x01 \x01(\x03R\bmaxStake\x12$\n" +
+	"\x0emax_daily_loss\x18\x02 \x01(\x03R\fmaxDailyLoss\x122\n" +
+	"\x15session_reminder_secs\x18\x03 \x01(\x03R\x13sessionReminderSecs\x12%\n" +
+	"\x0eexcluded_until\x18\x04 \x01(\x03R\rexcludedUntil\x12*\n" +
+	"\x11pending_max_stake\x18\x05 \x01(\x03R\x0fpendingMaxStake\x123\n" +
+	"\x16pending_max_daily_loss\x18\x06 \x01(\x03R\x13pendingMaxDailyLoss\x12\x1d\n" +
+	"\n" +
+	"pending_at\x18\a \x01(\x03R\tpendingAt\x12\x1d\n" +
+	"\n" +
+	"daily_loss\x18\b \x01(\x03R\tdailyLoss\"/\n" +
+	"\x10GetLimitsRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\"9\n" +
+	"\x11GetLimitsResponse\x12$\n" +
+	"\x06limits\x18\x01 \x01(\v2\f.pong.LimitsR\x06limits\"\xa6\x01\n" +
+	"\x10SetLimitsRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12\x1b\n" +
+	"\tmax_stake\x18\x02 \x01(\x03R\bmaxStake\x12$\n" +
+	"\x0emax_daily_loss\x18\x03 \x01(\x03R\fmaxDailyLoss\x122\n" +
+	"\x15session_reminder_secs\x18\x04 \x01(\x03R\x13sessionReminderSecs\"9\n" +
+	"\x11SetLimitsResponse\x12$\n" +
+	"\x06limits\x18\x01 \x01(\v2\f.pong.LimitsR\x06limits\"V\n" +
+	"\x12SelfExcludeRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12#\n" +
+	"\rduration_secs\x18\x02 \x01(\x03R\fdurationSecs\";\n" +
+	"\x13SelfExcludeResponse\x12$\n" +
+	"\x06limits\x18\x01 \x01(\v2\f.pong.LimitsR\x06limits\"|\n" +
+	"\bFairness\x12(\n" +
+	"\x10server_seed_hash\x18\x01 \x01(\fR\x0eserverSeedHash\x12\x1f\n" +
+	"\vserver_seed\x18\x02 \x01(\fR\n" +
+	"serverSeed\x12%\n" +
+	"\x0eplayer_entropy\x18\x03 \x03(\fR\rplayerEntropy\"e\n" +
+	"\fMatchReceipt\x12\x18\n" +
+	"\areceipt\x18\x01 \x01(\fR\areceipt\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\fR\tsignature\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\fR\tpublicKey*\xc8\x06\n" +
 	"\x10NotificationType\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\v\n" +
 	"\aMESSAGE\x10\x01\x12\x0e\n" +
@@ -1561,7 +5818,61 @@ const file_pong_proto_rawDesc = "" +
 	"\x0ePLAYER_LEFT_WR\x10\n" +
 	"\x12\x14\n" +
 	"\x10COUNTDOWN_UPDATE\x10\v\x12\x16\n" +
-	"\x12GAME_READY_TO_PLAY\x10\f2\x8b\x06\n" +
+	"\x12GAME_READY_TO_PLAY\x10\f\x12\r\n" +
+	"\tWR_INVITE\x10\r\x12\x16\n" +
+	"\x12CHALLENGE_RECEIVED\x10\x0e\x12\x16\n" +
+	"\x12CHALLENGE_ACCEPTED\x10\x0f\x12\x16\n" +
+	"\x12CHALLENGE_DECLINED\x10\x10\x12\x15\n" +
+	"\x11CHALLENGE_EXPIRED\x10\x11\x12\x11\n" +
+	"\rSERIES_UPDATE\x10\x12\x12\x15\n" +
+	"\x11REMATCH_AVAILABLE\x10\x13\x12\x14\n" +
+	"\x10REMATCH_PROPOSED\x10\x14\x12\x14\n" +
+	"\x10REMATCH_ACCEPTED\x10\x15\x12\x14\n" +
+	"\x10REMATCH_DECLINED\x10\x16\x12\x13\n" +
+	"\x0fREMATCH_EXPIRED\x10\x17\x12\x15\n" +
+	"\x11TOURNAMENT_UPDATE\x10\x18\x12\x1a\n" +
+	"\x16TOURNAMENT_MATCH_READY\x10\x19\x12\x14\n" +
+	"\x10TOURNAMENT_ENDED\x10\x1a\x12\x0f\n" +
+	"\vREADY_CHECK\x10\x1b\x12\x12\n" +
+	"\x0ePLAYER_EVICTED\x10\x1c\x12\x0e\n" +
+	"\n" +
+	"WR_EXPIRED\x10\x1d\x12\x11\n" +
+	"\rPLAYER_KICKED\x10\x1e\x12\r\n" +
+	"\tWR_LOCKED\x10\x1f\x12\x0f\n" +
+	"\vWR_UNLOCKED\x10 \x12\x14\n" +
+	"\x10HOST_TRANSFERRED\x10!\x12\x17\n" +
+	"\x13WR_SETTINGS_UPDATED\x10\"\x12\x0f\n" +
+	"\vWR_RESTORED\x10#\x12\x16\n" +
+	"\x12WITHDRAWAL_STARTED\x10$\x12\x13\n" +
+	"\x0fPAYOUT_PROGRESS\x10%\x12\x14\n" +
+	"\x10PAYOUT_COMPLETED\x10&\x12\x14\n" +
+	"\x10SESSION_REMINDER\x10'*}\n" +
+	"\x0fWaitingRoomSort\x12\x12\n" +
+	"\x0eWR_SORT_NEWEST\x10\x00\x12\x12\n" +
+	"\x0eWR_SORT_OLDEST\x10\x01\x12\x13\n" +
+	"\x0fWR_SORT_BET_ASC\x10\x02\x12\x14\n" +
+	"\x10WR_SORT_BET_DESC\x10\x03\x12\x17\n" +
+	"\x13WR_SORT_HOST_RATING\x10\x04*X\n" +
+	"\x0fLeaderboardKind\x12\r\n" +
+	"\tBY_RATING\x10\x00\x12\x13\n" +
+	"\x0fBY_NET_WINNINGS\x10\x01\x12\x11\n" +
+	"\rBY_WIN_STREAK\x10\x02\x12\x0e\n" +
+	"\n" +
+	"BY_CREDITS\x10\x03*6\n" +
+	"\x11LeaderboardWindow\x12\n" +
+	"\n" +
+	"\x06SEASON\x10\x00\x12\t\n" +
+	"\x05DAILY\x10\x01\x12\n" +
+	"\n" +
+	"\x06WEEKLY\x10\x02*B\n" +
+	"\x10TournamentFormat\x12\x16\n" +
+	"\x12SINGLE_ELIMINATION\x10\x00\x12\x16\n" +
+	"\x12DOUBLE_ELIMINATION\x10\x01*y\n" +
+	"\x0fTournamentState\x12\x1b\n" +
+	"\x17TOURNAMENT_REGISTRATION\x10\x00\x12\x16\n" +
+	"\x12TOURNAMENT_RUNNING\x10\x01\x12\x17\n" +
+	"\x13TOURNAMENT_FINISHED\x10\x02\x12\x18\n" +
+	"\x14TOURNAMENT_CANCELLED\x10\x032\xe3\x12\n" +
 	"\bPongGame\x122\n" +
 	"\tSendInput\x12\x11.pong.PlayerInput\x1a\x10.pong.GameUpdate\"\x00\x12H\n" +
 	"\x0fStartGameStream\x12\x1c.pong.StartGameStreamRequest\x1a\x15.pong.GameUpdateBytes0\x01\x12K\n" +
@@ -1572,7 +5883,33 @@ const file_pong_proto_rawDesc = "" +
 	"\x0fGetWaitingRooms\x12\x19.pong.WaitingRoomsRequest\x1a\x1a.pong.WaitingRoomsResponse\x12T\n" +
 	"\x11CreateWaitingRoom\x12\x1e.pong.CreateWaitingRoomRequest\x1a\x1f.pong.CreateWaitingRoomResponse\x12N\n" +
 	"\x0fJoinWaitingRoom\x12\x1c.pong.JoinWaitingRoomRequest\x1a\x1d.pong.JoinWaitingRoomResponse\x12Q\n" +
-	"\x10LeaveWaitingRoom\x12\x1d.pong.LeaveWaitingRoomRequest\x1a\x1e.pong.LeaveWaitingRoomResponseB\vZ\tgrpc/pongb\x06proto3"
+	"\x10LeaveWaitingRoom\x12\x1d.pong.LeaveWaitingRoomRequest\x1a\x1e.pong.LeaveWaitingRoomResponse\x12Z\n" +
+	"\x13InviteToWaitingRoom\x12 .pong.InviteToWaitingRoomRequest\x1a!.pong.InviteToWaitingRoomResponse\x12?\n" +
+	"\n" +
+	"KickPlayer\x12\x17.pong.KickPlayerRequest\x1a\x18.pong.KickPlayerResponse\x129\n" +
+	"\bLockRoom\x12\x15.pong.LockRoomRequest\x1a\x16.pong.LockRoomResponse\x12;\n" +
+	"\n" +
+	"UnlockRoom\x12\x15.pong.LockRoomRequest\x1a\x16.pong.LockRoomResponse\x12E\n" +
+	"\fTransferHost\x12\x19.pong.TransferHostRequest\x1a\x1a.pong.TransferHostResponse\x12W\n" +
+	"\x12UpdateRoomSettings\x12\x1f.pong.UpdateRoomSettingsRequest\x1a .pong.UpdateRoomSettingsResponse\x12N\n" +
+	"\x0fChallengePlayer\x12\x1c.pong.ChallengePlayerRequest\x1a\x1d.pong.ChallengePlayerResponse\x12Q\n" +
+	"\x10RespondChallenge\x12\x1d.pong.RespondChallengeRequest\x1a\x1e.pong.RespondChallengeResponse\x12K\n" +
+	"\x0eProposeRematch\x12\x1b.pong.ProposeRematchRequest\x1a\x1c.pong.ProposeRematchResponse\x12K\n" +
+	"\x0eRespondRematch\x12\x1b.pong.RespondRematchRequest\x1a\x1c.pong.RespondRematchResponse\x12N\n" +
+	"\x0fListTournaments\x12\x1c.pong.ListTournamentsRequest\x1a\x1d.pong.ListTournamentsResponse\x12H\n" +
+	"\rGetTournament\x12\x1a.pong.GetTournamentRequest\x1a\x1b.pong.GetTournamentResponse\x12W\n" +
+	"\x12RegisterTournament\x12\x1f.pong.RegisterTournamentRequest\x1a .pong.RegisterTournamentResponse\x12]\n" +
+	"\x14UnregisterTournament\x12!.pong.UnregisterTournamentRequest\x1a\".pong.UnregisterTournamentResponse\x12N\n" +
+	"\x0fSendChatMessage\x12\x1c.pong.SendChatMessageRequest\x1a\x1d.pong.SendChatMessageResponse\x12?\n" +
+	"\n" +
+	"MutePlayer\x12\x17.pong.MutePlayerRequest\x1a\x18.pong.MutePlayerResponse\x12E\n" +
+	"\x0eGetLeaderboard\x12\x18.pong.LeaderboardRequest\x1a\x19.pong.LeaderboardResponse\x12?\n" +
+	"\n" +
+	"GetBalance\x12\x17.pong.GetBalanceRequest\x1a\x18.pong.GetBalanceResponse\x129\n" +
+	"\bWithdraw\x12\x15.pong.WithdrawRequest\x1a\x16.pong.WithdrawResponse\x12<\n" +
+	"\tGetLimits\x12\x16.pong.GetLimitsRequest\x1a\x17.pong.GetLimitsResponse\x12<\n" +
+	"\tSetLimits\x12\x16.pong.SetLimitsRequest\x1a\x17.pong.SetLimitsResponse\x12B\n" +
+	"\vSelfExclude\x12\x18.pong.SelfExcludeRequest\x1a\x19.pong.SelfExcludeResponseB\vZ\tgrpc/pongb\x06proto3"
 
 var (
 	file_pong_proto_rawDescOnce sync.Once
@@ -1586,66 +5923,216 @@ func file_pong_proto_rawDescGZIP() []byte {
 	return file_pong_proto_rawDescData
 }
 
-var file_pong_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pong_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_pong_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_pong_proto_msgTypes = make([]protoimpl.MessageInfo, 78)
 var file_pong_proto_goTypes = []any{
-	(NotificationType)(0),             // 0: pong.NotificationType
-	(*UnreadyGameStreamRequest)(nil),  // 1: pong.UnreadyGameStreamRequest
-	(*UnreadyGameStreamResponse)(nil), // 2: pong.UnreadyGameStreamResponse
-	(*StartNtfnStreamRequest)(nil),    // 3: pong.StartNtfnStreamRequest
-	(*NtfnStreamResponse)(nil),        // 4: pong.NtfnStreamResponse
-	(*WaitingRoomsRequest)(nil),       // 5: pong.WaitingRoomsRequest
-	(*WaitingRoomsResponse)(nil),      // 6: pong.WaitingRoomsResponse
-	(*JoinWaitingRoomRequest)(nil),    // 7: pong.JoinWaitingRoomRequest
-	(*JoinWaitingRoomResponse)(nil),   // 8: pong.JoinWaitingRoomResponse
-	(*CreateWaitingRoomRequest)(nil),  // 9: pong.CreateWaitingRoomRequest
-	(*CreateWaitingRoomResponse)(nil), // 10: pong.CreateWaitingRoomResponse
-	(*WaitingRoom)(nil),               // 11: pong.WaitingRoom
-	(*WaitingRoomRequest)(nil),        // 12: pong.WaitingRoomRequest
-	(*WaitingRoomResponse)(nil),       // 13: pong.WaitingRoomResponse
-	(*Player)(nil),                    // 14: pong.Player
-	(*StartGameStreamRequest)(nil),    // 15: pong.StartGameStreamRequest
-	(*GameUpdateBytes)(nil),           // 16: pong.GameUpdateBytes
-	(*PlayerInput)(nil),               // 17: pong.PlayerInput
-	(*GameUpdate)(nil),                // 18: pong.GameUpdate
-	(*LeaveWaitingRoomRequest)(nil),   // 19: pong.LeaveWaitingRoomRequest
-	(*LeaveWaitingRoomResponse)(nil),  // 20: pong.LeaveWaitingRoomResponse
-	(*SignalReadyToPlayRequest)(nil),  // 21: pong.SignalReadyToPlayRequest
-	(*SignalReadyToPlayResponse)(nil), // 22: pong.SignalReadyToPlayResponse
+	(NotificationType)(0),                // 0: pong.NotificationType
+	(WaitingRoomSort)(0),                 // 1: pong.WaitingRoomSort
+	(LeaderboardKind)(0),                 // 2: pong.LeaderboardKind
+	(LeaderboardWindow)(0),               // 3: pong.LeaderboardWindow
+	(TournamentFormat)(0),                // 4: pong.TournamentFormat
+	(TournamentState)(0),                 // 5: pong.TournamentState
+	(*UnreadyGameStreamRequest)(nil),     // 6: pong.UnreadyGameStreamRequest
+	(*UnreadyGameStreamResponse)(nil),    // 7: pong.UnreadyGameStreamResponse
+	(*StartNtfnStreamRequest)(nil),       // 8: pong.StartNtfnStreamRequest
+	(*NtfnStreamResponse)(nil),           // 9: pong.NtfnStreamResponse
+	(*WaitingRoomsRequest)(nil),          // 10: pong.WaitingRoomsRequest
+	(*WaitingRoomsResponse)(nil),         // 11: pong.WaitingRoomsResponse
+	(*JoinWaitingRoomRequest)(nil),       // 12: pong.JoinWaitingRoomRequest
+	(*JoinWaitingRoomResponse)(nil),      // 13: pong.JoinWaitingRoomResponse
+	(*CreateWaitingRoomRequest)(nil),     // 14: pong.CreateWaitingRoomRequest
+	(*CreateWaitingRoomResponse)(nil),    // 15: pong.CreateWaitingRoomResponse
+	(*WaitingRoom)(nil),                  // 16: pong.WaitingRoom
+	(*GameRules)(nil),                    // 17: pong.GameRules
+	(*SeriesScore)(nil),                  // 18: pong.SeriesScore
+	(*SeriesState)(nil),                  // 19: pong.SeriesState
+	(*InviteToWaitingRoomRequest)(nil),   // 20: pong.InviteToWaitingRoomRequest
+	(*InviteToWaitingRoomResponse)(nil),  // 21: pong.InviteToWaitingRoomResponse
+	(*WaitingRoomRequest)(nil),           // 22: pong.WaitingRoomRequest
+	(*WaitingRoomResponse)(nil),          // 23: pong.WaitingRoomResponse
+	(*Player)(nil),                       // 24: pong.Player
+	(*StartGameStreamRequest)(nil),       // 25: pong.StartGameStreamRequest
+	(*GameUpdateBytes)(nil),              // 26: pong.GameUpdateBytes
+	(*PlayerInput)(nil),                  // 27: pong.PlayerInput
+	(*GameUpdate)(nil),                   // 28: pong.GameUpdate
+	(*LeaveWaitingRoomRequest)(nil),      // 29: pong.LeaveWaitingRoomRequest
+	(*LeaveWaitingRoomResponse)(nil),     // 30: pong.LeaveWaitingRoomResponse
+	(*SignalReadyToPlayRequest)(nil),     // 31: pong.SignalReadyToPlayRequest
+	(*SignalReadyToPlayResponse)(nil),    // 32: pong.SignalReadyToPlayResponse
+	(*LeaderboardRequest)(nil),           // 33: pong.LeaderboardRequest
+	(*LeaderboardEntry)(nil),             // 34: pong.LeaderboardEntry
+	(*LeaderboardResponse)(nil),          // 35: pong.LeaderboardResponse
+	(*Challenge)(nil),                    // 36: pong.Challenge
+	(*ChallengePlayerRequest)(nil),       // 37: pong.ChallengePlayerRequest
+	(*ChallengePlayerResponse)(nil),      // 38: pong.ChallengePlayerResponse
+	(*RespondChallengeRequest)(nil),      // 39: pong.RespondChallengeRequest
+	(*RespondChallengeResponse)(nil),     // 40: pong.RespondChallengeResponse
+	(*Rematch)(nil),                      // 41: pong.Rematch
+	(*ProposeRematchRequest)(nil),        // 42: pong.ProposeRematchRequest
+	(*ProposeRematchResponse)(nil),       // 43: pong.ProposeRematchResponse
+	(*RespondRematchRequest)(nil),        // 44: pong.RespondRematchRequest
+	(*RespondRematchResponse)(nil),       // 45: pong.RespondRematchResponse
+	(*TournamentPlayer)(nil),             // 46: pong.TournamentPlayer
+	(*TournamentMatch)(nil),              // 47: pong.TournamentMatch
+	(*Tournament)(nil),                   // 48: pong.Tournament
+	(*ListTournamentsRequest)(nil),       // 49: pong.ListTournamentsRequest
+	(*ListTournamentsResponse)(nil),      // 50: pong.ListTournamentsResponse
+	(*GetTournamentRequest)(nil),         // 51: pong.GetTournamentRequest
+	(*GetTournamentResponse)(nil),        // 52: pong.GetTournamentResponse
+	(*RegisterTournamentRequest)(nil),    // 53: pong.RegisterTournamentRequest
+	(*RegisterTournamentResponse)(nil),   // 54: pong.RegisterTournamentResponse
+	(*UnregisterTournamentRequest)(nil),  // 55: pong.UnregisterTournamentRequest
+	(*UnregisterTournamentResponse)(nil), // 56: pong.UnregisterTournamentResponse
+	(*ChatMessage)(nil),                  // 57: pong.ChatMessage
+	(*SendChatMessageRequest)(nil),       // 58: pong.SendChatMessageRequest
+	(*SendChatMessageResponse)(nil),      // 59: pong.SendChatMessageResponse
+	(*MutePlayerRequest)(nil),            // 60: pong.MutePlayerRequest
+	(*MutePlayerResponse)(nil),           // 61: pong.MutePlayerResponse
+	(*KickPlayerRequest)(nil),            // 62: pong.KickPlayerRequest
+	(*KickPlayerResponse)(nil),           // 63: pong.KickPlayerResponse
+	(*LockRoomRequest)(nil),              // 64: pong.LockRoomRequest
+	(*LockRoomResponse)(nil),             // 65: pong.LockRoomResponse
+	(*TransferHostRequest)(nil),          // 66: pong.TransferHostRequest
+	(*TransferHostResponse)(nil),         // 67: pong.TransferHostResponse
+	(*UpdateRoomSettingsRequest)(nil),    // 68: pong.UpdateRoomSettingsRequest
+	(*UpdateRoomSettingsResponse)(nil),   // 69: pong.UpdateRoomSettingsResponse
+	(*Balance)(nil),                      // 70: pong.Balance
+	(*GetBalanceRequest)(nil),            // 71: pong.GetBalanceRequest
+	(*GetBalanceResponse)(nil),           // 72: pong.GetBalanceResponse
+	(*WithdrawRequest)(nil),              // 73: pong.WithdrawRequest
+	(*WithdrawResponse)(nil),             // 74: pong.WithdrawResponse
+	(*Limits)(nil),                       // 75: pong.Limits
+	(*GetLimitsRequest)(nil),             // 76: pong.GetLimitsRequest
+	(*GetLimitsResponse)(nil),            // 77: pong.GetLimitsResponse
+	(*SetLimitsRequest)(nil),             // 78: pong.SetLimitsRequest
+	(*SetLimitsResponse)(nil),            // 79: pong.SetLimitsResponse
+	(*SelfExcludeRequest)(nil),           // 80: pong.SelfExcludeRequest
+	(*SelfExcludeResponse)(nil),          // 81: pong.SelfExcludeResponse
+	(*Fairness)(nil),                     // 82: pong.Fairness
+	(*MatchReceipt)(nil),                 // 83: pong.MatchReceipt
 }
 var file_pong_proto_depIdxs = []int32{
 	0,  // 0: pong.NtfnStreamResponse.notification_type:type_name -> pong.NotificationType
-	11, // 1: pong.NtfnStreamResponse.wr:type_name -> pong.WaitingRoom
-	11, // 2: pong.WaitingRoomsResponse.wr:type_name -> pong.WaitingRoom
-	11, // 3: pong.JoinWaitingRoomResponse.wr:type_name -> pong.WaitingRoom
-	11, // 4: pong.CreateWaitingRoomResponse.wr:type_name -> pong.WaitingRoom
-	14, // 5: pong.WaitingRoom.players:type_name -> pong.Player
-	14, // 6: pong.WaitingRoomResponse.players:type_name -> pong.Player
-	17, // 7: pong.PongGame.SendInput:input_type -> pong.PlayerInput
-	15, // 8: pong.PongGame.StartGameStream:input_type -> pong.StartGameStreamRequest
-	3,  // 9: pong.PongGame.StartNtfnStream:input_type -> pong.StartNtfnStreamRequest
-	1,  // 10: pong.PongGame.UnreadyGameStream:input_type -> pong.UnreadyGameStreamRequest
-	21, // 11: pong.PongGame.SignalReadyToPlay:input_type -> pong.SignalReadyToPlayRequest
-	12, // 12: pong.PongGame.GetWaitingRoom:input_type -> pong.WaitingRoomRequest
-	5,  // 13: pong.PongGame.GetWaitingRooms:input_type -> pong.WaitingRoomsRequest
-	9,  // 14: pong.PongGame.CreateWaitingRoom:input_type -> pong.CreateWaitingRoomRequest
-	7,  // 15: pong.PongGame.JoinWaitingRoom:input_type -> pong.JoinWaitingRoomRequest
-	19, // 16: pong.PongGame.LeaveWaitingRoom:input_type -> pong.LeaveWaitingRoomRequest
-	18, // 17: pong.PongGame.SendInput:output_type -> pong.GameUpdate
-	16, // 18: pong.PongGame.StartGameStream:output_type -> pong.GameUpdateBytes
-	4,  // 19: pong.PongGame.StartNtfnStream:output_type -> pong.NtfnStreamResponse
-	2,  // 20: pong.PongGame.UnreadyGameStream:output_type -> pong.UnreadyGameStreamResponse
-	22, // 21: pong.PongGame.SignalReadyToPlay:output_type -> pong.SignalReadyToPlayResponse
-	13, // 22: pong.PongGame.GetWaitingRoom:output_type -> pong.WaitingRoomResponse
-	6,  // 23: pong.PongGame.GetWaitingRooms:output_type -> pong.WaitingRoomsResponse
-	10, // 24: pong.PongGame.CreateWaitingRoom:output_type -> pong.CreateWaitingRoomResponse
-	8,  // 25: pong.PongGame.JoinWaitingRoom:output_type -> pong.JoinWaitingRoomResponse
-	20, // 26: pong.PongGame.LeaveWaitingRoom:output_type -> pong.LeaveWaitingRoomResponse
-	17, // [17:27] is the sub-list for method output_type
-	7,  // [7:17] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	16, // 1: pong.NtfnStreamResponse.wr:type_name -> pong.WaitingRoom
+	36, // 2: pong.NtfnStreamResponse.challenge:type_name -> pong.Challenge
+	19, // 3: pong.NtfnStreamResponse.series:type_name -> pong.SeriesState
+	41, // 4: pong.NtfnStreamResponse.rematch:type_name -> pong.Rematch
+	48, // 5: pong.NtfnStreamResponse.tournament:type_name -> pong.Tournament
+	57, // 6: pong.NtfnStreamResponse.chat:type_name -> pong.ChatMessage
+	70, // 7: pong.NtfnStreamResponse.balance:type_name -> pong.Balance
+	82, // 8: pong.NtfnStreamResponse.fairness:type_name -> pong.Fairness
+	83, // 9: pong.NtfnStreamResponse.receipt:type_name -> pong.MatchReceipt
+	17, // 10: pong.WaitingRoomsRequest.rules:type_name -> pong.GameRules
+	1,  // 11: pong.WaitingRoomsRequest.sort:type_name -> pong.WaitingRoomSort
+	16, // 12: pong.WaitingRoomsResponse.wr:type_name -> pong.WaitingRoom
+	16, // 13: pong.JoinWaitingRoomResponse.wr:type_name -> pong.WaitingRoom
+	17, // 14: pong.CreateWaitingRoomRequest.rules:type_name -> pong.GameRules
+	16, // 15: pong.CreateWaitingRoomResponse.wr:type_name -> pong.WaitingRoom
+	24, // 16: pong.WaitingRoom.players:type_name -> pong.Player
+	17, // 17: pong.WaitingRoom.rules:type_name -> pong.GameRules
+	18, // 18: pong.SeriesState.scores:type_name -> pong.SeriesScore
+	24, // 19: pong.WaitingRoomResponse.players:type_name -> pong.Player
+	2,  // 20: pong.LeaderboardRequest.kind:type_name -> pong.LeaderboardKind
+	3,  // 21: pong.LeaderboardRequest.window:type_name -> pong.LeaderboardWindow
+	2,  // 22: pong.LeaderboardResponse.kind:type_name -> pong.LeaderboardKind
+	3,  // 23: pong.LeaderboardResponse.window:type_name -> pong.LeaderboardWindow
+	34, // 24: pong.LeaderboardResponse.entries:type_name -> pong.LeaderboardEntry
+	17, // 25: pong.Challenge.rules:type_name -> pong.GameRules
+	17, // 26: pong.ChallengePlayerRequest.rules:type_name -> pong.GameRules
+	36, // 27: pong.ChallengePlayerResponse.challenge:type_name -> pong.Challenge
+	16, // 28: pong.RespondChallengeResponse.wr:type_name -> pong.WaitingRoom
+	17, // 29: pong.Rematch.rules:type_name -> pong.GameRules
+	17, // 30: pong.ProposeRematchRequest.rules:type_name -> pong.GameRules
+	41, // 31: pong.ProposeRematchResponse.rematch:type_name -> pong.Rematch
+	16, // 32: pong.RespondRematchResponse.wr:type_name -> pong.WaitingRoom
+	4,  // 33: pong.Tournament.format:type_name -> pong.TournamentFormat
+	5,  // 34: pong.Tournament.state:type_name -> pong.TournamentState
+	17, // 35: pong.Tournament.rules:type_name -> pong.GameRules
+	46, // 36: pong.Tournament.players:type_name -> pong.TournamentPlayer
+	47, // 37: pong.Tournament.matches:type_name -> pong.TournamentMatch
+	48, // 38: pong.ListTournamentsResponse.tournaments:type_name -> pong.Tournament
+	48, // 39: pong.GetTournamentResponse.tournament:type_name -> pong.Tournament
+	48, // 40: pong.RegisterTournamentResponse.tournament:type_name -> pong.Tournament
+	57, // 41: pong.SendChatMessageResponse.chat:type_name -> pong.ChatMessage
+	16, // 42: pong.KickPlayerResponse.wr:type_name -> pong.WaitingRoom
+	16, // 43: pong.LockRoomResponse.wr:type_name -> pong.WaitingRoom
+	16, // 44: pong.TransferHostResponse.wr:type_name -> pong.WaitingRoom
+	17, // 45: pong.UpdateRoomSettingsRequest.rules:type_name -> pong.GameRules
+	16, // 46: pong.UpdateRoomSettingsResponse.wr:type_name -> pong.WaitingRoom
+	70, // 47: pong.GetBalanceResponse.balance:type_name -> pong.Balance
+	70, // 48: pong.WithdrawResponse.balance:type_name -> pong.Balance
+	75, // 49: pong.GetLimitsResponse.limits:type_name -> pong.Limits
+	75, // 50: pong.SetLimitsResponse.limits:type_name -> pong.Limits
+	75, // 51: pong.SelfExcludeResponse.limits:type_name -> pong.Limits
+	27, // 52: pong.PongGame.SendInput:input_type -> pong.PlayerInput
+	25, // 53: pong.PongGame.StartGameStream:input_type -> pong.StartGameStreamRequest
+	8,  // 54: pong.PongGame.StartNtfnStream:input_type -> pong.StartNtfnStreamRequest
+	6,  // 55: pong.PongGame.UnreadyGameStream:input_type -> pong.UnreadyGameStreamRequest
+	31, // 56: pong.PongGame.SignalReadyToPlay:input_type -> pong.SignalReadyToPlayRequest
+	22, // 57: pong.PongGame.GetWaitingRoom:input_type -> pong.WaitingRoomRequest
+	10, // 58: pong.PongGame.GetWaitingRooms:input_type -> pong.WaitingRoomsRequest
+	14, // 59: pong.PongGame.CreateWaitingRoom:input_type -> pong.CreateWaitingRoomRequest
+	12, // 60: pong.PongGame.JoinWaitingRoom:input_type -> pong.JoinWaitingRoomRequest
+	29, // 61: pong.PongGame.LeaveWaitingRoom:input_type -> pong.LeaveWaitingRoomRequest
+	20, // 62: pong.PongGame.InviteToWaitingRoom:input_type -> pong.InviteToWaitingRoomRequest
+	62, // 63: pong.PongGame.KickPlayer:input_type -> pong.KickPlayerRequest
+	64, // 64: pong.PongGame.LockRoom:input_type -> pong.LockRoomRequest
+	64, // 65: pong.PongGame.UnlockRoom:input_type -> pong.LockRoomRequest
+	66, // 66: pong.PongGame.TransferHost:input_type -> pong.TransferHostRequest
+	68, // 67: pong.PongGame.UpdateRoomSettings:input_type -> pong.UpdateRoomSettingsRequest
+	37, // 68: pong.PongGame.ChallengePlayer:input_type -> pong.ChallengePlayerRequest
+	39, // 69: pong.PongGame.RespondChallenge:input_type -> pong.RespondChallengeRequest
+	42, // 70: pong.PongGame.ProposeRematch:input_type -> pong.ProposeRematchRequest
+	44, // 71: pong.PongGame.RespondRematch:input_type -> pong.RespondRematchRequest
+	49, // 72: pong.PongGame.ListTournaments:input_type -> pong.ListTournamentsRequest
+	51, // 73: pong.PongGame.GetTournament:input_type -> pong.GetTournamentRequest
+	53, // 74: pong.PongGame.RegisterTournament:input_type -> pong.RegisterTournamentRequest
+	55, // 75: pong.PongGame.UnregisterTournament:input_type -> pong.UnregisterTournamentRequest
+	58, // 76: pong.PongGame.SendChatMessage:input_type -> pong.SendChatMessageRequest
+	60, // 77: pong.PongGame.MutePlayer:input_type -> pong.MutePlayerRequest
+	33, // 78: pong.PongGame.GetLeaderboard:input_type -> pong.LeaderboardRequest
+	71, // 79: pong.PongGame.GetBalance:input_type -> pong.GetBalanceRequest
+	73, // 80: pong.PongGame.Withdraw:input_type -> pong.WithdrawRequest
+	76, // 81: pong.PongGame.GetLimits:input_type -> pong.GetLimitsRequest
+	78, // 82: pong.PongGame.SetLimits:input_type -> pong.SetLimitsRequest
+	80, // 83: pong.PongGame.SelfExclude:input_type -> pong.SelfExcludeRequest
+	28, // 84: pong.PongGame.SendInput:output_type -> pong.GameUpdate
+	26, // 85: pong.PongGame.StartGameStream:output_type -> pong.GameUpdateBytes
+	9,  // 86: pong.PongGame.StartNtfnStream:output_type -> pong.NtfnStreamResponse
+	7,  // 87: pong.PongGame.UnreadyGameStream:output_type -> pong.UnreadyGameStreamResponse
+	32, // 88: pong.PongGame.SignalReadyToPlay:output_type -> pong.SignalReadyToPlayResponse
+	23, // 89: pong.PongGame.GetWaitingRoom:output_type -> pong.WaitingRoomResponse
+	11, // 90: pong.PongGame.GetWaitingRooms:output_type -> pong.WaitingRoomsResponse
+	15, // 91: pong.PongGame.CreateWaitingRoom:output_type -> pong.CreateWaitingRoomResponse
+	13, // 92: pong.PongGame.JoinWaitingRoom:output_type -> pong.JoinWaitingRoomResponse
+	30, // 93: pong.PongGame.LeaveWaitingRoom:output_type -> pong.LeaveWaitingRoomResponse
+	21, // 94: pong.PongGame.InviteToWaitingRoom:output_type -> pong.InviteToWaitingRoomResponse
+	63, // 95: pong.PongGame.KickPlayer:output_type -> pong.KickPlayerResponse
+	65, // 96: pong.PongGame.LockRoom:output_type -> pong.LockRoomResponse
+	65, // 97: pong.PongGame.UnlockRoom:output_type -> pong.LockRoomResponse
+	67, // 98: pong.PongGame.TransferHost:output_type -> pong.TransferHostResponse
+	69, // 99: pong.PongGame.UpdateRoomSettings:output_type -> pong.UpdateRoomSettingsResponse
+	38, // 100: pong.PongGame.ChallengePlayer:output_type -> pong.ChallengePlayerResponse
+	40, // 101: pong.PongGame.RespondChallenge:output_type -> pong.RespondChallengeResponse
+	43, // 102: pong.PongGame.ProposeRematch:output_type -> pong.ProposeRematchResponse
+	45, // 103: pong.PongGame.RespondRematch:output_type -> pong.RespondRematchResponse
+	50, // 104: pong.PongGame.ListTournaments:output_type -> pong.ListTournamentsResponse
+	52, // 105: pong.PongGame.GetTournament:output_type -> pong.GetTournamentResponse
+	54, // 106: pong.PongGame.RegisterTournament:output_type -> pong.RegisterTournamentResponse
+	56, // 107: pong.PongGame.UnregisterTournament:output_type -> pong.UnregisterTournamentResponse
+	59, // 108: pong.PongGame.SendChatMessage:output_type -> pong.SendChatMessageResponse
+	61, // 109: pong.PongGame.MutePlayer:output_type -> pong.MutePlayerResponse
+	35, // 110: pong.PongGame.GetLeaderboard:output_type -> pong.LeaderboardResponse
+	72, // 111: pong.PongGame.GetBalance:output_type -> pong.GetBalanceResponse
+	74, // 112: pong.PongGame.Withdraw:output_type -> pong.WithdrawResponse
+	77, // 113: pong.PongGame.GetLimits:output_type -> pong.GetLimitsResponse
+	79, // 114: pong.PongGame.SetLimits:output_type -> pong.SetLimitsResponse
+	81, // 115: pong.PongGame.SelfExclude:output_type -> pong.SelfExcludeResponse
+	84, // [84:116] is the sub-list for method output_type
+	52, // [52:84] is the sub-list for method input_type
+	52, // [52:52] is the sub-list for extension type_name
+	52, // [52:52] is the sub-list for extension extendee
+	0,  // [0:52] is the sub-list for field type_name
 }
 
 func init() { file_pong_proto_init() }
@@ -1653,13 +6140,14 @@ func file_pong_proto_init() {
 	if File_pong_proto != nil {
 		return
 	}
+	file_pong_proto_msgTypes[62].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pong_proto_rawDesc), len(file_pong_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   22,
+			NumEnums:      6,
+			NumMessages:   78,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
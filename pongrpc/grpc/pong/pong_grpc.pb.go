@@ -34,6 +34,36 @@ type PongGameClient interface {
 	CreateWaitingRoom(ctx context.Context, in *CreateWaitingRoomRequest, opts ...grpc.CallOption) (*CreateWaitingRoomResponse, error)
 	JoinWaitingRoom(ctx context.Context, in *JoinWaitingRoomRequest, opts ...grpc.CallOption) (*JoinWaitingRoomResponse, error)
 	LeaveWaitingRoom(ctx context.Context, in *LeaveWaitingRoomRequest, opts ...grpc.CallOption) (*LeaveWaitingRoomResponse, error)
+	InviteToWaitingRoom(ctx context.Context, in *InviteToWaitingRoomRequest, opts ...grpc.CallOption) (*InviteToWaitingRoomResponse, error)
+	// waiting room moderation, restricted to the host
+	KickPlayer(ctx context.Context, in *KickPlayerRequest, opts ...grpc.CallOption) (*KickPlayerResponse, error)
+	LockRoom(ctx context.Context, in *LockRoomRequest, opts ...grpc.CallOption) (*LockRoomResponse, error)
+	UnlockRoom(ctx context.Context, in *LockRoomRequest, opts ...grpc.CallOption) (*LockRoomResponse, error)
+	TransferHost(ctx context.Context, in *TransferHostRequest, opts ...grpc.CallOption) (*TransferHostResponse, error)
+	UpdateRoomSettings(ctx context.Context, in *UpdateRoomSettingsRequest, opts ...grpc.CallOption) (*UpdateRoomSettingsResponse, error)
+	// direct challenges
+	ChallengePlayer(ctx context.Context, in *ChallengePlayerRequest, opts ...grpc.CallOption) (*ChallengePlayerResponse, error)
+	RespondChallenge(ctx context.Context, in *RespondChallengeRequest, opts ...grpc.CallOption) (*RespondChallengeResponse, error)
+	// rematches
+	ProposeRematch(ctx context.Context, in *ProposeRematchRequest, opts ...grpc.CallOption) (*ProposeRematchResponse, error)
+	RespondRematch(ctx context.Context, in *RespondRematchRequest, opts ...grpc.CallOption) (*RespondRematchResponse, error)
+	// tournaments
+	ListTournaments(ctx context.Context, in *ListTournamentsRequest, opts ...grpc.CallOption) (*ListTournamentsResponse, error)
+	GetTournament(ctx context.Context, in *GetTournamentRequest, opts ...grpc.CallOption) (*GetTournamentResponse, error)
+	RegisterTournament(ctx context.Context, in *RegisterTournamentRequest, opts ...grpc.CallOption) (*RegisterTournamentResponse, error)
+	UnregisterTournament(ctx context.Context, in *UnregisterTournamentRequest, opts ...grpc.CallOption) (*UnregisterTournamentResponse, error)
+	// chat
+	SendChatMessage(ctx context.Context, in *SendChatMessageRequest, opts ...grpc.CallOption) (*SendChatMessageResponse, error)
+	MutePlayer(ctx context.Context, in *MutePlayerRequest, opts ...grpc.CallOption) (*MutePlayerResponse, error)
+	// leaderboards
+	GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	// balance
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*WithdrawResponse, error)
+	// responsible gaming
+	GetLimits(ctx context.Context, in *GetLimitsRequest, opts ...grpc.CallOption) (*GetLimitsResponse, error)
+	SetLimits(ctx context.Context, in *SetLimitsRequest, opts ...grpc.CallOption) (*SetLimitsResponse, error)
+	SelfExclude(ctx context.Context, in *SelfExcludeRequest, opts ...grpc.CallOption) (*SelfExcludeResponse, error)
 }
 
 type pongGameClient struct {
@@ -180,6 +210,204 @@ func (c *pongGameClient) LeaveWaitingRoom(ctx context.Context, in *LeaveWaitingR
 	return out, nil
 }
 
+func (c *pongGameClient) InviteToWaitingRoom(ctx context.Context, in *InviteToWaitingRoomRequest, opts ...grpc.CallOption) (*InviteToWaitingRoomResponse, error) {
+	out := new(InviteToWaitingRoomResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/InviteToWaitingRoom", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) KickPlayer(ctx context.Context, in *KickPlayerRequest, opts ...grpc.CallOption) (*KickPlayerResponse, error) {
+	out := new(KickPlayerResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/KickPlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) LockRoom(ctx context.Context, in *LockRoomRequest, opts ...grpc.CallOption) (*LockRoomResponse, error) {
+	out := new(LockRoomResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/LockRoom", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) UnlockRoom(ctx context.Context, in *LockRoomRequest, opts ...grpc.CallOption) (*LockRoomResponse, error) {
+	out := new(LockRoomResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/UnlockRoom", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) TransferHost(ctx context.Context, in *TransferHostRequest, opts ...grpc.CallOption) (*TransferHostResponse, error) {
+	out := new(TransferHostResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/TransferHost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) UpdateRoomSettings(ctx context.Context, in *UpdateRoomSettingsRequest, opts ...grpc.CallOption) (*UpdateRoomSettingsResponse, error) {
+	out := new(UpdateRoomSettingsResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/UpdateRoomSettings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) ChallengePlayer(ctx context.Context, in *ChallengePlayerRequest, opts ...grpc.CallOption) (*ChallengePlayerResponse, error) {
+	out := new(ChallengePlayerResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/ChallengePlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) RespondChallenge(ctx context.Context, in *RespondChallengeRequest, opts ...grpc.CallOption) (*RespondChallengeResponse, error) {
+	out := new(RespondChallengeResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/RespondChallenge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) ProposeRematch(ctx context.Context, in *ProposeRematchRequest, opts ...grpc.CallOption) (*ProposeRematchResponse, error) {
+	out := new(ProposeRematchResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/ProposeRematch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) RespondRematch(ctx context.Context, in *RespondRematchRequest, opts ...grpc.CallOption) (*RespondRematchResponse, error) {
+	out := new(RespondRematchResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/RespondRematch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) ListTournaments(ctx context.Context, in *ListTournamentsRequest, opts ...grpc.CallOption) (*ListTournamentsResponse, error) {
+	out := new(ListTournamentsResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/ListTournaments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) GetTournament(ctx context.Context, in *GetTournamentRequest, opts ...grpc.CallOption) (*GetTournamentResponse, error) {
+	out := new(GetTournamentResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/GetTournament", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) RegisterTournament(ctx context.Context, in *RegisterTournamentRequest, opts ...grpc.CallOption) (*RegisterTournamentResponse, error) {
+	out := new(RegisterTournamentResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/RegisterTournament", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) UnregisterTournament(ctx context.Context, in *UnregisterTournamentRequest, opts ...grpc.CallOption) (*UnregisterTournamentResponse, error) {
+	out := new(UnregisterTournamentResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/UnregisterTournament", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) SendChatMessage(ctx context.Context, in *SendChatMessageRequest, opts ...grpc.CallOption) (*SendChatMessageResponse, error) {
+	out := new(SendChatMessageResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/SendChatMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) MutePlayer(ctx context.Context, in *MutePlayerRequest, opts ...grpc.CallOption) (*MutePlayerResponse, error) {
+	out := new(MutePlayerResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/MutePlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) GetLeaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/GetLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	out := new(GetBalanceResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*WithdrawResponse, error) {
+	out := new(WithdrawResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/Withdraw", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) GetLimits(ctx context.Context, in *GetLimitsRequest, opts ...grpc.CallOption) (*GetLimitsResponse, error) {
+	out := new(GetLimitsResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/GetLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) SetLimits(ctx context.Context, in *SetLimitsRequest, opts ...grpc.CallOption) (*SetLimitsResponse, error) {
+	out := new(SetLimitsResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/SetLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pongGameClient) SelfExclude(ctx context.Context, in *SelfExcludeRequest, opts ...grpc.CallOption) (*SelfExcludeResponse, error) {
+	out := new(SelfExcludeResponse)
+	err := c.cc.Invoke(ctx, "/pong.PongGame/SelfExclude", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PongGameServer is the server API for PongGame service.
 // All implementations must embed UnimplementedPongGameServer
 // for forward compatibility
@@ -196,6 +424,36 @@ type PongGameServer interface {
 	CreateWaitingRoom(context.Context, *CreateWaitingRoomRequest) (*CreateWaitingRoomResponse, error)
 	JoinWaitingRoom(context.Context, *JoinWaitingRoomRequest) (*JoinWaitingRoomResponse, error)
 	LeaveWaitingRoom(context.Context, *LeaveWaitingRoomRequest) (*LeaveWaitingRoomResponse, error)
+	InviteToWaitingRoom(context.Context, *InviteToWaitingRoomRequest) (*InviteToWaitingRoomResponse, error)
+	// waiting room moderation, restricted to the host
+	KickPlayer(context.Context, *KickPlayerRequest) (*KickPlayerResponse, error)
+	LockRoom(context.Context, *LockRoomRequest) (*LockRoomResponse, error)
+	UnlockRoom(context.Context, *LockRoomRequest) (*LockRoomResponse, error)
+	TransferHost(context.Context, *TransferHostRequest) (*TransferHostResponse, error)
+	UpdateRoomSettings(context.Context, *UpdateRoomSettingsRequest) (*UpdateRoomSettingsResponse, error)
+	// direct challenges
+	ChallengePlayer(context.Context, *ChallengePlayerRequest) (*ChallengePlayerResponse, error)
+	RespondChallenge(context.Context, *RespondChallengeRequest) (*RespondChallengeResponse, error)
+	// rematches
+	ProposeRematch(context.Context, *ProposeRematchRequest) (*ProposeRematchResponse, error)
+	RespondRematch(context.Context, *RespondRematchRequest) (*RespondRematchResponse, error)
+	// tournaments
+	ListTournaments(context.Context, *ListTournamentsRequest) (*ListTournamentsResponse, error)
+	GetTournament(context.Context, *GetTournamentRequest) (*GetTournamentResponse, error)
+	RegisterTournament(context.Context, *RegisterTournamentRequest) (*RegisterTournamentResponse, error)
+	UnregisterTournament(context.Context, *UnregisterTournamentRequest) (*UnregisterTournamentResponse, error)
+	// chat
+	SendChatMessage(context.Context, *SendChatMessageRequest) (*SendChatMessageResponse, error)
+	MutePlayer(context.Context, *MutePlayerRequest) (*MutePlayerResponse, error)
+	// leaderboards
+	GetLeaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error)
+	// balance
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	Withdraw(context.Context, *WithdrawRequest) (*WithdrawResponse, error)
+	// responsible gaming
+	GetLimits(context.Context, *GetLimitsRequest) (*GetLimitsResponse, error)
+	SetLimits(context.Context, *SetLimitsRequest) (*SetLimitsResponse, error)
+	SelfExclude(context.Context, *SelfExcludeRequest) (*SelfExcludeResponse, error)
 	mustEmbedUnimplementedPongGameServer()
 }
 
@@ -233,6 +491,72 @@ func (UnimplementedPongGameServer) JoinWaitingRoom(context.Context, *JoinWaiting
 func (UnimplementedPongGameServer) LeaveWaitingRoom(context.Context, *LeaveWaitingRoomRequest) (*LeaveWaitingRoomResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LeaveWaitingRoom not implemented")
 }
+func (UnimplementedPongGameServer) InviteToWaitingRoom(context.Context, *InviteToWaitingRoomRequest) (*InviteToWaitingRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteToWaitingRoom not implemented")
+}
+func (UnimplementedPongGameServer) KickPlayer(context.Context, *KickPlayerRequest) (*KickPlayerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickPlayer not implemented")
+}
+func (UnimplementedPongGameServer) LockRoom(context.Context, *LockRoomRequest) (*LockRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LockRoom not implemented")
+}
+func (UnimplementedPongGameServer) UnlockRoom(context.Context, *LockRoomRequest) (*LockRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlockRoom not implemented")
+}
+func (UnimplementedPongGameServer) TransferHost(context.Context, *TransferHostRequest) (*TransferHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferHost not implemented")
+}
+func (UnimplementedPongGameServer) UpdateRoomSettings(context.Context, *UpdateRoomSettingsRequest) (*UpdateRoomSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRoomSettings not implemented")
+}
+func (UnimplementedPongGameServer) ChallengePlayer(context.Context, *ChallengePlayerRequest) (*ChallengePlayerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChallengePlayer not implemented")
+}
+func (UnimplementedPongGameServer) RespondChallenge(context.Context, *RespondChallengeRequest) (*RespondChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondChallenge not implemented")
+}
+func (UnimplementedPongGameServer) ProposeRematch(context.Context, *ProposeRematchRequest) (*ProposeRematchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProposeRematch not implemented")
+}
+func (UnimplementedPongGameServer) RespondRematch(context.Context, *RespondRematchRequest) (*RespondRematchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondRematch not implemented")
+}
+func (UnimplementedPongGameServer) ListTournaments(context.Context, *ListTournamentsRequest) (*ListTournamentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTournaments not implemented")
+}
+func (UnimplementedPongGameServer) GetTournament(context.Context, *GetTournamentRequest) (*GetTournamentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTournament not implemented")
+}
+func (UnimplementedPongGameServer) RegisterTournament(context.Context, *RegisterTournamentRequest) (*RegisterTournamentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterTournament not implemented")
+}
+func (UnimplementedPongGameServer) UnregisterTournament(context.Context, *UnregisterTournamentRequest) (*UnregisterTournamentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterTournament not implemented")
+}
+func (UnimplementedPongGameServer) SendChatMessage(context.Context, *SendChatMessageRequest) (*SendChatMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendChatMessage not implemented")
+}
+func (UnimplementedPongGameServer) MutePlayer(context.Context, *MutePlayerRequest) (*MutePlayerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MutePlayer not implemented")
+}
+func (UnimplementedPongGameServer) GetLeaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedPongGameServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedPongGameServer) Withdraw(context.Context, *WithdrawRequest) (*WithdrawResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
+}
+func (UnimplementedPongGameServer) GetLimits(context.Context, *GetLimitsRequest) (*GetLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLimits not implemented")
+}
+func (UnimplementedPongGameServer) SetLimits(context.Context, *SetLimitsRequest) (*SetLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLimits not implemented")
+}
+func (UnimplementedPongGameServer) SelfExclude(context.Context, *SelfExcludeRequest) (*SelfExcludeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelfExclude not implemented")
+}
 func (UnimplementedPongGameServer) mustEmbedUnimplementedPongGameServer() {}
 
 // UnsafePongGameServer may be embedded to opt out of forward compatibility for this service.
@@ -432,6 +756,402 @@ func _PongGame_LeaveWaitingRoom_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PongGame_InviteToWaitingRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteToWaitingRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).InviteToWaitingRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/InviteToWaitingRoom",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).InviteToWaitingRoom(ctx, req.(*InviteToWaitingRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_KickPlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickPlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).KickPlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/KickPlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).KickPlayer(ctx, req.(*KickPlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_LockRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).LockRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/LockRoom",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).LockRoom(ctx, req.(*LockRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_UnlockRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).UnlockRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/UnlockRoom",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).UnlockRoom(ctx, req.(*LockRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_TransferHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).TransferHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/TransferHost",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).TransferHost(ctx, req.(*TransferHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_UpdateRoomSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoomSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).UpdateRoomSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/UpdateRoomSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).UpdateRoomSettings(ctx, req.(*UpdateRoomSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_ChallengePlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChallengePlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).ChallengePlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/ChallengePlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).ChallengePlayer(ctx, req.(*ChallengePlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_RespondChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).RespondChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/RespondChallenge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).RespondChallenge(ctx, req.(*RespondChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_ProposeRematch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposeRematchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).ProposeRematch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/ProposeRematch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).ProposeRematch(ctx, req.(*ProposeRematchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_RespondRematch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondRematchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).RespondRematch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/RespondRematch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).RespondRematch(ctx, req.(*RespondRematchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_ListTournaments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTournamentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).ListTournaments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/ListTournaments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).ListTournaments(ctx, req.(*ListTournamentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_GetTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).GetTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/GetTournament",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).GetTournament(ctx, req.(*GetTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_RegisterTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).RegisterTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/RegisterTournament",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).RegisterTournament(ctx, req.(*RegisterTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_UnregisterTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterTournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).UnregisterTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/UnregisterTournament",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).UnregisterTournament(ctx, req.(*UnregisterTournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_SendChatMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendChatMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).SendChatMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/SendChatMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).SendChatMessage(ctx, req.(*SendChatMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_MutePlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MutePlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).MutePlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/MutePlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).MutePlayer(ctx, req.(*MutePlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/GetLeaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).GetLeaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_Withdraw_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WithdrawRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).Withdraw(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/Withdraw",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).Withdraw(ctx, req.(*WithdrawRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_GetLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).GetLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/GetLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).GetLimits(ctx, req.(*GetLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_SetLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).SetLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/SetLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).SetLimits(ctx, req.(*SetLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PongGame_SelfExclude_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelfExcludeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PongGameServer).SelfExclude(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pong.PongGame/SelfExclude",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PongGameServer).SelfExclude(ctx, req.(*SelfExcludeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PongGame_ServiceDesc is the grpc.ServiceDesc for PongGame service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -471,6 +1191,94 @@ var PongGame_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LeaveWaitingRoom",
 			Handler:    _PongGame_LeaveWaitingRoom_Handler,
 		},
+		{
+			MethodName: "InviteToWaitingRoom",
+			Handler:    _PongGame_InviteToWaitingRoom_Handler,
+		},
+		{
+			MethodName: "KickPlayer",
+			Handler:    _PongGame_KickPlayer_Handler,
+		},
+		{
+			MethodName: "LockRoom",
+			Handler:    _PongGame_LockRoom_Handler,
+		},
+		{
+			MethodName: "UnlockRoom",
+			Handler:    _PongGame_UnlockRoom_Handler,
+		},
+		{
+			MethodName: "TransferHost",
+			Handler:    _PongGame_TransferHost_Handler,
+		},
+		{
+			MethodName: "UpdateRoomSettings",
+			Handler:    _PongGame_UpdateRoomSettings_Handler,
+		},
+		{
+			MethodName: "ChallengePlayer",
+			Handler:    _PongGame_ChallengePlayer_Handler,
+		},
+		{
+			MethodName: "RespondChallenge",
+			Handler:    _PongGame_RespondChallenge_Handler,
+		},
+		{
+			MethodName: "ProposeRematch",
+			Handler:    _PongGame_ProposeRematch_Handler,
+		},
+		{
+			MethodName: "RespondRematch",
+			Handler:    _PongGame_RespondRematch_Handler,
+		},
+		{
+			MethodName: "ListTournaments",
+			Handler:    _PongGame_ListTournaments_Handler,
+		},
+		{
+			MethodName: "GetTournament",
+			Handler:    _PongGame_GetTournament_Handler,
+		},
+		{
+			MethodName: "RegisterTournament",
+			Handler:    _PongGame_RegisterTournament_Handler,
+		},
+		{
+			MethodName: "UnregisterTournament",
+			Handler:    _PongGame_UnregisterTournament_Handler,
+		},
+		{
+			MethodName: "SendChatMessage",
+			Handler:    _PongGame_SendChatMessage_Handler,
+		},
+		{
+			MethodName: "MutePlayer",
+			Handler:    _PongGame_MutePlayer_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _PongGame_GetLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _PongGame_GetBalance_Handler,
+		},
+		{
+			MethodName: "Withdraw",
+			Handler:    _PongGame_Withdraw_Handler,
+		},
+		{
+			MethodName: "GetLimits",
+			Handler:    _PongGame_GetLimits_Handler,
+		},
+		{
+			MethodName: "SetLimits",
+			Handler:    _PongGame_SetLimits_Handler,
+		},
+		{
+			MethodName: "SelfExclude",
+			Handler:    _PongGame_SelfExclude_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
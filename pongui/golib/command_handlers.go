@@ -23,6 +23,7 @@ import (
 	"github.com/vctt94/bisonbotkit/config"
 	"github.com/vctt94/bisonbotkit/logging"
 	"github.com/vctt94/pong-bisonrelay/client"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -164,11 +165,43 @@ func handleInitClient(handle uint32, args initClient) (*localInfo, error) {
 		Nick: publicIdentity.Nick,
 	}
 
+	ntfns := client.NewNotificationManager()
+	ntfns.Register(client.OnUINotification(func(n client.UINotification) {
+		notify(NTUINotification, n, nil)
+	}))
+	ntfns.Register(client.OnChatMessageNtfn(func(chat *pong.ChatMessage, ts time.Time) {
+		notify(NTChatMessage, chat, nil)
+	}))
+	ntfns.Register(client.OnWRModerationNtfn(func(typ pong.NotificationType, msg string, wr *pong.WaitingRoom, ts time.Time) {
+		notify(NTWRModeration, &wrModerationNtfn{
+			Type:    typ.String(),
+			Message: msg,
+			Wr:      wr,
+		}, nil)
+	}))
+	ntfns.Register(client.OnPayoutNtfn(func(typ pong.NotificationType, msg string, balance *pong.Balance, ts time.Time) {
+		notify(NTPayout, &payoutNtfn{
+			Type:    typ.String(),
+			Message: msg,
+			Balance: balance,
+		}, nil)
+	}))
+	ntfns.Register(client.OnTournamentNtfn(func(typ pong.NotificationType, msg string, t *pong.Tournament, wr *pong.WaitingRoom, ts time.Time) {
+		notify(NTTournament, &tournamentNtfn{
+			Type:       typ.String(),
+			Message:    msg,
+			Tournament: t,
+			RoomID:     wr.GetId(),
+		}, nil)
+	}))
+
 	pc, err := client.NewPongClient(localInfo.ID.String(), &client.PongClientCfg{
-		ServerAddr:   args.ServerAddr,
-		ChatClient:   c.Chat,
-		Log:          logBackend.Logger("client"),
-		GRPCCertPath: args.GRPCCertPath,
+		ServerAddr:    args.ServerAddr,
+		Nick:          localInfo.Nick,
+		ChatClient:    c.Chat,
+		Log:           logBackend.Logger("client"),
+		GRPCCertPath:  args.GRPCCertPath,
+		Notifications: ntfns,
 	})
 	if err != nil {
 		cancel()
@@ -234,10 +267,20 @@ func handleClientCmd(cc *clientCtx, cmd *cmd) (interface{}, error) {
 		}
 		return res, nil
 	case CTGetWaitingRooms:
-		rooms, err := cc.c.GetWaitingRooms()
+		// The payload optionally holds a search query, in which case a
+		// page with its cursor is returned instead of the plain list.
+		query := &pong.WaitingRoomsRequest{}
+		paged := len(bytes.TrimSpace(cmd.Payload)) > 0 && string(cmd.Payload) != "null"
+		if paged {
+			if err := json.Unmarshal(cmd.Payload, query); err != nil {
+				return nil, fmt.Errorf("invalid waiting rooms query: %v", err)
+			}
+		}
+		page, err := cc.c.SearchWaitingRooms(query)
 		if err != nil {
 			return nil, err
 		}
+		rooms := page.Wr
 		res := make([]*waitingRoom, len(rooms))
 		for i, r := range rooms {
 			players := make([]*player, len(r.Players))
@@ -260,6 +303,13 @@ func handleClientCmd(cc *clientCtx, cmd *cmd) (interface{}, error) {
 				Players: players,
 			}
 		}
+		if paged {
+			return &waitingRoomsPage{
+				Rooms:      res,
+				NextCursor: page.NextCursor,
+				Total:      page.Total,
+			}, nil
+		}
 		return res, nil
 	case CTJoinWaitingRoom:
 		id := string(bytes.Trim(cmd.Payload, "\""))
@@ -310,6 +360,79 @@ func handleClientCmd(cc *clientCtx, cmd *cmd) (interface{}, error) {
 		fmt.Printf("Leaving waiting room: %s\n", id)
 		err := cc.c.LeaveWaitingRoom(id)
 		return nil, err
+
+	case CTListTournaments:
+		return cc.c.ListTournaments()
+
+	case CTGetTournament:
+		id := strings.Trim(string(cmd.Payload), `"`)
+		return cc.c.GetTournament(id)
+
+	case CTRegisterTournament:
+		id := strings.Trim(string(cmd.Payload), `"`)
+		return cc.c.RegisterTournament(id)
+
+	case CTUnregisterTournament:
+		id := strings.Trim(string(cmd.Payload), `"`)
+		return nil, cc.c.UnregisterTournament(id)
+
+	case CTSendChatMessage:
+		var text string
+		if err := json.Unmarshal(cmd.Payload, &text); err != nil {
+			return nil, fmt.Errorf("invalid chat message payload: %v", err)
+		}
+		return cc.c.SendChatMessage(text)
+
+	case CTMutePlayer:
+		var req mutePlayer
+		if err := json.Unmarshal(cmd.Payload, &req); err != nil {
+			return nil, fmt.Errorf("invalid mute player payload: %v", err)
+		}
+		return cc.c.MutePlayer(req.PlayerID, req.Mute)
+
+	case CTKickPlayer:
+		var req kickPlayer
+		if err := json.Unmarshal(cmd.Payload, &req); err != nil {
+			return nil, fmt.Errorf("invalid kick player payload: %v", err)
+		}
+		return cc.c.KickPlayer(req.RoomID, req.PlayerID, req.Ban)
+
+	case CTSetRoomLocked:
+		var req setRoomLocked
+		if err := json.Unmarshal(cmd.Payload, &req); err != nil {
+			return nil, fmt.Errorf("invalid lock room payload: %v", err)
+		}
+		return cc.c.SetRoomLocked(req.RoomID, req.Locked)
+
+	case CTTransferHost:
+		var req transferHost
+		if err := json.Unmarshal(cmd.Payload, &req); err != nil {
+			return nil, fmt.Errorf("invalid transfer host payload: %v", err)
+		}
+		return cc.c.TransferHost(req.RoomID, req.NewHostID)
+
+	case CTUpdateRoomSettings:
+		var req updateRoomSettings
+		if err := json.Unmarshal(cmd.Payload, &req); err != nil {
+			return nil, fmt.Errorf("invalid room settings payload: %v", err)
+		}
+		wr, inviteCode, err := cc.c.UpdateRoomSettings(req.RoomID, req.Rules, req.Private, req.BetAmt)
+		if err != nil {
+			return nil, err
+		}
+		return &roomSettings{Wr: wr, InviteCode: inviteCode}, nil
+
+	case CTGetBalance:
+		return cc.c.GetBalance()
+
+	case CTWithdraw:
+		var amount int64
+		if len(cmd.Payload) > 0 {
+			if err := json.Unmarshal(cmd.Payload, &amount); err != nil {
+				return nil, fmt.Errorf("invalid withdraw payload: %v", err)
+			}
+		}
+		return cc.c.Withdraw(amount)
 	}
 	return nil, nil
 }
@@ -27,6 +27,18 @@ const (
 	CTJoinWaitingRoom           = 0x07
 	CTCreateWaitingRoom         = 0x08
 	CTLeaveWaitingRoom          = 0x09
+	CTListTournaments           = 0x0a
+	CTGetTournament             = 0x0b
+	CTRegisterTournament        = 0x0c
+	CTUnregisterTournament      = 0x0d
+	CTSendChatMessage           = 0x0e
+	CTMutePlayer                = 0x0f
+	CTKickPlayer                = 0x10
+	CTSetRoomLocked             = 0x11
+	CTTransferHost              = 0x12
+	CTUpdateRoomSettings        = 0x13
+	CTGetBalance                = 0x14
+	CTWithdraw                  = 0x15
 
 	CTCreateLockFile        = 0x60
 	CTCloseLockFile         = 0x61
@@ -42,6 +54,10 @@ const (
 	NTLogLine        = 0x1003
 	NTNOP            = 0x1004
 	NTWRCreated      = 0x1005
+	NTTournament     = 0x1006
+	NTChatMessage    = 0x1007
+	NTWRModeration   = 0x1008
+	NTPayout         = 0x1009
 )
 
 type cmd struct {
@@ -45,6 +45,72 @@ type waitingRoom struct {
 	Players []*player `json:"players"`
 }
 
+type waitingRoomsPage struct {
+	Rooms      []*waitingRoom `json:"rooms"`
+	NextCursor string         `json:"next_cursor"`
+	Total      int32          `json:"total"`
+}
+
+// tournamentNtfn is sent to the UI on tournament updates. RoomID is set when
+// a match of the player is ready.
+type tournamentNtfn struct {
+	Type       string           `json:"type"`
+	Message    string           `json:"message"`
+	Tournament *pong.Tournament `json:"tournament"`
+	RoomID     string           `json:"room_id"`
+}
+
+type mutePlayer struct {
+	PlayerID string `json:"player_id"`
+	Mute     bool   `json:"mute"`
+}
+
+type kickPlayer struct {
+	RoomID   string `json:"room_id"`
+	PlayerID string `json:"player_id"`
+	Ban      bool   `json:"ban"`
+}
+
+type setRoomLocked struct {
+	RoomID string `json:"room_id"`
+	Locked bool   `json:"locked"`
+}
+
+type transferHost struct {
+	RoomID    string `json:"room_id"`
+	NewHostID string `json:"new_host_id"`
+}
+
+// updateRoomSettings changes the settings of a hosted room. Unset fields are
+// left unchanged.
+type updateRoomSettings struct {
+	RoomID  string          `json:"room_id"`
+	Rules   *pong.GameRules `json:"rules,omitempty"`
+	Private *bool           `json:"private,omitempty"`
+	BetAmt  int64           `json:"bet_amt,omitempty"`
+}
+
+type roomSettings struct {
+	Wr         *pong.WaitingRoom `json:"wr"`
+	InviteCode string            `json:"invite_code"`
+}
+
+// wrModerationNtfn is sent to the UI when the host of the room of the player
+// takes a moderation action. Wr is nil when the player was kicked.
+type wrModerationNtfn struct {
+	Type    string            `json:"type"`
+	Message string            `json:"message"`
+	Wr      *pong.WaitingRoom `json:"wr"`
+}
+
+// payoutNtfn is sent to the UI as a withdrawal or other payout to the player
+// progresses.
+type payoutNtfn struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Balance *pong.Balance `json:"balance"`
+}
+
 type player struct {
 	UID    client.UserID `json:"uid"`
 	Nick   string        `json:"nick"`
@@ -0,0 +1,131 @@
+// Package receipt provides the signed match receipts the bot gives players
+// at the end of every game as proof of its result.
+package receipt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/vctt94/pong-bisonrelay/matoms"
+)
+
+// signingContext is prepended to receipts before they are signed, so the
+// signatures can't be mistaken for signatures of anything else.
+const signingContext = "pong-bisonrelay match receipt v1\n"
+
+var ErrInvalidSignature = errors.New("invalid receipt signature")
+
+// Player is a participant of a match.
+type Player struct {
+	UID   string        `json:"uid"`
+	Nick  string        `json:"nick"`
+	Score int32         `json:"score"`
+	Stake matoms.Amount `json:"stake"`
+}
+
+// Game is one game of a match.
+type Game struct {
+	ID string `json:"id"`
+
+	// Winner is the UID of the winner of the game, empty for a draw.
+	Winner string `json:"winner,omitempty"`
+
+	// ReplayHash commits to the serve seed and the winner of every round.
+	ReplayHash []byte `json:"replay_hash"`
+}
+
+// Receipt is the result of a match.
+type Receipt struct {
+	GameID   string   `json:"game_id"`
+	SeriesID string   `json:"series_id,omitempty"`
+	Players  []Player `json:"players"`
+
+	// Winner is the UID of the winner, empty for a draw.
+	Winner string `json:"winner,omitempty"`
+
+	Pot    matoms.Amount `json:"pot"`
+	Rake   matoms.Amount `json:"rake"`
+	Payout matoms.Amount `json:"payout"`
+
+	// ReplayHash commits to the serve seed and the winner of every round
+	// of the last game.
+	ReplayHash []byte `json:"replay_hash"`
+
+	// Games are the games of the match in the order played, the last one
+	// being GameID.
+	Games []Game `json:"games,omitempty"`
+
+	// StartedAt is when the first game started.
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+}
+
+// Signed is a receipt with the signature of the bot. The receipt is kept as
+// the exact bytes that were signed.
+type Signed struct {
+	Receipt   json.RawMessage `json:"receipt"`
+	Signature []byte          `json:"signature"`
+	PublicKey []byte          `json:"public_key"`
+}
+
+// Sign signs the receipt with key.
+func Sign(key ed25519.PrivateKey, r *Receipt) (*Signed, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode receipt: %w", err)
+	}
+	return &Signed{
+		Receipt:   data,
+		Signature: ed25519.Sign(key, append([]byte(signingContext), data...)),
+		PublicKey: key.Public().(ed25519.PublicKey),
+	}, nil
+}
+
+// Verify checks the signature of the receipt and decodes it. Receipts signed
+// with a key other than pub are rejected. If pub is nil the key included in
+// the receipt is trusted, which only proves the receipt wasn't altered.
+func (s *Signed) Verify(pub ed25519.PublicKey) (*Receipt, error) {
+	if pub != nil && !bytes.Equal(pub, s.PublicKey) {
+		return nil, fmt.Errorf("%w: signed by %x, not %x", ErrInvalidSignature, s.PublicKey, []byte(pub))
+	}
+	if len(s.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: invalid public key", ErrInvalidSignature)
+	}
+	if !ed25519.Verify(s.PublicKey, append([]byte(signingContext), s.Receipt...), s.Signature) {
+		return nil, ErrInvalidSignature
+	}
+	var r Receipt
+	if err := json.Unmarshal(s.Receipt, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode receipt: %w", err)
+	}
+	return &r, nil
+}
+
+// LoadOrCreateKey reads the signing key at path, creating it if it doesn't
+// exist yet.
+func LoadOrCreateKey(path string) (ed25519.PrivateKey, error) {
+	seed, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate receipt key: %w", err)
+		}
+		if err := os.WriteFile(path, key.Seed(), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write receipt key: %w", err)
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid receipt key %s", path)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
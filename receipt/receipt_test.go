@@ -0,0 +1,54 @@
+package receipt
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	key, err := LoadOrCreateKey(filepath.Join(t.TempDir(), "receipt.key"))
+	require.NoError(t, err)
+
+	r := &Receipt{
+		GameID:    "game1",
+		Players:   []Player{{UID: "a", Nick: "alice", Score: 3, Stake: 100}, {UID: "b", Nick: "bob", Score: 1, Stake: 100}},
+		Winner:    "a",
+		Pot:       200,
+		Rake:      10,
+		Payout:    190,
+		StartedAt: time.Unix(1700000000, 0).UTC(),
+		EndedAt:   time.Unix(1700000300, 0).UTC(),
+	}
+	signed, err := Sign(key, r)
+	require.NoError(t, err)
+
+	got, err := signed.Verify(key.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	require.Equal(t, r, got)
+	_, err = signed.Verify(nil)
+	require.NoError(t, err)
+
+	// Other keys and altered receipts are rejected.
+	other, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, err = signed.Verify(other)
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+
+	signed.Receipt = []byte(string(signed.Receipt[:len(signed.Receipt)-1]) + " }")
+	_, err = signed.Verify(nil)
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "receipt.key")
+	key, err := LoadOrCreateKey(path)
+	require.NoError(t, err)
+	loaded, err := LoadOrCreateKey(path)
+	require.NoError(t, err)
+	require.Equal(t, key, loaded)
+}
@@ -0,0 +1,431 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+var errBanned = errors.New("banned from this server")
+
+type adminCtxKey struct{}
+
+// adminFromContext returns the name of the operator that made an admin
+// request.
+func adminFromContext(ctx context.Context) string {
+	admin, _ := ctx.Value(adminCtxKey{}).(string)
+	return admin
+}
+
+// authenticateAdmin returns the name of the operator a bearer token belongs
+// to, or an empty string if it belongs to none.
+func (s *Server) authenticateAdmin(token string) string {
+	var admin string
+	for name, t := range s.adminTokens {
+		if t != "" && subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			admin = name
+		}
+	}
+	return admin
+}
+
+// requireAdmin only lets requests carrying the bearer token of an operator
+// through to h, passing on the name of the operator in their context.
+func (s *Server) requireAdmin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.adminTokens) == 0 {
+			http.Error(w, "admin API disabled: no admin tokens configured", http.StatusForbidden)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		admin := ""
+		if ok {
+			admin = s.authenticateAdmin(token)
+		}
+		if admin == "" {
+			s.log.Warnf("Rejected unauthenticated admin request to %s from %s", r.URL.Path, r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h(w, r.WithContext(context.WithValue(r.Context(), adminCtxKey{}, admin)))
+	}
+}
+
+// logAdminAction writes an action taken through the admin API to the audit
+// trail, along with the error it failed with, if any.
+func (s *Server) logAdminAction(ctx context.Context, action, target, details string, actionErr error) {
+	entry := &serverdb.AdminAction{
+		Admin:     adminFromContext(ctx),
+		Action:    action,
+		Target:    target,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+	if actionErr != nil {
+		entry.Error = actionErr.Error()
+	}
+	s.log.Infof("Admin %s: %s %s %s (error: %v)", entry.Admin, action, target, details, actionErr)
+	if err := s.db.StoreAdminAction(ctx, entry); err != nil {
+		s.log.Errorf("Failed to store admin action %s: %v", action, err)
+	}
+}
+
+// checkBanned fails if a player is banned.
+func (s *Server) checkBanned(ctx context.Context, uid zkidentity.ShortID) error {
+	ban, err := s.db.FetchBan(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if ban != nil {
+		return fmt.Errorf("you are %w", errBanned)
+	}
+	return nil
+}
+
+// banPlayer bans a player and disconnects them. Their stakes in waiting
+// rooms are released and their balance returned.
+func (s *Server) banPlayer(ctx context.Context, uid zkidentity.ShortID, reason string) error {
+	err := s.db.StoreBan(ctx, &serverdb.Ban{
+		UID:       uid,
+		Reason:    reason,
+		BannedBy:  adminFromContext(ctx),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if s.gameManager.PlayerSessions.GetPlayer(uid) != nil {
+		s.handleDisconnect(uid)
+	}
+	return nil
+}
+
+// unbanPlayer lifts the ban of a player.
+func (s *Server) unbanPlayer(ctx context.Context, uid zkidentity.ShortID) error {
+	ban, err := s.db.FetchBan(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if ban == nil {
+		return fmt.Errorf("%s is not banned", uid)
+	}
+	return s.db.DeleteBan(ctx, uid)
+}
+
+// endGame stops a running game. With a winner, the winner takes the series
+// and the stakes are settled to them. Without one the series ends undecided
+// and the stakes are released to the players.
+func (s *Server) endGame(id string, winner *zkidentity.ShortID) error {
+	s.gameManager.RLock()
+	game := s.gameManager.Games[id]
+	s.gameManager.RUnlock()
+	if game == nil {
+		return fmt.Errorf("game %s not found", id)
+	}
+	if winner != nil {
+		playing := false
+		for _, player := range game.Players {
+			playing = playing || *player.ID == *winner
+		}
+		if !playing {
+			return fmt.Errorf("%s is not playing game %s", winner, id)
+		}
+	}
+	return game.Stop(winner)
+}
+
+// cancelRoom closes a waiting room and releases the stakes of its players.
+func (s *Server) cancelRoom(id string) error {
+	wr := s.gameManager.GetWaitingRoom(id)
+	if wr == nil {
+		return fmt.Errorf("waiting room %s not found", id)
+	}
+	s.notifyRoom(wr, wr.GetPlayers(), pong.NotificationType_WR_EXPIRED,
+		"Waiting room was cancelled by the server operator. Your stake was released.")
+	s.closeWaitingRoom(wr)
+	return nil
+}
+
+// adminSession is a connected player as listed by the admin API.
+type adminSession struct {
+	UID    zkidentity.ShortID `json:"uid"`
+	Nick   string             `json:"nick"`
+	BetAmt matoms.Amount      `json:"bet_amt"`
+	Ready  bool               `json:"ready"`
+	Room   string             `json:"room,omitempty"`
+	Game   string             `json:"game,omitempty"`
+}
+
+// adminGamePlayer is a player of a game listed by the admin API.
+type adminGamePlayer struct {
+	UID   zkidentity.ShortID `json:"uid"`
+	Nick  string             `json:"nick"`
+	Score int                `json:"score"`
+}
+
+// adminGame is a game in progress as listed by the admin API.
+type adminGame struct {
+	ID        string            `json:"id"`
+	Players   []adminGamePlayer `json:"players"`
+	Running   bool              `json:"running"`
+	StartedAt time.Time         `json:"started_at"`
+}
+
+func (s *Server) adminSessions() []adminSession {
+	s.gameManager.PlayerSessions.RLock()
+	players := make([]adminSession, 0, len(s.gameManager.PlayerSessions.Sessions))
+	for uid, player := range s.gameManager.PlayerSessions.Sessions {
+		session := adminSession{
+			UID:    uid,
+			Nick:   player.Nick,
+			BetAmt: player.BetAmt,
+			Ready:  player.Ready,
+		}
+		if player.WR != nil {
+			session.Room = player.WR.ID
+		}
+		players = append(players, session)
+	}
+	s.gameManager.PlayerSessions.RUnlock()
+
+	for i := range players {
+		if game := s.gameManager.GetPlayerGame(players[i].UID); game != nil {
+			players[i].Game = game.Id
+		}
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].UID.String() < players[j].UID.String()
+	})
+	return players
+}
+
+func (s *Server) adminGames() []adminGame {
+	s.gameManager.RLock()
+	defer s.gameManager.RUnlock()
+
+	games := make([]adminGame, 0, len(s.gameManager.Games))
+	for id, game := range s.gameManager.Games {
+		g := adminGame{ID: id, Players: []adminGamePlayer{}}
+		game.RLock()
+		g.Running = game.Running
+		g.StartedAt = game.StartedAt
+		game.RUnlock()
+		for _, player := range game.Players {
+			g.Players = append(g.Players, adminGamePlayer{
+				UID:   *player.ID,
+				Nick:  player.Nick,
+				Score: player.Score,
+			})
+		}
+		games = append(games, g)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].StartedAt.Before(games[j].StartedAt)
+	})
+	return games
+}
+
+func (s *Server) adminRooms() ([]*pong.WaitingRoom, error) {
+	s.gameManager.RLock()
+	rooms := append([]*ponggame.WaitingRoom(nil), s.gameManager.WaitingRooms...)
+	s.gameManager.RUnlock()
+
+	pongRooms := make([]*pong.WaitingRoom, 0, len(rooms))
+	for _, wr := range rooms {
+		pwr, err := wr.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		pongRooms = append(pongRooms, pwr)
+	}
+	return pongRooms, nil
+}
+
+// writeJSON encodes the response of an admin request.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, fmt.Sprintf("error encoding response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// parseUIDParam parses a query parameter holding the id of a player.
+func parseUIDParam(r *http.Request, name string) (zkidentity.ShortID, error) {
+	var uid zkidentity.ShortID
+	if err := uid.FromString(r.URL.Query().Get(name)); err != nil {
+		return uid, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return uid, nil
+}
+
+// handleAdminSessionsHandler lists the connected players.
+func (s *Server) handleAdminSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.adminSessions())
+}
+
+// handleAdminRoomsHandler lists the waiting rooms, private ones included.
+func (s *Server) handleAdminRoomsHandler(w http.ResponseWriter, r *http.Request) {
+	rooms, err := s.adminRooms()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error fetching waiting rooms: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rooms)
+}
+
+// handleAdminGamesHandler lists the games in progress.
+func (s *Server) handleAdminGamesHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.adminGames())
+}
+
+// handleAdminEndGameHandler stops the game with the given id. The winner
+// query parameter picks the player that wins it, without it the game ends
+// without a winner and the stakes are released.
+func (s *Server) handleAdminEndGameHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.URL.Query().Get("id")
+	var winner *zkidentity.ShortID
+	details := "no winner, stakes released"
+	if r.URL.Query().Get("winner") != "" {
+		uid, err := parseUIDParam(r, "winner")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		winner = &uid
+		details = fmt.Sprintf("winner %s", uid)
+	}
+
+	err := s.endGame(id, winner)
+	s.logAdminAction(r.Context(), "end_game", id, details, err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error ending game: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminCancelRoomHandler closes a waiting room, releasing the stakes of
+// its players.
+func (s *Server) handleAdminCancelRoomHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.URL.Query().Get("id")
+	err := s.cancelRoom(id)
+	s.logAdminAction(r.Context(), "cancel_room", id, "", err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error cancelling room: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminMarkPayoutHandler marks a payout paid or failed.
+func (s *Server) handleAdminMarkPayoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid id: %v", err), http.StatusBadRequest)
+		return
+	}
+	status := serverdb.TipStatus(r.URL.Query().Get("status"))
+
+	err = s.markPayout(r.Context(), id, status)
+	s.logAdminAction(r.Context(), "mark_payout", strconv.FormatUint(id, 10), string(status), err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error marking payout: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminBansHandler lists the banned players.
+func (s *Server) handleAdminBansHandler(w http.ResponseWriter, r *http.Request) {
+	bans, err := s.db.FetchBans(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error fetching bans: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, bans)
+}
+
+// handleAdminBanHandler bans the player with the given uid for reason.
+func (s *Server) handleAdminBanHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	uid, err := parseUIDParam(r, "uid")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reason := r.URL.Query().Get("reason")
+
+	err = s.banPlayer(r.Context(), uid, reason)
+	s.logAdminAction(r.Context(), "ban", uid.String(), reason, err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error banning player: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminUnbanHandler lifts the ban of the player with the given uid.
+func (s *Server) handleAdminUnbanHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	uid, err := parseUIDParam(r, "uid")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = s.unbanPlayer(r.Context(), uid)
+	s.logAdminAction(r.Context(), "unban", uid.String(), "", err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error unbanning player: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminActionsHandler lists the audit trail of admin actions taken
+// since the since unix timestamp.
+func (s *Server) handleAdminActionsHandler(w http.ResponseWriter, r *http.Request) {
+	since, err := parseUnixParam(r, "since")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	actions, err := s.db.FetchAdminActions(r.Context(), since)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error fetching admin actions: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, actions)
+}
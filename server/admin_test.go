@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func adminContext() context.Context {
+	return context.WithValue(context.Background(), adminCtxKey{}, "alice")
+}
+
+func TestAdminAuth(t *testing.T) {
+	srv := setupTestServer(t)
+	handler := srv.requireAdmin(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "alice", adminFromContext(r.Context()))
+	})
+
+	call := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/admin/sessions", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	// The admin API is disabled without tokens.
+	require.Equal(t, http.StatusForbidden, call("secret"))
+
+	srv.adminTokens = map[string]string{"alice": "secret", "bob": "other"}
+	require.Equal(t, http.StatusUnauthorized, call(""))
+	require.Equal(t, http.StatusUnauthorized, call("wrong"))
+	require.Equal(t, http.StatusOK, call("secret"))
+}
+
+func TestAdminBan(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := adminContext()
+	p1ID, _ := setupChallengePlayers(t, srv)
+
+	w := httptest.NewRecorder()
+	srv.handleAdminBanHandler(w, httptest.NewRequest(http.MethodPost,
+		"/admin/ban?uid="+p1ID.String()+"&reason=cheating", nil).WithContext(ctx))
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	// Banned players are disconnected, refunded and can't play.
+	require.Nil(t, srv.gameManager.PlayerSessions.GetPlayer(p1ID))
+	funds, err := srv.playerFunds(ctx, p1ID)
+	require.NoError(t, err)
+	require.Zero(t, funds)
+	require.ErrorIs(t, srv.checkLimits(ctx, p1ID, 10000000000), errBanned)
+
+	bans, err := srv.db.FetchBans(ctx)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+	require.Equal(t, "cheating", bans[0].Reason)
+	require.Equal(t, "alice", bans[0].BannedBy)
+
+	require.NoError(t, srv.unbanPlayer(ctx, p1ID))
+	require.NoError(t, srv.checkLimits(ctx, p1ID, 0))
+	require.Error(t, srv.unbanPlayer(ctx, p1ID))
+
+	actions, err := srv.db.FetchAdminActions(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	require.Equal(t, "ban", actions[0].Action)
+	require.Equal(t, "alice", actions[0].Admin)
+	require.Equal(t, p1ID.String(), actions[0].Target)
+}
+
+func TestAdminCancelRoom(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := adminContext()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	srv.handleAdminCancelRoomHandler(w, httptest.NewRequest(http.MethodPost,
+		"/admin/rooms/cancel?id="+resp.Wr.Id, nil).WithContext(ctx))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Nil(t, srv.gameManager.GetWaitingRoom(resp.Wr.Id))
+	for _, uid := range []zkidentity.ShortID{p1ID, p2ID} {
+		require.Nil(t, srv.gameManager.PlayerSessions.GetPlayer(uid).WR)
+	}
+	available, reserved, err := srv.fetchPlayerBalance(ctx, p2ID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(50000000000), available)
+	require.Zero(t, reserved)
+
+	// Failed actions are in the audit trail too.
+	w = httptest.NewRecorder()
+	srv.handleAdminCancelRoomHandler(w, httptest.NewRequest(http.MethodPost,
+		"/admin/rooms/cancel?id="+resp.Wr.Id, nil).WithContext(ctx))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	actions, err := srv.db.FetchAdminActions(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	require.Empty(t, actions[0].Error)
+	require.Contains(t, actions[1].Error, "not found")
+}
+
+func TestAdminEndGame(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := adminContext()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+
+	done := make(chan struct{})
+	go func() {
+		srv.handleGameLifecycle(context.Background(), wr.ID, wr.GetPlayers(), wr.BetAmount,
+			ponggame.GameRules{MaxScore: 3, BestOf: 3})
+		close(done)
+	}()
+	var games []adminGame
+	require.Eventually(t, func() bool {
+		games = srv.adminGames()
+		return len(games) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, games[0].Players, 2)
+
+	var stranger zkidentity.ShortID
+	require.Error(t, srv.endGame(games[0].ID, &stranger))
+	require.NoError(t, srv.endGame(games[0].ID, &p2ID))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("game did not end")
+	}
+
+	// The chosen winner takes the series and the stakes.
+	records, err := srv.db.FetchSendTipProgressByClient(ctx, p2ID[:])
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, matoms.Amount(100000000000), records[0].TotalAmount)
+
+	// Payouts can be marked paid by hand.
+	require.NoError(t, srv.markPayout(ctx, records[0].ID, serverdb.StatusPaid))
+	require.Error(t, srv.markPayout(ctx, records[0].ID, serverdb.StatusFailed))
+	report, err := srv.reconcile(ctx)
+	require.NoError(t, err)
+	require.True(t, report.OK)
+}
+
+func TestAdminRoutes(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.adminTokens = map[string]string{"alice": "secret"}
+	handler := srv.httpHandler()
+	p1ID, _ := setupChallengePlayers(t, srv)
+
+	call := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// The tips of players are only shown to operators.
+	for _, path := range []string{
+		"/received?clientID=" + p1ID.String(),
+		"/fetchAllUnprocessedTips",
+		"/tipprogress?clientID=" + p1ID.String(),
+	} {
+		require.Equal(t, http.StatusUnauthorized, call(path, ""), path)
+		require.Equal(t, http.StatusOK, call(path, "secret"), path)
+	}
+
+	// Public endpoints stay open.
+	require.Equal(t, http.StatusOK, call("/healthz", ""))
+	require.Equal(t, http.StatusOK, call("/metrics", ""))
+}
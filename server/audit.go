@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// auditPosting is a line of the audit export: one posting of a journal
+// entry.
+type auditPosting struct {
+	Time    time.Time          `json:"time"`
+	EntryID uint64             `json:"entry_id"`
+	Kind    serverdb.EntryKind `json:"kind"`
+	Ref     string             `json:"ref,omitempty"`
+	Account string             `json:"account"`
+	Amount  matoms.Amount      `json:"amount"`
+}
+
+// auditTotal is the number of journal entries of a kind and the amount they
+// moved.
+type auditTotal struct {
+	Kind    serverdb.EntryKind `json:"kind"`
+	Entries int                `json:"entries"`
+	Amount  matoms.Amount      `json:"amount"`
+}
+
+// auditExport is every money movement recorded over a period: tips received,
+// stakes reserved, released and settled, payouts, refunds and withdrawals.
+type auditExport struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Postings []auditPosting `json:"postings"`
+	Totals   []auditTotal   `json:"totals"`
+	// Net is how much the balance of every account changed.
+	Net map[string]matoms.Amount `json:"net"`
+}
+
+// fetchAuditExport returns the journal entries recorded from from until
+// to. A zero to means until now.
+func (s *Server) fetchAuditExport(ctx context.Context, from, to time.Time) (*auditExport, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	entries, err := s.db.FetchJournalEntries(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &auditExport{
+		From:     from,
+		To:       to,
+		Postings: []auditPosting{},
+		Totals:   []auditTotal{},
+		Net:      make(map[string]matoms.Amount),
+	}
+	totals := make(map[serverdb.EntryKind]*auditTotal)
+	for _, entry := range entries {
+		if !entry.CreatedAt.Before(to) {
+			continue
+		}
+		total := totals[entry.Kind]
+		if total == nil {
+			total = &auditTotal{Kind: entry.Kind}
+			totals[entry.Kind] = total
+		}
+		total.Entries++
+		for _, p := range entry.Postings {
+			export.Postings = append(export.Postings, auditPosting{
+				Time:    entry.CreatedAt,
+				EntryID: entry.ID,
+				Kind:    entry.Kind,
+				Ref:     entry.Ref,
+				Account: p.Account,
+				Amount:  p.Amount,
+			})
+			export.Net[p.Account] += p.Amount
+			if p.Amount > 0 {
+				total.Amount += p.Amount
+			}
+		}
+	}
+	for _, total := range totals {
+		export.Totals = append(export.Totals, *total)
+	}
+	sort.Slice(export.Totals, func(i, j int) bool {
+		return export.Totals[i].Kind < export.Totals[j].Kind
+	})
+	return export, nil
+}
+
+// writeCSV writes the postings of the export followed by its totals. Total
+// rows carry the number of entries instead of an entry id.
+func (e *auditExport) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"type", "time", "entry_id", "kind", "ref", "account", "entries", "amount_matoms", "amount_dcr"})
+	for _, p := range e.Postings {
+		cw.Write([]string{"posting", p.Time.UTC().Format(time.RFC3339), strconv.FormatUint(p.EntryID, 10),
+			string(p.Kind), p.Ref, p.Account, "", strconv.FormatInt(int64(p.Amount), 10), p.Amount.String()})
+	}
+	for _, t := range e.Totals {
+		cw.Write([]string{"total", "", "", string(t.Kind), "", "", strconv.Itoa(t.Entries),
+			strconv.FormatInt(int64(t.Amount), 10), t.Amount.String()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// stuckTip is a tip left in the sending status without a payout in flight
+// to finish it.
+type stuckTip struct {
+	UID        string        `json:"uid"`
+	SequenceID uint64        `json:"sequence_id"`
+	Amount     matoms.Amount `json:"amount"`
+	Reason     string        `json:"reason"`
+}
+
+// orphanedPayout is a payout record whose tips don't back it.
+type orphanedPayout struct {
+	ID     uint64             `json:"id"`
+	UID    string             `json:"uid"`
+	Status serverdb.TipStatus `json:"status"`
+	Amount matoms.Amount      `json:"amount"`
+	Reason string             `json:"reason"`
+}
+
+// reconciliation is the result of cross checking the ledger, the received
+// tips and the payout records.
+type reconciliation struct {
+	CheckedAt time.Time `json:"checked_at"`
+	// LedgerError is why the journal doesn't replay to the stored
+	// balances, if it doesn't.
+	LedgerError       string           `json:"ledger_error,omitempty"`
+	StuckTips         []stuckTip       `json:"stuck_tips"`
+	OrphanedPayouts   []orphanedPayout `json:"orphaned_payouts"`
+	BalanceMismatches []fundsMismatch  `json:"balance_mismatches"`
+	// OK is set when nothing was flagged.
+	OK bool `json:"ok"`
+}
+
+// reconcile flags tips stuck in sending, payout records that aren't backed
+// by their tips and players whose funds drifted from their tips.
+func (s *Server) reconcile(ctx context.Context) (*reconciliation, error) {
+	report := &reconciliation{
+		CheckedAt:         time.Now(),
+		StuckTips:         []stuckTip{},
+		OrphanedPayouts:   []orphanedPayout{},
+		BalanceMismatches: []fundsMismatch{},
+	}
+	if err := s.db.CheckLedger(ctx); err != nil {
+		report.LedgerError = err.Error()
+	}
+	mismatches, err := s.fundsMismatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.BalanceMismatches = append(report.BalanceMismatches, mismatches...)
+
+	tips, err := s.db.FetchReceivedTips(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.db.FetchTipProgressByStatus(ctx, serverdb.StatusQueued,
+		serverdb.StatusSending, serverdb.StatusFailed, serverdb.StatusPaid)
+	if err != nil {
+		return nil, err
+	}
+
+	statusBySeq := make(map[uint64]serverdb.TipStatus, len(tips))
+	for _, tip := range tips {
+		statusBySeq[tip.Tip.SequenceId] = tip.Status
+	}
+	recordBySeq := make(map[uint64]*serverdb.TipProgressRecord)
+	for _, record := range records {
+		var uid zkidentity.ShortID
+		reason := ""
+		if err := uid.FromBytes(record.WinnerUID); err != nil {
+			reason = "invalid recipient"
+		}
+		for _, tip := range record.Tips {
+			if prev := recordBySeq[tip.SequenceId]; prev == nil || prev.Status == serverdb.StatusPaid {
+				recordBySeq[tip.SequenceId] = record
+			}
+			status, ok := statusBySeq[tip.SequenceId]
+			switch {
+			case reason != "":
+			case !ok:
+				reason = fmt.Sprintf("tip %d was never received", tip.SequenceId)
+			case status == serverdb.StatusUnpaid:
+				reason = fmt.Sprintf("tip %d is unpaid", tip.SequenceId)
+			case status == serverdb.StatusPaid && record.Status != serverdb.StatusPaid:
+				reason = fmt.Sprintf("tip %d was already paid", tip.SequenceId)
+			}
+		}
+		if reason != "" {
+			report.OrphanedPayouts = append(report.OrphanedPayouts, orphanedPayout{
+				ID:     record.ID,
+				UID:    fmt.Sprintf("%x", record.WinnerUID),
+				Status: record.Status,
+				Amount: record.TotalAmount,
+				Reason: reason,
+			})
+		}
+	}
+
+	for _, tip := range tips {
+		if tip.Status != serverdb.StatusSending {
+			continue
+		}
+		reason := ""
+		switch record := recordBySeq[tip.Tip.SequenceId]; {
+		case record == nil:
+			reason = "no payout holds the tip"
+		case record.Status == serverdb.StatusFailed:
+			reason = fmt.Sprintf("payout %d failed", record.ID)
+		case record.Status == serverdb.StatusPaid:
+			reason = fmt.Sprintf("payout %d completed without marking the tip paid", record.ID)
+		default:
+			continue
+		}
+		report.StuckTips = append(report.StuckTips, stuckTip{
+			UID:        fmt.Sprintf("%x", tip.Tip.Uid),
+			SequenceID: tip.Tip.SequenceId,
+			Amount:     matoms.Amount(tip.Tip.AmountMatoms),
+			Reason:     reason,
+		})
+	}
+	sort.Slice(report.StuckTips, func(i, j int) bool {
+		return report.StuckTips[i].SequenceID < report.StuckTips[j].SequenceID
+	})
+	report.OK = report.LedgerError == "" && len(report.StuckTips) == 0 &&
+		len(report.OrphanedPayouts) == 0 && len(report.BalanceMismatches) == 0
+	return report, nil
+}
+
+// parseUnixParam parses an optional query parameter holding unix seconds.
+func parseUnixParam(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// handleAuditExportHandler exports the ledger postings between the from and
+// to unix timestamps, as JSON or, with format=csv, as CSV.
+func (s *Server) handleAuditExportHandler(w http.ResponseWriter, r *http.Request) {
+	from, err := parseUnixParam(r, "from")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseUnixParam(r, "to")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, fmt.Sprintf("invalid format: %s", format), http.StatusBadRequest)
+		return
+	}
+
+	export, err := s.fetchAuditExport(r.Context(), from, to)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error exporting ledger: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		if err := export.writeCSV(w); err != nil {
+			s.log.Errorf("Failed to write audit export: %v", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(export); err != nil {
+		http.Error(w, fmt.Sprintf("error encoding response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// handleReconcileHandler reports the tips, payouts and balances that don't
+// reconcile.
+func (s *Server) handleReconcileHandler(w http.ResponseWriter, r *http.Request) {
+	report, err := s.reconcile(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error reconciling: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, fmt.Sprintf("error encoding response: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func TestAuditExport(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	srv.handleGameEnd(ctx, wr.ID, &ponggame.GameInstance{Id: "game1", Winner: &p1ID}, series, players)
+
+	export, err := srv.fetchAuditExport(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	totals := make(map[serverdb.EntryKind]auditTotal)
+	for _, total := range export.Totals {
+		totals[total.Kind] = total
+	}
+	require.Equal(t, auditTotal{Kind: serverdb.EntryDeposit, Entries: 2, Amount: 100000000000}, totals[serverdb.EntryDeposit])
+	require.Equal(t, 2, totals[serverdb.EntryReserve].Entries)
+	require.Equal(t, matoms.Amount(100000000000), totals[serverdb.EntrySettle].Amount)
+	require.Equal(t, matoms.Amount(100000000000), totals[serverdb.EntryPayout].Amount)
+	require.Equal(t, matoms.Amount(-100000000000), export.Net[serverdb.DepositsAccount])
+	require.Equal(t, matoms.Amount(100000000000), export.Net[serverdb.PayoutsAccount])
+
+	// Nothing was recorded before the period.
+	export, err = srv.fetchAuditExport(ctx, time.Time{}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, export.Postings)
+
+	w := httptest.NewRecorder()
+	srv.handleAuditExportHandler(w, httptest.NewRequest(http.MethodGet, "/audit/export?format=csv", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "amount_matoms", rows[0][7])
+	last := rows[len(rows)-1]
+	require.Equal(t, "total", last[0])
+	require.Equal(t, string(serverdb.EntrySettle), last[3])
+	require.Equal(t, "1.00000000", last[8])
+
+	w = httptest.NewRecorder()
+	srv.handleAuditExportHandler(w, httptest.NewRequest(http.MethodGet, "/audit/export?format=xml", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReconcile(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	// Refunds queue a payout that holds the tips being sent.
+	require.NoError(t, srv.handleReturnUnprocessedTips(ctx, p1ID))
+	report, err := srv.reconcile(ctx)
+	require.NoError(t, err)
+	require.True(t, report.OK)
+
+	// A tip spent without a payout is stuck and leaves the ledger behind.
+	tipID := make([]byte, 8)
+	binary.BigEndian.PutUint64(tipID, 401)
+	require.NoError(t, srv.db.UpdateTipStatus(ctx, p2ID[:], tipID, serverdb.StatusSending))
+	report, err = srv.reconcile(ctx)
+	require.NoError(t, err)
+	require.False(t, report.OK)
+	require.Len(t, report.StuckTips, 1)
+	require.Equal(t, uint64(401), report.StuckTips[0].SequenceID)
+	require.Equal(t, "no payout holds the tip", report.StuckTips[0].Reason)
+	require.Equal(t, []fundsMismatch{{UID: p2ID.String(), Ledger: 50000000000}}, report.BalanceMismatches)
+	require.Empty(t, report.OrphanedPayouts)
+
+	// Payouts of tips that are still unpaid are orphaned.
+	records, err := srv.db.FetchSendTipProgressByClient(ctx, p1ID[:])
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	binary.BigEndian.PutUint64(tipID, 400)
+	require.NoError(t, srv.db.UpdateTipStatus(ctx, p1ID[:], tipID, serverdb.StatusUnpaid))
+	report, err = srv.reconcile(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.OrphanedPayouts, 1)
+	require.Equal(t, records[0].ID, report.OrphanedPayouts[0].ID)
+	require.Equal(t, "tip 400 is unpaid", report.OrphanedPayouts[0].Reason)
+}
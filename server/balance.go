@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// playerBalance is the balance of a player along with the payouts sent to
+// them that haven't completed yet.
+type playerBalance struct {
+	available     matoms.Amount
+	reserved      matoms.Amount
+	pendingPayout matoms.Amount
+}
+
+func (b *playerBalance) marshal() *pong.Balance {
+	return &pong.Balance{
+		Available:     int64(b.available),
+		Reserved:      int64(b.reserved),
+		PendingPayout: int64(b.pendingPayout),
+	}
+}
+
+// fetchBalance returns the available and reserved balance of a player along
+// with the payouts sent to them that haven't completed yet.
+func (s *Server) fetchBalance(ctx context.Context, uid zkidentity.ShortID) (*playerBalance, error) {
+	available, reserved, err := s.fetchPlayerBalance(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.db.FetchSendTipProgressByClient(ctx, uid.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payouts of %s: %v", uid, err)
+	}
+	balance := &playerBalance{available: available, reserved: reserved}
+	for _, record := range records {
+		if record.Status != serverdb.StatusPaid {
+			balance.pendingPayout += record.TotalAmount
+		}
+	}
+	return balance, nil
+}
+
+// sendBalance queues a payout of an amount of the available balance of a
+// player back to them. The payout is recorded in the ledger before it is
+// queued so concurrent payouts can't overdraw the player, and reversed if
+// the player's tips can't cover it.
+func (s *Server) sendBalance(ctx context.Context, uid zkidentity.ShortID, kind serverdb.EntryKind, amount matoms.Amount) error {
+	amount, err := s.recordPayout(ctx, kind, uid.String(), uid, amount)
+	if err != nil {
+		return err
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	// Spend the amount from the player's tips and queue the payout with
+	// the tips it used up.
+	tips, err := s.db.SpendTips(ctx, uid, amount, serverdb.StatusSending)
+	if err != nil {
+		reversal := &serverdb.JournalEntry{
+			Kind: serverdb.EntryReversal,
+			Ref:  uid.String(),
+			Postings: []serverdb.Posting{
+				{Account: serverdb.PayoutsAccount, Amount: -amount},
+				{Account: serverdb.PlayerAccount(uid), Amount: amount},
+			},
+		}
+		if rerr := s.db.PostJournalEntry(ctx, reversal); rerr != nil {
+			s.log.Errorf("Failed to reverse %s of %s: %v", kind, uid, rerr)
+		}
+		return fmt.Errorf("failed to spend tips of %s: %v", uid, err)
+	}
+	return s.queuePayout(ctx, uid, amount, tips)
+}
+
+// notifyBalance sends the current balance of a connected player along with a
+// payout notification.
+func (s *Server) notifyBalance(ctx context.Context, uid zkidentity.ShortID, typ pong.NotificationType, msg string) {
+	player := s.gameManager.PlayerSessions.GetPlayer(uid)
+	if player == nil || player.NotifierStream == nil {
+		return
+	}
+	balance, err := s.fetchBalance(ctx, uid)
+	if err != nil {
+		s.log.Errorf("Failed to fetch balance of %s: %v", uid, err)
+		return
+	}
+	player.BetAmt = balance.available + balance.reserved
+	player.NotifierStream.Send(&pong.NtfnStreamResponse{
+		NotificationType: typ,
+		Message:          msg,
+		PlayerId:         uid.String(),
+		BetAmt:           int64(player.BetAmt),
+		Balance:          balance.marshal(),
+	})
+}
+
+// notifyPayoutProgress tells the recipient of a payout about its progress.
+func (s *Server) notifyPayoutProgress(ctx context.Context, tip *types.TipProgressEvent) {
+	var uid zkidentity.ShortID
+	if err := uid.FromBytes(tip.Uid); err != nil {
+		return
+	}
+	amount := matoms.Amount(tip.AmountMatoms)
+	switch {
+	case tip.Completed:
+		s.notifyBalance(ctx, uid, pong.NotificationType_PAYOUT_COMPLETED,
+			fmt.Sprintf("Payout of %s completed", amount))
+	case tip.AttemptErr != "":
+		s.notifyBalance(ctx, uid, pong.NotificationType_PAYOUT_PROGRESS,
+			fmt.Sprintf("Payout of %s failed on attempt %d: %s", amount, tip.Attempt, tip.AttemptErr))
+	}
+}
+
+// GetBalance returns the available, reserved and pending payout amounts of a
+// player.
+func (s *Server) GetBalance(ctx context.Context, req *pong.GetBalanceRequest) (*pong.GetBalanceResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	balance, err := s.fetchBalance(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return &pong.GetBalanceResponse{Balance: balance.marshal()}, nil
+}
+
+// Withdraw sends part or all of the available balance of a player back to
+// them. Stakes reserved in waiting rooms, games and tournaments can't be
+// withdrawn.
+func (s *Server) Withdraw(ctx context.Context, req *pong.WithdrawRequest) (*pong.WithdrawResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("withdrawal amount can't be negative")
+	}
+	if s.isF2P {
+		return nil, fmt.Errorf("credits of free-to-play servers can't be withdrawn")
+	}
+
+	available, reserved, err := s.fetchPlayerBalance(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	// Only whole atoms can be sent, so withdrawing everything leaves the
+	// milli-atoms left over in the balance.
+	amount := matoms.Amount(req.Amount)
+	if amount%matoms.PerAtom != 0 {
+		return nil, fmt.Errorf("withdrawal amount must be a whole number of atoms")
+	}
+	if amount == 0 {
+		amount = matoms.FromAtoms(available.Atoms())
+	}
+	if amount == 0 {
+		return nil, fmt.Errorf("no available balance to withdraw")
+	}
+	if amount > available {
+		return nil, fmt.Errorf("insufficient balance. Available: %s, Reserved: %s, Requested: %s",
+			available, reserved, amount)
+	}
+
+	if err := s.sendBalance(ctx, clientID, serverdb.EntryWithdrawal, amount); err != nil {
+		s.log.Errorf("Withdrawal of %s failed: %v", clientID, err)
+		return nil, err
+	}
+	s.log.Infof("Player %s withdrew %s", clientID, amount)
+	s.notifyBalance(ctx, clientID, pong.NotificationType_WITHDRAWAL_STARTED,
+		fmt.Sprintf("Withdrawal of %s sent", amount))
+
+	balance, err := s.fetchBalance(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return &pong.WithdrawResponse{Amount: int64(amount), Balance: balance.marshal()}, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func TestWithdraw(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+
+	_, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 10000000000,
+	})
+	require.NoError(t, err)
+	res, err := srv.GetBalance(ctx, &pong.GetBalanceRequest{ClientId: p1ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, int64(40000000000), res.Balance.Available)
+	require.Equal(t, int64(10000000000), res.Balance.Reserved)
+	require.Zero(t, res.Balance.PendingPayout)
+
+	// Reserved stakes can't be withdrawn.
+	_, err = srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: p1ID.String(), Amount: 50000000000})
+	require.ErrorContains(t, err, "insufficient balance")
+
+	withdrawal, err := srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: p1ID.String(), Amount: 10000000000})
+	require.NoError(t, err)
+	srv.processPayouts(ctx, time.Now())
+	require.Equal(t, int64(10000000000), withdrawal.Amount)
+	require.Equal(t, int64(30000000000), withdrawal.Balance.Available)
+	require.Equal(t, int64(10000000000), withdrawal.Balance.PendingPayout)
+	require.Contains(t, srv.bot.(*minimalTestBot).paidTips, p1ID.String())
+	msgs := p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_WITHDRAWAL_STARTED, msgs[len(msgs)-1].NotificationType)
+	require.Equal(t, int64(40000000000), msgs[len(msgs)-1].BetAmt)
+
+	// A zero amount withdraws the rest of the available balance.
+	withdrawal, err = srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: p1ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, int64(30000000000), withdrawal.Amount)
+	require.Zero(t, withdrawal.Balance.Available)
+	require.Equal(t, int64(40000000000), withdrawal.Balance.PendingPayout)
+	_, err = srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: p1ID.String()})
+	require.Error(t, err)
+	require.NoError(t, srv.checkLedger(ctx))
+
+	// Completed payouts are no longer pending.
+	err = srv.HandleTipProgress(ctx, &types.TipProgressEvent{
+		SequenceId:   1,
+		Uid:          p1ID[:],
+		AmountMatoms: 10000000000,
+		Completed:    true,
+	})
+	require.NoError(t, err)
+	res, err = srv.GetBalance(ctx, &pong.GetBalanceRequest{ClientId: p1ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, int64(30000000000), res.Balance.PendingPayout)
+	msgs = p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PAYOUT_COMPLETED, msgs[len(msgs)-1].NotificationType)
+}
+
+func TestWithdrawSubAtomAmount(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+
+	var uid zkidentity.ShortID
+	_ = uid.FromString(strings.Repeat("6", 64))
+	createTestPlayer(srv, uid)
+	err := srv.HandleReceiveTip(ctx, &types.ReceivedTip{Uid: uid[:], AmountMatoms: 50000000500, SequenceId: 600})
+	require.NoError(t, err)
+
+	// Milli-atoms can't be sent.
+	_, err = srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: uid.String(), Amount: 10000000500})
+	require.ErrorContains(t, err, "whole number of atoms")
+
+	// Withdrawing everything sends the whole atoms and keeps the rest.
+	withdrawal, err := srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: uid.String()})
+	require.NoError(t, err)
+	require.Equal(t, int64(50000000000), withdrawal.Amount)
+	require.Equal(t, int64(500), withdrawal.Balance.Available)
+	require.Equal(t, int64(50000000000), withdrawal.Balance.PendingPayout)
+	srv.processPayouts(ctx, time.Now())
+	require.Equal(t, dcrutil.Amount(50000000), srv.bot.(*minimalTestBot).paidTips[uid.String()])
+	_, err = srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: uid.String()})
+	require.ErrorContains(t, err, "no available balance")
+	require.NoError(t, srv.checkLedger(ctx))
+}
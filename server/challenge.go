@@ -0,0 +1,406 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+const (
+	defaultChallengeTimeout = 2 * time.Minute
+	maxChallengeTimeout     = 15 * time.Minute
+
+	// challengeCooldown is how long a player has to wait between sending
+	// challenges, which notify their target with a PM.
+	challengeCooldown = 30 * time.Second
+)
+
+// challenge is a pending invitation from one player to another to play a game
+// with a given stake and rules.
+type challenge struct {
+	id             string
+	challengerID   zkidentity.ShortID
+	challengerNick string
+	targetID       zkidentity.ShortID
+	betAmt         matoms.Amount
+	rules          ponggame.GameRules
+	expiresAt      time.Time
+	timer          *time.Timer
+}
+
+func (c *challenge) marshal() *pong.Challenge {
+	return &pong.Challenge{
+		Id:             c.id,
+		ChallengerId:   c.challengerID.String(),
+		ChallengerNick: c.challengerNick,
+		TargetId:       c.targetID.String(),
+		BetAmt:         int64(c.betAmt),
+		Rules:          c.rules.Marshal(),
+		ExpiresAt:      c.expiresAt.Unix(),
+	}
+}
+
+// ChallengePlayer sends a direct challenge to another player, found either by
+// client id or by nick. The challenged player has until the deadline to
+// accept or decline it.
+func (s *Server) ChallengePlayer(ctx context.Context, req *pong.ChallengePlayerRequest) (*pong.ChallengePlayerResponse, error) {
+	var challengerID zkidentity.ShortID
+	if err := challengerID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	challenger := s.gameManager.PlayerSessions.GetPlayer(challengerID)
+	if challenger == nil {
+		return nil, fmt.Errorf("player not found: %s", req.ClientId)
+	}
+	if err := s.checkAvailable(challenger); err != nil {
+		return nil, err
+	}
+
+	targetID, err := s.resolvePlayer(req.Target)
+	if err != nil {
+		return nil, err
+	}
+	if targetID == challengerID {
+		return nil, fmt.Errorf("cannot challenge yourself")
+	}
+
+	betAmt := matoms.Amount(req.BetAmt)
+	if err := s.validateBetAmt(betAmt); err != nil {
+		return nil, err
+	}
+	rules := ponggame.GameRulesFromProto(req.Rules)
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkLimits(ctx, challengerID, betAmt); err != nil {
+		return nil, err
+	}
+	if _, err := s.fetchStakeTips(ctx, challengerID, betAmt); err != nil {
+		return nil, err
+	}
+
+	timeout := defaultChallengeTimeout
+	if req.TimeoutSecs > 0 {
+		timeout = time.Duration(req.TimeoutSecs) * time.Second
+		if timeout > maxChallengeTimeout {
+			timeout = maxChallengeTimeout
+		}
+	}
+
+	id, err := utils.GenerateRandomString(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge ID: %w", err)
+	}
+	c := &challenge{
+		id:             id,
+		challengerID:   challengerID,
+		challengerNick: challenger.Nick,
+		targetID:       targetID,
+		betAmt:         betAmt,
+		rules:          rules,
+		expiresAt:      time.Now().Add(timeout),
+	}
+
+	s.challengesMtx.Lock()
+	if s.challenges == nil {
+		s.challenges = make(map[string]*challenge)
+	}
+	if s.challengeSent == nil {
+		s.challengeSent = make(map[zkidentity.ShortID]time.Time)
+	}
+	for _, pending := range s.challenges {
+		if pending.challengerID == challengerID && pending.targetID == targetID {
+			s.challengesMtx.Unlock()
+			return nil, fmt.Errorf("challenge to %s already pending", targetID)
+		}
+	}
+	now := time.Now()
+	if wait := s.challengeSent[challengerID].Add(challengeCooldown).Sub(now); wait > 0 {
+		s.challengesMtx.Unlock()
+		return nil, fmt.Errorf("sending challenges too fast, wait %s", wait.Round(time.Second))
+	}
+	s.challengeSent[challengerID] = now
+	s.challenges[id] = c
+	c.timer = time.AfterFunc(timeout, func() { s.expireChallenge(id) })
+	s.challengesMtx.Unlock()
+
+	pc := c.marshal()
+	name := challenger.Nick
+	if name == "" {
+		name = challengerID.String()
+	}
+	if target := s.gameManager.PlayerSessions.GetPlayer(targetID); target != nil && target.NotifierStream != nil {
+		target.NotifierStream.Send(&pong.NtfnStreamResponse{
+			NotificationType: pong.NotificationType_CHALLENGE_RECEIVED,
+			Message:          fmt.Sprintf("%s challenged you to a game", name),
+			PlayerId:         challengerID.String(),
+			BetAmt:           int64(c.betAmt),
+			Challenge:        pc,
+		})
+	}
+	msg := fmt.Sprintf("%s challenged you to pong for %s DCR, first to %d points. "+
+		"Open the pong client to accept or decline within %s (challenge %s).",
+		name, c.betAmt, rules.MaxScore, timeout.Round(time.Second), id)
+	if err := s.bot.SendPM(ctx, targetID.String(), msg); err != nil {
+		s.log.Warnf("failed to send challenge PM to %s: %v", targetID, err)
+	}
+
+	s.log.Debugf("challenge %s from %s to %s", id, challengerID, targetID)
+	return &pong.ChallengePlayerResponse{
+		Challenge: pc,
+	}, nil
+}
+
+// RespondChallenge accepts or declines a pending challenge. Accepting reserves
+// the stake of both players and puts them in a private waiting room, where
+// the game starts as soon as both signal they are ready.
+func (s *Server) RespondChallenge(ctx context.Context, req *pong.RespondChallengeRequest) (*pong.RespondChallengeResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+
+	s.challengesMtx.Lock()
+	c := s.challenges[req.ChallengeId]
+	s.challengesMtx.Unlock()
+	if c == nil || c.targetID != clientID {
+		return nil, fmt.Errorf("challenge not found: %s", req.ChallengeId)
+	}
+
+	if !req.Accept {
+		if !s.removeChallenge(c.id) {
+			return nil, fmt.Errorf("challenge not found: %s", req.ChallengeId)
+		}
+		s.notifyChallenge(c, c.challengerID, pong.NotificationType_CHALLENGE_DECLINED,
+			"Your challenge was declined", nil)
+		return &pong.RespondChallengeResponse{}, nil
+	}
+
+	challenger := s.gameManager.PlayerSessions.GetPlayer(c.challengerID)
+	if challenger == nil {
+		return nil, fmt.Errorf("challenger %s is not connected", c.challengerID)
+	}
+	target := s.gameManager.PlayerSessions.GetPlayer(clientID)
+	if target == nil {
+		return nil, fmt.Errorf("player not found: %s", req.ClientId)
+	}
+	for _, p := range []*ponggame.Player{challenger, target} {
+		if err := s.checkAvailable(p); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkLimits(ctx, clientID, c.betAmt); err != nil {
+		return nil, err
+	}
+	if err := s.checkOpponentLimits(ctx, c.challengerID, c.betAmt); err != nil {
+		return nil, err
+	}
+	challengerTips, err := s.fetchStakeTips(ctx, c.challengerID, c.betAmt)
+	if err != nil {
+		return nil, fmt.Errorf("challenger can't cover the stake: %v", err)
+	}
+	targetTips, err := s.fetchStakeTips(ctx, clientID, c.betAmt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Removing the challenge is what commits the accept, so it can't race
+	// with its expiration.
+	if !s.removeChallenge(c.id) {
+		return nil, fmt.Errorf("challenge not found: %s", req.ChallengeId)
+	}
+
+	wr, err := s.createPairedRoom(challenger, target, c.betAmt, c.rules,
+		append(challengerTips, targetTips...))
+	if err != nil {
+		return nil, err
+	}
+	pongWR, err := wr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range []zkidentity.ShortID{c.challengerID, clientID} {
+		s.notifyChallenge(c, id, pong.NotificationType_CHALLENGE_ACCEPTED,
+			"Challenge accepted, signal ready to start the game", pongWR)
+	}
+
+	return &pong.RespondChallengeResponse{
+		Wr: pongWR,
+	}, nil
+}
+
+// expireChallenge drops a challenge that was not answered in time.
+func (s *Server) expireChallenge(id string) {
+	s.challengesMtx.Lock()
+	c := s.challenges[id]
+	delete(s.challenges, id)
+	s.challengesMtx.Unlock()
+	if c == nil {
+		return
+	}
+
+	s.log.Debugf("challenge %s expired", id)
+	for _, uid := range []zkidentity.ShortID{c.challengerID, c.targetID} {
+		s.notifyChallenge(c, uid, pong.NotificationType_CHALLENGE_EXPIRED,
+			"Challenge expired", nil)
+	}
+}
+
+// removeChallenge deletes a pending challenge, returning false if it was
+// already answered or expired.
+func (s *Server) removeChallenge(id string) bool {
+	s.challengesMtx.Lock()
+	defer s.challengesMtx.Unlock()
+	c := s.challenges[id]
+	if c == nil {
+		return false
+	}
+	c.timer.Stop()
+	delete(s.challenges, id)
+	return true
+}
+
+func (s *Server) notifyChallenge(c *challenge, to zkidentity.ShortID, typ pong.NotificationType, msg string, wr *pong.WaitingRoom) {
+	player := s.gameManager.PlayerSessions.GetPlayer(to)
+	if player == nil || player.NotifierStream == nil {
+		return
+	}
+	player.NotifierStream.Send(&pong.NtfnStreamResponse{
+		NotificationType: typ,
+		Message:          msg,
+		PlayerId:         to.String(),
+		BetAmt:           int64(c.betAmt),
+		Challenge:        c.marshal(),
+		Wr:               wr,
+	})
+}
+
+// resolvePlayer finds a player by client id or, failing that, by the nick of
+// a connected player. Nicks used by more than one player are rejected, as
+// anyone may take a nick.
+func (s *Server) resolvePlayer(target string) (zkidentity.ShortID, error) {
+	var uid zkidentity.ShortID
+	if target == "" {
+		return uid, fmt.Errorf("no player to challenge")
+	}
+	if err := uid.FromString(target); err == nil {
+		return uid, nil
+	}
+	players := s.gameManager.PlayerSessions.GetPlayersByNick(target)
+	switch len(players) {
+	case 0:
+		return uid, fmt.Errorf("player not found: %s", target)
+	case 1:
+		return *players[0].ID, nil
+	}
+	uids := make([]string, 0, len(players))
+	for _, player := range players {
+		uids = append(uids, player.ID.String())
+	}
+	sort.Strings(uids)
+	return uid, fmt.Errorf("nick %s is used by several players, challenge one of them by id: %s",
+		target, strings.Join(uids, ", "))
+}
+
+// checkAvailable returns an error if the player is already committed to a
+// waiting room, game or tournament.
+func (s *Server) checkAvailable(player *ponggame.Player) error {
+	if player.WR != nil {
+		return fmt.Errorf("player %s is already in a waiting room", player.ID)
+	}
+	if s.gameManager.GetPlayerGame(*player.ID) != nil {
+		return fmt.Errorf("player %s is already in a game", player.ID)
+	}
+	if s.tournamentHoldsStake(*player.ID) {
+		return fmt.Errorf("player %s is registered in a tournament", player.ID)
+	}
+	return nil
+}
+
+func (s *Server) validateBetAmt(betAmt matoms.Amount) error {
+	if !s.isF2P && betAmt == 0 {
+		return fmt.Errorf("bet needs to be higher than 0")
+	}
+	if !s.isF2P && betAmt < s.minBetAmt {
+		return fmt.Errorf("bet needs to be higher than %s", s.minBetAmt)
+	}
+	return nil
+}
+
+// fetchStakeTips returns the unpaid tips backing the stake of a player,
+// failing if their available balance can't cover it. Only the stake is
+// reserved, the rest of the balance stays available. Credits staked on
+// free-to-play servers aren't backed by tips.
+func (s *Server) fetchStakeTips(ctx context.Context, uid zkidentity.ShortID, betAmt matoms.Amount) ([]*types.ReceivedTip, error) {
+	s.grantCredits(ctx, uid, time.Now())
+	available, _, err := s.fetchPlayerBalance(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if available < betAmt {
+		return nil, fmt.Errorf("insufficient balance. Available: %s, Required: %s",
+			available, betAmt)
+	}
+	if s.isF2P {
+		return nil, nil
+	}
+	tips, err := s.db.FetchReceivedTipsByUID(ctx, uid, serverdb.StatusUnpaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch player tips: %v", err)
+	}
+	return tips, nil
+}
+
+// createPairedRoom creates a private waiting room already holding both
+// players and their reserved tips.
+func (s *Server) createPairedRoom(host, guest *ponggame.Player, betAmt matoms.Amount, rules ponggame.GameRules, tips []*types.ReceivedTip) (*ponggame.WaitingRoom, error) {
+	wr, err := ponggame.NewWaitingRoom(host, betAmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create waiting room: %v", err)
+	}
+	if _, err := wr.MakePrivate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if err := s.reserveStake(ctx, wr.ID, *host.ID, betAmt); err != nil {
+		return nil, err
+	}
+	if err := s.reserveStake(ctx, wr.ID, *guest.ID, betAmt); err != nil {
+		s.releaseStakes(ctx, wr.ID)
+		return nil, err
+	}
+	wr.Invite(*guest.ID)
+	wr.AddPlayer(guest)
+
+	wr.Lock()
+	wr.Rules = rules
+	wr.ReservedTips = tips
+	wr.Unlock()
+	s.setRoomRake(wr)
+
+	host.WR = wr
+	guest.WR = wr
+
+	s.gameManager.Lock()
+	s.gameManager.WaitingRooms = append(s.gameManager.WaitingRooms, wr)
+	s.gameManager.Unlock()
+
+	select {
+	case s.waitingRoomCreated <- struct{}{}:
+	default:
+	}
+	return wr, nil
+}
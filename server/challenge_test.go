@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func setupChallengePlayers(t *testing.T, srv *Server) (zkidentity.ShortID, zkidentity.ShortID) {
+	t.Helper()
+	ctx := context.Background()
+
+	var challengerID, targetID zkidentity.ShortID
+	_ = challengerID.FromString(strings.Repeat("4", 64))
+	_ = targetID.FromString(strings.Repeat("5", 64))
+	for i, id := range []zkidentity.ShortID{challengerID, targetID} {
+		err := srv.db.StoreUnprocessedTip(ctx, &types.ReceivedTip{
+			Uid:          id[:],
+			AmountMatoms: 50000000000,
+			SequenceId:   uint64(400 + i),
+		})
+		require.NoError(t, err)
+		createTestPlayer(srv, id).BetAmt = 50000000000
+	}
+	srv.gameManager.PlayerSessions.GetPlayer(challengerID).Nick = "alice"
+	srv.gameManager.PlayerSessions.GetPlayer(targetID).Nick = "bob"
+	return challengerID, targetID
+}
+
+func TestChallengePlayerAccept(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	challengerID, targetID := setupChallengePlayers(t, srv)
+
+	// Players can be targeted by nick.
+	resp, err := srv.ChallengePlayer(ctx, &pong.ChallengePlayerRequest{
+		ClientId: challengerID.String(),
+		Target:   "Bob",
+		BetAmt:   50000000000,
+		Rules:    &pong.GameRules{MaxScore: 5},
+	})
+	require.NoError(t, err)
+	require.Equal(t, targetID.String(), resp.Challenge.TargetId)
+
+	target := srv.gameManager.PlayerSessions.GetPlayer(targetID)
+	msgs := target.NotifierStream.(*mockNotifierStream).messages
+	require.Len(t, msgs, 1)
+	require.Equal(t, pong.NotificationType_CHALLENGE_RECEIVED, msgs[0].NotificationType)
+	bot := srv.bot.(*minimalTestBot)
+	require.Len(t, bot.sentPMs, 1)
+	require.Equal(t, targetID.String(), bot.sentPMs[0].nick)
+
+	// Only the challenged player may answer.
+	_, err = srv.RespondChallenge(ctx, &pong.RespondChallengeRequest{
+		ClientId:    challengerID.String(),
+		ChallengeId: resp.Challenge.Id,
+		Accept:      true,
+	})
+	require.Error(t, err)
+
+	accepted, err := srv.RespondChallenge(ctx, &pong.RespondChallengeRequest{
+		ClientId:    targetID.String(),
+		ChallengeId: resp.Challenge.Id,
+		Accept:      true,
+	})
+	require.NoError(t, err)
+	require.Len(t, accepted.Wr.Players, 2)
+	require.True(t, accepted.Wr.Private)
+	require.Equal(t, int32(5), accepted.Wr.Rules.MaxScore)
+
+	wr := srv.gameManager.GetWaitingRoom(accepted.Wr.Id)
+	require.NotNil(t, wr)
+	require.Len(t, wr.ReservedTips, 2)
+	require.Equal(t, wr, target.WR)
+
+	// The challenge can't be answered twice.
+	_, err = srv.RespondChallenge(ctx, &pong.RespondChallengeRequest{
+		ClientId:    targetID.String(),
+		ChallengeId: resp.Challenge.Id,
+		Accept:      true,
+	})
+	require.Error(t, err)
+}
+
+func TestChallengePlayerDeclineAndExpire(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	challengerID, targetID := setupChallengePlayers(t, srv)
+	challenger := srv.gameManager.PlayerSessions.GetPlayer(challengerID)
+
+	req := &pong.ChallengePlayerRequest{
+		ClientId: challengerID.String(),
+		Target:   targetID.String(),
+		BetAmt:   50000000000,
+	}
+	resp, err := srv.ChallengePlayer(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), resp.Challenge.Rules.MaxScore)
+
+	// Only one pending challenge per pair.
+	_, err = srv.ChallengePlayer(ctx, req)
+	require.Error(t, err)
+
+	_, err = srv.RespondChallenge(ctx, &pong.RespondChallengeRequest{
+		ClientId:    targetID.String(),
+		ChallengeId: resp.Challenge.Id,
+	})
+	require.NoError(t, err)
+	msgs := challenger.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_CHALLENGE_DECLINED, msgs[len(msgs)-1].NotificationType)
+
+	// Challenges can't be sent again right away.
+	_, err = srv.ChallengePlayer(ctx, req)
+	require.ErrorContains(t, err, "too fast")
+	srv.challengeSent[challengerID] = time.Now().Add(-challengeCooldown)
+
+	resp, err = srv.ChallengePlayer(ctx, req)
+	require.NoError(t, err)
+	srv.expireChallenge(resp.Challenge.Id)
+	msgs = challenger.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_CHALLENGE_EXPIRED, msgs[len(msgs)-1].NotificationType)
+	require.Nil(t, challenger.WR)
+
+	_, err = srv.RespondChallenge(ctx, &pong.RespondChallengeRequest{
+		ClientId:    targetID.String(),
+		ChallengeId: resp.Challenge.Id,
+		Accept:      true,
+	})
+	require.Error(t, err)
+}
+
+func TestChallengeAmbiguousNick(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	challengerID, targetID := setupChallengePlayers(t, srv)
+
+	// Anyone can take a nick, so shared nicks don't resolve to a player.
+	var impostorID zkidentity.ShortID
+	_ = impostorID.FromString(strings.Repeat("6", 64))
+	createTestPlayer(srv, impostorID).Nick = "BOB"
+	_, err := srv.ChallengePlayer(ctx, &pong.ChallengePlayerRequest{
+		ClientId: challengerID.String(),
+		Target:   "bob",
+		BetAmt:   50000000000,
+	})
+	require.ErrorContains(t, err, "several players")
+	require.ErrorContains(t, err, targetID.String())
+	require.ErrorContains(t, err, impostorID.String())
+	require.Empty(t, srv.bot.(*minimalTestBot).sentPMs)
+
+	resp, err := srv.ChallengePlayer(ctx, &pong.ChallengePlayerRequest{
+		ClientId: challengerID.String(),
+		Target:   targetID.String(),
+		BetAmt:   50000000000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, targetID.String(), resp.Challenge.TargetId)
+}
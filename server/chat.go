@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+const (
+	// maxChatMessageLen is the max number of characters of a chat message.
+	maxChatMessageLen = 280
+
+	// chatRateBurst messages may be sent by a player within chatRateWindow.
+	chatRateBurst  = 5
+	chatRateWindow = 10 * time.Second
+)
+
+// chatState is the per player chat state kept by the server.
+type chatState struct {
+	sent  []time.Time
+	muted map[zkidentity.ShortID]struct{}
+}
+
+// SendChatMessage sends a text message to the players of a waiting room or
+// game the sender is in.
+func (s *Server) SendChatMessage(ctx context.Context, req *pong.SendChatMessageRequest) (*pong.SendChatMessageResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	sender := s.gameManager.PlayerSessions.GetPlayer(clientID)
+	if sender == nil {
+		return nil, fmt.Errorf("player not found: %s", req.ClientId)
+	}
+
+	text, err := cleanChatText(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, roomID, gameID, err := s.chatRecipients(sender, req.RoomId, req.GameId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !s.allowChat(clientID, now) {
+		return nil, fmt.Errorf("sending messages too fast, wait a few seconds")
+	}
+
+	msg := &pong.ChatMessage{
+		SenderId:   clientID.String(),
+		SenderNick: sender.Nick,
+		RoomId:     roomID,
+		GameId:     gameID,
+		Text:       text,
+		Timestamp:  now.Unix(),
+	}
+	for _, player := range recipients {
+		if player.NotifierStream == nil || s.isMuted(*player.ID, clientID) {
+			continue
+		}
+		player.NotifierStream.Send(&pong.NtfnStreamResponse{
+			NotificationType: pong.NotificationType_MESSAGE,
+			Message:          text,
+			PlayerId:         clientID.String(),
+			RoomId:           roomID,
+			GameId:           gameID,
+			Chat:             msg,
+		})
+	}
+
+	return &pong.SendChatMessageResponse{
+		Chat: msg,
+	}, nil
+}
+
+// MutePlayer mutes or unmutes the chat messages of another player for the
+// client.
+func (s *Server) MutePlayer(ctx context.Context, req *pong.MutePlayerRequest) (*pong.MutePlayerResponse, error) {
+	var clientID, playerID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	if err := playerID.FromString(req.PlayerId); err != nil {
+		return nil, err
+	}
+	if clientID == playerID {
+		return nil, fmt.Errorf("cannot mute yourself")
+	}
+
+	s.chatMtx.Lock()
+	defer s.chatMtx.Unlock()
+
+	cs := s.chatStateLocked(clientID)
+	if req.Mute {
+		cs.muted[playerID] = struct{}{}
+	} else {
+		delete(cs.muted, playerID)
+	}
+
+	muted := make([]string, 0, len(cs.muted))
+	for id := range cs.muted {
+		muted = append(muted, id.String())
+	}
+	sort.Strings(muted)
+	return &pong.MutePlayerResponse{
+		Muted: muted,
+	}, nil
+}
+
+// chatRecipients returns the players of the waiting room or game a message is
+// sent to. The sender must be one of them.
+func (s *Server) chatRecipients(sender *ponggame.Player, roomID, gameID string) ([]*ponggame.Player, string, string, error) {
+	if roomID == "" && gameID == "" {
+		if game := s.gameManager.GetPlayerGame(*sender.ID); game != nil {
+			gameID = game.Id
+		} else if sender.WR != nil {
+			roomID = sender.WR.ID
+		} else {
+			return nil, "", "", fmt.Errorf("player %s is not in a waiting room or game", sender.ID)
+		}
+	}
+
+	var players []*ponggame.Player
+	if gameID != "" {
+		s.gameManager.RLock()
+		game := s.gameManager.Games[gameID]
+		s.gameManager.RUnlock()
+		if game == nil {
+			return nil, "", "", fmt.Errorf("game not found: %s", gameID)
+		}
+		players = game.Players
+		roomID = ""
+	} else {
+		wr := s.gameManager.GetWaitingRoom(roomID)
+		if wr == nil {
+			return nil, "", "", fmt.Errorf("waiting room not found: %s", roomID)
+		}
+		players = wr.GetPlayers()
+	}
+
+	for _, player := range players {
+		if *player.ID == *sender.ID {
+			return players, roomID, gameID, nil
+		}
+	}
+	return nil, "", "", fmt.Errorf("player %s is not part of the conversation", sender.ID)
+}
+
+// allowChat records a message sent by a player, returning false if the
+// player exceeded the rate limit.
+func (s *Server) allowChat(uid zkidentity.ShortID, now time.Time) bool {
+	s.chatMtx.Lock()
+	defer s.chatMtx.Unlock()
+
+	cs := s.chatStateLocked(uid)
+	recent := cs.sent[:0]
+	for _, ts := range cs.sent {
+		if now.Sub(ts) < chatRateWindow {
+			recent = append(recent, ts)
+		}
+	}
+	cs.sent = recent
+	if len(cs.sent) >= chatRateBurst {
+		return false
+	}
+	cs.sent = append(cs.sent, now)
+	return true
+}
+
+// isMuted returns whether the recipient muted the sender.
+func (s *Server) isMuted(recipient, sender zkidentity.ShortID) bool {
+	s.chatMtx.Lock()
+	defer s.chatMtx.Unlock()
+	cs := s.chats[recipient]
+	if cs == nil {
+		return false
+	}
+	_, muted := cs.muted[sender]
+	return muted
+}
+
+// chatStateLocked returns the chat state of a player, creating it if needed.
+// Must be called with chatMtx held.
+func (s *Server) chatStateLocked(uid zkidentity.ShortID) *chatState {
+	if s.chats == nil {
+		s.chats = make(map[zkidentity.ShortID]*chatState)
+	}
+	cs := s.chats[uid]
+	if cs == nil {
+		cs = &chatState{muted: make(map[zkidentity.ShortID]struct{})}
+		s.chats[uid] = cs
+	}
+	return cs
+}
+
+// cleanChatText strips control characters from a chat message and checks its
+// length.
+func cleanChatText(text string) (string, error) {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("empty message")
+	}
+	if n := utf8.RuneCountInString(text); n > maxChatMessageLen {
+		return "", fmt.Errorf("message too long: %d characters, max %d", n, maxChatMessageLen)
+	}
+	return text, nil
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func TestSendChatMessage(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	// Players need a room or game to chat in.
+	_, err := srv.SendChatMessage(ctx, &pong.SendChatMessageRequest{ClientId: p1ID.String(), Text: "hi"})
+	require.Error(t, err)
+
+	wr, err := srv.createPairedRoom(p1, p2, 0, ponggame.DefaultGameRules(), nil)
+	require.NoError(t, err)
+
+	resp, err := srv.SendChatMessage(ctx, &pong.SendChatMessageRequest{ClientId: p1ID.String(), Text: " good\x07 luck "})
+	require.NoError(t, err)
+	require.Equal(t, "good luck", resp.Chat.Text)
+	require.Equal(t, wr.ID, resp.Chat.RoomId)
+	require.Equal(t, "alice", resp.Chat.SenderNick)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_MESSAGE, msgs[len(msgs)-1].NotificationType)
+	require.Equal(t, "good luck", msgs[len(msgs)-1].Chat.Text)
+
+	_, err = srv.SendChatMessage(ctx, &pong.SendChatMessageRequest{
+		ClientId: p1ID.String(),
+		Text:     strings.Repeat("a", maxChatMessageLen+1),
+	})
+	require.Error(t, err)
+	_, err = srv.SendChatMessage(ctx, &pong.SendChatMessageRequest{ClientId: p1ID.String(), Text: "  "})
+	require.Error(t, err)
+
+	// Muted players don't reach the recipient.
+	muted, err := srv.MutePlayer(ctx, &pong.MutePlayerRequest{ClientId: p2ID.String(), PlayerId: p1ID.String(), Mute: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{p1ID.String()}, muted.Muted)
+	before := len(p2.NotifierStream.(*mockNotifierStream).messages)
+	_, err = srv.SendChatMessage(ctx, &pong.SendChatMessageRequest{ClientId: p1ID.String(), Text: "hello?"})
+	require.NoError(t, err)
+	require.Len(t, p2.NotifierStream.(*mockNotifierStream).messages, before)
+
+	// The rate limit allows a short burst only.
+	for i := 2; i < chatRateBurst; i++ {
+		_, err = srv.SendChatMessage(ctx, &pong.SendChatMessageRequest{ClientId: p1ID.String(), Text: "spam"})
+		require.NoError(t, err)
+	}
+	_, err = srv.SendChatMessage(ctx, &pong.SendChatMessageRequest{ClientId: p1ID.String(), Text: "spam"})
+	require.Error(t, err)
+}
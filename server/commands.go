@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+const limitsUsage = `Responsible gaming commands:
+!limits - show your limits
+!limits stake <dcr|off> - most you can stake in a game
+!limits loss <dcr|off> - most you can lose in 24 hours
+!limits reminder <minutes|off> - remind you how long you have been playing
+!exclude <days> - stop yourself from playing for a number of days
+Stricter limits apply at once, looser ones after 24 hours. Self-exclusion can't be undone.`
+
+// HandlePM handles the commands players send the bot in private messages.
+// Anything that isn't a command is ignored.
+func (s *Server) HandlePM(ctx context.Context, pm *types.ReceivedPM) error {
+	if pm.Msg == nil || !strings.HasPrefix(strings.TrimSpace(pm.Msg.Message), "!") {
+		return nil
+	}
+	var uid zkidentity.ShortID
+	if err := uid.FromBytes(pm.Uid); err != nil {
+		return fmt.Errorf("invalid PM sender: %v", err)
+	}
+
+	reply, err := s.handleCommand(ctx, uid, strings.Fields(pm.Msg.Message), time.Now())
+	if err != nil {
+		reply = fmt.Sprintf("Error: %v", err)
+	}
+	return s.bot.SendPM(ctx, uid.String(), reply)
+}
+
+func (s *Server) handleCommand(ctx context.Context, uid zkidentity.ShortID, args []string, now time.Time) (string, error) {
+	switch args[0] {
+	case "!limits":
+		return s.handleLimitsCommand(ctx, uid, args[1:], now)
+	case "!exclude":
+		if len(args) != 2 {
+			return limitsUsage, nil
+		}
+		days, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "", fmt.Errorf("invalid number of days: %s", args[1])
+		}
+		limits, err := s.selfExclude(ctx, uid, time.Duration(days)*24*time.Hour, now)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("You are self-excluded from playing until %s.",
+			limits.ExcludedUntil.UTC().Format(time.RFC1123)), nil
+	default:
+		return limitsUsage, nil
+	}
+}
+
+func (s *Server) handleLimitsCommand(ctx context.Context, uid zkidentity.ShortID, args []string, now time.Time) (string, error) {
+	limits, err := s.fetchLimits(ctx, uid, now)
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		lost, err := s.dailyLoss(ctx, uid, now)
+		if err != nil {
+			return "", err
+		}
+		return formatLimits(limits, lost, now), nil
+	}
+	if len(args) != 2 {
+		return limitsUsage, nil
+	}
+
+	// Limits not being changed keep their requested values, pending or not.
+	stake, loss, reminder := limits.MaxStake, limits.MaxDailyLoss, limits.SessionReminder
+	if p := limits.Pending; p != nil {
+		stake, loss = p.MaxStake, p.MaxDailyLoss
+	}
+	off := args[1] == "off"
+	switch args[0] {
+	case "stake", "loss":
+		amount := matoms.Amount(0)
+		if !off {
+			amount, err = matoms.Parse(args[1])
+			if err != nil {
+				return "", err
+			}
+		}
+		if args[0] == "stake" {
+			stake = amount
+		} else {
+			loss = amount
+		}
+	case "reminder":
+		reminder = 0
+		if !off {
+			minutes, err := strconv.Atoi(args[1])
+			if err != nil {
+				return "", fmt.Errorf("invalid number of minutes: %s", args[1])
+			}
+			reminder = time.Duration(minutes) * time.Minute
+		}
+	default:
+		return limitsUsage, nil
+	}
+
+	limits, err = s.setLimits(ctx, uid, stake, loss, reminder, now)
+	if err != nil {
+		return "", err
+	}
+	lost, err := s.dailyLoss(ctx, uid, now)
+	if err != nil {
+		return "", err
+	}
+	return formatLimits(limits, lost, now), nil
+}
+
+func formatLimit(amount matoms.Amount) string {
+	if amount == 0 {
+		return "none"
+	}
+	return amount.String() + " DCR"
+}
+
+func formatLimits(limits *serverdb.PlayerLimits, lost matoms.Amount, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Max stake per game: %s\n", formatLimit(limits.MaxStake))
+	fmt.Fprintf(&b, "Max loss per 24 hours: %s (lost %s DCR)\n", formatLimit(limits.MaxDailyLoss), lost)
+	if limits.SessionReminder > 0 {
+		fmt.Fprintf(&b, "Session reminder: every %s\n", limits.SessionReminder)
+	} else {
+		b.WriteString("Session reminder: off\n")
+	}
+	if p := limits.Pending; p != nil {
+		fmt.Fprintf(&b, "From %s: max stake %s, max loss %s\n", p.EffectiveAt.UTC().Format(time.RFC1123),
+			formatLimit(p.MaxStake), formatLimit(p.MaxDailyLoss))
+	}
+	if now.Before(limits.ExcludedUntil) {
+		fmt.Fprintf(&b, "Self-excluded until %s\n", limits.ExcludedUntil.UTC().Format(time.RFC1123))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
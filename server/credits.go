@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// defaultCreditGrant is the balance free-to-play players are topped up to
+// every day when the server doesn't configure one. Credits use the same
+// units as DCR amounts.
+const defaultCreditGrant = 10 * matoms.PerDCR
+
+// grantCredits tops the credits of a player of a free-to-play server up to
+// the daily grant, once per UTC day.
+func (s *Server) grantCredits(ctx context.Context, uid zkidentity.ShortID, now time.Time) {
+	if !s.isF2P {
+		return
+	}
+	day := now.UTC().Format(time.DateOnly)
+	granted, err := s.db.GrantCredits(ctx, uid, s.creditGrant, day)
+	if err != nil {
+		s.log.Errorf("Failed to grant credits to %s: %v", uid, err)
+		return
+	}
+	if granted > 0 {
+		s.log.Infof("Granted %s credits to %s", granted, uid)
+	}
+}
+
+// returnTip sends a tip received by a free-to-play server back to its
+// sender, since its games are only played with credits.
+func (s *Server) returnTip(ctx context.Context, tip *types.ReceivedTip) error {
+	var uid zkidentity.ShortID
+	if err := uid.FromBytes(tip.Uid); err != nil {
+		return fmt.Errorf("invalid tip sender: %v", err)
+	}
+	amount := matoms.Amount(tip.AmountMatoms)
+	if err := s.sendBalance(ctx, uid, serverdb.EntryRefund, amount); err != nil {
+		return fmt.Errorf("failed to return tip %d: %w", tip.SequenceId, err)
+	}
+	s.log.Infof("Returned tip of %s sent by %s to the free-to-play server", amount, uid)
+
+	msg := fmt.Sprintf("This server is free to play with credits, so your tip of %s DCR is being sent back.", amount)
+	if err := s.bot.SendPM(ctx, uid.String(), msg); err != nil {
+		s.log.Warnf("Failed to tell %s their tip is being returned: %v", uid, err)
+	}
+	return nil
+}
+
+// creditLeaderboard ranks the players of a free-to-play server by the
+// credits they hold, reserved or not.
+func (s *Server) creditLeaderboard(ctx context.Context, limit int) ([]*pong.LeaderboardEntry, error) {
+	if !s.isF2P {
+		return nil, fmt.Errorf("credits are only kept by free-to-play servers")
+	}
+
+	credits := make(map[zkidentity.ShortID]matoms.Amount)
+	for _, prefix := range []string{serverdb.CreditAccounts.Player, serverdb.CreditAccounts.Escrow} {
+		balances, err := s.db.FetchAccountBalances(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for account, amount := range balances {
+			var uid zkidentity.ShortID
+			if err := uid.FromString(account[strings.LastIndex(account, "/")+1:]); err != nil {
+				return nil, fmt.Errorf("invalid ledger account %s", account)
+			}
+			credits[uid] += amount
+		}
+	}
+
+	ratings, err := s.db.FetchPlayerRatings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ratingByUID := make(map[string]*serverdb.PlayerRating, len(ratings))
+	for _, r := range ratings {
+		ratingByUID[string(r.UID)] = r
+	}
+
+	entries := make([]*pong.LeaderboardEntry, 0, len(credits))
+	for uid, amount := range credits {
+		entry := &pong.LeaderboardEntry{
+			Uid:     uid.String(),
+			Rating:  defaultRating,
+			Credits: int64(amount),
+		}
+		if r := ratingByUID[string(uid[:])]; r != nil {
+			entry.Nick = r.Nick
+			entry.Rating = r.Rating
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Credits != entries[j].Credits {
+			return entries[i].Credits > entries[j].Credits
+		}
+		a, _ := hex.DecodeString(entries[i].Uid)
+		b, _ := hex.DecodeString(entries[j].Uid)
+		return bytes.Compare(a, b) < 0
+	})
+	if limit < len(entries) {
+		entries = entries[:limit]
+	}
+	for i, entry := range entries {
+		entry.Rank = int32(i + 1)
+	}
+	return entries, nil
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func setupF2PServer(t *testing.T) (*Server, zkidentity.ShortID, zkidentity.ShortID) {
+	t.Helper()
+	srv := setupTestServer(t)
+	srv.isF2P = true
+	srv.creditGrant = defaultCreditGrant
+
+	var p1ID, p2ID zkidentity.ShortID
+	_ = p1ID.FromString(strings.Repeat("6", 64))
+	_ = p2ID.FromString(strings.Repeat("7", 64))
+	createTestPlayer(srv, p1ID).Nick = "alice"
+	createTestPlayer(srv, p2ID).Nick = "bob"
+	return srv, p1ID, p2ID
+}
+
+func TestCreditGrants(t *testing.T) {
+	srv, p1ID, _ := setupF2PServer(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	// Credits are granted once a day.
+	srv.grantCredits(ctx, p1ID, now)
+	srv.grantCredits(ctx, p1ID, now)
+	available, reserved, err := srv.fetchPlayerBalance(ctx, p1ID)
+	require.NoError(t, err)
+	require.Equal(t, defaultCreditGrant, available)
+	require.Zero(t, reserved)
+
+	// Reserved credits count towards the grant, which tops the balance up.
+	require.NoError(t, srv.reserveStake(ctx, "room1", p1ID, 3*matoms.PerDCR))
+	_, err = srv.db.SweepAccounts(ctx, serverdb.EntrySettle, "room1",
+		serverdb.CreditAccounts.EscrowPrefix("room1"), serverdb.HouseAccount)
+	require.NoError(t, err)
+	require.NoError(t, srv.reserveStake(ctx, "room2", p1ID, 2*matoms.PerDCR))
+	srv.grantCredits(ctx, p1ID, now.Add(24*time.Hour))
+	available, reserved, err = srv.fetchPlayerBalance(ctx, p1ID)
+	require.NoError(t, err)
+	require.Equal(t, 8*matoms.PerDCR, available)
+	require.Equal(t, 2*matoms.PerDCR, reserved)
+
+	// Credits stay apart from DCR.
+	dcr, err := srv.db.FetchAccountBalance(ctx, serverdb.PlayerAccount(p1ID))
+	require.NoError(t, err)
+	require.Zero(t, dcr)
+	require.NoError(t, srv.checkLedger(ctx))
+}
+
+func TestCreditGame(t *testing.T) {
+	srv, p1ID, p2ID := setupF2PServer(t)
+	ctx := context.Background()
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: int64(3 * matoms.PerDCR),
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	srv.handleGameEnd(ctx, wr.ID, &ponggame.GameInstance{Id: "game1", Winner: &p1ID}, series, players)
+
+	// The winner is credited, nothing is paid out.
+	for uid, want := range map[zkidentity.ShortID]matoms.Amount{p1ID: 13 * matoms.PerDCR, p2ID: 7 * matoms.PerDCR} {
+		funds, err := srv.playerFunds(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, want, funds)
+	}
+	paid, err := srv.db.FetchAccountBalance(ctx, serverdb.PayoutsAccount)
+	require.NoError(t, err)
+	require.Zero(t, paid)
+	require.NoError(t, srv.checkLedger(ctx))
+
+	lb, err := srv.GetLeaderboard(ctx, &pong.LeaderboardRequest{Kind: pong.LeaderboardKind_BY_CREDITS})
+	require.NoError(t, err)
+	require.Len(t, lb.Entries, 2)
+	require.Equal(t, p1ID.String(), lb.Entries[0].Uid)
+	require.Equal(t, "alice", lb.Entries[0].Nick)
+	require.Equal(t, int64(13*matoms.PerDCR), lb.Entries[0].Credits)
+	require.Equal(t, int32(2), lb.Entries[1].Rank)
+
+	_, err = srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: p1ID.String()})
+	require.ErrorContains(t, err, "can't be withdrawn")
+}
+
+func TestCreditServerReturnsTips(t *testing.T) {
+	srv, p1ID, _ := setupF2PServer(t)
+	ctx := context.Background()
+
+	err := srv.HandleReceiveTip(ctx, &types.ReceivedTip{
+		Uid:          p1ID[:],
+		AmountMatoms: int64(matoms.PerDCR),
+		SequenceId:   1,
+	})
+	require.NoError(t, err)
+
+	records, err := srv.db.FetchSendTipProgressByClient(ctx, p1ID.Bytes())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, matoms.PerDCR, records[0].TotalAmount)
+	bot := srv.bot.(*minimalTestBot)
+	require.Len(t, bot.sentPMs, 1)
+	require.Contains(t, bot.sentPMs[0].msg, "sent back")
+
+	funds, err := srv.playerFunds(ctx, p1ID)
+	require.NoError(t, err)
+	require.Zero(t, funds)
+	require.NoError(t, srv.checkLedger(ctx))
+
+	// Leaderboards of credits are only kept by free-to-play servers.
+	srv.isF2P = false
+	_, err = srv.GetLeaderboard(ctx, &pong.LeaderboardRequest{Kind: pong.LeaderboardKind_BY_CREDITS})
+	require.Error(t, err)
+}
@@ -1,121 +1,169 @@
 package server
 
 import (
-	"bytes"
 	"context"
-	"encoding/binary"
 	"fmt"
 	"sync"
 	"time"
 
-	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
-	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/ponggame"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 	"github.com/vctt94/pong-bisonrelay/server/serverdb"
 )
 
+// handleReturnUnprocessedTips pays back the available balance of a player.
+// Stakes still reserved stay with the bot until they are settled or released.
 func (s *Server) handleReturnUnprocessedTips(ctx context.Context, clientID zkidentity.ShortID) error {
-	tips, err := s.db.FetchReceivedTipsByUID(ctx, clientID, serverdb.StatusUnpaid)
+	// Free-to-play servers return tips as they arrive.
+	if s.isF2P {
+		return nil
+	}
+	refund, _, err := s.fetchPlayerBalance(ctx, clientID)
 	if err != nil {
-		s.log.Errorf("Failed to fetch unprocessed tips for client %s: %v", clientID.String(), err)
+		s.log.Errorf("Failed to fetch balance of client %s: %v", clientID.String(), err)
 		return err
 	}
 
-	if len(tips) == 0 {
+	if refund <= 0 {
 		return nil
 	}
 
-	totalDcrAmount := int64(0)
-	for _, tip := range tips {
-		totalDcrAmount += tip.AmountMatoms
-	}
-
-	totalDcrAmount = int64(float64(totalDcrAmount) * 1e-3)
-
-	if err := s.bot.PayTip(ctx, clientID, dcrutil.Amount(totalDcrAmount), 3); err != nil {
+	if err := s.sendBalance(ctx, clientID, serverdb.EntryRefund, refund); err != nil {
 		s.log.Errorf("Failed to return unprocessed tips to client %s: %v", clientID.String(), err)
 		return err
 	}
 
-	s.log.Infof("Returned unprocessed tips to client %s: %.8f", clientID.String(), totalDcrAmount)
+	s.log.Infof("Returned unprocessed tips to client %s: %s", clientID.String(), refund)
+	return nil
+}
+
+// seriesBreak is the pause between the games of a series.
+const seriesBreak = 5 * time.Second
 
-	// Convert total back to matoms for storage
-	totalMatoms := int64(totalDcrAmount * 1e11)
+// handleGameLifecycle plays the series of a started waiting room. The stakes
+// reserved in the room escrow are settled to the winner, or released if the
+// series ends without one.
+func (s *Server) handleGameLifecycle(ctx context.Context, escrowID string, players []*ponggame.Player, betAmt matoms.Amount, rules ponggame.GameRules) {
+	defer s.releaseStakes(context.Background(), escrowID)
 
-	// Store send progress with all tips being returned
-	err = s.db.StoreSendTipProgress(ctx, clientID.Bytes(), totalMatoms, tips, serverdb.StatusSending)
+	series, err := ponggame.NewSeries(players, rules.BestOf)
 	if err != nil {
-		s.log.Errorf("Failed to store return tip progress: %v", err)
-		return err
+		s.log.Errorf("Failed to start series: %v", err)
+		return
 	}
 
-	for _, tip := range tips {
-		tipID := make([]byte, 8)
-		binary.BigEndian.PutUint64(tipID, tip.SequenceId)
-		if err := s.db.UpdateTipStatus(ctx, clientID.Bytes(), tipID, serverdb.StatusSending); err != nil {
-			s.log.Errorf("Failed to update tip status for client %s: %v", clientID.String(), err)
+	defer func() {
+		s.resetPlayers(players)
+		if series.Decided() {
+			s.openRematchWindow(players, betAmt, rules)
 		}
-	}
-
-	return nil
-}
-
-func (s *Server) handleFetchTotalUnprocessedTips(ctx context.Context, clientID zkidentity.ShortID) (int64, []*types.ReceivedTip, error) {
-	// Fetch unprocessed tips from the database
-	tips, err := s.db.FetchReceivedTipsByUID(ctx, clientID, serverdb.StatusUnpaid)
-	if err != nil {
-		s.log.Errorf("Failed to fetch unprocessed tips for client %s: %v", clientID.String(), err)
-		return 0, nil, err
-	}
+	}()
 
-	// Calculate total DCR amount
-	totalDcrAmount := int64(0)
-	for _, tip := range tips {
-		totalDcrAmount += tip.AmountMatoms
+	game := s.playSeries(ctx, series, rules, betAmt)
+	if game == nil {
+		return
 	}
-
-	s.log.Infof("Fetched %d unprocessed tips for client %s, total amount: %.8f", len(tips), clientID.String(), float64(totalDcrAmount)/1e11)
-	return totalDcrAmount, tips, nil
+	s.handleGameEnd(ctx, escrowID, game, series, players)
 }
 
-func (s *Server) handleGameLifecycle(ctx context.Context, players []*ponggame.Player, tips []*types.ReceivedTip) {
-	game, err := s.gameManager.StartGame(ctx, players)
-	if err != nil {
-		s.log.Errorf("Failed to start game: %v", err)
-		return
+// resetPlayers clears the game state of players once their match is over and
+// refreshes their bet amount.
+func (s *Server) resetPlayers(players []*ponggame.Player) {
+	for _, player := range players {
+		player.ResetPlayer()
+		// Fetch latest balance and update bet amount
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		funds, err := s.playerFunds(ctx, *player.ID)
+		cancel()
+		if err != nil {
+			s.log.Errorf("Error fetching balance of player %s: %v", player.ID, err)
+			continue
+		}
+		playerSession := s.gameManager.PlayerSessions.GetPlayer(*player.ID)
+		if playerSession == nil {
+			s.log.Errorf("Error finding player session %s", player.ID)
+			continue
+		}
+		playerSession.BetAmt = funds
+		s.log.Debugf("Reset player %s with updated bet amount: %s", player.ID, funds)
 	}
+}
 
-	defer func() {
-		// reset player status
-		for _, player := range game.Players {
-			player.ResetPlayer()
-			// Fetch latest unprocessed tips and update bet amount
-			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-			totalDcrAmount, _, err := s.handleFetchTotalUnprocessedTips(ctx, *player.ID)
-			cancel()
-			if err != nil {
-				s.log.Errorf("Error fetching tips for player %s: %v", player.ID, err)
-				continue
-			}
-			playerSession := s.gameManager.PlayerSessions.GetPlayer(*player.ID)
-			if playerSession == nil {
-				s.log.Errorf("Error finding player session %s", player.ID)
-				continue
-			}
-			playerSession.BetAmt = totalDcrAmount
-			s.log.Debugf("Reset player %s with updated bet amount: %.8f", player.ID, float64(totalDcrAmount)/1e11)
+// playSeries plays the games of a series until it is decided. It returns the
+// last game played, with the series result as its winner and scores, or nil if
+// no game could be started. The stake is what each player wagered on the
+// series.
+func (s *Server) playSeries(ctx context.Context, series *ponggame.Series, rules ponggame.GameRules, stake matoms.Amount) *ponggame.GameInstance {
+	players := series.Players
+	var game *ponggame.GameInstance
+	for {
+		next, err := s.gameManager.StartGameWithRules(ctx, series.NextPlayers(), rules, stake)
+		if err != nil {
+			s.log.Errorf("Failed to start game: %v", err)
+			return nil
 		}
+		game = next
+		s.playGame(ctx, game, series)
+		series.AddGame(game)
+
 		// remove game from gameManager after it ended
 		delete(s.gameManager.Games, game.Id)
 		s.log.Debugf("Game %s cleaned up", game.Id)
-	}()
 
+		// A game stopped by an admin ends the series with its
+		// resolution.
+		if game.Stopped {
+			if game.Winner != nil {
+				series.Forfeit(*game.Winner)
+			}
+			break
+		}
+		if series.RecordGame(game.Winner) {
+			break
+		}
+
+		// A player that left forfeits the rest of the series.
+		if left := s.seriesDropout(players); left != nil {
+			for _, player := range players {
+				if *player.ID != *left {
+					series.Forfeit(*player.ID)
+				}
+			}
+			break
+		}
+		if game.Winner == nil {
+			// Nobody won, so the series ends undecided.
+			break
+		}
+
+		series.NextGameAt = time.Now().Add(seriesBreak)
+		s.notifySeriesUpdate(game, series)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(seriesBreak):
+		}
+		series.NextGameAt = time.Time{}
+	}
+
+	if rules.BestOf > 1 {
+		// The series counts as a single match, scored by games won.
+		game.Winner = series.Winner
+		for _, player := range players {
+			player.Score = int(series.Wins[*player.ID])
+		}
+	}
+	return game
+}
+
+// playGame runs a single game of a series and blocks until it ends.
+func (s *Server) playGame(ctx context.Context, game *ponggame.GameInstance, series *ponggame.Series) {
 	game.Run()
 
 	var wg sync.WaitGroup
-	for _, player := range players {
+	for _, player := range game.Players {
 		wg.Add(1)
 		go func(player *ponggame.Player) {
 			defer wg.Done()
@@ -125,6 +173,7 @@ func (s *Server) handleGameLifecycle(ctx context.Context, players []*ponggame.Pl
 					Message:          "Game started with ID: " + game.Id,
 					Started:          true,
 					GameId:           game.Id,
+					Series:           series.Marshal(),
 				})
 				if err != nil {
 					s.log.Warnf("Failed to notify player %s: %v", player.ID, err)
@@ -135,11 +184,46 @@ func (s *Server) handleGameLifecycle(ctx context.Context, players []*ponggame.Pl
 	}
 
 	wg.Wait() // Wait for both players' streams to finish
+	game.Cleanup()
+}
 
-	s.handleGameEnd(ctx, game, players, tips)
+// seriesDropout returns the first player of a series that is no longer
+// connected.
+func (s *Server) seriesDropout(players []*ponggame.Player) *zkidentity.ShortID {
+	for _, player := range players {
+		if s.gameManager.PlayerSessions.GetPlayer(*player.ID) != player {
+			return player.ID
+		}
+	}
+	return nil
 }
 
-func (s *Server) handleGameEnd(ctx context.Context, game *ponggame.GameInstance, players []*ponggame.Player, tips []*types.ReceivedTip) {
+// notifySeriesUpdate tells the players of a series the result of the last game
+// and when the next one starts, revealing its serve seed.
+func (s *Server) notifySeriesUpdate(game *ponggame.GameInstance, series *ponggame.Series) {
+	state := series.Marshal()
+	for _, player := range series.Players {
+		message := "Draw."
+		if game.Winner != nil && *game.Winner == *player.ID {
+			message = "You won the game."
+		} else if game.Winner != nil {
+			message = "You lost the game."
+		}
+		if player.NotifierStream == nil {
+			continue
+		}
+		player.NotifierStream.Send(&pong.NtfnStreamResponse{
+			NotificationType: pong.NotificationType_SERIES_UPDATE,
+			Message: fmt.Sprintf("%s Series %d-%d, next game in %s.", message,
+				series.Wins[*player.ID], series.GamesPlayed-series.Wins[*player.ID], seriesBreak),
+			GameId:   game.Id,
+			Series:   state,
+			Fairness: game.Fairness(true),
+		})
+	}
+}
+
+func (s *Server) handleGameEnd(ctx context.Context, escrowID string, game *ponggame.GameInstance, series *ponggame.Series, players []*ponggame.Player) {
 	winner := game.Winner
 	var winnerID string
 	if winner != nil {
@@ -149,82 +233,102 @@ func (s *Server) handleGameEnd(ctx context.Context, game *ponggame.GameInstance,
 		s.log.Infof("Game ended in a draw.")
 	}
 
-	// Calculate total from actual reserved tips
-	totalAmountMatoms := int64(0)
-	for _, tip := range tips {
-		totalAmountMatoms += tip.AmountMatoms
+	// Calculate total from the stakes held in escrow
+	stakes, err := s.fetchStakes(ctx, escrowID)
+	if err != nil {
+		s.log.Errorf("Failed to fetch stakes of game %s: %v", game.Id, err)
+	}
+
+	if err := s.recordMatchResult(ctx, game, players, stakes); err != nil {
+		s.log.Errorf("Failed to record result of game %s: %v", game.Id, err)
 	}
 
-	// Convert total to matoms for storage
-	totalDcrAmount := float64(totalAmountMatoms) / 1e11
+	// Transfer the stakes to the winner before the players are told, so
+	// they only see what was actually paid.
+	var rake, paid matoms.Amount
+	if winner != nil {
+		rake, paid = s.payWinner(ctx, escrowID, game.Id, *winner, stakes)
+	}
+
+	signed := s.matchReceipt(game, series, players, stakes, rake, paid)
+	defer s.sendReceipt(ctx, game.Id, players, signed)
 
 	// Notify players of game outcome
 	for _, player := range players {
 		message := "Game ended in a draw."
-		if player.ID == winner {
-			message = fmt.Sprintf("Congratulations, you won and received: %.8f", totalDcrAmount)
-		} else {
-			// Calculate lost amount for this player
-			lostAmount := 0.0
-			for _, tip := range tips {
-				// Compare player ID with tip UID
-				if bytes.Equal(player.ID[:], tip.Uid) {
-					lostAmount += float64(tip.AmountMatoms) / 1e11
-				}
+		if winner != nil && *player.ID == *winner {
+			message = fmt.Sprintf("Congratulations, you won and received: %s", paid)
+			if rake > 0 {
+				message += fmt.Sprintf(" (house rake: %s)", rake)
 			}
-			message = fmt.Sprintf("Sorry, you lost and lose: %.8f", lostAmount)
+		} else if winner != nil {
+			message = fmt.Sprintf("Sorry, you lost and lose: %s", stakes[*player.ID])
 		}
 		player.NotifierStream.Send(&pong.NtfnStreamResponse{
 			NotificationType: pong.NotificationType_GAME_END,
 			Message:          message,
 			GameId:           game.Id,
+			Series:           series.Marshal(),
+			Fairness:         game.Fairness(true),
+			Receipt:          marshalReceipt(signed),
 		})
 		// delete player from gameManager PlayerGameMap
 		delete(s.gameManager.PlayerGameMap, *player.ID)
 	}
+}
 
-	// Transfer actual reserved tip amounts to winner
-	if winner != nil {
-		// Store send progress with ALL tips (both players')
-		err := s.db.StoreSendTipProgress(ctx, winner[:], totalAmountMatoms, tips, serverdb.StatusSending)
-		if err != nil {
-			s.log.Errorf("Failed to store send progress: %v", err)
-			return
-		}
-		// Process the reserved tips
-		for _, tip := range tips {
-			tipID := make([]byte, 8)
-			binary.BigEndian.PutUint64(tipID, tip.SequenceId)
-			err := s.db.UpdateTipStatus(ctx, tip.Uid, tipID, serverdb.StatusSending)
-			if err != nil {
-				s.log.Errorf("Failed to update tip status for player %s: %v", tip.Uid, err)
-			}
-		}
+// payWinner settles the stakes of a game to its winner and queues their
+// payout. It returns the rake kept by the house and the amount paid, which
+// only counts the whole atoms sent.
+func (s *Server) payWinner(ctx context.Context, escrowID, gameID string, winner zkidentity.ShortID,
+	stakes map[zkidentity.ShortID]matoms.Amount) (rake, paid matoms.Amount) {
+	won, rake, err := s.settleStakes(ctx, escrowID, gameID, winner)
+	if err != nil {
+		s.log.Errorf("Failed to settle stakes of game %s: %v", gameID, err)
+		return 0, 0
+	}
+	if rake > 0 {
+		s.log.Infof("House rake of game %s: %s", gameID, rake)
+	}
+	if s.isF2P {
+		s.log.Infof("Credited %s credits to winner %s", won, winner)
+		return rake, won
+	}
 
-		// Process the reserved tips
-		for _, tip := range tips {
-			tipID := make([]byte, 8)
-			binary.BigEndian.PutUint64(tipID, tip.SequenceId)
-			err := s.db.UpdateTipStatus(ctx, tip.Uid, tipID, serverdb.StatusSending)
-			if err != nil {
-				s.log.Errorf("Failed to update tip status for player %s: %v", tip.Uid, err)
-			}
-		}
-		totalAtoms := int64(float64(totalAmountMatoms) * 1e-3)
-		err = s.bot.PayTip(ctx, *winner, dcrutil.Amount(totalAtoms), 3)
-		if err != nil {
-			s.log.Errorf("Failed to transfer bet amount to winner %s: %v", winner.String(), err)
-			return
-		}
-		s.log.Infof("Transferred bet amount to winner %s: %.8f", winner.String(), totalDcrAmount)
+	// Spend the stakes from the players' tips, except for what the winner
+	// keeps in their balance. Tips spent in full are marked paid once the
+	// payout completes.
+	paid, err = s.recordPayout(ctx, serverdb.EntryPayout, gameID, winner, won)
+	if err != nil {
+		s.log.Errorf("Failed to record payout of game %s: %v", gameID, err)
 	}
+	spent := s.spendStakes(ctx, stakes, map[zkidentity.ShortID]matoms.Amount{winner: won - paid})
+	if err != nil {
+		return rake, 0
+	}
+	if err := s.queuePayout(ctx, winner, paid, spent); err != nil {
+		s.log.Errorf("Failed to transfer bet amount to winner %s: %v", winner.String(), err)
+		return rake, paid
+	}
+	s.log.Infof("Queued bet amount to winner %s: %s", winner.String(), paid)
+	return rake, paid
 }
 
 func (s *Server) handleWaitingRoomRemoved(wr *pong.WaitingRoom) {
 	s.log.Infof("Waiting room %s removed", wr.Id)
 
-	// Notify all users about the waiting room removal
-	for _, user := range s.users {
+	// Notify all users about the waiting room removal. Private rooms were
+	// never announced, so only their members are told.
+	members := make(map[string]bool, len(wr.Players))
+	for _, p := range wr.Players {
+		members[p.Uid] = true
+	}
+	s.RLock()
+	defer s.RUnlock()
+	for id, user := range s.users {
+		if user.NotifierStream == nil || (wr.Private && !members[id.String()]) {
+			continue
+		}
 		user.NotifierStream.Send(&pong.NtfnStreamResponse{
 			NotificationType: pong.NotificationType_ON_WR_REMOVED,
 			Message:          fmt.Sprintf("Waiting room %s was removed", wr.Id),
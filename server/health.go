@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// healthTimeout bounds how long a single component check may take.
+	healthTimeout = 2 * time.Second
+	// healthInterval is how often the gRPC health status is refreshed.
+	healthInterval = 10 * time.Second
+	// payoutStallAge is how long a payout may wait to be paid before the
+	// payout backlog is reported as degraded.
+	payoutStallAge = 30 * time.Minute
+)
+
+// Statuses of the components of a health report.
+const (
+	componentOK       = "ok"
+	componentDegraded = "degraded"
+	componentDown     = "down"
+)
+
+// componentHealth is the status of one component of the server.
+type componentHealth struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// healthReport is the status of the components of the server. Degraded
+// components don't make the server unready, down ones do.
+type healthReport struct {
+	Status     string                     `json:"status"`
+	CheckedAt  time.Time                  `json:"checked_at"`
+	Components map[string]componentHealth `json:"components"`
+}
+
+// live returns whether the server is still able to run games.
+func (r *healthReport) live() bool {
+	return r.Components["games"].Status != componentDown
+}
+
+// ready returns whether the server can take players.
+func (r *healthReport) ready() bool {
+	for _, c := range r.Components {
+		if c.Status == componentDown {
+			return false
+		}
+	}
+	return true
+}
+
+// botPinger is implemented by bots that can query their Bison Relay client.
+type botPinger interface {
+	UserPublicIdentity(ctx context.Context, req *types.PublicIdentityReq, res *types.PublicIdentity) error
+}
+
+func (s *Server) checkHealth(ctx context.Context) *healthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	report := &healthReport{
+		CheckedAt: time.Now(),
+		Components: map[string]componentHealth{
+			"server":  {Status: componentOK},
+			"bot":     s.checkBot(ctx),
+			"db":      s.checkDB(ctx),
+			"payouts": s.checkPayouts(ctx, time.Now()),
+			"games":   s.checkGames(ctx),
+		},
+	}
+	if s.shuttingDown.Load() {
+		report.Components["server"] = componentHealth{Status: componentDown, Detail: "shutting down"}
+	}
+	return report
+}
+
+// checkBot checks the bot is running and its Bison Relay client answers.
+func (s *Server) checkBot(ctx context.Context) componentHealth {
+	s.botMtx.Lock()
+	err := s.botErr
+	s.botMtx.Unlock()
+	if err != nil {
+		return componentHealth{Status: componentDown, Detail: err.Error()}
+	}
+	if p, ok := s.bot.(botPinger); ok {
+		var id types.PublicIdentity
+		if err := p.UserPublicIdentity(ctx, &types.PublicIdentityReq{}, &id); err != nil {
+			return componentHealth{Status: componentDown, Detail: err.Error()}
+		}
+	}
+	return componentHealth{Status: componentOK}
+}
+
+func (s *Server) checkDB(ctx context.Context) componentHealth {
+	if err := s.db.Ping(ctx); err != nil {
+		return componentHealth{Status: componentDown, Detail: err.Error()}
+	}
+	return componentHealth{Status: componentOK}
+}
+
+// checkPayouts reports the payout backlog as degraded when payouts failed
+// or have been waiting longer than payoutStallAge.
+func (s *Server) checkPayouts(ctx context.Context, now time.Time) componentHealth {
+	records, err := s.db.FetchTipProgressByStatus(ctx, serverdb.StatusQueued,
+		serverdb.StatusSending, serverdb.StatusFailed)
+	if err != nil {
+		return componentHealth{Status: componentDegraded, Detail: err.Error()}
+	}
+
+	counts := make(map[serverdb.TipStatus]int)
+	var oldest time.Time
+	for _, record := range records {
+		counts[record.Status]++
+		if record.Status != serverdb.StatusFailed && (oldest.IsZero() || record.CreatedAt.Before(oldest)) {
+			oldest = record.CreatedAt
+		}
+	}
+	c := componentHealth{
+		Status: componentOK,
+		Detail: fmt.Sprintf("%d queued, %d sending, %d failed", counts[serverdb.StatusQueued],
+			counts[serverdb.StatusSending], counts[serverdb.StatusFailed]),
+	}
+	if !oldest.IsZero() {
+		age := now.Sub(oldest).Truncate(time.Second)
+		c.Detail += fmt.Sprintf(", oldest pending for %s", age)
+		if age > payoutStallAge {
+			c.Status = componentDegraded
+		}
+	}
+	if counts[serverdb.StatusFailed] > 0 {
+		c.Status = componentDegraded
+	}
+	return c
+}
+
+// checkGames checks the game manager isn't stuck holding its lock.
+func (s *Server) checkGames(ctx context.Context) componentHealth {
+	games := make(chan int, 1)
+	go func() {
+		s.gameManager.RLock()
+		n := len(s.gameManager.Games)
+		s.gameManager.RUnlock()
+		games <- n
+	}()
+	select {
+	case n := <-games:
+		return componentHealth{Status: componentOK, Detail: fmt.Sprintf("%d games", n)}
+	case <-ctx.Done():
+		return componentHealth{Status: componentDown, Detail: "game manager is unresponsive"}
+	}
+}
+
+// runBot runs the bot, recording why it stopped for the health checks.
+func (s *Server) runBot(ctx context.Context) {
+	err := s.bot.Run(ctx)
+	if err == nil {
+		err = errors.New("bot stopped")
+	}
+	s.botMtx.Lock()
+	s.botErr = err
+	s.botMtx.Unlock()
+}
+
+// HealthServer returns the grpc.health.v1 service of the server.
+func (s *Server) HealthServer() healthpb.HealthServer {
+	return s.health
+}
+
+// runHealthLoop keeps the status of the gRPC health service up to date.
+func (s *Server) runHealthLoop(ctx context.Context) {
+	for {
+		s.updateGRPCHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(healthInterval):
+		}
+	}
+}
+
+func (s *Server) updateGRPCHealth(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !s.checkHealth(ctx).ready() {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(pong.PongGame_ServiceDesc.ServiceName, status)
+}
+
+func newHealthServer() *health.Server {
+	hs := health.NewServer()
+	hs.SetServingStatus(pong.PongGame_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return hs
+}
+
+func (s *Server) writeHealth(w http.ResponseWriter, ok bool, status string, report *healthReport) {
+	code := http.StatusOK
+	if !ok {
+		code = http.StatusServiceUnavailable
+	}
+	report.Status = status
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		s.log.Errorf("Failed to encode health report: %v", err)
+	}
+}
+
+// handleHealthzHandler reports whether the server is alive.
+func (s *Server) handleHealthzHandler(w http.ResponseWriter, r *http.Request) {
+	report := s.checkHealth(r.Context())
+	if report.live() {
+		s.writeHealth(w, true, "ok", report)
+		return
+	}
+	s.writeHealth(w, false, "unhealthy", report)
+}
+
+// handleReadyzHandler reports whether the server can take players.
+func (s *Server) handleReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	report := s.checkHealth(r.Context())
+	if report.ready() {
+		s.writeHealth(w, true, "ready", report)
+		return
+	}
+	s.writeHealth(w, false, "not ready", report)
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func probeHealth(t *testing.T, handler http.HandlerFunc) (int, healthReport) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	var report healthReport
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	return w.Code, report
+}
+
+func TestHealth(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+
+	code, report := probeHealth(t, srv.handleReadyzHandler)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ready", report.Status)
+	for _, name := range []string{"server", "bot", "db", "payouts", "games"} {
+		require.Equal(t, componentOK, report.Components[name].Status, name)
+	}
+
+	// A stalled payout backlog is reported without making the server unready.
+	require.NoError(t, srv.handleReturnUnprocessedTips(ctx, p1ID))
+	payouts := srv.checkPayouts(ctx, time.Now())
+	require.Equal(t, componentOK, payouts.Status)
+	require.Contains(t, payouts.Detail, "1 queued")
+	require.Equal(t, componentDegraded, srv.checkPayouts(ctx, time.Now().Add(time.Hour)).Status)
+
+	// The server is alive but not ready without its bot.
+	srv.botErr = errors.New("connection lost")
+	code, report = probeHealth(t, srv.handleReadyzHandler)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, componentHealth{Status: componentDown, Detail: "connection lost"}, report.Components["bot"])
+	code, _ = probeHealth(t, srv.handleHealthzHandler)
+	require.Equal(t, http.StatusOK, code)
+	srv.botErr = nil
+
+	srv.updateGRPCHealth(ctx)
+	check := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := srv.HealthServer().Check(ctx, &healthpb.HealthCheckRequest{
+			Service: pong.PongGame_ServiceDesc.ServiceName,
+		})
+		require.NoError(t, err)
+		return resp.Status
+	}
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, check())
+
+	// Shutting down flips both to not ready.
+	require.NoError(t, srv.Shutdown(ctx))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check())
+	srv.updateGRPCHealth(ctx)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check())
+	code, report = probeHealth(t, srv.handleReadyzHandler)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, componentDown, report.Components["server"].Status)
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 )
 
 // handleFetchTipsByClientIDHandler fetches tips for a specific client ID.
@@ -78,3 +81,91 @@ func (s *Server) handleGetSendProgressByWinnerHandler(w http.ResponseWriter, r *
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(records)
 }
+
+// handleLeaderboardHandler returns a ranked leaderboard. The kind and window
+// query parameters take the lowercase names of the LeaderboardKind and
+// LeaderboardWindow enums (e.g. kind=by_net_winnings&window=weekly).
+func (s *Server) handleLeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	kind := pong.LeaderboardKind_BY_RATING
+	if v := q.Get("kind"); v != "" {
+		k, ok := pong.LeaderboardKind_value[strings.ToUpper(v)]
+		if !ok {
+			http.Error(w, fmt.Sprintf("invalid kind: %s", v), http.StatusBadRequest)
+			return
+		}
+		kind = pong.LeaderboardKind(k)
+	}
+
+	window := pong.LeaderboardWindow_SEASON
+	if v := q.Get("window"); v != "" {
+		win, ok := pong.LeaderboardWindow_value[strings.ToUpper(v)]
+		if !ok {
+			http.Error(w, fmt.Sprintf("invalid window: %s", v), http.StatusBadRequest)
+			return
+		}
+		window = pong.LeaderboardWindow(win)
+	}
+
+	var season uint64
+	if v := q.Get("season"); v != "" {
+		var err error
+		season, err = strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid season: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	board, err := s.buildLeaderboard(r.Context(), kind, window, uint32(season), limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error building leaderboard: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(board)
+}
+
+// handleCreateTournamentHandler creates a tournament from a JSON encoded
+// TournamentConfig.
+func (s *Server) handleCreateTournamentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var cfg TournamentConfig
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, fmt.Sprintf("invalid tournament config: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	t, err := s.CreateTournament(cfg)
+	target := ""
+	if t != nil {
+		target = t.Id
+	}
+	s.logAdminAction(r.Context(), "create_tournament", target,
+		fmt.Sprintf("%q, buy-in %s, %d players", cfg.Name, cfg.BuyIn, cfg.MaxPlayers), err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error creating tournament: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(t); err != nil {
+		http.Error(w, fmt.Sprintf("error encoding response: %v", err), http.StatusInternalServerError)
+	}
+}
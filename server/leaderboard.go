@@ -0,0 +1,413 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+const (
+	// defaultRating is the rating every player starts a season with.
+	defaultRating = 1500.0
+	// eloK is the maximum rating change of a single match.
+	eloK = 32.0
+
+	defaultSeasonLength     = 30 * 24 * time.Hour
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+// eloUpdate returns the new ratings of the winner and loser of a match.
+func eloUpdate(winner, loser float64) (float64, float64) {
+	expected := 1 / (1 + math.Pow(10, (loser-winner)/400))
+	delta := eloK * (1 - expected)
+	return winner + delta, loser - delta
+}
+
+// recordMatchResult stores the outcome of a decided game and updates the
+// ratings of its players.
+func (s *Server) recordMatchResult(ctx context.Context, game *ponggame.GameInstance, players []*ponggame.Player, stakes map[zkidentity.ShortID]matoms.Amount) error {
+	if game.Winner == nil || len(players) != 2 {
+		return nil
+	}
+
+	result := &serverdb.MatchResult{
+		GameID:    game.Id,
+		WinnerUID: game.Winner.Bytes(),
+		EndedAt:   time.Now(),
+	}
+	for _, player := range players {
+		stake := stakes[*player.ID]
+		result.TotalBet += stake
+		result.Players = append(result.Players, serverdb.MatchPlayer{
+			UID:   player.ID.Bytes(),
+			Nick:  player.Nick,
+			Score: int32(player.Score),
+			Stake: stake,
+		})
+	}
+
+	// Ratings are updated in the transaction storing the result, so
+	// matches ending at the same time don't overwrite each other's.
+	return s.db.StoreMatchResult(ctx, result, func(ratings []*serverdb.PlayerRating) ([]*serverdb.PlayerRating, error) {
+		var winnerRating, loserRating *serverdb.PlayerRating
+		for i, player := range players {
+			rating := ratings[i]
+			if rating == nil {
+				rating = &serverdb.PlayerRating{UID: player.ID.Bytes(), Rating: defaultRating}
+			}
+			if player.Nick != "" {
+				rating.Nick = player.Nick
+			}
+			rating.Games++
+			if *player.ID == *game.Winner {
+				winnerRating = rating
+			} else {
+				loserRating = rating
+			}
+		}
+		if winnerRating == nil || loserRating == nil {
+			return nil, fmt.Errorf("winner %s is not a player of game %s", game.Winner, game.Id)
+		}
+		winnerRating.Rating, loserRating.Rating = eloUpdate(winnerRating.Rating, loserRating.Rating)
+		return []*serverdb.PlayerRating{winnerRating, loserRating}, nil
+	})
+}
+
+// computeStandings aggregates per player statistics from matches ordered
+// from oldest to newest.
+func computeStandings(matches []*serverdb.MatchResult, ratings []*serverdb.PlayerRating) []serverdb.SeasonStanding {
+	ratingByUID := make(map[string]*serverdb.PlayerRating, len(ratings))
+	for _, r := range ratings {
+		ratingByUID[string(r.UID)] = r
+	}
+
+	byUID := make(map[string]*serverdb.SeasonStanding)
+	var order []string
+	for _, match := range matches {
+		for _, p := range match.Players {
+			st := byUID[string(p.UID)]
+			if st == nil {
+				st = &serverdb.SeasonStanding{UID: p.UID, Rating: defaultRating}
+				if r := ratingByUID[string(p.UID)]; r != nil {
+					st.Rating = r.Rating
+					st.Nick = r.Nick
+				}
+				byUID[string(p.UID)] = st
+				order = append(order, string(p.UID))
+			}
+			if p.Nick != "" {
+				st.Nick = p.Nick
+			}
+
+			if bytes.Equal(p.UID, match.WinnerUID) {
+				st.Wins++
+				st.NetWinnings += match.TotalBet - p.Stake
+				st.CurrentStreak++
+				if st.CurrentStreak > st.BestStreak {
+					st.BestStreak = st.CurrentStreak
+				}
+			} else {
+				st.Losses++
+				st.NetWinnings -= p.Stake
+				st.CurrentStreak = 0
+			}
+		}
+	}
+
+	standings := make([]serverdb.SeasonStanding, 0, len(order))
+	for _, uid := range order {
+		standings = append(standings, *byUID[uid])
+	}
+	return standings
+}
+
+// sortStandings orders standings for the given leaderboard kind.
+func sortStandings(standings []serverdb.SeasonStanding, kind pong.LeaderboardKind) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		switch kind {
+		case pong.LeaderboardKind_BY_NET_WINNINGS:
+			if a.NetWinnings != b.NetWinnings {
+				return a.NetWinnings > b.NetWinnings
+			}
+		case pong.LeaderboardKind_BY_WIN_STREAK:
+			if a.BestStreak != b.BestStreak {
+				return a.BestStreak > b.BestStreak
+			}
+			if a.CurrentStreak != b.CurrentStreak {
+				return a.CurrentStreak > b.CurrentStreak
+			}
+		default:
+			if a.Rating != b.Rating {
+				return a.Rating > b.Rating
+			}
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return bytes.Compare(a.UID, b.UID) < 0
+	})
+}
+
+// leaderboardWindowStart returns when the given window started. Daily and
+// weekly windows follow UTC calendar days and ISO weeks, clamped to the
+// start of the season.
+func leaderboardWindowStart(window pong.LeaderboardWindow, season *serverdb.Season, now time.Time) time.Time {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var start time.Time
+	switch window {
+	case pong.LeaderboardWindow_DAILY:
+		start = day
+	case pong.LeaderboardWindow_WEEKLY:
+		start = weekStart(now)
+	default:
+		return season.StartedAt
+	}
+	if start.Before(season.StartedAt) {
+		return season.StartedAt
+	}
+	return start
+}
+
+// weekStart returns midnight UTC of the monday of t's week.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func marshalStandings(standings []serverdb.SeasonStanding, limit int) []*pong.LeaderboardEntry {
+	if limit > len(standings) {
+		limit = len(standings)
+	}
+	entries := make([]*pong.LeaderboardEntry, limit)
+	for i := 0; i < limit; i++ {
+		st := standings[i]
+		entries[i] = &pong.LeaderboardEntry{
+			Rank:          int32(i + 1),
+			Uid:           hex.EncodeToString(st.UID),
+			Nick:          st.Nick,
+			Rating:        st.Rating,
+			NetWinnings:   int64(st.NetWinnings),
+			BestStreak:    st.BestStreak,
+			CurrentStreak: st.CurrentStreak,
+			Wins:          st.Wins,
+			Losses:        st.Losses,
+		}
+	}
+	return entries
+}
+
+// buildLeaderboard ranks players of the current season within a window, or
+// the archived final standings of a past season.
+func (s *Server) buildLeaderboard(ctx context.Context, kind pong.LeaderboardKind, window pong.LeaderboardWindow, seasonNumber uint32, limit int) (*pong.LeaderboardResponse, error) {
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	if limit > maxLeaderboardLimit {
+		limit = maxLeaderboardLimit
+	}
+
+	season, err := s.db.FetchCurrentSeason(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == pong.LeaderboardKind_BY_CREDITS {
+		entries, err := s.creditLeaderboard(ctx, limit)
+		if err != nil {
+			return nil, err
+		}
+		return &pong.LeaderboardResponse{
+			Kind:    kind,
+			Window:  window,
+			Season:  season.Number,
+			Entries: entries,
+		}, nil
+	}
+
+	if seasonNumber != 0 && seasonNumber != season.Number {
+		archive, err := s.db.FetchSeasonArchive(ctx, seasonNumber)
+		if err != nil {
+			return nil, fmt.Errorf("season %d: %w", seasonNumber, err)
+		}
+		standings := append([]serverdb.SeasonStanding(nil), archive.Standings...)
+		sortStandings(standings, kind)
+		return &pong.LeaderboardResponse{
+			Kind:        kind,
+			Window:      pong.LeaderboardWindow_SEASON,
+			Season:      archive.Number,
+			WindowStart: archive.StartedAt.Unix(),
+			Entries:     marshalStandings(standings, limit),
+		}, nil
+	}
+
+	start := leaderboardWindowStart(window, season, time.Now())
+	matches, err := s.db.FetchMatchResults(ctx, season.Number, start)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.db.FetchPlayerRatings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := computeStandings(matches, ratings)
+	sortStandings(standings, kind)
+	return &pong.LeaderboardResponse{
+		Kind:        kind,
+		Window:      window,
+		Season:      season.Number,
+		WindowStart: start.Unix(),
+		Entries:     marshalStandings(standings, limit),
+	}, nil
+}
+
+// GetLeaderboard returns a ranked leaderboard.
+func (s *Server) GetLeaderboard(ctx context.Context, req *pong.LeaderboardRequest) (*pong.LeaderboardResponse, error) {
+	return s.buildLeaderboard(ctx, req.Kind, req.Window, req.Season, int(req.Limit))
+}
+
+// runLeaderboardLoop resets seasons once they run past their length and
+// posts the weekly top players.
+func (s *Server) runLeaderboardLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if err := s.maybeRolloverSeason(ctx, time.Now()); err != nil {
+			s.log.Errorf("Failed to roll over season: %v", err)
+		}
+		if err := s.maybePostWeeklyLeaderboard(ctx, time.Now()); err != nil {
+			s.log.Errorf("Failed to post weekly leaderboard: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// maybeRolloverSeason archives the final standings of the current season and
+// starts a new one when the season is over.
+func (s *Server) maybeRolloverSeason(ctx context.Context, now time.Time) error {
+	if s.seasonLength <= 0 {
+		return nil
+	}
+	season, err := s.db.FetchCurrentSeason(ctx)
+	if err != nil {
+		return err
+	}
+	if now.Sub(season.StartedAt) < s.seasonLength {
+		return nil
+	}
+
+	matches, err := s.db.FetchMatchResults(ctx, season.Number, season.StartedAt)
+	if err != nil {
+		return err
+	}
+	ratings, err := s.db.FetchPlayerRatings(ctx)
+	if err != nil {
+		return err
+	}
+	standings := computeStandings(matches, ratings)
+	sortStandings(standings, pong.LeaderboardKind_BY_RATING)
+
+	archive := &serverdb.SeasonArchive{
+		Number:    season.Number,
+		StartedAt: season.StartedAt,
+		EndedAt:   now,
+		Standings: standings,
+	}
+	next := &serverdb.Season{
+		Number:         season.Number + 1,
+		StartedAt:      now,
+		LastWeeklyPost: season.LastWeeklyPost,
+	}
+	if err := s.db.ArchiveSeason(ctx, archive, next); err != nil {
+		return err
+	}
+	s.log.Infof("Season %d ended with %d ranked players; season %d started",
+		season.Number, len(standings), next.Number)
+	return nil
+}
+
+// maybePostWeeklyLeaderboard sends the top players of the previous week to
+// everyone that played during that week, once per week.
+func (s *Server) maybePostWeeklyLeaderboard(ctx context.Context, now time.Time) error {
+	if s.bot == nil {
+		return nil
+	}
+	season, err := s.db.FetchCurrentSeason(ctx)
+	if err != nil {
+		return err
+	}
+	thisWeek := weekStart(now)
+	if !season.LastWeeklyPost.Before(thisWeek) {
+		return nil
+	}
+	lastWeek := thisWeek.AddDate(0, 0, -7)
+
+	matches, err := s.db.FetchMatchResults(ctx, season.Number, lastWeek)
+	if err != nil {
+		return err
+	}
+	weekMatches := matches[:0]
+	for _, m := range matches {
+		if m.EndedAt.Before(thisWeek) {
+			weekMatches = append(weekMatches, m)
+		}
+	}
+
+	season.LastWeeklyPost = now
+	if err := s.db.StoreSeason(ctx, season); err != nil {
+		return err
+	}
+	if len(weekMatches) == 0 {
+		return nil
+	}
+
+	ratings, err := s.db.FetchPlayerRatings(ctx)
+	if err != nil {
+		return err
+	}
+	standings := computeStandings(weekMatches, ratings)
+	sortStandings(standings, pong.LeaderboardKind_BY_NET_WINNINGS)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pong weekly top %d (week of %s):\n", defaultLeaderboardLimit, lastWeek.Format("2006-01-02"))
+	for _, e := range marshalStandings(standings, defaultLeaderboardLimit) {
+		name := e.Nick
+		if name == "" {
+			name = e.Uid[:12]
+		}
+		fmt.Fprintf(&b, "%d. %s - %d W / %d L, net %s DCR, rating %.0f\n",
+			e.Rank, name, e.Wins, e.Losses, matoms.Amount(e.NetWinnings), e.Rating)
+	}
+	msg := b.String()
+
+	for _, st := range standings {
+		var uid zkidentity.ShortID
+		copy(uid[:], st.UID)
+		if err := s.bot.SendPM(ctx, uid.String(), msg); err != nil {
+			s.log.Warnf("Failed to send weekly leaderboard to %s: %v", uid, err)
+		}
+	}
+	return nil
+}
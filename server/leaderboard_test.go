@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func TestEloUpdate(t *testing.T) {
+	w, l := eloUpdate(defaultRating, defaultRating)
+	require.InDelta(t, defaultRating+eloK/2, w, 1e-9)
+	require.InDelta(t, defaultRating-eloK/2, l, 1e-9)
+
+	// Beating a much weaker player gains little.
+	w, l = eloUpdate(2000, 1200)
+	require.Less(t, w-2000, 1.0)
+	require.InDelta(t, 2000+1200, w+l, 1e-9)
+}
+
+func TestComputeStandings(t *testing.T) {
+	a, b, c := []byte{1}, []byte{2}, []byte{3}
+	match := func(winner, loser []byte, stake matoms.Amount) *serverdb.MatchResult {
+		return &serverdb.MatchResult{
+			WinnerUID: winner,
+			TotalBet:  2 * stake,
+			Players: []serverdb.MatchPlayer{
+				{UID: winner, Stake: stake},
+				{UID: loser, Stake: stake},
+			},
+		}
+	}
+	matches := []*serverdb.MatchResult{
+		match(a, b, 100),
+		match(a, c, 100),
+		match(b, a, 300),
+		match(b, c, 100),
+		match(b, a, 100),
+	}
+
+	standings := computeStandings(matches, []*serverdb.PlayerRating{{UID: c, Rating: 1700}})
+	byUID := make(map[byte]serverdb.SeasonStanding)
+	for _, st := range standings {
+		byUID[st.UID[0]] = st
+	}
+
+	require.Equal(t, int32(2), byUID[1].Wins)
+	require.Equal(t, int32(2), byUID[1].BestStreak)
+	require.Equal(t, int32(0), byUID[1].CurrentStreak)
+	require.Equal(t, matoms.Amount(100+100-300-100), byUID[1].NetWinnings)
+
+	require.Equal(t, int32(3), byUID[2].BestStreak)
+	require.Equal(t, int32(3), byUID[2].CurrentStreak)
+	require.Equal(t, matoms.Amount(-100+300+100+100), byUID[2].NetWinnings)
+
+	require.Equal(t, 1700.0, byUID[3].Rating)
+	require.Equal(t, defaultRating, byUID[1].Rating)
+
+	sortStandings(standings, pong.LeaderboardKind_BY_NET_WINNINGS)
+	require.Equal(t, b, standings[0].UID)
+	sortStandings(standings, pong.LeaderboardKind_BY_RATING)
+	require.Equal(t, c, standings[0].UID)
+}
+
+func TestLeaderboardWindowStart(t *testing.T) {
+	season := &serverdb.Season{StartedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)}
+	// Thursday.
+	now := time.Date(2025, 3, 20, 15, 4, 5, 0, time.UTC)
+
+	require.Equal(t, time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC),
+		leaderboardWindowStart(pong.LeaderboardWindow_DAILY, season, now))
+	require.Equal(t, time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC),
+		leaderboardWindowStart(pong.LeaderboardWindow_WEEKLY, season, now))
+	require.Equal(t, season.StartedAt,
+		leaderboardWindowStart(pong.LeaderboardWindow_SEASON, season, now))
+
+	// Windows never reach back past the season start.
+	season.StartedAt = time.Date(2025, 3, 19, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, season.StartedAt,
+		leaderboardWindowStart(pong.LeaderboardWindow_WEEKLY, season, now))
+}
+
+func TestLeaderboardSeasonRollover(t *testing.T) {
+	srv := setupTestServer(t)
+	srv.seasonLength = time.Hour
+	ctx := context.Background()
+
+	var p1ID, p2ID zkidentity.ShortID
+	_ = p1ID.FromString("dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd")
+	_ = p2ID.FromString("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+	players := []*ponggame.Player{
+		{ID: &p1ID, Nick: "alice", Score: 3},
+		{ID: &p2ID, Nick: "bob", Score: 1},
+	}
+	game := &ponggame.GameInstance{Id: "game1", Winner: &p1ID}
+	require.NoError(t, srv.recordMatchResult(ctx, game, players, nil))
+
+	board, err := srv.GetLeaderboard(ctx, &pong.LeaderboardRequest{})
+	require.NoError(t, err)
+	require.Len(t, board.Entries, 2)
+	require.Equal(t, "alice", board.Entries[0].Nick)
+	require.Greater(t, board.Entries[0].Rating, defaultRating)
+	require.Equal(t, uint32(1), board.Season)
+
+	season, err := srv.db.FetchCurrentSeason(ctx)
+	require.NoError(t, err)
+	require.NoError(t, srv.maybeRolloverSeason(ctx, season.StartedAt.Add(2*time.Hour)))
+
+	// The new season starts empty with reset ratings.
+	board, err = srv.GetLeaderboard(ctx, &pong.LeaderboardRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), board.Season)
+	require.Empty(t, board.Entries)
+	ratings, err := srv.db.FetchPlayerRatings(ctx)
+	require.NoError(t, err)
+	require.Empty(t, ratings)
+
+	// The final standings of the first season are archived.
+	board, err = srv.GetLeaderboard(ctx, &pong.LeaderboardRequest{Season: 1})
+	require.NoError(t, err)
+	require.Len(t, board.Entries, 2)
+	require.Equal(t, p1ID.String(), board.Entries[0].Uid)
+}
+
+func TestRecordMatchResultConcurrent(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+
+	var p1ID, p2ID zkidentity.ShortID
+	_ = p1ID.FromString("dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd")
+	_ = p2ID.FromString("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+
+	// Matches ending at the same time all count towards the ratings.
+	const matches = 10
+	errs := make(chan error, matches)
+	for i := 0; i < matches; i++ {
+		go func(i int) {
+			players := []*ponggame.Player{{ID: &p1ID, Score: 3}, {ID: &p2ID, Score: 1}}
+			game := &ponggame.GameInstance{Id: fmt.Sprintf("game%d", i), Winner: &p1ID}
+			errs <- srv.recordMatchResult(ctx, game, players, nil)
+		}(i)
+	}
+	for i := 0; i < matches; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	ratings, err := srv.db.FetchPlayerRatings(ctx)
+	require.NoError(t, err)
+	require.Len(t, ratings, 2)
+	for _, r := range ratings {
+		require.Equal(t, int32(matches), r.Games)
+	}
+	results, err := srv.db.FetchMatchResults(ctx, 1, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, results, matches)
+	results, err = srv.db.FetchMatchResults(ctx, 2, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
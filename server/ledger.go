@@ -0,0 +1,337 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// stakeAccounts returns the ledger accounts players stake from: the DCR they
+// tip the bot, or the virtual credits of free-to-play servers.
+func (s *Server) stakeAccounts() serverdb.Accounts {
+	if s.isF2P {
+		return serverdb.CreditAccounts
+	}
+	return serverdb.DCRAccounts
+}
+
+// fetchPlayerBalance returns the available balance of a player and the total
+// of the stakes they have reserved in games, waiting rooms and tournaments.
+func (s *Server) fetchPlayerBalance(ctx context.Context, uid zkidentity.ShortID) (available, reserved matoms.Amount, err error) {
+	accounts := s.stakeAccounts()
+	available, err = s.db.FetchAccountBalance(ctx, accounts.PlayerAccount(uid))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch balance of %s: %v", uid, err)
+	}
+	escrows, err := s.db.FetchAccountBalances(ctx, accounts.Escrow)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch escrows: %v", err)
+	}
+	suffix := "/" + uid.String()
+	for account, amount := range escrows {
+		if strings.HasSuffix(account, suffix) {
+			reserved += amount
+		}
+	}
+	return available, reserved, nil
+}
+
+// playerFunds returns everything a player has deposited and not lost or been
+// paid back, reserved or not.
+func (s *Server) playerFunds(ctx context.Context, uid zkidentity.ShortID) (matoms.Amount, error) {
+	available, reserved, err := s.fetchPlayerBalance(ctx, uid)
+	return available + reserved, err
+}
+
+// reserveStake moves the stake of a player from their available balance to
+// the escrow of a game, waiting room or tournament.
+func (s *Server) reserveStake(ctx context.Context, escrowID string, uid zkidentity.ShortID, amount matoms.Amount) error {
+	if amount == 0 {
+		return nil
+	}
+	accounts := s.stakeAccounts()
+	err := s.db.PostJournalEntry(ctx, &serverdb.JournalEntry{
+		Kind: serverdb.EntryReserve,
+		Ref:  escrowID,
+		Postings: []serverdb.Posting{
+			{Account: accounts.PlayerAccount(uid), Amount: -amount},
+			{Account: accounts.EscrowAccount(escrowID, uid), Amount: amount},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reserve stake of %s: %w", uid, err)
+	}
+	return nil
+}
+
+// changeStake moves the stake a player holds in an escrow from one amount to
+// another in a single journal entry, so the old stake is kept if the player
+// can't cover the new one.
+func (s *Server) changeStake(ctx context.Context, escrowID string, uid zkidentity.ShortID, from, to matoms.Amount) error {
+	diff := to - from
+	if diff == 0 {
+		return nil
+	}
+	accounts := s.stakeAccounts()
+	err := s.db.PostJournalEntry(ctx, &serverdb.JournalEntry{
+		Kind: serverdb.EntryReserve,
+		Ref:  escrowID,
+		Postings: []serverdb.Posting{
+			{Account: accounts.PlayerAccount(uid), Amount: -diff},
+			{Account: accounts.EscrowAccount(escrowID, uid), Amount: diff},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change stake of %s: %w", uid, err)
+	}
+	return nil
+}
+
+// releaseStake returns the stake a player holds in an escrow to their
+// available balance.
+func (s *Server) releaseStake(ctx context.Context, escrowID string, uid zkidentity.ShortID) {
+	accounts := s.stakeAccounts()
+	_, err := s.db.SweepAccounts(ctx, serverdb.EntryRelease, escrowID,
+		accounts.EscrowAccount(escrowID, uid), accounts.PlayerAccount(uid))
+	if err != nil {
+		s.log.Errorf("Failed to release stake of %s in %s: %v", uid, escrowID, err)
+	}
+}
+
+// fetchStakes returns the stake each player holds in an escrow.
+func (s *Server) fetchStakes(ctx context.Context, escrowID string) (map[zkidentity.ShortID]matoms.Amount, error) {
+	prefix := s.stakeAccounts().EscrowPrefix(escrowID)
+	escrows, err := s.db.FetchAccountBalances(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stakes held in %s: %v", escrowID, err)
+	}
+	stakes := make(map[zkidentity.ShortID]matoms.Amount, len(escrows))
+	for account, amount := range escrows {
+		var uid zkidentity.ShortID
+		if err := uid.FromString(strings.TrimPrefix(account, prefix)); err != nil {
+			return nil, fmt.Errorf("invalid escrow account %s: %v", account, err)
+		}
+		stakes[uid] = amount
+	}
+	return stakes, nil
+}
+
+// releaseStakes returns every stake held in an escrow to the players that
+// reserved them.
+func (s *Server) releaseStakes(ctx context.Context, escrowID string) {
+	stakes, err := s.fetchStakes(ctx, escrowID)
+	if err != nil {
+		s.log.Errorf("Failed to release stakes of %s: %v", escrowID, err)
+		return
+	}
+	for uid := range stakes {
+		s.releaseStake(ctx, escrowID, uid)
+	}
+}
+
+// releaseRoomStake releases the stake of a player leaving their waiting room
+// for good. Rooms close when their host leaves, so every stake of the room is
+// released then.
+func (s *Server) releaseRoomStake(player *ponggame.Player) {
+	wr := player.WR
+	if wr == nil || s.gameManager.GetWaitingRoom(wr.ID) == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if wr.IsHost(*player.ID) {
+		s.releaseStakes(ctx, wr.ID)
+		return
+	}
+	s.releaseStake(ctx, wr.ID, *player.ID)
+}
+
+// settleStakes moves every stake held in an escrow to the winner, keeping the
+// rake for the house. The settlement is journaled under the game ID. It
+// returns the amount won and the rake.
+func (s *Server) settleStakes(ctx context.Context, escrowID, gameID string, winner zkidentity.ShortID) (won, rake matoms.Amount, err error) {
+	escrows, err := s.db.FetchAccountBalances(ctx, s.stakeAccounts().EscrowPrefix(escrowID))
+	if err != nil {
+		return 0, 0, err
+	}
+	pot := matoms.Amount(0)
+	for _, amount := range escrows {
+		pot += amount
+	}
+	rake = s.rakeFor(pot)
+	won = pot - rake
+	err = s.settleShares(ctx, escrowID, gameID, escrows, map[zkidentity.ShortID]matoms.Amount{winner: won}, rake)
+	if err != nil {
+		return 0, 0, err
+	}
+	return won, rake, nil
+}
+
+// settleShares splits the stakes held in the given escrow accounts between
+// several players and the house. The shares and rake must add up to the
+// stakes held. The settlement is journaled under ref.
+func (s *Server) settleShares(ctx context.Context, escrowID, ref string, escrows map[string]matoms.Amount, shares map[zkidentity.ShortID]matoms.Amount, rake matoms.Amount) error {
+	entry := &serverdb.JournalEntry{Kind: serverdb.EntrySettle, Ref: ref}
+	for account, amount := range escrows {
+		entry.Postings = append(entry.Postings, serverdb.Posting{Account: account, Amount: -amount})
+	}
+	for uid, amount := range shares {
+		if amount == 0 {
+			continue
+		}
+		entry.Postings = append(entry.Postings, serverdb.Posting{Account: s.stakeAccounts().PlayerAccount(uid), Amount: amount})
+	}
+	if rake > 0 {
+		entry.Postings = append(entry.Postings, serverdb.Posting{Account: serverdb.HouseAccount, Amount: rake})
+	}
+	if len(entry.Postings) == 0 {
+		return nil
+	}
+	sort.Slice(entry.Postings, func(i, j int) bool {
+		return entry.Postings[i].Account < entry.Postings[j].Account
+	})
+	return s.db.PostJournalEntry(ctx, entry)
+}
+
+// spendStakes spends the settled stakes from the unpaid tips backing them.
+// The winnings a player kept in their balance, such as the milli-atoms left
+// over by recordPayout, stay backed by the unspent part of their stake's tips.
+// It returns the tips spent in full, which are now being sent.
+func (s *Server) spendStakes(ctx context.Context, stakes, kept map[zkidentity.ShortID]matoms.Amount) []*types.ReceivedTip {
+	var spent []*types.ReceivedTip
+	for uid, amount := range stakes {
+		amount = max(amount-kept[uid], 0)
+		if amount == 0 {
+			continue
+		}
+		tips, err := s.db.SpendTips(ctx, uid, amount, serverdb.StatusSending)
+		if err != nil {
+			s.log.Errorf("Failed to spend stake of %s: %v", uid, err)
+			continue
+		}
+		spent = append(spent, tips...)
+	}
+	return spent
+}
+
+// recordPayout moves an amount sent to a player out of their available
+// balance and returns it. Only whole atoms can be sent, so the amount is
+// rounded down and the milli-atoms left over stay in the player's balance.
+func (s *Server) recordPayout(ctx context.Context, kind serverdb.EntryKind, ref string, uid zkidentity.ShortID, amount matoms.Amount) (matoms.Amount, error) {
+	amount = matoms.FromAtoms(amount.Atoms())
+	if amount == 0 {
+		return 0, nil
+	}
+	err := s.db.PostJournalEntry(ctx, &serverdb.JournalEntry{
+		Kind: kind,
+		Ref:  ref,
+		Postings: []serverdb.Posting{
+			{Account: serverdb.PlayerAccount(uid), Amount: -amount},
+			{Account: serverdb.PayoutsAccount, Amount: amount},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// releaseOrphanedStakes returns the stakes held by escrows that no longer
+// belong to a waiting room, such as games and tournaments interrupted by a
+// restart.
+func (s *Server) releaseOrphanedStakes(ctx context.Context) error {
+	root := s.stakeAccounts().Escrow
+	escrows, err := s.db.FetchAccountBalances(ctx, root)
+	if err != nil {
+		return err
+	}
+	orphaned := make(map[string]struct{})
+	for account := range escrows {
+		escrowID, _, ok := strings.Cut(strings.TrimPrefix(account, root), "/")
+		if ok && s.gameManager.GetWaitingRoom(escrowID) == nil {
+			orphaned[escrowID] = struct{}{}
+		}
+	}
+	for escrowID := range orphaned {
+		s.log.Infof("Releasing stakes of interrupted game %s", escrowID)
+		s.releaseStakes(ctx, escrowID)
+	}
+	return nil
+}
+
+// checkLedger verifies the ledger is consistent and that the funds of every
+// player match the unspent part of their unpaid tips.
+func (s *Server) checkLedger(ctx context.Context) error {
+	if err := s.db.CheckLedger(ctx); err != nil {
+		return err
+	}
+	mismatches, err := s.fundsMismatches(ctx)
+	if err != nil {
+		return err
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	m := mismatches[0]
+	if m.Tips == 0 {
+		return fmt.Errorf("funds of %s drifted: ledger %s, no unpaid tips", m.UID, m.Ledger)
+	}
+	return fmt.Errorf("funds of %s drifted: ledger %s, unpaid tips %s", m.UID, m.Ledger, m.Tips)
+}
+
+// fundsMismatch is a player whose DCR funds in the ledger don't match the
+// unspent part of their unpaid tips.
+type fundsMismatch struct {
+	UID    string        `json:"uid"`
+	Ledger matoms.Amount `json:"ledger"`
+	Tips   matoms.Amount `json:"tips"`
+}
+
+// fundsMismatches returns the players whose DCR funds in the ledger don't
+// match their unpaid tips, ordered by UID.
+func (s *Server) fundsMismatches(ctx context.Context) ([]fundsMismatch, error) {
+	tipFunds, err := s.db.FetchUnspentTips(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerFunds := make(map[zkidentity.ShortID]matoms.Amount)
+	for _, prefix := range []string{serverdb.DCRAccounts.Player, serverdb.DCRAccounts.Escrow} {
+		balances, err := s.db.FetchAccountBalances(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for account, amount := range balances {
+			var uid zkidentity.ShortID
+			if err := uid.FromString(account[strings.LastIndex(account, "/")+1:]); err != nil {
+				return nil, fmt.Errorf("invalid ledger account %s", account)
+			}
+			ledgerFunds[uid] += amount
+		}
+	}
+
+	var mismatches []fundsMismatch
+	for uid, funds := range tipFunds {
+		if ledgerFunds[uid] != funds {
+			mismatches = append(mismatches, fundsMismatch{UID: uid.String(), Ledger: ledgerFunds[uid], Tips: funds})
+		}
+		delete(ledgerFunds, uid)
+	}
+	for uid, funds := range ledgerFunds {
+		if funds != 0 {
+			mismatches = append(mismatches, fundsMismatch{UID: uid.String(), Ledger: funds})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].UID < mismatches[j].UID
+	})
+	return mismatches, nil
+}
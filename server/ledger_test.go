@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func TestLedgerGameSettlement(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	available, reserved, err := srv.fetchPlayerBalance(ctx, p2ID)
+	require.NoError(t, err)
+	require.Zero(t, available)
+	require.Equal(t, matoms.Amount(50000000000), reserved)
+	require.NoError(t, srv.checkLedger(ctx))
+
+	// Leaving the room releases the stake.
+	leave, err := srv.LeaveWaitingRoom(ctx, &pong.LeaveWaitingRoomRequest{
+		ClientId: p2ID.String(),
+		RoomId:   resp.Wr.Id,
+	})
+	require.NoError(t, err)
+	require.True(t, leave.Success)
+	available, reserved, err = srv.fetchPlayerBalance(ctx, p2ID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(50000000000), available)
+	require.Zero(t, reserved)
+
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	// The winner takes both stakes, which are then paid out.
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	game := &ponggame.GameInstance{Id: "game1", Winner: &p1ID}
+	srv.handleGameEnd(ctx, wr.ID, game, series, players)
+
+	for _, uid := range []zkidentity.ShortID{p1ID, p2ID} {
+		funds, err := srv.playerFunds(ctx, uid)
+		require.NoError(t, err)
+		require.Zero(t, funds)
+	}
+	paid, err := srv.db.FetchAccountBalance(ctx, serverdb.PayoutsAccount)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(100000000000), paid)
+	require.NoError(t, srv.checkLedger(ctx))
+}
+
+func TestLedgerRefundAndDrift(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	// Closing a room releases every stake before refunds.
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	srv.closeWaitingRoom(srv.gameManager.GetWaitingRoom(resp.Wr.Id))
+	available, reserved, err := srv.fetchPlayerBalance(ctx, p1ID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(50000000000), available)
+	require.Zero(t, reserved)
+
+	require.NoError(t, srv.handleReturnUnprocessedTips(ctx, p1ID))
+	funds, err := srv.playerFunds(ctx, p1ID)
+	require.NoError(t, err)
+	require.Zero(t, funds)
+	require.NoError(t, srv.checkLedger(ctx))
+
+	// Tips spent behind the ledger's back are caught.
+	tipID := make([]byte, 8)
+	binary.BigEndian.PutUint64(tipID, 401)
+	require.NoError(t, srv.db.UpdateTipStatus(ctx, p2ID[:], tipID, serverdb.StatusSending))
+	require.ErrorContains(t, srv.checkLedger(ctx), "drifted")
+}
+
+func TestLedgerPartialStakes(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	// Stakes can't exceed the available balance.
+	_, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 60000000000,
+	})
+	require.ErrorContains(t, err, "insufficient balance")
+
+	// Only the stake is reserved, the rest stays available.
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 10000000000,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+	available, reserved, err := srv.fetchPlayerBalance(ctx, p2ID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(40000000000), available)
+	require.Equal(t, matoms.Amount(10000000000), reserved)
+
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	game := &ponggame.GameInstance{Id: "game1", Winner: &p1ID}
+	srv.handleGameEnd(ctx, wr.ID, game, series, players)
+
+	// The winner is paid both stakes and both players keep the rest of
+	// their balance, backed by their partially spent tips.
+	paid, err := srv.db.FetchAccountBalance(ctx, serverdb.PayoutsAccount)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(20000000000), paid)
+	for _, uid := range []zkidentity.ShortID{p1ID, p2ID} {
+		available, reserved, err := srv.fetchPlayerBalance(ctx, uid)
+		require.NoError(t, err)
+		require.Equal(t, matoms.Amount(40000000000), available)
+		require.Zero(t, reserved)
+	}
+	require.NoError(t, srv.checkLedger(ctx))
+
+	// Refunds pay back what is left.
+	require.NoError(t, srv.handleReturnUnprocessedTips(ctx, p2ID))
+	funds, err := srv.playerFunds(ctx, p2ID)
+	require.NoError(t, err)
+	require.Zero(t, funds)
+	unpaid, err := srv.db.FetchReceivedTipsByUID(ctx, p2ID, serverdb.StatusUnpaid)
+	require.NoError(t, err)
+	require.Empty(t, unpaid)
+	require.NoError(t, srv.checkLedger(ctx))
+}
+
+func TestLedgerSubAtomStakes(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 10000000333,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	game := &ponggame.GameInstance{Id: "game1", Winner: &p1ID}
+	srv.handleGameEnd(ctx, wr.ID, game, series, players)
+
+	// Only whole atoms are paid, the winner keeps the milli-atoms left over
+	// backed by their unspent tips.
+	paid, err := srv.db.FetchAccountBalance(ctx, serverdb.PayoutsAccount)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(20000000000), paid)
+	available, _, err := srv.fetchPlayerBalance(ctx, p1ID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(40000000333), available)
+	require.NoError(t, srv.checkLedger(ctx))
+
+	// The winner is told what was paid.
+	msgs := players[0].NotifierStream.(*mockNotifierStream).messages
+	ntfn := msgs[len(msgs)-1]
+	require.Equal(t, pong.NotificationType_GAME_END, ntfn.NotificationType)
+	require.Contains(t, ntfn.Message, "received: "+matoms.Amount(20000000000).String())
+}
@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+const (
+	// limitsCoolOff is how long looser limits take to apply.
+	limitsCoolOff = 24 * time.Hour
+
+	// lossWindow is the period the daily loss limit applies to.
+	lossWindow = 24 * time.Hour
+
+	// minSelfExclusion and maxSelfExclusion bound the length of a
+	// self-exclusion.
+	minSelfExclusion = 24 * time.Hour
+	maxSelfExclusion = 5 * 365 * 24 * time.Hour
+
+	// minSessionReminder is the shortest interval between session
+	// reminders, and sessionCheckInterval how often they are checked.
+	minSessionReminder   = 15 * time.Minute
+	sessionCheckInterval = time.Minute
+)
+
+var errLimitExceeded = errors.New("responsible gaming limit")
+
+// fetchLimits returns the limits of a player, applying pending limits whose
+// cool-off is over.
+func (s *Server) fetchLimits(ctx context.Context, uid zkidentity.ShortID, now time.Time) (*serverdb.PlayerLimits, error) {
+	limits, err := s.db.FetchPlayerLimits(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch limits of %s: %v", uid, err)
+	}
+	if p := limits.Pending; p != nil && !now.Before(p.EffectiveAt) {
+		limits.MaxStake = p.MaxStake
+		limits.MaxDailyLoss = p.MaxDailyLoss
+		limits.Pending = nil
+		if err := s.db.StorePlayerLimits(ctx, uid, limits); err != nil {
+			return nil, fmt.Errorf("failed to store limits of %s: %v", uid, err)
+		}
+	}
+	return limits, nil
+}
+
+// stricter returns whether limit is at least as strict as current. Zero means
+// no limit.
+func stricter(limit, current matoms.Amount) bool {
+	return limit != 0 && (current == 0 || limit <= current)
+}
+
+// setLimits replaces the limits of a player. Stricter limits apply at once,
+// looser ones after limitsCoolOff, replacing any limits already pending.
+func (s *Server) setLimits(ctx context.Context, uid zkidentity.ShortID, maxStake, maxDailyLoss matoms.Amount, reminder time.Duration, now time.Time) (*serverdb.PlayerLimits, error) {
+	if maxStake < 0 || maxDailyLoss < 0 || reminder < 0 {
+		return nil, fmt.Errorf("limits can't be negative")
+	}
+	if reminder != 0 && reminder < minSessionReminder {
+		return nil, fmt.Errorf("session reminders can't be more frequent than every %s", minSessionReminder)
+	}
+	limits, err := s.fetchLimits(ctx, uid, now)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &serverdb.PendingLimits{
+		MaxStake:     limits.MaxStake,
+		MaxDailyLoss: limits.MaxDailyLoss,
+		EffectiveAt:  now.Add(limitsCoolOff),
+	}
+	looser := false
+	if stricter(maxStake, limits.MaxStake) {
+		limits.MaxStake = maxStake
+		pending.MaxStake = maxStake
+	} else if maxStake != limits.MaxStake {
+		pending.MaxStake = maxStake
+		looser = true
+	}
+	if stricter(maxDailyLoss, limits.MaxDailyLoss) {
+		limits.MaxDailyLoss = maxDailyLoss
+		pending.MaxDailyLoss = maxDailyLoss
+	} else if maxDailyLoss != limits.MaxDailyLoss {
+		pending.MaxDailyLoss = maxDailyLoss
+		looser = true
+	}
+	limits.Pending = nil
+	if looser {
+		limits.Pending = pending
+	}
+	limits.SessionReminder = reminder
+
+	if err := s.db.StorePlayerLimits(ctx, uid, limits); err != nil {
+		return nil, fmt.Errorf("failed to store limits of %s: %v", uid, err)
+	}
+	s.log.Infof("Player %s set limits: stake %s, daily loss %s, reminder %s",
+		uid, maxStake, maxDailyLoss, reminder)
+	return limits, nil
+}
+
+// selfExclude keeps a player from playing for d. Exclusions can be extended
+// but never shortened.
+func (s *Server) selfExclude(ctx context.Context, uid zkidentity.ShortID, d time.Duration, now time.Time) (*serverdb.PlayerLimits, error) {
+	if d < minSelfExclusion || d > maxSelfExclusion {
+		return nil, fmt.Errorf("self-exclusion must last between %d and %d days",
+			minSelfExclusion/(24*time.Hour), maxSelfExclusion/(24*time.Hour))
+	}
+	limits, err := s.fetchLimits(ctx, uid, now)
+	if err != nil {
+		return nil, err
+	}
+	if until := now.Add(d); until.After(limits.ExcludedUntil) {
+		limits.ExcludedUntil = until
+	}
+	if err := s.db.StorePlayerLimits(ctx, uid, limits); err != nil {
+		return nil, fmt.Errorf("failed to store limits of %s: %v", uid, err)
+	}
+	s.log.Infof("Player %s self-excluded until %s", uid, limits.ExcludedUntil.Format(time.RFC3339))
+	return limits, nil
+}
+
+// dailyLoss returns how much a player lost in settled games and tournaments
+// in the lossWindow before now.
+func (s *Server) dailyLoss(ctx context.Context, uid zkidentity.ShortID, now time.Time) (matoms.Amount, error) {
+	entries, err := s.db.FetchJournalEntries(ctx, now.Add(-lossWindow))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch journal entries: %v", err)
+	}
+	accounts := s.stakeAccounts()
+	account := accounts.PlayerAccount(uid)
+	suffix := "/" + uid.String()
+	net := matoms.Amount(0)
+	for _, entry := range entries {
+		if entry.Kind != serverdb.EntrySettle {
+			continue
+		}
+		for _, p := range entry.Postings {
+			if p.Account == account || strings.HasPrefix(p.Account, accounts.Escrow) && strings.HasSuffix(p.Account, suffix) {
+				net += p.Amount
+			}
+		}
+	}
+	return max(-net, 0), nil
+}
+
+// checkLimits fails if the limits of a player don't allow them to play for
+// stake.
+func (s *Server) checkLimits(ctx context.Context, uid zkidentity.ShortID, stake matoms.Amount) error {
+	if err := s.checkBanned(ctx, uid); err != nil {
+		return err
+	}
+	now := time.Now()
+	limits, err := s.fetchLimits(ctx, uid, now)
+	if err != nil {
+		return err
+	}
+	if now.Before(limits.ExcludedUntil) {
+		return fmt.Errorf("%w: you are self-excluded from playing until %s", errLimitExceeded,
+			limits.ExcludedUntil.UTC().Format(time.RFC1123))
+	}
+	if limits.MaxStake > 0 && stake > limits.MaxStake {
+		return fmt.Errorf("%w: a stake of %s is above your limit of %s per game",
+			errLimitExceeded, stake, limits.MaxStake)
+	}
+	if limits.MaxDailyLoss > 0 && stake > 0 {
+		lost, err := s.dailyLoss(ctx, uid, now)
+		if err != nil {
+			return err
+		}
+		if lost+stake > limits.MaxDailyLoss {
+			return fmt.Errorf("%w: a stake of %s could take your losses over your daily limit of %s "+
+				"(lost %s in the last 24 hours)", errLimitExceeded, stake, limits.MaxDailyLoss, lost)
+		}
+	}
+	return nil
+}
+
+// checkOpponentLimits is checkLimits for the opponent of the player asking to
+// play, without telling them which limit the opponent reached.
+func (s *Server) checkOpponentLimits(ctx context.Context, uid zkidentity.ShortID, stake matoms.Amount) error {
+	err := s.checkLimits(ctx, uid, stake)
+	if errors.Is(err, errLimitExceeded) || errors.Is(err, errBanned) {
+		return fmt.Errorf("opponent can't play for %s right now", stake)
+	}
+	return err
+}
+
+// marshalLimits converts limits to their proto form, along with the loss of
+// the player in the last 24 hours.
+func marshalLimits(limits *serverdb.PlayerLimits, lost matoms.Amount) *pong.Limits {
+	pl := &pong.Limits{
+		MaxStake:            int64(limits.MaxStake),
+		MaxDailyLoss:        int64(limits.MaxDailyLoss),
+		SessionReminderSecs: int64(limits.SessionReminder / time.Second),
+		DailyLoss:           int64(lost),
+	}
+	if !limits.ExcludedUntil.IsZero() {
+		pl.ExcludedUntil = limits.ExcludedUntil.Unix()
+	}
+	if p := limits.Pending; p != nil {
+		pl.PendingMaxStake = int64(p.MaxStake)
+		pl.PendingMaxDailyLoss = int64(p.MaxDailyLoss)
+		pl.PendingAt = p.EffectiveAt.Unix()
+	}
+	return pl
+}
+
+func (s *Server) limitsResponse(ctx context.Context, uid zkidentity.ShortID, limits *serverdb.PlayerLimits) (*pong.Limits, error) {
+	lost, err := s.dailyLoss(ctx, uid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return marshalLimits(limits, lost), nil
+}
+
+// GetLimits returns the responsible gaming limits of a player.
+func (s *Server) GetLimits(ctx context.Context, req *pong.GetLimitsRequest) (*pong.GetLimitsResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	limits, err := s.fetchLimits(ctx, clientID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	pl, err := s.limitsResponse(ctx, clientID, limits)
+	if err != nil {
+		return nil, err
+	}
+	return &pong.GetLimitsResponse{Limits: pl}, nil
+}
+
+// SetLimits replaces the responsible gaming limits of a player.
+func (s *Server) SetLimits(ctx context.Context, req *pong.SetLimitsRequest) (*pong.SetLimitsResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	limits, err := s.setLimits(ctx, clientID, matoms.Amount(req.MaxStake), matoms.Amount(req.MaxDailyLoss),
+		time.Duration(req.SessionReminderSecs)*time.Second, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	pl, err := s.limitsResponse(ctx, clientID, limits)
+	if err != nil {
+		return nil, err
+	}
+	return &pong.SetLimitsResponse{Limits: pl}, nil
+}
+
+// SelfExclude keeps a player from playing for the requested time.
+func (s *Server) SelfExclude(ctx context.Context, req *pong.SelfExcludeRequest) (*pong.SelfExcludeResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	limits, err := s.selfExclude(ctx, clientID, time.Duration(req.DurationSecs)*time.Second, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	pl, err := s.limitsResponse(ctx, clientID, limits)
+	if err != nil {
+		return nil, err
+	}
+	return &pong.SelfExcludeResponse{Limits: pl}, nil
+}
+
+// remindSession reminds a connected player of how long they have been
+// connected, as often as their session reminder limit asks for.
+func (s *Server) remindSession(ctx context.Context, player *ponggame.Player) {
+	ticker := time.NewTicker(sessionCheckInterval)
+	defer ticker.Stop()
+	start := time.Now()
+	last := start
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			limits, err := s.fetchLimits(ctx, *player.ID, now)
+			if err != nil {
+				s.log.Warnf("Failed to fetch limits of %s: %v", player.ID, err)
+				continue
+			}
+			if limits.SessionReminder == 0 || now.Sub(last) < limits.SessionReminder {
+				continue
+			}
+			last = now
+			if player.NotifierStream == nil {
+				continue
+			}
+			player.NotifierStream.Send(&pong.NtfnStreamResponse{
+				NotificationType: pong.NotificationType_SESSION_REMINDER,
+				Message: fmt.Sprintf("You have been playing for %s. Consider taking a break.",
+					now.Sub(start).Round(time.Minute)),
+				PlayerId: player.ID.String(),
+			})
+		}
+	}
+}
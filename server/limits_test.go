@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func TestLimitsCoolOff(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+	now := time.Now()
+
+	// Stricter limits apply at once.
+	limits, err := srv.setLimits(ctx, p1ID, 10000000000, 0, 0, now)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(10000000000), limits.MaxStake)
+	require.Nil(t, limits.Pending)
+	_, err = srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.ErrorIs(t, err, errLimitExceeded)
+	require.ErrorContains(t, err, "above your limit")
+
+	// Looser ones only after the cool-off.
+	limits, err = srv.setLimits(ctx, p1ID, 0, 0, 0, now)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(10000000000), limits.MaxStake)
+	require.NotNil(t, limits.Pending)
+	require.Zero(t, limits.Pending.MaxStake)
+	require.ErrorIs(t, srv.checkLimits(ctx, p1ID, 50000000000), errLimitExceeded)
+
+	limits, err = srv.fetchLimits(ctx, p1ID, now.Add(limitsCoolOff))
+	require.NoError(t, err)
+	require.Zero(t, limits.MaxStake)
+	require.Nil(t, limits.Pending)
+	require.NoError(t, srv.checkLimits(ctx, p1ID, 50000000000))
+
+	_, err = srv.setLimits(ctx, p1ID, 0, 0, time.Minute, now)
+	require.ErrorContains(t, err, "more frequent")
+}
+
+func TestSelfExclusion(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	limits, err := srv.selfExclude(ctx, p2ID, 48*time.Hour, now)
+	require.NoError(t, err)
+	until := limits.ExcludedUntil
+
+	// Exclusions can't be shortened.
+	limits, err = srv.selfExclude(ctx, p2ID, 24*time.Hour, now)
+	require.NoError(t, err)
+	require.True(t, until.Equal(limits.ExcludedUntil))
+	_, err = srv.selfExclude(ctx, p2ID, time.Hour, now)
+	require.EqualError(t, err, "self-exclusion must last between 1 and 1825 days")
+
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.ErrorIs(t, err, errLimitExceeded)
+	require.ErrorContains(t, err, "self-excluded")
+
+	// Challengers aren't told why their opponent can't play.
+	challenge, err := srv.ChallengePlayer(ctx, &pong.ChallengePlayerRequest{
+		ClientId: p2ID.String(),
+		Target:   p1ID.String(),
+		BetAmt:   50000000000,
+	})
+	require.ErrorIs(t, err, errLimitExceeded)
+	require.Nil(t, challenge)
+	err = srv.checkOpponentLimits(ctx, p2ID, 50000000000)
+	require.ErrorContains(t, err, "opponent can't play")
+	require.NotContains(t, err.Error(), "self-excluded")
+}
+
+func TestDailyLossLimit(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 20000000000,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	srv.handleGameEnd(ctx, wr.ID, &ponggame.GameInstance{Id: "game1", Winner: &p1ID}, series, players)
+
+	now := time.Now()
+	lost, err := srv.dailyLoss(ctx, p2ID, now)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(20000000000), lost)
+	lost, err = srv.dailyLoss(ctx, p1ID, now)
+	require.NoError(t, err)
+	require.Zero(t, lost)
+
+	_, err = srv.setLimits(ctx, p2ID, 0, 30000000000, 0, now)
+	require.NoError(t, err)
+	require.NoError(t, srv.checkLimits(ctx, p2ID, 10000000000))
+	err = srv.checkLimits(ctx, p2ID, 20000000000)
+	require.ErrorIs(t, err, errLimitExceeded)
+	require.ErrorContains(t, err, "daily limit")
+}
+
+func TestLimitsCommands(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+	bot := srv.bot.(*minimalTestBot)
+	pm := func(msg string) string {
+		t.Helper()
+		n := len(bot.sentPMs)
+		err := srv.HandlePM(ctx, &types.ReceivedPM{
+			Uid: p1ID[:],
+			Msg: &types.RMPrivateMessage{Message: msg},
+		})
+		require.NoError(t, err)
+		require.Len(t, bot.sentPMs, n+1)
+		require.Equal(t, p1ID.String(), bot.sentPMs[n].nick)
+		return bot.sentPMs[n].msg
+	}
+
+	require.Contains(t, pm("!limits"), "Max stake per game: none")
+	require.Contains(t, pm("!limits stake 0.1"), "Max stake per game: 0.10000000 DCR")
+	require.Contains(t, pm("!limits loss 2"), "Max loss per 24 hours: 2.00000000 DCR")
+	reply := pm("!limits stake off")
+	require.Contains(t, reply, "Max stake per game: 0.10000000 DCR")
+	require.Contains(t, reply, "max stake none, max loss 2.00000000 DCR")
+	require.Contains(t, pm("!limits reminder 30"), "Session reminder: every 30m0s")
+	require.Contains(t, pm("!limits stake lots"), "Error:")
+	require.Contains(t, pm("!help"), "!exclude <days>")
+
+	require.Contains(t, pm("!exclude 7"), "self-excluded from playing until")
+	require.ErrorIs(t, srv.checkLimits(ctx, p1ID, 0), errLimitExceeded)
+
+	// Messages that aren't commands are left alone.
+	n := len(bot.sentPMs)
+	err := srv.HandlePM(ctx, &types.ReceivedPM{
+		Uid: p1ID[:],
+		Msg: &types.RMPrivateMessage{Message: "hello"},
+	})
+	require.NoError(t, err)
+	require.Len(t, bot.sentPMs, n)
+}
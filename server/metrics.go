@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+const metricsNamespace = "pong"
+
+// serverMetrics holds the metrics the server exposes on /metrics.
+type serverMetrics struct {
+	registry *prometheus.Registry
+
+	frameSend     prometheus.Histogram
+	sendInput     prometheus.Histogram
+	framesDropped prometheus.Counter
+	tipsReceived  prometheus.Counter
+	tipsAmount    prometheus.Counter
+	payoutsSent   prometheus.Counter
+	payoutsPaid   prometheus.Counter
+	payoutsFailed prometheus.Counter
+}
+
+func newServerMetrics(s *Server) *serverMetrics {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		frameSend: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "frame_send_duration_seconds",
+			Help:      "Duration of sending a game frame to a player.",
+			Buckets:   prometheus.ExponentialBuckets(0.00005, 4, 8),
+		}),
+		sendInput: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "send_input_duration_seconds",
+			Help:      "Duration of processing a SendInput call.",
+			Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 8),
+		}),
+		framesDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "frames_dropped_total",
+			Help:      "Game frames dropped because a player's frame buffer was full.",
+		}),
+		tipsReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tips_received_total",
+			Help:      "Tips received from players.",
+		}),
+		tipsAmount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tips_received_matoms_total",
+			Help:      "Amount of the tips received from players, in milli-atoms.",
+		}),
+		payoutsSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "payouts_sent_total",
+			Help:      "Payout attempts handed to the bot.",
+		}),
+		payoutsPaid: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "payouts_completed_total",
+			Help:      "Payouts completed by the bot.",
+		}),
+		payoutsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "payouts_failed_total",
+			Help:      "Payouts that failed after running out of attempts.",
+		}),
+	}
+
+	gm := s.gameManager
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		serverdb.OpDuration,
+		m.frameSend, m.sendInput, m.framesDropped,
+		m.tipsReceived, m.tipsAmount,
+		m.payoutsSent, m.payoutsPaid, m.payoutsFailed,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "sessions",
+			Help:      "Connected player sessions.",
+		}, func() float64 {
+			gm.PlayerSessions.RLock()
+			defer gm.PlayerSessions.RUnlock()
+			return float64(len(gm.PlayerSessions.Sessions))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "waiting_rooms",
+			Help:      "Open waiting rooms.",
+		}, func() float64 {
+			gm.RLock()
+			defer gm.RUnlock()
+			return float64(len(gm.WaitingRooms))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "games",
+			Help:      "Games being played.",
+		}, func() float64 {
+			gm.RLock()
+			defer gm.RUnlock()
+			return float64(len(gm.Games))
+		}),
+		&payoutCollector{db: s.db},
+	)
+	return m
+}
+
+// observeSince observes the seconds elapsed since start in h.
+func observeSince(h prometheus.Histogram, start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+var (
+	payoutsPendingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "payouts_pending"),
+		"Payouts not yet paid, by status.",
+		[]string{"status"}, nil)
+	payoutsOldestDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "payouts_oldest_pending_seconds"),
+		"Age of the oldest payout not yet paid, by status.",
+		[]string{"status"}, nil)
+)
+
+// payoutCollector reports the payouts waiting to be paid, so stalled
+// payouts can be alerted on.
+type payoutCollector struct {
+	db serverdb.ServerDB
+}
+
+func (c *payoutCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- payoutsPendingDesc
+	ch <- payoutsOldestDesc
+}
+
+func (c *payoutCollector) Collect(ch chan<- prometheus.Metric) {
+	statuses := []serverdb.TipStatus{serverdb.StatusQueued, serverdb.StatusSending, serverdb.StatusFailed}
+	records, err := c.db.FetchTipProgressByStatus(context.Background(), statuses...)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(payoutsPendingDesc, err)
+		return
+	}
+
+	now := time.Now()
+	counts := make(map[serverdb.TipStatus]int)
+	oldest := make(map[serverdb.TipStatus]time.Time)
+	for _, record := range records {
+		counts[record.Status]++
+		if t, ok := oldest[record.Status]; !ok || record.CreatedAt.Before(t) {
+			oldest[record.Status] = record.CreatedAt
+		}
+	}
+	for _, status := range statuses {
+		var age float64
+		if t, ok := oldest[status]; ok {
+			age = now.Sub(t).Seconds()
+		}
+		ch <- prometheus.MustNewConstMetric(payoutsPendingDesc,
+			prometheus.GaugeValue, float64(counts[status]), string(status))
+		ch <- prometheus.MustNewConstMetric(payoutsOldestDesc,
+			prometheus.GaugeValue, age, string(status))
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/require"
+)
+
+func scrapeMetrics(t *testing.T, srv *Server) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	promhttp.HandlerFor(srv.metrics.registry, promhttp.HandlerOpts{}).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+
+	var uid zkidentity.ShortID
+	_ = uid.FromString(strings.Repeat("6", 64))
+	createTestPlayer(srv, uid)
+	tip := &types.ReceivedTip{Uid: uid[:], AmountMatoms: 50000000000, SequenceId: 600}
+	require.NoError(t, srv.HandleReceiveTip(ctx, tip))
+	// Redelivered tips are not counted again.
+	require.NoError(t, srv.HandleReceiveTip(ctx, tip))
+
+	body := scrapeMetrics(t, srv)
+	require.Contains(t, body, "pong_sessions 1\n")
+	require.Contains(t, body, "pong_waiting_rooms 0\n")
+	require.Contains(t, body, "pong_games 0\n")
+	require.Contains(t, body, "pong_tips_received_total 1\n")
+	require.Contains(t, body, "pong_tips_received_matoms_total 5e+10\n")
+	require.Contains(t, body, "pong_db_op_duration_seconds_count{op=\"StoreUnprocessedTip\"}")
+
+	// Refunded tips wait in the payout queue until they are sent.
+	require.NoError(t, srv.handleReturnUnprocessedTips(ctx, uid))
+	body = scrapeMetrics(t, srv)
+	require.Contains(t, body, "pong_payouts_pending{status=\"queued\"} 1\n")
+
+	srv.processPayouts(ctx, time.Now())
+	body = scrapeMetrics(t, srv)
+	require.Contains(t, body, "pong_payouts_sent_total 1\n")
+	require.Contains(t, body, "pong_payouts_pending{status=\"queued\"} 0\n")
+	require.Contains(t, body, "pong_payouts_pending{status=\"sending\"} 1\n")
+}
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// KickPlayer removes a player from a waiting room, releasing their reserved
+// tips. Banned players can't join the room again.
+func (s *Server) KickPlayer(ctx context.Context, req *pong.KickPlayerRequest) (*pong.KickPlayerResponse, error) {
+	wr, hostID, err := s.hostedRoom(req.ClientId, req.RoomId)
+	if err != nil {
+		return nil, err
+	}
+	var playerID zkidentity.ShortID
+	if err := playerID.FromString(req.PlayerId); err != nil {
+		return nil, err
+	}
+	if playerID == hostID {
+		return nil, fmt.Errorf("the host cannot kick themselves")
+	}
+	player := wr.GetPlayer(&playerID)
+	if player == nil {
+		return nil, fmt.Errorf("player %s is not in waiting room %s", req.PlayerId, req.RoomId)
+	}
+
+	s.log.Infof("Host %s kicked player %s from waiting room %s", hostID, playerID, wr.ID)
+	wr.RemovePlayer(playerID)
+	s.releaseStake(ctx, wr.ID, playerID)
+	if req.Ban {
+		wr.Ban(playerID)
+	}
+	player.WR = nil
+	if player.Ready {
+		s.unreadyPlayer(player)
+	}
+
+	// The room is no longer full, so a running ready check is void.
+	wr.Lock()
+	wr.ReadyDeadline = time.Time{}
+	wr.Unlock()
+
+	pwr, err := wr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if player.NotifierStream != nil {
+		player.NotifierStream.Send(&pong.NtfnStreamResponse{
+			NotificationType: pong.NotificationType_PLAYER_KICKED,
+			Message:          "You were removed from the waiting room by the host",
+			PlayerId:         player.ID.String(),
+			RoomId:           wr.ID,
+		})
+	}
+	s.notifyRoom(wr, wr.GetPlayers(), pong.NotificationType_PLAYER_KICKED,
+		fmt.Sprintf("Player %s was removed by the host", player.Nick))
+
+	return &pong.KickPlayerResponse{
+		Wr: pwr,
+	}, nil
+}
+
+// LockRoom keeps new players from joining a waiting room.
+func (s *Server) LockRoom(ctx context.Context, req *pong.LockRoomRequest) (*pong.LockRoomResponse, error) {
+	return s.setRoomLocked(req, true)
+}
+
+// UnlockRoom opens a locked waiting room to new players again.
+func (s *Server) UnlockRoom(ctx context.Context, req *pong.LockRoomRequest) (*pong.LockRoomResponse, error) {
+	return s.setRoomLocked(req, false)
+}
+
+func (s *Server) setRoomLocked(req *pong.LockRoomRequest, locked bool) (*pong.LockRoomResponse, error) {
+	wr, _, err := s.hostedRoom(req.ClientId, req.RoomId)
+	if err != nil {
+		return nil, err
+	}
+	if wr.IsLocked() == locked {
+		if locked {
+			return nil, fmt.Errorf("waiting room %s is already locked", req.RoomId)
+		}
+		return nil, fmt.Errorf("waiting room %s is not locked", req.RoomId)
+	}
+	wr.SetLocked(locked)
+	wr.Touch()
+
+	typ, msg := pong.NotificationType_WR_LOCKED, "The host locked the waiting room"
+	if !locked {
+		typ, msg = pong.NotificationType_WR_UNLOCKED, "The host unlocked the waiting room"
+	}
+	s.notifyRoom(wr, wr.GetPlayers(), typ, msg)
+
+	pwr, err := wr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &pong.LockRoomResponse{
+		Wr: pwr,
+	}, nil
+}
+
+// TransferHost hands the host role of a waiting room to another player in it.
+func (s *Server) TransferHost(ctx context.Context, req *pong.TransferHostRequest) (*pong.TransferHostResponse, error) {
+	wr, hostID, err := s.hostedRoom(req.ClientId, req.RoomId)
+	if err != nil {
+		return nil, err
+	}
+	var newHostID zkidentity.ShortID
+	if err := newHostID.FromString(req.NewHostId); err != nil {
+		return nil, err
+	}
+	if newHostID == hostID {
+		return nil, fmt.Errorf("player %s is already the host", req.NewHostId)
+	}
+	if err := wr.TransferHost(newHostID); err != nil {
+		return nil, err
+	}
+
+	newHost := wr.GetPlayer(&newHostID)
+	s.log.Infof("Host of waiting room %s transferred from %s to %s", wr.ID, hostID, newHostID)
+	s.notifyRoom(wr, wr.GetPlayers(), pong.NotificationType_HOST_TRANSFERRED,
+		fmt.Sprintf("%s is now the host", newHost.Nick))
+
+	pwr, err := wr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &pong.TransferHostResponse{
+		Wr: pwr,
+	}, nil
+}
+
+// UpdateRoomSettings changes the rules, visibility or bet of a waiting room.
+// The bet can only be changed while the host is alone in the room, since the
+// tips reserved by other players were made for the previous amount.
+func (s *Server) UpdateRoomSettings(ctx context.Context, req *pong.UpdateRoomSettingsRequest) (*pong.UpdateRoomSettingsResponse, error) {
+	wr, hostID, err := s.hostedRoom(req.ClientId, req.RoomId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Rules == nil && req.Private == nil && req.BetAmt == 0 {
+		return nil, fmt.Errorf("no settings to update")
+	}
+
+	players := wr.GetPlayers()
+	for _, p := range players {
+		if p.Ready {
+			return nil, fmt.Errorf("cannot change settings while players are ready")
+		}
+	}
+
+	var rules ponggame.GameRules
+	if req.Rules != nil {
+		rules = ponggame.GameRulesFromProto(req.Rules)
+		if err := rules.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	wr.RLock()
+	betAmt := wr.BetAmount
+	wr.RUnlock()
+	newBetAmt := matoms.Amount(req.BetAmt)
+	if newBetAmt != 0 && newBetAmt != betAmt {
+		if len(players) > 1 {
+			return nil, fmt.Errorf("the bet can only be changed while the host is alone in the room")
+		}
+		if !s.isF2P && newBetAmt < s.minBetAmt {
+			return nil, fmt.Errorf("bet needs to be higher than %s", s.minBetAmt)
+		}
+		if err := s.checkLimits(ctx, hostID, newBetAmt); err != nil {
+			return nil, err
+		}
+		available, _, err := s.fetchPlayerBalance(ctx, hostID)
+		if err != nil {
+			return nil, err
+		}
+		if available+betAmt < newBetAmt {
+			return nil, fmt.Errorf("insufficient balance. Available: %s, Requested: %s",
+				available+betAmt, newBetAmt)
+		}
+		tips, err := s.db.FetchReceivedTipsByUID(ctx, hostID, serverdb.StatusUnpaid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch unprocessed tips: %v", err)
+		}
+		// Players only take a seat under the room lock after
+		// checking its bet, so nobody joins at the old bet meanwhile.
+		wr.Lock()
+		if len(wr.Players) > 1 || wr.BetAmount != betAmt {
+			wr.Unlock()
+			return nil, fmt.Errorf("the bet can only be changed while the host is alone in the room")
+		}
+		if err := s.changeStake(ctx, wr.ID, hostID, betAmt, newBetAmt); err != nil {
+			wr.Unlock()
+			return nil, err
+		}
+		wr.BetAmount = newBetAmt
+		wr.ReservedTips = tips
+		wr.Unlock()
+		s.setRoomRake(wr)
+	}
+
+	if req.Rules != nil {
+		wr.Lock()
+		wr.Rules = rules
+		wr.Unlock()
+	}
+
+	var inviteCode string
+	if req.Private != nil {
+		wr.RLock()
+		private := wr.Private
+		inviteCode = wr.InviteCode
+		wr.RUnlock()
+		switch {
+		case *req.Private && !private:
+			inviteCode, err = wr.MakePrivate()
+			if err != nil {
+				return nil, err
+			}
+		case !*req.Private && private:
+			wr.MakePublic()
+			inviteCode = ""
+		}
+	}
+	wr.Touch()
+
+	s.notifyRoom(wr, wr.GetPlayers(), pong.NotificationType_WR_SETTINGS_UPDATED,
+		"The host updated the waiting room settings")
+
+	pwr, err := wr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &pong.UpdateRoomSettingsResponse{
+		Wr:         pwr,
+		InviteCode: inviteCode,
+	}, nil
+}
+
+// hostedRoom returns the waiting room if the client is its host. Tournament
+// rooms are run by the server and can't be moderated.
+func (s *Server) hostedRoom(clientID, roomID string) (*ponggame.WaitingRoom, zkidentity.ShortID, error) {
+	var uid zkidentity.ShortID
+	if err := uid.FromString(clientID); err != nil {
+		return nil, uid, err
+	}
+	wr := s.gameManager.GetWaitingRoom(roomID)
+	if wr == nil {
+		return nil, uid, fmt.Errorf("waiting room not found: %s", roomID)
+	}
+	if s.isTournamentRoom(wr.ID) {
+		return nil, uid, fmt.Errorf("tournament rooms cannot be moderated")
+	}
+	if !wr.IsHost(uid) {
+		return nil, uid, fmt.Errorf("only the host can moderate waiting room %s", roomID)
+	}
+	return wr, uid, nil
+}
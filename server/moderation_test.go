@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestKickPlayer(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	tips := []*types.ReceivedTip{
+		{Uid: p1ID[:], AmountMatoms: 50000000000},
+		{Uid: p2ID[:], AmountMatoms: 50000000000},
+	}
+	wr, err := srv.createPairedRoom(p1, p2, 50000000000, ponggame.DefaultGameRules(), tips)
+	require.NoError(t, err)
+
+	// Only the host may kick.
+	_, err = srv.KickPlayer(ctx, &pong.KickPlayerRequest{
+		ClientId: p2ID.String(),
+		RoomId:   wr.ID,
+		PlayerId: p1ID.String(),
+	})
+	require.Error(t, err)
+	_, err = srv.KickPlayer(ctx, &pong.KickPlayerRequest{
+		ClientId: p1ID.String(),
+		RoomId:   wr.ID,
+		PlayerId: p1ID.String(),
+	})
+	require.Error(t, err)
+
+	resp, err := srv.KickPlayer(ctx, &pong.KickPlayerRequest{
+		ClientId: p1ID.String(),
+		RoomId:   wr.ID,
+		PlayerId: p2ID.String(),
+		Ban:      true,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Wr.Players, 1)
+	require.Nil(t, p2.WR)
+	require.Len(t, wr.ReservedTips, 1)
+	require.Equal(t, p1ID[:], wr.ReservedTips[0].Uid)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PLAYER_KICKED, msgs[len(msgs)-1].NotificationType)
+	msgs = p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PLAYER_KICKED, msgs[len(msgs)-1].NotificationType)
+
+	// Banned players can't come back, even with the invite code.
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		ClientId:   p2ID.String(),
+		RoomId:     wr.ID,
+		InviteCode: wr.InviteCode,
+	})
+	require.ErrorContains(t, err, "removed")
+}
+
+func TestLockRoom(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+
+	wr, err := ponggame.NewWaitingRoom(p1, 50000000000)
+	require.NoError(t, err)
+	p1.WR = wr
+	srv.gameManager.WaitingRooms = append(srv.gameManager.WaitingRooms, wr)
+
+	_, err = srv.LockRoom(ctx, &pong.LockRoomRequest{ClientId: p2ID.String(), RoomId: wr.ID})
+	require.Error(t, err)
+
+	resp, err := srv.LockRoom(ctx, &pong.LockRoomRequest{ClientId: p1ID.String(), RoomId: wr.ID})
+	require.NoError(t, err)
+	require.True(t, resp.Wr.Locked)
+	msgs := p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_WR_LOCKED, msgs[len(msgs)-1].NotificationType)
+
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{ClientId: p2ID.String(), RoomId: wr.ID})
+	require.ErrorContains(t, err, "locked")
+
+	_, err = srv.UnlockRoom(ctx, &pong.LockRoomRequest{ClientId: p1ID.String(), RoomId: wr.ID})
+	require.NoError(t, err)
+	_, err = srv.UnlockRoom(ctx, &pong.LockRoomRequest{ClientId: p1ID.String(), RoomId: wr.ID})
+	require.Error(t, err)
+
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{ClientId: p2ID.String(), RoomId: wr.ID})
+	require.NoError(t, err)
+	require.Len(t, wr.GetPlayers(), 2)
+}
+
+func TestTransferHost(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	wr, err := srv.createPairedRoom(p1, p2, 0, ponggame.DefaultGameRules(), nil)
+	require.NoError(t, err)
+
+	_, err = srv.TransferHost(ctx, &pong.TransferHostRequest{
+		ClientId:  p2ID.String(),
+		RoomId:    wr.ID,
+		NewHostId: p2ID.String(),
+	})
+	require.Error(t, err)
+
+	resp, err := srv.TransferHost(ctx, &pong.TransferHostRequest{
+		ClientId:  p1ID.String(),
+		RoomId:    wr.ID,
+		NewHostId: p2ID.String(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, p2ID.String(), resp.Wr.HostId)
+	msgs := p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_HOST_TRANSFERRED, msgs[len(msgs)-1].NotificationType)
+
+	// When the host leaves, the remaining player takes over.
+	leave, err := srv.LeaveWaitingRoom(ctx, &pong.LeaveWaitingRoomRequest{
+		ClientId: p2ID.String(),
+		RoomId:   wr.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, leave.Success)
+	require.True(t, wr.IsHost(p1ID))
+	msgs = p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_HOST_TRANSFERRED, msgs[len(msgs)-1].NotificationType)
+}
+
+func TestUpdateRoomSettings(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	wr, err := srv.createPairedRoom(p1, p2, 50000000000, ponggame.DefaultGameRules(), nil)
+	require.NoError(t, err)
+
+	_, err = srv.UpdateRoomSettings(ctx, &pong.UpdateRoomSettingsRequest{
+		ClientId: p2ID.String(),
+		RoomId:   wr.ID,
+		Rules:    &pong.GameRules{MaxScore: 5},
+	})
+	require.Error(t, err)
+
+	// The bet is fixed once someone joined.
+	_, err = srv.UpdateRoomSettings(ctx, &pong.UpdateRoomSettingsRequest{
+		ClientId: p1ID.String(),
+		RoomId:   wr.ID,
+		BetAmt:   20000000000,
+	})
+	require.ErrorContains(t, err, "alone")
+
+	resp, err := srv.UpdateRoomSettings(ctx, &pong.UpdateRoomSettingsRequest{
+		ClientId: p1ID.String(),
+		RoomId:   wr.ID,
+		Rules:    &pong.GameRules{MaxScore: 5},
+		Private:  proto.Bool(false),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(5), resp.Wr.Rules.MaxScore)
+	require.False(t, resp.Wr.Private)
+	require.Empty(t, resp.InviteCode)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_WR_SETTINGS_UPDATED, msgs[len(msgs)-1].NotificationType)
+
+	// Settings can't change under ready players.
+	p2.Ready = true
+	_, err = srv.UpdateRoomSettings(ctx, &pong.UpdateRoomSettingsRequest{
+		ClientId: p1ID.String(),
+		RoomId:   wr.ID,
+		Private:  proto.Bool(true),
+	})
+	require.Error(t, err)
+}
+
+func TestUpdateRoomBet(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 20000000000,
+	})
+	require.NoError(t, err)
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	requireStake := func(available, reserved matoms.Amount) {
+		t.Helper()
+		a, r, err := srv.fetchPlayerBalance(ctx, p1ID)
+		require.NoError(t, err)
+		require.Equal(t, available, a)
+		require.Equal(t, reserved, r)
+	}
+	requireStake(30000000000, 20000000000)
+
+	// A bet higher than the balance keeps the previous stake in escrow.
+	_, err = srv.UpdateRoomSettings(ctx, &pong.UpdateRoomSettingsRequest{
+		ClientId: p1ID.String(),
+		RoomId:   wr.ID,
+		BetAmt:   60000000000,
+	})
+	require.ErrorContains(t, err, "insufficient balance")
+	require.Equal(t, matoms.Amount(20000000000), wr.BetAmount)
+	requireStake(30000000000, 20000000000)
+	err = srv.changeStake(ctx, wr.ID, p1ID, 20000000000, 60000000000)
+	require.ErrorIs(t, err, serverdb.ErrInsufficientBalance)
+	requireStake(30000000000, 20000000000)
+
+	_, err = srv.UpdateRoomSettings(ctx, &pong.UpdateRoomSettingsRequest{
+		ClientId: p1ID.String(),
+		RoomId:   wr.ID,
+		BetAmt:   50000000000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(50000000000), wr.BetAmount)
+	requireStake(0, 50000000000)
+
+	// A guest that reserved the old bet doesn't get a seat.
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+	require.ErrorContains(t, wr.TakeSeat(p2, roomSeats, 20000000000), "changed")
+	require.Len(t, wr.GetPlayers(), 1)
+	require.NoError(t, wr.TakeSeat(p2, roomSeats, 50000000000))
+}
@@ -0,0 +1,397 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+const (
+	// payoutInterval is how often the payout queue is checked for payouts
+	// due to be retried.
+	payoutInterval = 10 * time.Second
+
+	// payoutMaxAttempts is how many times a payout is sent before it is
+	// failed and left for an admin to look at.
+	payoutMaxAttempts = 5
+
+	// payoutBaseBackoff is the wait before the first retry of a payout.
+	// It doubles with every attempt up to payoutMaxBackoff.
+	payoutBaseBackoff = 30 * time.Second
+	payoutMaxBackoff  = 30 * time.Minute
+
+	// payoutSendTimeout is how long the bot has to report the outcome of a
+	// payout before it is failed and left for an admin to look at.
+	payoutSendTimeout = time.Hour
+)
+
+// payoutRecipient returns the user a payout is sent to.
+func payoutRecipient(record *serverdb.TipProgressRecord) (zkidentity.ShortID, error) {
+	var uid zkidentity.ShortID
+	if err := uid.FromBytes(record.WinnerUID); err != nil {
+		return uid, fmt.Errorf("invalid recipient of payout %d: %v", record.ID, err)
+	}
+	return uid, nil
+}
+
+// payoutBackoff is the wait before a payout is sent again after its given
+// number of attempts.
+func payoutBackoff(attempts int) time.Duration {
+	backoff := payoutBaseBackoff
+	for i := 1; i < attempts && backoff < payoutMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, payoutMaxBackoff)
+}
+
+// queuePayout queues a payout of amount to uid. The tips are marked
+// paid once the payout completes.
+func (s *Server) queuePayout(ctx context.Context, uid zkidentity.ShortID, amount matoms.Amount, tips []*types.ReceivedTip) error {
+	err := s.db.StoreSendTipProgress(ctx, uid[:], amount, tips, serverdb.StatusQueued)
+	if err != nil {
+		return fmt.Errorf("failed to queue payout to %s: %v", uid, err)
+	}
+	s.wakePayouts()
+	return nil
+}
+
+func (s *Server) wakePayouts() {
+	select {
+	case s.payoutWake <- struct{}{}:
+	default:
+	}
+}
+
+// runPayoutLoop sends queued payouts as they are queued and retries failed
+// ones once their backoff expires. Payouts queued before a restart are
+// picked up on the first pass.
+func (s *Server) runPayoutLoop(ctx context.Context) {
+	ticker := time.NewTicker(payoutInterval)
+	defer ticker.Stop()
+	for {
+		s.processPayouts(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.payoutWake:
+		}
+	}
+}
+
+// processPayouts sends the queued payouts that are due. Tip progress events
+// only carry the recipient and the amount sent, so only one payout per
+// recipient may have its progress reported at a time and every event belongs
+// to that payout.
+func (s *Server) processPayouts(ctx context.Context, now time.Time) {
+	for _, record := range s.claimPayouts(ctx, now) {
+		uid, _ := payoutRecipient(record)
+		if err := s.bot.PayTip(ctx, uid, record.TotalAmount.Atoms(), 3); err != nil {
+			s.payoutsMtx.Lock()
+			s.retryClaimedPayout(ctx, record, now, err.Error())
+			s.payoutsMtx.Unlock()
+			continue
+		}
+		s.metrics.payoutsSent.Inc()
+		s.log.Infof("Sent payout %d of %s to %s (attempt %d)", record.ID,
+			record.TotalAmount, uid, record.Attempts)
+	}
+}
+
+// claimPayouts marks the queued payouts that are due as sending and returns
+// them, so they can be sent without holding payoutsMtx. Payouts whose outcome
+// the bot didn't report within payoutSendTimeout are failed, since sending
+// them again could pay them twice, and no other payout is sent to their
+// recipient until the outcome arrives or an admin resolves them.
+func (s *Server) claimPayouts(ctx context.Context, now time.Time) []*serverdb.TipProgressRecord {
+	s.payoutsMtx.Lock()
+	defer s.payoutsMtx.Unlock()
+
+	records, err := s.db.FetchTipProgressByStatus(ctx, serverdb.StatusSending,
+		serverdb.StatusQueued, serverdb.StatusFailed)
+	if err != nil {
+		s.log.Errorf("Failed to fetch pending payouts: %v", err)
+		return nil
+	}
+
+	inFlight := make(map[zkidentity.ShortID]bool)
+	for _, record := range records {
+		if record.Status == serverdb.StatusSending && now.Sub(record.SentAt) > payoutSendTimeout {
+			record.TimedOut = true
+			s.failPayout(ctx, record, fmt.Sprintf("no outcome reported within %s of sending", payoutSendTimeout))
+		}
+		if record.Status != serverdb.StatusSending && !record.TimedOut {
+			continue
+		}
+		if uid, err := payoutRecipient(record); err == nil {
+			inFlight[uid] = true
+		}
+	}
+
+	var claimed []*serverdb.TipProgressRecord
+	for _, record := range records {
+		if record.Status != serverdb.StatusQueued || record.NextAttempt.After(now) {
+			continue
+		}
+		uid, err := payoutRecipient(record)
+		if err != nil {
+			s.log.Errorf("Skipping payout: %v", err)
+			continue
+		}
+		if inFlight[uid] {
+			continue
+		}
+
+		record.Attempts++
+		record.Status = serverdb.StatusSending
+		record.SentAt = now
+		if err := s.db.UpdateTipProgress(ctx, record); err != nil {
+			s.log.Errorf("Failed to update payout %d: %v", record.ID, err)
+			continue
+		}
+		inFlight[uid] = true
+		claimed = append(claimed, record)
+	}
+	return claimed
+}
+
+// retryClaimedPayout retries a claimed payout the bot failed to send, unless
+// an admin changed it in the meantime.
+func (s *Server) retryClaimedPayout(ctx context.Context, claimed *serverdb.TipProgressRecord, now time.Time, reason string) {
+	record, err := s.db.FetchTipProgress(ctx, claimed.ID)
+	if err != nil {
+		s.log.Errorf("Failed to fetch payout %d: %v", claimed.ID, err)
+		return
+	}
+	if record.Status != serverdb.StatusSending || !record.SentAt.Equal(claimed.SentAt) {
+		return
+	}
+	s.retryPayout(ctx, record, now, reason)
+}
+
+// retryPayout queues a payout that could not be sent to be retried after a
+// backoff, or fails it once it runs out of attempts.
+func (s *Server) retryPayout(ctx context.Context, record *serverdb.TipProgressRecord, now time.Time, reason string) {
+	record.TimedOut = false
+	if record.Attempts >= payoutMaxAttempts {
+		s.failPayout(ctx, record, reason)
+		return
+	}
+	record.LastError = reason
+	record.Status = serverdb.StatusQueued
+	record.NextAttempt = now.Add(payoutBackoff(record.Attempts))
+	if err := s.db.UpdateTipProgress(ctx, record); err != nil {
+		s.log.Errorf("Failed to update payout %d: %v", record.ID, err)
+		return
+	}
+
+	uid, _ := payoutRecipient(record)
+	s.log.Warnf("Payout %d of %s to %s failed on attempt %d, retrying at %s: %s",
+		record.ID, record.TotalAmount, uid, record.Attempts, record.NextAttempt.Format(time.RFC3339), reason)
+}
+
+// failPayout stops sending a payout and tells its recipient it was sent for
+// review.
+func (s *Server) failPayout(ctx context.Context, record *serverdb.TipProgressRecord, reason string) {
+	record.LastError = reason
+	record.Status = serverdb.StatusFailed
+	record.NextAttempt = time.Time{}
+	if err := s.db.UpdateTipProgress(ctx, record); err != nil {
+		s.log.Errorf("Failed to update payout %d: %v", record.ID, err)
+		return
+	}
+
+	uid, _ := payoutRecipient(record)
+	amount := record.TotalAmount
+	s.metrics.payoutsFailed.Inc()
+	s.log.Errorf("Payout %d of %s to %s failed after %d attempts: %s",
+		record.ID, amount, uid, record.Attempts, reason)
+	s.notifyBalance(ctx, uid, pong.NotificationType_PAYOUT_PROGRESS,
+		fmt.Sprintf("Payout of %s failed and was sent for review", amount))
+}
+
+// applyPayoutProgress applies a completion or final failure event of the bot
+// to the payout it belongs to. Events already applied are ignored.
+func (s *Server) applyPayoutProgress(ctx context.Context, tip *types.TipProgressEvent) error {
+	s.payoutsMtx.Lock()
+	defer s.payoutsMtx.Unlock()
+
+	var uid zkidentity.ShortID
+	if err := uid.FromBytes(tip.Uid); err != nil {
+		return fmt.Errorf("invalid payout recipient: %v", err)
+	}
+	records, err := s.db.FetchSendTipProgressByClient(ctx, uid[:])
+	if err != nil {
+		return fmt.Errorf("failed to fetch payouts of %s: %v", uid, err)
+	}
+
+	// The worker only lets one payout per recipient have its progress
+	// reported, the one in flight or the one that timed out, so the event
+	// belongs to it. The amount only tells apart payouts sent together by
+	// older versions.
+	var record *serverdb.TipProgressRecord
+	amount := matoms.Amount(tip.AmountMatoms)
+	for _, r := range records {
+		if tip.SequenceId != 0 && r.ProgressSeq == tip.SequenceId {
+			s.log.Debugf("Tip progress %d already applied to payout %d", tip.SequenceId, r.ID)
+			return nil
+		}
+		pending := r.Status == serverdb.StatusSending || (r.Status == serverdb.StatusFailed && r.TimedOut)
+		if record == nil && pending && r.TotalAmount.Atoms() == amount.Atoms() {
+			record = r
+		}
+	}
+	if record == nil {
+		s.log.Warnf("No payout in flight to %s of %s for tip progress %d",
+			uid, amount, tip.SequenceId)
+		return nil
+	}
+	defer s.wakePayouts()
+
+	record.ProgressSeq = tip.SequenceId
+	if !tip.Completed {
+		reason := tip.AttemptErr
+		if reason == "" {
+			reason = "payout was not completed"
+		}
+		s.retryPayout(ctx, record, time.Now(), reason)
+		return nil
+	}
+	if err := s.completePayout(ctx, record); err != nil {
+		return err
+	}
+	s.metrics.payoutsPaid.Inc()
+	s.log.Infof("Payout %d of %s to %s completed", record.ID, record.TotalAmount, uid)
+	return nil
+}
+
+// completePayout marks a payout and the tips it spent as paid.
+func (s *Server) completePayout(ctx context.Context, record *serverdb.TipProgressRecord) error {
+	for _, rt := range record.Tips {
+		tipID := make([]byte, 8)
+		binary.BigEndian.PutUint64(tipID, rt.SequenceId)
+
+		if err := s.db.UpdateTipStatus(ctx, rt.Uid, tipID, serverdb.StatusPaid); err != nil {
+			s.log.Warnf("Error updating tip %d status: %v", rt.SequenceId, err)
+			continue
+		}
+		if err := s.bot.AckTipReceived(ctx, rt.SequenceId); err != nil {
+			s.log.Warnf("Error acknowledging tip %d: %v", rt.SequenceId, err)
+		}
+	}
+
+	record.Status = serverdb.StatusPaid
+	record.LastError = ""
+	record.TimedOut = false
+	if err := s.db.UpdateTipProgress(ctx, record); err != nil {
+		return fmt.Errorf("failed to update payout %d: %v", record.ID, err)
+	}
+	return nil
+}
+
+// requeuePayout gives a failed payout a new set of attempts.
+func (s *Server) requeuePayout(ctx context.Context, id uint64) error {
+	s.payoutsMtx.Lock()
+	defer s.payoutsMtx.Unlock()
+
+	record, err := s.db.FetchTipProgress(ctx, id)
+	if err != nil {
+		return err
+	}
+	if record.Status != serverdb.StatusFailed {
+		return fmt.Errorf("payout %d is %s, not failed", id, record.Status)
+	}
+	record.Status = serverdb.StatusQueued
+	record.Attempts = 0
+	record.NextAttempt = time.Time{}
+	record.TimedOut = false
+	if err := s.db.UpdateTipProgress(ctx, record); err != nil {
+		return err
+	}
+	s.wakePayouts()
+	return nil
+}
+
+// markPayout sets the status of a payout that hasn't completed by hand. A
+// payout marked paid is taken as sent outside of the bot and marks its tips
+// paid, one marked failed stops being retried.
+func (s *Server) markPayout(ctx context.Context, id uint64, status serverdb.TipStatus) error {
+	s.payoutsMtx.Lock()
+	defer s.payoutsMtx.Unlock()
+
+	record, err := s.db.FetchTipProgress(ctx, id)
+	if err != nil {
+		return err
+	}
+	if record.Status == serverdb.StatusPaid {
+		return fmt.Errorf("payout %d is already paid", id)
+	}
+	switch status {
+	case serverdb.StatusPaid:
+		return s.completePayout(ctx, record)
+	case serverdb.StatusFailed:
+		record.Status = serverdb.StatusFailed
+		record.LastError = "marked failed by an admin"
+		record.NextAttempt = time.Time{}
+		record.TimedOut = false
+		return s.db.UpdateTipProgress(ctx, record)
+	default:
+		return fmt.Errorf("payouts can only be marked paid or failed")
+	}
+}
+
+// handlePayoutsHandler lists the payouts that haven't completed. The status
+// query parameter restricts it to queued, sending or failed payouts.
+func (s *Server) handlePayoutsHandler(w http.ResponseWriter, r *http.Request) {
+	statuses := []serverdb.TipStatus{serverdb.StatusQueued, serverdb.StatusSending, serverdb.StatusFailed}
+	if v := r.URL.Query().Get("status"); v != "" {
+		status := serverdb.TipStatus(v)
+		switch status {
+		case serverdb.StatusQueued, serverdb.StatusSending, serverdb.StatusFailed, serverdb.StatusPaid:
+		default:
+			http.Error(w, fmt.Sprintf("invalid status: %s", v), http.StatusBadRequest)
+			return
+		}
+		statuses = []serverdb.TipStatus{status}
+	}
+
+	records, err := s.db.FetchTipProgressByStatus(r.Context(), statuses...)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error fetching payouts: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		http.Error(w, fmt.Sprintf("error encoding response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// handleRetryPayoutHandler queues a failed payout to be sent again.
+func (s *Server) handleRetryPayoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid id: %v", err), http.StatusBadRequest)
+		return
+	}
+	err = s.requeuePayout(r.Context(), id)
+	s.logAdminAction(r.Context(), "retry_payout", strconv.FormatUint(id, 10), "", err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error retrying payout: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func TestPayoutBackoff(t *testing.T) {
+	require.Equal(t, payoutBaseBackoff, payoutBackoff(1))
+	require.Equal(t, 4*payoutBaseBackoff, payoutBackoff(3))
+	require.Equal(t, payoutMaxBackoff, payoutBackoff(20))
+}
+
+func TestPayoutWorker(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	bot := srv.bot.(*minimalTestBot)
+	now := time.Now()
+
+	fetch := func(id uint64) *serverdb.TipProgressRecord {
+		t.Helper()
+		record, err := srv.db.FetchTipProgress(ctx, id)
+		require.NoError(t, err)
+		return record
+	}
+
+	// Two equal payouts to the same player.
+	require.NoError(t, srv.queuePayout(ctx, p1ID, 10000000000, nil))
+	require.NoError(t, srv.queuePayout(ctx, p1ID, 10000000000, nil))
+	queued, err := srv.db.FetchTipProgressByStatus(ctx, serverdb.StatusQueued)
+	require.NoError(t, err)
+	require.Len(t, queued, 2)
+	first, second := queued[0].ID, queued[1].ID
+
+	// Failed sends are retried after a backoff, while the next payout to
+	// the player is sent.
+	bot.payTipErr = errors.New("bot offline")
+	srv.processPayouts(ctx, now)
+	require.Equal(t, serverdb.StatusQueued, fetch(first).Status)
+	require.Equal(t, 1, fetch(first).Attempts)
+	require.Equal(t, "bot offline", fetch(first).LastError)
+	require.Equal(t, serverdb.StatusQueued, fetch(second).Status)
+	bot.payTipErr = nil
+	srv.processPayouts(ctx, now)
+	require.Equal(t, serverdb.StatusQueued, fetch(first).Status)
+	require.Equal(t, serverdb.StatusSending, fetch(second).Status)
+
+	// Only one payout per player is in flight at a time.
+	now = now.Add(payoutBaseBackoff)
+	srv.processPayouts(ctx, now)
+	require.Equal(t, serverdb.StatusQueued, fetch(first).Status)
+
+	// The completion matches the payout in flight, and a redelivery of it
+	// doesn't complete the next one.
+	completed := &types.TipProgressEvent{
+		SequenceId:   10,
+		Uid:          p1ID[:],
+		AmountMatoms: 10000000000,
+		Completed:    true,
+	}
+	require.NoError(t, srv.HandleTipProgress(ctx, completed))
+	require.Equal(t, serverdb.StatusPaid, fetch(second).Status)
+	srv.processPayouts(ctx, now)
+	require.Equal(t, serverdb.StatusSending, fetch(first).Status)
+	require.NoError(t, srv.HandleTipProgress(ctx, completed))
+	require.Equal(t, serverdb.StatusSending, fetch(first).Status)
+	require.True(t, bot.ackedTipProgress[10])
+
+	// Payouts the bot gives up on are queued again.
+	err = srv.HandleTipProgress(ctx, &types.TipProgressEvent{
+		SequenceId:   11,
+		Uid:          p1ID[:],
+		AmountMatoms: 10000000000,
+		AttemptErr:   "no route",
+	})
+	require.NoError(t, err)
+	require.Equal(t, serverdb.StatusQueued, fetch(first).Status)
+	require.Equal(t, "no route", fetch(first).LastError)
+
+	// Payouts out of attempts are failed and the player is told.
+	bot.payTipErr = errors.New("bot offline")
+	for i := 0; i < payoutMaxAttempts; i++ {
+		now = now.Add(payoutMaxBackoff)
+		srv.processPayouts(ctx, now)
+	}
+	record := fetch(first)
+	require.Equal(t, serverdb.StatusFailed, record.Status)
+	require.Equal(t, payoutMaxAttempts, record.Attempts)
+	msgs := p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PAYOUT_PROGRESS, msgs[len(msgs)-1].NotificationType)
+	balance, err := srv.fetchBalance(ctx, p1ID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(10000000000), balance.pendingPayout)
+
+	// Failed payouts can be retried.
+	require.Error(t, srv.requeuePayout(ctx, second))
+	require.NoError(t, srv.requeuePayout(ctx, first))
+	bot.payTipErr = nil
+	srv.processPayouts(ctx, now)
+	require.Equal(t, serverdb.StatusSending, fetch(first).Status)
+}
+
+func TestPayoutTimeout(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+	now := time.Now()
+
+	fetch := func(id uint64) *serverdb.TipProgressRecord {
+		t.Helper()
+		record, err := srv.db.FetchTipProgress(ctx, id)
+		require.NoError(t, err)
+		return record
+	}
+
+	require.NoError(t, srv.queuePayout(ctx, p1ID, 10000000000, nil))
+	require.NoError(t, srv.queuePayout(ctx, p1ID, 10000000000, nil))
+	queued, err := srv.db.FetchTipProgressByStatus(ctx, serverdb.StatusQueued)
+	require.NoError(t, err)
+	first, second := queued[0].ID, queued[1].ID
+	srv.processPayouts(ctx, now)
+	require.Equal(t, serverdb.StatusSending, fetch(first).Status)
+
+	// A payout without an outcome is failed once it times out, and the
+	// next payout to the player waits for it to be resolved.
+	now = now.Add(payoutSendTimeout + time.Second)
+	srv.processPayouts(ctx, now)
+	record := fetch(first)
+	require.Equal(t, serverdb.StatusFailed, record.Status)
+	require.True(t, record.TimedOut)
+	require.Equal(t, serverdb.StatusQueued, fetch(second).Status)
+
+	// A late completion settles the payout that timed out, not the next
+	// one, which is sent afterwards.
+	require.NoError(t, srv.HandleTipProgress(ctx, &types.TipProgressEvent{
+		SequenceId:   20,
+		Uid:          p1ID[:],
+		AmountMatoms: 10000000000,
+		Completed:    true,
+	}))
+	require.Equal(t, serverdb.StatusPaid, fetch(first).Status)
+	require.Equal(t, serverdb.StatusQueued, fetch(second).Status)
+	srv.processPayouts(ctx, now)
+	require.Equal(t, serverdb.StatusSending, fetch(second).Status)
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// rakeConfig is the commission the house keeps from the pot of games that
+// end with a winner.
+type rakeConfig struct {
+	bps  int64 // share of the pot in basis points
+	flat matoms.Amount
+	cap  matoms.Amount // 0 for no cap
+}
+
+func newRakeConfig(percent float64, flat, maxRake matoms.Amount) (rakeConfig, error) {
+	if percent < 0 || percent > 100 {
+		return rakeConfig{}, fmt.Errorf("rake percentage must be between 0 and 100")
+	}
+	if flat < 0 || maxRake < 0 {
+		return rakeConfig{}, fmt.Errorf("rake fee and cap can't be negative")
+	}
+	return rakeConfig{
+		bps:  int64(math.Round(percent * 100)),
+		flat: flat,
+		cap:  maxRake,
+	}, nil
+}
+
+// amount returns the rake kept from a pot. It never exceeds the pot.
+func (r rakeConfig) amount(pot matoms.Amount) matoms.Amount {
+	bps := matoms.Amount(r.bps)
+	rake := pot/10000*bps + pot%10000*bps/10000 + r.flat
+	if r.cap > 0 && rake > r.cap {
+		rake = r.cap
+	}
+	if rake > pot {
+		rake = pot
+	}
+	return rake
+}
+
+// rakeFor returns the rake kept from a pot. Free-to-play games are not raked.
+func (s *Server) rakeFor(pot matoms.Amount) matoms.Amount {
+	if s.isF2P || pot <= 0 {
+		return 0
+	}
+	return s.rake.amount(pot)
+}
+
+// setRoomRake updates the rake a waiting room advertises for its pot.
+func (s *Server) setRoomRake(wr *ponggame.WaitingRoom) {
+	wr.Lock()
+	wr.Rake = s.rakeFor(wr.BetAmount * roomSeats)
+	wr.Unlock()
+}
+
+// rakeEntry is the rake collected from one game.
+type rakeEntry struct {
+	GameID string        `json:"game_id"`
+	Rake   matoms.Amount `json:"rake"`
+	Time   time.Time     `json:"time"`
+}
+
+// rakeReport is the house revenue collected over a period.
+type rakeReport struct {
+	HouseBalance matoms.Amount `json:"house_balance"`
+	Total        matoms.Amount `json:"total"`
+	Games        int           `json:"games"`
+	Entries      []rakeEntry   `json:"entries"`
+}
+
+// fetchRakeReport returns the rake collected since the given time.
+func (s *Server) fetchRakeReport(ctx context.Context, since time.Time) (*rakeReport, error) {
+	balance, err := s.db.FetchAccountBalance(ctx, serverdb.HouseAccount)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.db.FetchJournalEntries(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &rakeReport{HouseBalance: balance, Entries: []rakeEntry{}}
+	for _, entry := range entries {
+		if entry.Kind != serverdb.EntrySettle {
+			continue
+		}
+		for _, p := range entry.Postings {
+			if p.Account != serverdb.HouseAccount {
+				continue
+			}
+			report.Entries = append(report.Entries, rakeEntry{
+				GameID: entry.Ref,
+				Rake:   p.Amount,
+				Time:   entry.CreatedAt,
+			})
+			report.Total += p.Amount
+			report.Games++
+		}
+	}
+	return report, nil
+}
+
+// handleRakeHandler reports the rake collected by the house, optionally
+// since a unix timestamp.
+func (s *Server) handleRakeHandler(w http.ResponseWriter, r *http.Request) {
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		secs, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid since: %v", err), http.StatusBadRequest)
+			return
+		}
+		since = time.Unix(secs, 0)
+	}
+
+	report, err := s.fetchRakeReport(r.Context(), since)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error fetching rake: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, fmt.Sprintf("error encoding response: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func TestRakeAmount(t *testing.T) {
+	tests := []struct {
+		name        string
+		percent     float64
+		flat, limit matoms.Amount
+		pot, want   matoms.Amount
+	}{
+		{"none", 0, 0, 0, 100000000000, 0},
+		{"percent", 2.5, 0, 0, 100000000000, 2500000000},
+		{"flat", 0, matoms.PerDCR / 100, 0, 100000000000, 1000000000},
+		{"percent and flat", 1, matoms.PerDCR / 100, 0, 100000000000, 2000000000},
+		{"capped", 10, 0, 5 * matoms.PerDCR / 100, 100000000000, 5000000000},
+		{"never more than the pot", 0, matoms.PerDCR, 0, 50000000000, 50000000000},
+		{"rounds down", 3, 0, 0, 33, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rake, err := newRakeConfig(tc.percent, tc.flat, tc.limit)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, rake.amount(tc.pot))
+		})
+	}
+
+	_, err := newRakeConfig(101, 0, 0)
+	require.Error(t, err)
+	_, err = newRakeConfig(0, -matoms.PerDCR, 0)
+	require.Error(t, err)
+}
+
+func TestRakeSettlement(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	var err error
+	srv.rake, err = newRakeConfig(5, 0, 0)
+	require.NoError(t, err)
+
+	// The room advertises the rake of its pot.
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(5000000000), resp.Wr.Rake)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	game := &ponggame.GameInstance{Id: "game1", Winner: &p1ID}
+	srv.handleGameEnd(ctx, wr.ID, game, series, players)
+
+	house, err := srv.db.FetchAccountBalance(ctx, serverdb.HouseAccount)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(5000000000), house)
+	paid, err := srv.db.FetchAccountBalance(ctx, serverdb.PayoutsAccount)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(95000000000), paid)
+	require.NoError(t, srv.db.CheckLedger(ctx))
+
+	report, err := srv.fetchRakeReport(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(5000000000), report.HouseBalance)
+	require.Equal(t, matoms.Amount(5000000000), report.Total)
+	require.Equal(t, 1, report.Games)
+	require.Equal(t, game.Id, report.Entries[0].GameID)
+
+	report, err = srv.fetchRakeReport(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, report.Total)
+	require.Empty(t, report.Entries)
+}
+
+func TestRakeF2PExempt(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+
+	var err error
+	srv.rake, err = newRakeConfig(5, matoms.PerDCR/100, 0)
+	require.NoError(t, err)
+	srv.isF2P = true
+	srv.creditGrant = defaultCreditGrant
+
+	require.Zero(t, srv.rakeFor(100000000000))
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	require.Zero(t, resp.Wr.Rake)
+}
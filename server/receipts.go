@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/receipt"
+)
+
+// receiptKeyFile is the file in the server dir holding the key match
+// receipts are signed with.
+const receiptKeyFile = "receipt.key"
+
+// matchReceipt signs the receipt of a finished match, listing every game of
+// its series. It must be signed once the stakes are settled, with the amounts
+// settled. Nothing is signed if the server has no receipt key.
+func (s *Server) matchReceipt(game *ponggame.GameInstance, series *ponggame.Series, players []*ponggame.Player,
+	stakes map[zkidentity.ShortID]matoms.Amount, rake, payout matoms.Amount) *receipt.Signed {
+	if s.receiptKey == nil {
+		return nil
+	}
+
+	r := &receipt.Receipt{
+		GameID:     game.Id,
+		Rake:       rake,
+		Payout:     payout,
+		ReplayHash: game.ReplayHash(),
+		EndedAt:    time.Now().UTC(),
+	}
+	var games []ponggame.SeriesGame
+	if series != nil {
+		r.SeriesID = series.ID
+		games = series.Games
+	}
+	if len(games) == 0 {
+		games = []ponggame.SeriesGame{{ID: game.Id, Winner: game.Winner, ReplayHash: r.ReplayHash, StartedAt: game.StartedAt}}
+	}
+	for _, g := range games {
+		rg := receipt.Game{ID: g.ID, ReplayHash: g.ReplayHash}
+		if g.Winner != nil {
+			rg.Winner = g.Winner.String()
+		}
+		r.Games = append(r.Games, rg)
+	}
+	r.StartedAt = games[0].StartedAt.UTC()
+	if game.Winner != nil {
+		r.Winner = game.Winner.String()
+	}
+	for _, player := range players {
+		r.Players = append(r.Players, receipt.Player{
+			UID:   player.ID.String(),
+			Nick:  player.Nick,
+			Score: int32(player.Score),
+			Stake: stakes[*player.ID],
+		})
+		r.Pot += stakes[*player.ID]
+	}
+
+	signed, err := receipt.Sign(s.receiptKey, r)
+	if err != nil {
+		s.log.Errorf("Failed to sign receipt of game %s: %v", game.Id, err)
+		return nil
+	}
+	return signed
+}
+
+func marshalReceipt(signed *receipt.Signed) *pong.MatchReceipt {
+	if signed == nil {
+		return nil
+	}
+	return &pong.MatchReceipt{
+		Receipt:   signed.Receipt,
+		Signature: signed.Signature,
+		PublicKey: signed.PublicKey,
+	}
+}
+
+// sendReceipt sends the receipt of a match to the players over Bison Relay,
+// to be checked with the verifyreceipt command.
+func (s *Server) sendReceipt(ctx context.Context, gameID string, players []*ponggame.Player, signed *receipt.Signed) {
+	if signed == nil {
+		return
+	}
+	data, err := json.Marshal(signed)
+	if err != nil {
+		s.log.Errorf("Failed to encode receipt of game %s: %v", gameID, err)
+		return
+	}
+	msg := fmt.Sprintf("Signed receipt of game %s:\n%s", gameID, data)
+	for _, player := range players {
+		if err := s.bot.SendPM(ctx, player.ID.String(), msg); err != nil {
+			s.log.Warnf("Failed to send receipt of game %s to %s: %v", gameID, player.ID, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/receipt"
+)
+
+func TestMatchReceipt(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	bot := srv.bot.(*minimalTestBot)
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	srv.receiptKey = key
+	srv.rake, err = newRakeConfig(5, 0, 0)
+	require.NoError(t, err)
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 50000000000,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	players[0].Score = 3
+	series, err := ponggame.NewSeries(players, 1)
+	require.NoError(t, err)
+	game := &ponggame.GameInstance{Id: "game1", Winner: &p1ID}
+	srv.handleGameEnd(ctx, wr.ID, game, series, players)
+
+	// The receipt is sent with the game end notification.
+	msgs := players[0].NotifierStream.(*mockNotifierStream).messages
+	ntfn := msgs[len(msgs)-1]
+	require.Equal(t, pong.NotificationType_GAME_END, ntfn.NotificationType)
+	require.NotNil(t, ntfn.Receipt)
+	signed := &receipt.Signed{
+		Receipt:   ntfn.Receipt.Receipt,
+		Signature: ntfn.Receipt.Signature,
+		PublicKey: ntfn.Receipt.PublicKey,
+	}
+	r, err := signed.Verify(pub)
+	require.NoError(t, err)
+	require.Equal(t, "game1", r.GameID)
+	require.Equal(t, series.ID, r.SeriesID)
+	require.Equal(t, p1ID.String(), r.Winner)
+	require.Len(t, r.Players, 2)
+	require.Equal(t, int32(3), r.Players[0].Score)
+	require.Equal(t, matoms.Amount(50000000000), r.Players[1].Stake)
+	require.Equal(t, matoms.Amount(100000000000), r.Pot)
+	require.Equal(t, matoms.Amount(5000000000), r.Rake)
+	require.Equal(t, matoms.Amount(95000000000), r.Payout)
+	require.NotEmpty(t, r.ReplayHash)
+
+	// And to both players as a PM.
+	var pms int
+	for _, pm := range bot.sentPMs {
+		_, data, ok := strings.Cut(pm.msg, "\n")
+		if !ok || !strings.HasPrefix(pm.msg, "Signed receipt of game game1") {
+			continue
+		}
+		var fromPM receipt.Signed
+		require.NoError(t, json.Unmarshal([]byte(data), &fromPM))
+		_, err := fromPM.Verify(pub)
+		require.NoError(t, err)
+		pms++
+	}
+	require.Equal(t, 2, pms)
+}
+
+func TestSeriesReceipt(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	srv.receiptKey = key
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId: p1ID.String(),
+		BetAmt: 10000000333,
+	})
+	require.NoError(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: p2ID.String(),
+	})
+	require.NoError(t, err)
+
+	wr := srv.gameManager.GetWaitingRoom(resp.Wr.Id)
+	players := wr.GetPlayers()
+	series, err := ponggame.NewSeries(players, 3)
+	require.NoError(t, err)
+	games := []*ponggame.GameInstance{
+		{Id: "game1", Winner: &p1ID},
+		{Id: "game2", Winner: &p2ID},
+		{Id: "game3", Winner: &p1ID},
+	}
+	for _, game := range games {
+		series.AddGame(game)
+		series.RecordGame(game.Winner)
+	}
+	srv.handleGameEnd(ctx, wr.ID, games[2], series, players)
+
+	// Every game of the series is listed and the payout is what was
+	// settled, in whole atoms.
+	msgs := players[0].NotifierStream.(*mockNotifierStream).messages
+	ntfn := msgs[len(msgs)-1]
+	require.NotNil(t, ntfn.Receipt)
+	signed := &receipt.Signed{
+		Receipt:   ntfn.Receipt.Receipt,
+		Signature: ntfn.Receipt.Signature,
+		PublicKey: ntfn.Receipt.PublicKey,
+	}
+	r, err := signed.Verify(pub)
+	require.NoError(t, err)
+	require.Equal(t, "game3", r.GameID)
+	require.Len(t, r.Games, 3)
+	for i, game := range games {
+		require.Equal(t, game.Id, r.Games[i].ID)
+		require.Equal(t, game.Winner.String(), r.Games[i].Winner)
+		require.NotEmpty(t, r.Games[i].ReplayHash)
+	}
+	require.Equal(t, matoms.Amount(20000000666), r.Pot)
+	require.Equal(t, matoms.Amount(20000000000), r.Payout)
+}
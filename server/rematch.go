@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+// rematchWindow is how long players have to agree on a rematch after a game
+// ends.
+const rematchWindow = 2 * time.Minute
+
+// rematch is the offer window opened for the players of a finished game.
+type rematch struct {
+	id        string
+	players   [2]zkidentity.ShortID
+	betAmt    matoms.Amount
+	rules     ponggame.GameRules
+	proposer  *zkidentity.ShortID
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+func (r *rematch) marshal() *pong.Rematch {
+	pr := &pong.Rematch{
+		Id:        r.id,
+		PlayerIds: []string{r.players[0].String(), r.players[1].String()},
+		BetAmt:    int64(r.betAmt),
+		Rules:     r.rules.Marshal(),
+		ExpiresAt: r.expiresAt.Unix(),
+	}
+	if r.proposer != nil {
+		pr.ProposerId = r.proposer.String()
+	}
+	return pr
+}
+
+func (r *rematch) opponent(id zkidentity.ShortID) zkidentity.ShortID {
+	if r.players[0] == id {
+		return r.players[1]
+	}
+	return r.players[0]
+}
+
+// openRematchWindow offers the players of a finished game a rematch with the
+// same stake and rules.
+func (s *Server) openRematchWindow(players []*ponggame.Player, betAmt matoms.Amount, rules ponggame.GameRules) {
+	if len(players) != 2 {
+		return
+	}
+	for _, player := range players {
+		if s.gameManager.PlayerSessions.GetPlayer(*player.ID) == nil {
+			return
+		}
+	}
+
+	id, err := utils.GenerateRandomString(16)
+	if err != nil {
+		s.log.Errorf("failed to generate rematch ID: %v", err)
+		return
+	}
+	r := &rematch{
+		id:        id,
+		players:   [2]zkidentity.ShortID{*players[0].ID, *players[1].ID},
+		betAmt:    betAmt,
+		rules:     rules,
+		expiresAt: time.Now().Add(rematchWindow),
+	}
+
+	s.rematchesMtx.Lock()
+	if s.rematches == nil {
+		s.rematches = make(map[zkidentity.ShortID]*rematch)
+	}
+	for _, uid := range r.players {
+		if old := s.rematches[uid]; old != nil {
+			s.closeRematchLocked(old)
+		}
+		s.rematches[uid] = r
+	}
+	r.timer = time.AfterFunc(rematchWindow, func() { s.expireRematch(r) })
+	s.rematchesMtx.Unlock()
+
+	for _, uid := range r.players {
+		s.notifyRematch(r, uid, pong.NotificationType_REMATCH_AVAILABLE,
+			"Rematch available", nil)
+	}
+}
+
+// ProposeRematch proposes a rematch to the opponent of the last game, either
+// with the same stake and rules or new ones.
+func (s *Server) ProposeRematch(ctx context.Context, req *pong.ProposeRematchRequest) (*pong.ProposeRematchResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	player := s.gameManager.PlayerSessions.GetPlayer(clientID)
+	if player == nil {
+		return nil, fmt.Errorf("player not found: %s", req.ClientId)
+	}
+	if err := s.checkAvailable(player); err != nil {
+		return nil, err
+	}
+
+	s.rematchesMtx.Lock()
+	r := s.rematches[clientID]
+	var betAmt matoms.Amount
+	var rules ponggame.GameRules
+	if r != nil {
+		betAmt, rules = r.betAmt, r.rules
+	}
+	s.rematchesMtx.Unlock()
+	if r == nil {
+		return nil, fmt.Errorf("no rematch available")
+	}
+
+	if req.BetAmt != 0 {
+		betAmt = matoms.Amount(req.BetAmt)
+	}
+	if req.Rules != nil {
+		rules = ponggame.GameRulesFromProto(req.Rules)
+	}
+	if err := s.validateBetAmt(betAmt); err != nil {
+		return nil, err
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkLimits(ctx, clientID, betAmt); err != nil {
+		return nil, err
+	}
+	if _, err := s.fetchStakeTips(ctx, clientID, betAmt); err != nil {
+		return nil, err
+	}
+
+	s.rematchesMtx.Lock()
+	if s.rematches[clientID] != r {
+		s.rematchesMtx.Unlock()
+		return nil, fmt.Errorf("no rematch available")
+	}
+	r.betAmt = betAmt
+	r.rules = rules
+	r.proposer = &clientID
+	pr := r.marshal()
+	opponent := r.opponent(clientID)
+	s.rematchesMtx.Unlock()
+
+	s.notifyRematch(r, opponent, pong.NotificationType_REMATCH_PROPOSED,
+		fmt.Sprintf("Rematch proposed for %s DCR", betAmt), nil)
+
+	return &pong.ProposeRematchResponse{
+		Rematch: pr,
+	}, nil
+}
+
+// RespondRematch accepts or declines the rematch proposed by the opponent.
+// Accepting reserves the stake of both players again and puts them back in a
+// private waiting room together.
+func (s *Server) RespondRematch(ctx context.Context, req *pong.RespondRematchRequest) (*pong.RespondRematchResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+
+	s.rematchesMtx.Lock()
+	r := s.rematches[clientID]
+	var proposer zkidentity.ShortID
+	var betAmt matoms.Amount
+	var rules ponggame.GameRules
+	if r != nil && r.proposer != nil {
+		proposer, betAmt, rules = *r.proposer, r.betAmt, r.rules
+	}
+	s.rematchesMtx.Unlock()
+	if r == nil || r.proposer == nil {
+		return nil, fmt.Errorf("no rematch proposed")
+	}
+	if proposer == clientID {
+		return nil, fmt.Errorf("waiting for the opponent to answer the rematch")
+	}
+
+	if !req.Accept {
+		s.rematchesMtx.Lock()
+		closed := s.closeRematchLocked(r)
+		s.rematchesMtx.Unlock()
+		if closed {
+			s.notifyRematch(r, proposer, pong.NotificationType_REMATCH_DECLINED,
+				"Rematch declined", nil)
+		}
+		return &pong.RespondRematchResponse{}, nil
+	}
+
+	host := s.gameManager.PlayerSessions.GetPlayer(proposer)
+	if host == nil {
+		return nil, fmt.Errorf("opponent %s is not connected", proposer)
+	}
+	guest := s.gameManager.PlayerSessions.GetPlayer(clientID)
+	if guest == nil {
+		return nil, fmt.Errorf("player not found: %s", req.ClientId)
+	}
+	for _, p := range []*ponggame.Player{host, guest} {
+		if err := s.checkAvailable(p); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkLimits(ctx, clientID, betAmt); err != nil {
+		return nil, err
+	}
+	if err := s.checkOpponentLimits(ctx, proposer, betAmt); err != nil {
+		return nil, err
+	}
+	hostTips, err := s.fetchStakeTips(ctx, proposer, betAmt)
+	if err != nil {
+		return nil, fmt.Errorf("opponent can't cover the stake: %v", err)
+	}
+	guestTips, err := s.fetchStakeTips(ctx, clientID, betAmt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stake or rules may have changed by a counter proposal while
+	// tips were fetched.
+	s.rematchesMtx.Lock()
+	stale := s.rematches[clientID] != r || r.proposer == nil || *r.proposer != proposer ||
+		r.betAmt != betAmt || r.rules != rules
+	if !stale {
+		s.closeRematchLocked(r)
+	}
+	s.rematchesMtx.Unlock()
+	if stale {
+		return nil, fmt.Errorf("rematch proposal changed, try again")
+	}
+
+	wr, err := s.createPairedRoom(host, guest, betAmt, rules, append(hostTips, guestTips...))
+	if err != nil {
+		return nil, err
+	}
+	pongWR, err := wr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	for _, uid := range r.players {
+		s.notifyRematch(r, uid, pong.NotificationType_REMATCH_ACCEPTED,
+			"Rematch accepted", pongWR)
+	}
+
+	return &pong.RespondRematchResponse{
+		Wr: pongWR,
+	}, nil
+}
+
+// closeRematchLocked removes a rematch window, returning false if it was
+// already closed. Must be called with rematchesMtx held.
+func (s *Server) closeRematchLocked(r *rematch) bool {
+	closed := false
+	for _, uid := range r.players {
+		if s.rematches[uid] == r {
+			delete(s.rematches, uid)
+			closed = true
+		}
+	}
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	return closed
+}
+
+func (s *Server) expireRematch(r *rematch) {
+	s.rematchesMtx.Lock()
+	closed := s.closeRematchLocked(r)
+	s.rematchesMtx.Unlock()
+	if !closed {
+		return
+	}
+	for _, uid := range r.players {
+		s.notifyRematch(r, uid, pong.NotificationType_REMATCH_EXPIRED,
+			"Rematch window closed", nil)
+	}
+}
+
+func (s *Server) notifyRematch(r *rematch, to zkidentity.ShortID, typ pong.NotificationType, msg string, wr *pong.WaitingRoom) {
+	player := s.gameManager.PlayerSessions.GetPlayer(to)
+	if player == nil || player.NotifierStream == nil {
+		return
+	}
+	s.rematchesMtx.Lock()
+	pr := r.marshal()
+	s.rematchesMtx.Unlock()
+	player.NotifierStream.Send(&pong.NtfnStreamResponse{
+		NotificationType: typ,
+		Message:          msg,
+		PlayerId:         r.opponent(to).String(),
+		BetAmt:           pr.BetAmt,
+		Rematch:          pr,
+		Wr:               wr,
+	})
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func TestRematch(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	// Stake of the finished game.
+	lastBet := matoms.Amount(50000000000)
+
+	// Nothing to propose before a game ended.
+	_, err := srv.ProposeRematch(ctx, &pong.ProposeRematchRequest{ClientId: p1ID.String()})
+	require.Error(t, err)
+
+	srv.openRematchWindow([]*ponggame.Player{p1, p2}, lastBet, ponggame.DefaultGameRules())
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_REMATCH_AVAILABLE, msgs[len(msgs)-1].NotificationType)
+
+	// A new stake needs matching tips.
+	_, err = srv.ProposeRematch(ctx, &pong.ProposeRematchRequest{
+		ClientId: p1ID.String(),
+		BetAmt:   70000000000,
+	})
+	require.Error(t, err)
+
+	resp, err := srv.ProposeRematch(ctx, &pong.ProposeRematchRequest{
+		ClientId: p1ID.String(),
+		Rules:    &pong.GameRules{BestOf: 3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(50000000000), resp.Rematch.BetAmt)
+	require.Equal(t, p1ID.String(), resp.Rematch.ProposerId)
+	msgs = p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_REMATCH_PROPOSED, msgs[len(msgs)-1].NotificationType)
+
+	// The proposer can't accept its own proposal.
+	_, err = srv.RespondRematch(ctx, &pong.RespondRematchRequest{ClientId: p1ID.String(), Accept: true})
+	require.Error(t, err)
+
+	accepted, err := srv.RespondRematch(ctx, &pong.RespondRematchRequest{ClientId: p2ID.String(), Accept: true})
+	require.NoError(t, err)
+	require.Len(t, accepted.Wr.Players, 2)
+	require.Equal(t, int32(3), accepted.Wr.Rules.BestOf)
+	require.NotNil(t, p1.WR)
+	require.Len(t, p1.WR.ReservedTips, 2)
+
+	// The window closes once used.
+	_, err = srv.RespondRematch(ctx, &pong.RespondRematchRequest{ClientId: p2ID.String(), Accept: true})
+	require.Error(t, err)
+}
+
+func TestRematchDeclineAndExpire(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+	lastBet := matoms.Amount(50000000000)
+
+	srv.openRematchWindow([]*ponggame.Player{p1, p2}, lastBet, ponggame.DefaultGameRules())
+	_, err := srv.ProposeRematch(ctx, &pong.ProposeRematchRequest{ClientId: p2ID.String()})
+	require.NoError(t, err)
+	_, err = srv.RespondRematch(ctx, &pong.RespondRematchRequest{ClientId: p1ID.String()})
+	require.NoError(t, err)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_REMATCH_DECLINED, msgs[len(msgs)-1].NotificationType)
+
+	srv.openRematchWindow([]*ponggame.Player{p1, p2}, lastBet, ponggame.DefaultGameRules())
+	srv.expireRematch(srv.rematches[p1ID])
+	msgs = p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_REMATCH_EXPIRED, msgs[len(msgs)-1].NotificationType)
+	_, err = srv.ProposeRematch(ctx, &pong.ProposeRematchRequest{ClientId: p1ID.String()})
+	require.Error(t, err)
+}
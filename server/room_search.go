@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+const (
+	defaultWaitingRoomsLimit = 20
+	maxWaitingRoomsLimit     = 100
+
+	// roomSeats is the number of players a waiting room holds.
+	roomSeats = 2
+)
+
+// roomCursor is the position of a room in a sorted listing. Rooms are ordered
+// by N, then F, then ID, all ascending, so every sort maps to these keys.
+type roomCursor struct {
+	Sort pong.WaitingRoomSort `json:"s"`
+	N    int64                `json:"n,omitempty"`
+	F    float64              `json:"f,omitempty"`
+	ID   string               `json:"id"`
+}
+
+func roomCursorFor(wr *pong.WaitingRoom, order pong.WaitingRoomSort) roomCursor {
+	c := roomCursor{Sort: order, ID: wr.Id}
+	switch order {
+	case pong.WaitingRoomSort_WR_SORT_OLDEST:
+		c.N = wr.CreatedAt
+	case pong.WaitingRoomSort_WR_SORT_BET_ASC:
+		c.N = wr.BetAmt
+	case pong.WaitingRoomSort_WR_SORT_BET_DESC:
+		c.N = -wr.BetAmt
+	case pong.WaitingRoomSort_WR_SORT_HOST_RATING:
+		c.F = -wr.HostRating
+	default:
+		c.N = -wr.CreatedAt
+	}
+	return c
+}
+
+func (c roomCursor) less(o roomCursor) bool {
+	if c.N != o.N {
+		return c.N < o.N
+	}
+	if c.F != o.F {
+		return c.F < o.F
+	}
+	return c.ID < o.ID
+}
+
+func (c roomCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeRoomCursor(s string, order pong.WaitingRoomSort) (roomCursor, error) {
+	var c roomCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor: %v", err)
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("invalid cursor: %v", err)
+	}
+	if c.Sort != order {
+		return c, fmt.Errorf("cursor was issued for a different sort order")
+	}
+	return c, nil
+}
+
+// GetWaitingRooms lists the public waiting rooms matching the request
+// filters, one page at a time.
+func (s *Server) GetWaitingRooms(ctx context.Context, req *pong.WaitingRoomsRequest) (*pong.WaitingRoomsResponse, error) {
+	if req.MinBet < 0 || req.MaxBet < 0 || (req.MaxBet > 0 && req.MaxBet < req.MinBet) {
+		return nil, fmt.Errorf("invalid bet range")
+	}
+	if req.MaxHostRating > 0 && req.MaxHostRating < req.MinHostRating {
+		return nil, fmt.Errorf("invalid host rating range")
+	}
+	if req.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d", req.Limit)
+	}
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultWaitingRoomsLimit
+	} else if limit > maxWaitingRoomsLimit {
+		limit = maxWaitingRoomsLimit
+	}
+	var after *roomCursor
+	if req.Cursor != "" {
+		c, err := decodeRoomCursor(req.Cursor, req.Sort)
+		if err != nil {
+			return nil, err
+		}
+		after = &c
+	}
+
+	ratings, err := s.db.FetchPlayerRatings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ratings: %v", err)
+	}
+	ratingByUID := make(map[string]float64, len(ratings))
+	for _, r := range ratings {
+		ratingByUID[string(r.UID)] = r.Rating
+	}
+
+	// Work on a snapshot so listing doesn't hold up the game manager.
+	s.gameManager.RLock()
+	rooms := append([]*ponggame.WaitingRoom(nil), s.gameManager.WaitingRooms...)
+	s.gameManager.RUnlock()
+
+	matches := make([]*pong.WaitingRoom, 0, len(rooms))
+	for _, room := range rooms {
+		if req.RoomId != "" && room.ID != req.RoomId {
+			continue
+		}
+		wr, err := room.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		// private rooms are only reachable through invites
+		if wr.Private {
+			continue
+		}
+		wr.HostRating = defaultRating
+		if room.HostID != nil {
+			if r, ok := ratingByUID[string(room.HostID[:])]; ok {
+				wr.HostRating = r
+			}
+		}
+		if roomMatches(wr, req) {
+			matches = append(matches, wr)
+		}
+	}
+
+	keys := make(map[string]roomCursor, len(matches))
+	for _, wr := range matches {
+		keys[wr.Id] = roomCursorFor(wr, req.Sort)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return keys[matches[i].Id].less(keys[matches[j].Id])
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matches), func(i int) bool {
+			return after.less(keys[matches[i].Id])
+		})
+	}
+	end := start + limit
+	if end > len(matches) {
+		end = len(matches)
+	}
+	page := matches[start:end]
+
+	var next string
+	if end < len(matches) {
+		next = keys[page[len(page)-1].Id].encode()
+	}
+	return &pong.WaitingRoomsResponse{
+		Wr:         page,
+		NextCursor: next,
+		Total:      int32(len(matches)),
+	}, nil
+}
+
+// roomMatches returns whether a listed room passes the request filters.
+func roomMatches(wr *pong.WaitingRoom, req *pong.WaitingRoomsRequest) bool {
+	if wr.BetAmt < req.MinBet || (req.MaxBet > 0 && wr.BetAmt > req.MaxBet) {
+		return false
+	}
+	if req.OpenSeats && (wr.Locked || len(wr.Players) >= roomSeats) {
+		return false
+	}
+	if rules := req.Rules; rules != nil {
+		if rules.MaxScore != 0 && rules.MaxScore != wr.Rules.GetMaxScore() {
+			return false
+		}
+		if rules.BestOf != 0 && rules.BestOf != wr.Rules.GetBestOf() {
+			return false
+		}
+	}
+	if wr.HostRating < req.MinHostRating || (req.MaxHostRating > 0 && wr.HostRating > req.MaxHostRating) {
+		return false
+	}
+	return true
+}
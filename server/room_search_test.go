@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// addSearchRoom adds a public waiting room hosted by a new player.
+func addSearchRoom(t *testing.T, srv *Server, hostByte byte, betAmt matoms.Amount, created time.Time) *ponggame.WaitingRoom {
+	t.Helper()
+	host := createTestPlayer(srv, zkidentity.ShortID{hostByte})
+	wr, err := ponggame.NewWaitingRoom(host, betAmt)
+	require.NoError(t, err)
+	wr.CreatedAt = created
+	host.WR = wr
+	srv.gameManager.WaitingRooms = append(srv.gameManager.WaitingRooms, wr)
+	return wr
+}
+
+func roomIDs(rooms []*pong.WaitingRoom) []string {
+	ids := make([]string, len(rooms))
+	for i, wr := range rooms {
+		ids[i] = wr.Id
+	}
+	return ids
+}
+
+func TestGetWaitingRoomsFilters(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	cheap := addSearchRoom(t, srv, 1, 10000000000, now.Add(-3*time.Minute))
+	mid := addSearchRoom(t, srv, 2, 50000000000, now.Add(-2*time.Minute))
+	rich := addSearchRoom(t, srv, 3, 100000000000, now.Add(-time.Minute))
+	rich.Rules.MaxScore = 7
+	full := addSearchRoom(t, srv, 4, 50000000000, now)
+	full.AddPlayer(createTestPlayer(srv, zkidentity.ShortID{5}))
+	private := addSearchRoom(t, srv, 6, 50000000000, now)
+	_, err := private.MakePrivate()
+	require.NoError(t, err)
+
+	err = srv.db.StoreMatchResult(ctx, &serverdb.MatchResult{GameID: "g"}, func([]*serverdb.PlayerRating) ([]*serverdb.PlayerRating, error) {
+		return []*serverdb.PlayerRating{
+			{UID: mid.HostID.Bytes(), Rating: 1700},
+			{UID: cheap.HostID.Bytes(), Rating: 1300},
+		}, nil
+	})
+	require.NoError(t, err)
+
+	list := func(req *pong.WaitingRoomsRequest) []string {
+		t.Helper()
+		res, err := srv.GetWaitingRooms(ctx, req)
+		require.NoError(t, err)
+		return roomIDs(res.Wr)
+	}
+
+	require.Equal(t, []string{full.ID, rich.ID, mid.ID, cheap.ID}, list(&pong.WaitingRoomsRequest{}))
+	require.Equal(t, []string{mid.ID}, list(&pong.WaitingRoomsRequest{RoomId: mid.ID}))
+	require.Empty(t, list(&pong.WaitingRoomsRequest{RoomId: private.ID}))
+	require.Equal(t, []string{full.ID, mid.ID}, list(&pong.WaitingRoomsRequest{
+		MinBet: 20000000000,
+		MaxBet: 50000000000,
+	}))
+	require.Equal(t, []string{rich.ID, mid.ID, cheap.ID}, list(&pong.WaitingRoomsRequest{OpenSeats: true}))
+	require.Equal(t, []string{rich.ID}, list(&pong.WaitingRoomsRequest{Rules: &pong.GameRules{MaxScore: 7}}))
+	require.Equal(t, []string{mid.ID}, list(&pong.WaitingRoomsRequest{MinHostRating: 1600}))
+	require.Equal(t, []string{cheap.ID}, list(&pong.WaitingRoomsRequest{MaxHostRating: 1400}))
+
+	require.Equal(t, []string{cheap.ID, mid.ID, rich.ID, full.ID},
+		list(&pong.WaitingRoomsRequest{Sort: pong.WaitingRoomSort_WR_SORT_OLDEST}))
+	require.Equal(t, []string{rich.ID},
+		list(&pong.WaitingRoomsRequest{Sort: pong.WaitingRoomSort_WR_SORT_BET_DESC, Limit: 1}))
+	res, err := srv.GetWaitingRooms(ctx, &pong.WaitingRoomsRequest{Sort: pong.WaitingRoomSort_WR_SORT_HOST_RATING})
+	require.NoError(t, err)
+	require.Equal(t, mid.ID, res.Wr[0].Id)
+	require.Equal(t, 1700.0, res.Wr[0].HostRating)
+	require.Equal(t, cheap.ID, res.Wr[3].Id)
+
+	_, err = srv.GetWaitingRooms(ctx, &pong.WaitingRoomsRequest{MinBet: 10, MaxBet: 5})
+	require.Error(t, err)
+}
+
+func TestGetWaitingRoomsPagination(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	var want []string
+	for i := 0; i < 5; i++ {
+		wr := addSearchRoom(t, srv, byte(i+1), 10000000000, now.Add(time.Duration(i)*time.Second))
+		want = append(want, wr.ID)
+	}
+
+	var got []string
+	req := &pong.WaitingRoomsRequest{Sort: pong.WaitingRoomSort_WR_SORT_OLDEST, Limit: 2}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		res, err := srv.GetWaitingRooms(ctx, req)
+		require.NoError(t, err)
+		require.Equal(t, int32(len(want)), res.Total)
+		got = append(got, roomIDs(res.Wr)...)
+		if res.NextCursor == "" {
+			break
+		}
+		req.Cursor = res.NextCursor
+
+		// Rooms removed between pages don't shift the listing.
+		if pages == 0 {
+			srv.gameManager.RemoveWaitingRoom(want[2])
+			want = append(want[:2:2], want[3:]...)
+		}
+	}
+	require.Equal(t, want, got)
+
+	// Cursors are bound to the sort order they were issued for.
+	res, err := srv.GetWaitingRooms(ctx, &pong.WaitingRoomsRequest{Limit: 1})
+	require.NoError(t, err)
+	_, err = srv.GetWaitingRooms(ctx, &pong.WaitingRoomsRequest{
+		Cursor: res.NextCursor,
+		Sort:   pong.WaitingRoomSort_WR_SORT_BET_ASC,
+	})
+	require.Error(t, err)
+}
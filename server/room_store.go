@@ -0,0 +1,324 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+// defaultRestoreGrace is how long players of restored waiting rooms have to
+// reconnect after a restart.
+const defaultRestoreGrace = 2 * time.Minute
+
+func (s *Server) roomRestoreGrace() time.Duration {
+	if s.restoreGrace > 0 {
+		return s.restoreGrace
+	}
+	return defaultRestoreGrace
+}
+
+// storedRoom converts a waiting room to its db representation.
+func storedRoom(wr *ponggame.WaitingRoom) *serverdb.StoredWaitingRoom {
+	wr.RLock()
+	defer wr.RUnlock()
+
+	room := &serverdb.StoredWaitingRoom{
+		ID:         wr.ID,
+		BetAmt:     wr.BetAmount,
+		MaxScore:   wr.Rules.MaxScore,
+		BestOf:     wr.Rules.BestOf,
+		Private:    wr.Private,
+		InviteCode: wr.InviteCode,
+		Locked:     wr.Locked,
+		CreatedAt:  wr.CreatedAt,
+	}
+	if wr.HostID != nil {
+		room.HostUID = wr.HostID.Bytes()
+	}
+	for id := range wr.Invited {
+		room.Invited = append(room.Invited, id.Bytes())
+	}
+	for id := range wr.Banned {
+		room.Banned = append(room.Banned, id.Bytes())
+	}
+	for _, p := range wr.Players {
+		member := serverdb.StoredRoomMember{UID: p.ID.Bytes(), Nick: p.Nick}
+		for _, tip := range wr.ReservedTips {
+			if bytes.Equal(tip.Uid, p.ID[:]) {
+				member.TipIDs = append(member.TipIDs, tip.SequenceId)
+			}
+		}
+		room.Members = append(room.Members, member)
+	}
+	return room
+}
+
+// persistRoom stores the waiting room if it changed since the last stored
+// snapshot and returns the current one.
+func (s *Server) persistRoom(ctx context.Context, wr *ponggame.WaitingRoom, last []byte) []byte {
+	if s.shuttingDown.Load() || s.isTournamentRoom(wr.ID) {
+		return last
+	}
+	room := storedRoom(wr)
+	snapshot, err := json.Marshal(room)
+	if err != nil {
+		s.log.Errorf("Failed to encode waiting room %s: %v", wr.ID, err)
+		return last
+	}
+	if bytes.Equal(snapshot, last) {
+		return last
+	}
+	if err := s.db.StoreWaitingRoom(ctx, room); err != nil {
+		s.log.Errorf("Failed to store waiting room %s: %v", wr.ID, err)
+		return last
+	}
+	return snapshot
+}
+
+// forgetRoom removes a closed waiting room from the db. Rooms are kept when
+// the server is shutting down so they can be restored.
+func (s *Server) forgetRoom(ctx context.Context, wr *ponggame.WaitingRoom) {
+	s.restoredRooms.Delete(wr.ID)
+	if s.shuttingDown.Load() {
+		return
+	}
+	if err := s.db.DeleteWaitingRoom(ctx, wr.ID); err != nil {
+		s.log.Errorf("Failed to delete waiting room %s: %v", wr.ID, err)
+	}
+}
+
+// restoreWaitingRooms recreates the waiting rooms stored before a restart.
+// Members whose stake is no longer held in escrow are dropped. The players
+// of restored rooms have until the restore grace period ends to reconnect.
+func (s *Server) restoreWaitingRooms(ctx context.Context, now time.Time) error {
+	rooms, err := s.db.FetchWaitingRooms(ctx)
+	if err != nil {
+		return err
+	}
+
+	restored := 0
+	for _, room := range rooms {
+		wr, err := s.restoreWaitingRoom(ctx, room)
+		if err != nil {
+			s.log.Warnf("Dropping stored waiting room %s: %v", room.ID, err)
+			if err := s.db.DeleteWaitingRoom(ctx, room.ID); err != nil {
+				return err
+			}
+			s.releaseStakes(ctx, room.ID)
+			continue
+		}
+		s.restoredRooms.Store(wr.ID, now.Add(s.roomRestoreGrace()))
+		s.gameManager.Lock()
+		s.gameManager.WaitingRooms = append(s.gameManager.WaitingRooms, wr)
+		s.gameManager.Unlock()
+		restored++
+	}
+	if restored > 0 {
+		s.log.Infof("Restored %d waiting rooms", restored)
+		select {
+		case s.waitingRoomCreated <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *Server) restoreWaitingRoom(ctx context.Context, room *serverdb.StoredWaitingRoom) (*ponggame.WaitingRoom, error) {
+	var hostID zkidentity.ShortID
+	if err := hostID.FromBytes(room.HostUID); err != nil {
+		return nil, fmt.Errorf("invalid host: %v", err)
+	}
+
+	var players []*ponggame.Player
+	var tips []*types.ReceivedTip
+	for _, member := range room.Members {
+		var uid zkidentity.ShortID
+		if err := uid.FromBytes(member.UID); err != nil {
+			return nil, fmt.Errorf("invalid member: %v", err)
+		}
+		memberTips, err := s.reservedTips(ctx, uid, member.TipIDs)
+		if err != nil {
+			return nil, err
+		}
+		escrowed, err := s.db.FetchAccountBalance(ctx, s.stakeAccounts().EscrowAccount(room.ID, uid))
+		if err != nil {
+			return nil, err
+		}
+		reserved := escrowed >= room.BetAmt
+		if escrowed == 0 && room.BetAmt > 0 && len(memberTips) == len(member.TipIDs) {
+			// Rooms stored before the ledger existed hold no escrow, the
+			// stake is reserved again while its tips are still unpaid.
+			if err := s.reserveStake(ctx, room.ID, uid, room.BetAmt); err != nil {
+				return nil, err
+			}
+			reserved = true
+		}
+		if !reserved {
+			if uid == hostID {
+				return nil, fmt.Errorf("host stake is no longer reserved")
+			}
+			s.log.Warnf("Dropping %s from restored waiting room %s: stake is no longer reserved", uid, room.ID)
+			s.releaseStake(ctx, room.ID, uid)
+			continue
+		}
+
+		player := s.gameManager.PlayerSessions.CreateSession(uid)
+		player.Nick = member.Nick
+		player.BetAmt = room.BetAmt
+		players = append(players, player)
+		tips = append(tips, memberTips...)
+	}
+	if len(players) == 0 {
+		return nil, fmt.Errorf("no members left")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wr := &ponggame.WaitingRoom{
+		Ctx:          ctx,
+		Cancel:       cancel,
+		ID:           room.ID,
+		HostID:       players[0].ID,
+		Players:      players,
+		BetAmount:    room.BetAmt,
+		ReservedTips: tips,
+		Rules:        ponggame.GameRules{MaxScore: room.MaxScore, BestOf: room.BestOf},
+		Private:      room.Private,
+		InviteCode:   room.InviteCode,
+		Invited:      make(map[zkidentity.ShortID]struct{}),
+		Locked:       room.Locked,
+		CreatedAt:    room.CreatedAt,
+		LastActivity: time.Now(),
+	}
+	s.setRoomRake(wr)
+	for _, p := range players {
+		if *p.ID == hostID {
+			wr.HostID = p.ID
+		}
+		p.WR = wr
+	}
+	for _, b := range room.Invited {
+		var id zkidentity.ShortID
+		if id.FromBytes(b) == nil {
+			wr.Invited[id] = struct{}{}
+		}
+	}
+	for _, b := range room.Banned {
+		var id zkidentity.ShortID
+		if id.FromBytes(b) == nil {
+			wr.Ban(id)
+		}
+	}
+	return wr, nil
+}
+
+// reservedTips returns the tips of a player with the given sequence ids that
+// are still unpaid.
+func (s *Server) reservedTips(ctx context.Context, uid zkidentity.ShortID, ids []uint64) ([]*types.ReceivedTip, error) {
+	unpaid, err := s.db.FetchReceivedTipsByUID(ctx, uid, serverdb.StatusUnpaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tips of %s: %v", uid, err)
+	}
+	byID := make(map[uint64]*types.ReceivedTip, len(unpaid))
+	for _, tip := range unpaid {
+		byID[tip.SequenceId] = tip
+	}
+	tips := make([]*types.ReceivedTip, 0, len(ids))
+	for _, id := range ids {
+		if tip, ok := byID[id]; ok {
+			tips = append(tips, tip)
+		}
+	}
+	return tips, nil
+}
+
+// checkRestoredRoom waits for the players of a restored waiting room to
+// reconnect. Once they are all back or the grace period ends, players that
+// didn't return are removed and refunded. It returns whether the room is
+// still waiting for players and whether it was closed because nobody
+// returned.
+func (s *Server) checkRestoredRoom(wr *ponggame.WaitingRoom, now time.Time) (waiting, closed bool) {
+	v, ok := s.restoredRooms.Load(wr.ID)
+	if !ok {
+		return false, false
+	}
+	var absent []*ponggame.Player
+	for _, p := range wr.GetPlayers() {
+		if p.NotifierStream == nil {
+			absent = append(absent, p)
+		}
+	}
+	if len(absent) > 0 && now.Before(v.(time.Time)) {
+		return true, false
+	}
+	s.restoredRooms.Delete(wr.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	for _, p := range absent {
+		s.log.Infof("Player %s did not return to restored waiting room %s", p.ID, wr.ID)
+		wr.RemovePlayer(*p.ID)
+		s.releaseStake(ctx, wr.ID, *p.ID)
+		p.WR = nil
+		s.gameManager.PlayerSessions.RemovePlayer(*p.ID)
+		if err := s.handleReturnUnprocessedTips(ctx, *p.ID); err != nil {
+			s.log.Errorf("Error returning tips of %s: %v", p.ID, err)
+		}
+	}
+
+	remaining := wr.GetPlayers()
+	if len(remaining) == 0 {
+		s.log.Infof("Nobody returned to restored waiting room %s", wr.ID)
+		wr.Cancel()
+		s.gameManager.RemoveWaitingRoom(wr.ID)
+		return false, true
+	}
+
+	wr.RLock()
+	hostID := wr.HostID
+	wr.RUnlock()
+	if wr.GetPlayer(hostID) == nil {
+		next := wr.NextHost()
+		if next != nil && wr.TransferHost(*next.ID) == nil {
+			s.notifyRoom(wr, remaining, pong.NotificationType_HOST_TRANSFERRED,
+				fmt.Sprintf("Host did not return. %s is now the host", next.Nick))
+		}
+	}
+	for _, p := range absent {
+		s.notifyRoom(wr, remaining, pong.NotificationType_PLAYER_EVICTED,
+			fmt.Sprintf("Player %s did not return after the restart", p.Nick))
+	}
+	wr.Touch()
+	return false, false
+}
+
+// notifyRestoredRoom tells a reconnecting player about the waiting room they
+// were in before the restart.
+func (s *Server) notifyRestoredRoom(player *ponggame.Player) {
+	wr := player.WR
+	if wr == nil {
+		return
+	}
+	if _, ok := s.restoredRooms.Load(wr.ID); !ok {
+		return
+	}
+	pwr, err := wr.Marshal()
+	if err != nil {
+		s.log.Errorf("Failed to marshal waiting room: %v", err)
+		return
+	}
+	player.NotifierStream.Send(&pong.NtfnStreamResponse{
+		NotificationType: pong.NotificationType_WR_RESTORED,
+		Message:          "Your waiting room was restored after a server restart",
+		PlayerId:         player.ID.String(),
+		RoomId:           wr.ID,
+		Wr:               pwr,
+	})
+}
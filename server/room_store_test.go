@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+// storeRestoreRoom persists a paired room holding both challenge players and
+// wipes the in-memory state, as if the server had restarted.
+func storeRestoreRoom(t *testing.T, srv *Server) (*ponggame.WaitingRoom, zkidentity.ShortID, zkidentity.ShortID) {
+	t.Helper()
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	tips, err := srv.reservedTips(ctx, p1ID, []uint64{400})
+	require.NoError(t, err)
+	guestTips, err := srv.reservedTips(ctx, p2ID, []uint64{401})
+	require.NoError(t, err)
+	wr, err := srv.createPairedRoom(p1, p2, 50000000000, ponggame.DefaultGameRules(), append(tips, guestTips...))
+	require.NoError(t, err)
+	require.NotNil(t, srv.persistRoom(ctx, wr, nil))
+
+	srv.gameManager.WaitingRooms = nil
+	srv.gameManager.PlayerSessions.Sessions = make(map[zkidentity.ShortID]*ponggame.Player)
+	return wr, p1ID, p2ID
+}
+
+func TestRestoreWaitingRooms(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	stored, p1ID, p2ID := storeRestoreRoom(t, srv)
+	now := time.Now()
+
+	require.NoError(t, srv.restoreWaitingRooms(ctx, now))
+	wr := srv.gameManager.GetWaitingRoom(stored.ID)
+	require.NotNil(t, wr)
+	require.True(t, wr.IsHost(p1ID))
+	require.Len(t, wr.GetPlayers(), 2)
+	require.Len(t, wr.ReservedTips, 2)
+	require.Equal(t, stored.InviteCode, wr.InviteCode)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+	require.Equal(t, wr, p2.WR)
+
+	// The room waits for its players during the grace period.
+	waiting, closed := srv.checkRestoredRoom(wr, now)
+	require.True(t, waiting)
+	require.False(t, closed)
+
+	// A reconnecting player is told about the restored room.
+	p2.NotifierStream = &mockNotifierStream{}
+	srv.notifyRestoredRoom(p2)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Len(t, msgs, 1)
+	require.Equal(t, pong.NotificationType_WR_RESTORED, msgs[0].NotificationType)
+	require.Equal(t, wr.ID, msgs[0].Wr.Id)
+
+	// The host didn't return in time: they are refunded and the guest takes
+	// over the room.
+	waiting, closed = srv.checkRestoredRoom(wr, now.Add(srv.roomRestoreGrace()))
+	require.False(t, waiting)
+	require.False(t, closed)
+	require.True(t, wr.IsHost(p2ID))
+	require.Len(t, wr.GetPlayers(), 1)
+	require.Nil(t, srv.gameManager.PlayerSessions.GetPlayer(p1ID))
+	srv.processPayouts(ctx, now)
+	bot := srv.bot.(*minimalTestBot)
+	require.Contains(t, bot.paidTips, p1ID.String())
+	require.NotContains(t, bot.paidTips, p2ID.String())
+	msgs = p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PLAYER_EVICTED, msgs[len(msgs)-1].NotificationType)
+}
+
+func TestRestoreWaitingRoomsNobodyReturns(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	stored, p1ID, p2ID := storeRestoreRoom(t, srv)
+	now := time.Now()
+
+	require.NoError(t, srv.restoreWaitingRooms(ctx, now))
+	wr := srv.gameManager.GetWaitingRoom(stored.ID)
+	require.NotNil(t, wr)
+
+	_, closed := srv.checkRestoredRoom(wr, now.Add(srv.roomRestoreGrace()))
+	require.True(t, closed)
+	require.Nil(t, srv.gameManager.GetWaitingRoom(stored.ID))
+	srv.processPayouts(ctx, now)
+	bot := srv.bot.(*minimalTestBot)
+	require.Contains(t, bot.paidTips, p1ID.String())
+	require.Contains(t, bot.paidTips, p2ID.String())
+
+	// Closed rooms are dropped from the db.
+	srv.forgetRoom(ctx, wr)
+	rooms, err := srv.db.FetchWaitingRooms(ctx)
+	require.NoError(t, err)
+	require.Empty(t, rooms)
+}
+
+func TestRestoreWaitingRoomsDropsSpentStakes(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	stored, p1ID, _ := storeRestoreRoom(t, srv)
+
+	// The host's stake was released and refunded before the restart.
+	srv.releaseStake(ctx, stored.ID, p1ID)
+	require.NoError(t, srv.handleReturnUnprocessedTips(ctx, p1ID))
+
+	require.NoError(t, srv.restoreWaitingRooms(ctx, time.Now()))
+	require.Nil(t, srv.gameManager.GetWaitingRoom(stored.ID))
+	rooms, err := srv.db.FetchWaitingRooms(ctx)
+	require.NoError(t, err)
+	require.Empty(t, rooms)
+}
+
+func TestRestoreWaitingRoomsKeepsEscrowedStakes(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+	require.NoError(t, srv.db.StoreUnprocessedTip(ctx, &types.ReceivedTip{
+		Uid:          p2ID[:],
+		AmountMatoms: 50000000000,
+		SequenceId:   402,
+	}))
+
+	tips, err := srv.fetchStakeTips(ctx, p1ID, 50000000000)
+	require.NoError(t, err)
+	guestTips, err := srv.fetchStakeTips(ctx, p2ID, 50000000000)
+	require.NoError(t, err)
+	require.Len(t, guestTips, 2)
+	wr, err := srv.createPairedRoom(p1, p2, 50000000000, ponggame.DefaultGameRules(), append(tips, guestTips...))
+	require.NoError(t, err)
+	require.NotNil(t, srv.persistRoom(ctx, wr, nil))
+
+	// Withdrawing the rest of the balance spends one of the tips stored
+	// with the room, but the stake is still held in escrow.
+	_, err = srv.Withdraw(ctx, &pong.WithdrawRequest{ClientId: p2ID.String()})
+	require.NoError(t, err)
+
+	srv.gameManager.WaitingRooms = nil
+	srv.gameManager.PlayerSessions.Sessions = make(map[zkidentity.ShortID]*ponggame.Player)
+	require.NoError(t, srv.restoreWaitingRooms(ctx, time.Now()))
+	restored := srv.gameManager.GetWaitingRoom(wr.ID)
+	require.NotNil(t, restored)
+	require.Len(t, restored.GetPlayers(), 2)
+	_, reserved, err := srv.fetchPlayerBalance(ctx, p2ID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(50000000000), reserved)
+}
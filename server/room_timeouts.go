@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+const (
+	// defaultReadyTimeout is how long players of a full waiting room have
+	// to get ready before idle players are evicted.
+	defaultReadyTimeout = time.Minute
+
+	// defaultRoomTTL is how long a waiting room may go without activity
+	// before it expires.
+	defaultRoomTTL = 30 * time.Minute
+)
+
+func (s *Server) roomReadyTimeout() time.Duration {
+	if s.readyTimeout > 0 {
+		return s.readyTimeout
+	}
+	return defaultReadyTimeout
+}
+
+func (s *Server) roomTTL() time.Duration {
+	if s.waitingRoomTTL > 0 {
+		return s.waitingRoomTTL
+	}
+	return defaultRoomTTL
+}
+
+// checkRoomTimeouts starts the ready check of a full waiting room, evicts its
+// idle players once the check times out and expires rooms without activity.
+// It returns true if the room was closed.
+func (s *Server) checkRoomTimeouts(wr *ponggame.WaitingRoom, now time.Time) bool {
+	// Tournament matches have their own no-show deadline.
+	if s.isTournamentRoom(wr.ID) {
+		return false
+	}
+
+	players := wr.GetPlayers()
+	if len(players) >= 2 {
+		wr.Lock()
+		deadline := wr.ReadyDeadline
+		started := deadline.IsZero()
+		if started {
+			deadline = now.Add(s.roomReadyTimeout())
+			wr.ReadyDeadline = deadline
+		}
+		wr.Unlock()
+
+		if started {
+			s.notifyRoom(wr, players, pong.NotificationType_READY_CHECK,
+				fmt.Sprintf("Room is full. Get ready within %s or you will be removed.", s.roomReadyTimeout()))
+			return false
+		}
+		if now.Before(deadline) {
+			return false
+		}
+		return s.evictIdlePlayers(wr)
+	}
+
+	wr.Lock()
+	wr.ReadyDeadline = time.Time{}
+	idle := now.Sub(wr.LastActivity)
+	wr.Unlock()
+	if idle < s.roomTTL() {
+		return false
+	}
+
+	s.log.Infof("Waiting room %s expired after %s without activity", wr.ID, idle.Truncate(time.Second))
+	s.notifyRoom(wr, players, pong.NotificationType_WR_EXPIRED,
+		"Waiting room expired due to inactivity")
+	s.closeWaitingRoom(wr)
+	return true
+}
+
+// evictIdlePlayers removes the players that didn't get ready in time,
+// releasing their reserved tips. The room is closed if the host is evicted.
+// It returns true if the room was closed.
+func (s *Server) evictIdlePlayers(wr *ponggame.WaitingRoom) bool {
+	idle := wr.UnreadyPlayers()
+	hostEvicted := false
+	for _, player := range idle {
+		if *player.ID == *wr.HostID {
+			hostEvicted = true
+		}
+		s.log.Infof("Evicting idle player %s from waiting room %s", player.ID, wr.ID)
+		wr.RemovePlayer(*player.ID)
+		player.WR = nil
+		s.releaseStake(context.Background(), wr.ID, *player.ID)
+		if player.NotifierStream != nil {
+			player.NotifierStream.Send(&pong.NtfnStreamResponse{
+				NotificationType: pong.NotificationType_PLAYER_EVICTED,
+				Message:          "You were removed from the waiting room for not getting ready",
+				PlayerId:         player.ID.String(),
+				RoomId:           wr.ID,
+			})
+		}
+	}
+
+	remaining := wr.GetPlayers()
+	if hostEvicted {
+		s.notifyRoom(wr, remaining, pong.NotificationType_WR_EXPIRED,
+			"Host was removed for not getting ready. Room closed.")
+		s.closeWaitingRoom(wr)
+		return true
+	}
+
+	wr.Lock()
+	wr.ReadyDeadline = time.Time{}
+	wr.Unlock()
+	for _, player := range idle {
+		s.notifyRoom(wr, remaining, pong.NotificationType_PLAYER_EVICTED,
+			fmt.Sprintf("Player %s was removed for not getting ready", player.Nick))
+	}
+	return false
+}
+
+// closeWaitingRoom removes a waiting room, releasing the reserved tips and
+// the ready status of its players.
+func (s *Server) closeWaitingRoom(wr *ponggame.WaitingRoom) {
+	for _, player := range wr.GetPlayers() {
+		player.WR = nil
+		if player.Ready {
+			s.unreadyPlayer(player)
+		}
+	}
+	wr.Cancel()
+	s.gameManager.RemoveWaitingRoom(wr.ID)
+	s.releaseStakes(context.Background(), wr.ID)
+}
+
+// unreadyPlayer closes the game stream a player opened to signal readiness.
+func (s *Server) unreadyPlayer(player *ponggame.Player) {
+	player.Ready = false
+	if cancel, ok := s.activeGameStreams.Load(*player.ID); ok {
+		if cancelFn, isCancel := cancel.(context.CancelFunc); isCancel {
+			cancelFn()
+		}
+	}
+	s.activeGameStreams.Delete(*player.ID)
+	player.GameStream = nil
+}
+
+// notifyRoom sends a notification with the current room state to players.
+func (s *Server) notifyRoom(wr *ponggame.WaitingRoom, players []*ponggame.Player, typ pong.NotificationType, msg string) {
+	pwr, err := wr.Marshal()
+	if err != nil {
+		s.log.Errorf("Failed to marshal waiting room: %v", err)
+		return
+	}
+	for _, player := range players {
+		if player.NotifierStream == nil {
+			continue
+		}
+		player.NotifierStream.Send(&pong.NtfnStreamResponse{
+			NotificationType: typ,
+			Message:          msg,
+			PlayerId:         player.ID.String(),
+			RoomId:           wr.ID,
+			Wr:               pwr,
+		})
+	}
+}
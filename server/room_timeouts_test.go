@@ -0,0 +1,78 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+)
+
+func TestReadyCheckEvictsIdlePlayers(t *testing.T) {
+	srv := setupTestServer(t)
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	tips := []*types.ReceivedTip{
+		{Uid: p1ID[:], AmountMatoms: 50000000000},
+		{Uid: p2ID[:], AmountMatoms: 50000000000},
+	}
+	wr, err := srv.createPairedRoom(p1, p2, 50000000000, ponggame.DefaultGameRules(), tips)
+	require.NoError(t, err)
+	p1.Ready = true
+
+	now := time.Now()
+	require.False(t, srv.checkRoomTimeouts(wr, now))
+	require.Equal(t, now.Add(defaultReadyTimeout), wr.ReadyDeadline)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_READY_CHECK, msgs[len(msgs)-1].NotificationType)
+
+	// Nothing happens before the deadline.
+	require.False(t, srv.checkRoomTimeouts(wr, now.Add(defaultReadyTimeout/2)))
+	require.Len(t, wr.GetPlayers(), 2)
+
+	require.False(t, srv.checkRoomTimeouts(wr, now.Add(defaultReadyTimeout)))
+	require.Equal(t, []*ponggame.Player{p1}, wr.GetPlayers())
+	require.Nil(t, p2.WR)
+	require.Len(t, wr.ReservedTips, 1)
+	require.True(t, wr.ReadyDeadline.IsZero())
+	msgs = p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PLAYER_EVICTED, msgs[len(msgs)-1].NotificationType)
+	msgs = p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PLAYER_EVICTED, msgs[len(msgs)-1].NotificationType)
+}
+
+func TestWaitingRoomExpires(t *testing.T) {
+	srv := setupTestServer(t)
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	// An idle host closes the room.
+	wr, err := srv.createPairedRoom(p1, p2, 0, ponggame.DefaultGameRules(), nil)
+	require.NoError(t, err)
+	now := time.Now()
+	require.False(t, srv.checkRoomTimeouts(wr, now))
+	require.True(t, srv.checkRoomTimeouts(wr, now.Add(defaultReadyTimeout)))
+	require.Nil(t, srv.gameManager.GetWaitingRoom(wr.ID))
+	require.Nil(t, p1.WR)
+	require.Nil(t, p2.WR)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_PLAYER_EVICTED, msgs[len(msgs)-1].NotificationType)
+
+	// Rooms waiting for an opponent expire after the TTL.
+	wr, err = ponggame.NewWaitingRoom(p1, 0)
+	require.NoError(t, err)
+	p1.WR = wr
+	srv.gameManager.WaitingRooms = append(srv.gameManager.WaitingRooms, wr)
+	require.False(t, srv.checkRoomTimeouts(wr, wr.LastActivity.Add(defaultRoomTTL/2)))
+	require.True(t, srv.checkRoomTimeouts(wr, wr.LastActivity.Add(defaultRoomTTL)))
+	require.Nil(t, srv.gameManager.GetWaitingRoom(wr.ID))
+	require.Nil(t, p1.WR)
+	require.Error(t, wr.Ctx.Err())
+	msgs = p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_WR_EXPIRED, msgs[len(msgs)-1].NotificationType)
+}
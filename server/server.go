@@ -2,21 +2,27 @@ package server
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"net/http"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/slog"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/vctt94/bisonbotkit"
 	"github.com/vctt94/bisonbotkit/logging"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/ponggame"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/receipt"
 	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+	"google.golang.org/grpc/health"
 )
 
 const (
@@ -30,6 +36,7 @@ type BotInterface interface {
 	AckTipProgress(ctx context.Context, sequenceId uint64) error
 	AckTipReceived(ctx context.Context, sequenceId uint64) error
 	PayTip(ctx context.Context, recipient zkidentity.ShortID, amount dcrutil.Amount, priority int32) error
+	SendPM(ctx context.Context, nick string, msg string) error
 }
 
 type ServerConfig struct {
@@ -37,7 +44,7 @@ type ServerConfig struct {
 
 	Bot *bisonbotkit.Bot
 
-	MinBetAmt             float64
+	MinBetAmt             matoms.Amount
 	IsF2P                 bool
 	DebugLevel            string
 	DebugGameManagerLevel string
@@ -45,6 +52,39 @@ type ServerConfig struct {
 	ChatClient            types.ChatServiceClient
 	HTTPPort              string
 	LogBackend            *logging.LogBackend
+
+	// SeasonLength is how long a leaderboard season lasts before its
+	// standings are archived and ratings reset.
+	SeasonLength time.Duration
+
+	// ReadyTimeout is how long players of a full waiting room have to get
+	// ready before idle players are evicted.
+	ReadyTimeout time.Duration
+
+	// RoomTTL is how long a waiting room may go without activity before
+	// it expires.
+	RoomTTL time.Duration
+
+	// RestoreGrace is how long players of waiting rooms restored after a
+	// restart have to reconnect before they are refunded.
+	RestoreGrace time.Duration
+
+	// RakePercent is the share of the pot, in percent, the house keeps
+	// from games that end with a winner. RakeFee is a flat fee added to
+	// it and RakeCap, if set, the most taken from a single pot.
+	// Free-to-play servers are never raked.
+	RakePercent float64
+	RakeFee     matoms.Amount
+	RakeCap     matoms.Amount
+
+	// CreditGrant is the balance of virtual credits free-to-play players
+	// are topped up to once a day.
+	CreditGrant matoms.Amount
+
+	// AdminTokens maps the names of the operators allowed to use the admin
+	// HTTP API to their bearer tokens. The admin API is disabled when it is
+	// empty.
+	AdminTokens map[string]string
 }
 
 type Server struct {
@@ -54,12 +94,65 @@ type Server struct {
 	bot                BotInterface
 	log                slog.Logger
 	isF2P              bool
-	minBetAmt          float64
+	minBetAmt          matoms.Amount
+	seasonLength       time.Duration
+	readyTimeout       time.Duration
+	waitingRoomTTL     time.Duration
+	restoreGrace       time.Duration
+	rake               rakeConfig
+	creditGrant        matoms.Amount
 	waitingRoomCreated chan struct{}
 
+	// managedRooms holds the ids of waiting rooms with a running
+	// ManageWaitingRoom.
+	managedRooms sync.Map
+
+	// shuttingDown is set once Shutdown starts. Waiting rooms and their
+	// reservations are kept in the db from then on so they can be restored.
+	shuttingDown atomic.Bool
+
+	// restoredRooms maps the ids of waiting rooms restored from the db to
+	// the deadline their players have to reconnect.
+	restoredRooms sync.Map
+
 	users       map[zkidentity.ShortID]*ponggame.Player
 	gameManager *ponggame.GameManager
 
+	challengesMtx sync.Mutex
+	challenges    map[string]*challenge
+	// challengeSent is when each player last sent a challenge.
+	challengeSent map[zkidentity.ShortID]time.Time
+
+	rematchesMtx sync.Mutex
+	rematches    map[zkidentity.ShortID]*rematch
+
+	tournamentsMtx     sync.Mutex
+	tournaments        map[string]*tournament
+	tournamentRooms    map[string]tournamentRoom
+	tournamentSchedMtx sync.Mutex
+
+	chatMtx sync.Mutex
+	chats   map[zkidentity.ShortID]*chatState
+
+	// payoutsMtx serializes updates to the payout queue. It isn't held
+	// while payouts are sent.
+	payoutsMtx sync.Mutex
+	payoutWake chan struct{}
+
+	// receiptKey signs the receipts of finished matches.
+	receiptKey ed25519.PrivateKey
+
+	// adminTokens maps operator names to the tokens of the admin API.
+	adminTokens map[string]string
+
+	metrics *serverMetrics
+
+	// health is the grpc.health.v1 service, and botErr why the bot
+	// stopped running.
+	health *health.Server
+	botMtx sync.Mutex
+	botErr error
+
 	httpServer        *http.Server
 	activeNtfnStreams sync.Map
 	activeGameStreams sync.Map
@@ -70,6 +163,16 @@ type Server struct {
 
 func NewServer(id *zkidentity.ShortID, cfg ServerConfig) (*Server, error) {
 
+	rake, err := newRakeConfig(cfg.RakePercent, cfg.RakeFee, cfg.RakeCap)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptKey, err := receipt.LoadOrCreateKey(filepath.Join(cfg.ServerDir, receiptKeyFile))
+	if err != nil {
+		return nil, err
+	}
+
 	dbPath := filepath.Join(cfg.ServerDir, "server.db")
 	db, err := serverdb.NewBoltDB(dbPath)
 	if err != nil {
@@ -86,6 +189,14 @@ func NewServer(id *zkidentity.ShortID, cfg ServerConfig) (*Server, error) {
 		MaxBufferLines: 1000,
 	})
 	logGM := bknd.Logger("GM")
+	seasonLength := cfg.SeasonLength
+	if seasonLength == 0 {
+		seasonLength = defaultSeasonLength
+	}
+	creditGrant := cfg.CreditGrant
+	if creditGrant == 0 {
+		creditGrant = defaultCreditGrant
+	}
 	s := &Server{
 		appdata:            cfg.ServerDir,
 		bot:                cfg.Bot,
@@ -93,8 +204,23 @@ func NewServer(id *zkidentity.ShortID, cfg ServerConfig) (*Server, error) {
 		db:                 db,
 		isF2P:              cfg.IsF2P,
 		minBetAmt:          cfg.MinBetAmt,
+		seasonLength:       seasonLength,
+		readyTimeout:       cfg.ReadyTimeout,
+		waitingRoomTTL:     cfg.RoomTTL,
+		restoreGrace:       cfg.RestoreGrace,
+		rake:               rake,
+		creditGrant:        creditGrant,
+		receiptKey:         receiptKey,
+		adminTokens:        cfg.AdminTokens,
+		health:             newHealthServer(),
 		waitingRoomCreated: make(chan struct{}, 1),
+		payoutWake:         make(chan struct{}, 1),
 		users:              make(map[zkidentity.ShortID]*ponggame.Player),
+		challenges:         make(map[string]*challenge),
+		rematches:          make(map[zkidentity.ShortID]*rematch),
+		tournaments:        make(map[string]*tournament),
+		tournamentRooms:    make(map[string]tournamentRoom),
+		chats:              make(map[zkidentity.ShortID]*chatState),
 		gameManager: &ponggame.GameManager{
 			ID:             id,
 			Games:          make(map[string]*ponggame.GameInstance),
@@ -105,16 +231,29 @@ func NewServer(id *zkidentity.ShortID, cfg ServerConfig) (*Server, error) {
 		},
 	}
 	s.gameManager.OnWaitingRoomRemoved = s.handleWaitingRoomRemoved
+	s.metrics = newServerMetrics(s)
+	s.gameManager.OnFrameDropped = s.metrics.framesDropped.Inc
+	s.log.Infof("Match receipts are signed with key %x", []byte(receiptKey.Public().(ed25519.PublicKey)))
+
+	if err := s.restoreWaitingRooms(context.Background(), time.Now()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to restore waiting rooms: %w", err)
+	}
+	if err := s.releaseOrphanedStakes(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to release interrupted stakes: %w", err)
+	}
+	if err := s.checkLedger(context.Background()); err != nil {
+		s.log.Errorf("Ledger check failed: %v", err)
+	}
 
 	if cfg.HTTPPort != "" {
-		// Set up HTTP server for db calls
-		mux := http.NewServeMux()
-		mux.HandleFunc("/received", s.handleFetchTipsByClientIDHandler)
-		mux.HandleFunc("/fetchAllUnprocessedTips", s.handleFetchAllUnprocessedTipsHandler)
-		mux.HandleFunc("/tipprogress", s.handleGetSendProgressByWinnerHandler)
+		if len(cfg.AdminTokens) == 0 {
+			s.log.Warnf("No admin tokens configured, the admin API is disabled")
+		}
 		s.httpServer = &http.Server{
 			Addr:    fmt.Sprintf(":%s", cfg.HTTPPort),
-			Handler: mux,
+			Handler: s.httpHandler(),
 		}
 
 		go func() {
@@ -128,6 +267,37 @@ func NewServer(id *zkidentity.ShortID, cfg ServerConfig) (*Server, error) {
 	return s, nil
 }
 
+// httpHandler routes the endpoints of the HTTP port.
+func (s *Server) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/leaderboard", s.handleLeaderboardHandler)
+	mux.HandleFunc("/healthz", s.handleHealthzHandler)
+	mux.HandleFunc("/readyz", s.handleReadyzHandler)
+	mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
+
+	// Operator endpoints require an admin token.
+	mux.HandleFunc("/received", s.requireAdmin(s.handleFetchTipsByClientIDHandler))
+	mux.HandleFunc("/fetchAllUnprocessedTips", s.requireAdmin(s.handleFetchAllUnprocessedTipsHandler))
+	mux.HandleFunc("/tipprogress", s.requireAdmin(s.handleGetSendProgressByWinnerHandler))
+	mux.HandleFunc("/tournament/create", s.requireAdmin(s.handleCreateTournamentHandler))
+	mux.HandleFunc("/rake", s.requireAdmin(s.handleRakeHandler))
+	mux.HandleFunc("/payouts", s.requireAdmin(s.handlePayoutsHandler))
+	mux.HandleFunc("/payouts/retry", s.requireAdmin(s.handleRetryPayoutHandler))
+	mux.HandleFunc("/audit/export", s.requireAdmin(s.handleAuditExportHandler))
+	mux.HandleFunc("/audit/reconcile", s.requireAdmin(s.handleReconcileHandler))
+	mux.HandleFunc("/admin/sessions", s.requireAdmin(s.handleAdminSessionsHandler))
+	mux.HandleFunc("/admin/rooms", s.requireAdmin(s.handleAdminRoomsHandler))
+	mux.HandleFunc("/admin/rooms/cancel", s.requireAdmin(s.handleAdminCancelRoomHandler))
+	mux.HandleFunc("/admin/games", s.requireAdmin(s.handleAdminGamesHandler))
+	mux.HandleFunc("/admin/games/end", s.requireAdmin(s.handleAdminEndGameHandler))
+	mux.HandleFunc("/admin/payouts/mark", s.requireAdmin(s.handleAdminMarkPayoutHandler))
+	mux.HandleFunc("/admin/bans", s.requireAdmin(s.handleAdminBansHandler))
+	mux.HandleFunc("/admin/ban", s.requireAdmin(s.handleAdminBanHandler))
+	mux.HandleFunc("/admin/unban", s.requireAdmin(s.handleAdminUnbanHandler))
+	mux.HandleFunc("/admin/actions", s.requireAdmin(s.handleAdminActionsHandler))
+	return mux
+}
+
 func (s *Server) StartGameStream(req *pong.StartGameStreamRequest, stream pong.PongGame_StartGameStreamServer) error {
 	ctx, cancel := context.WithCancel(stream.Context())
 	defer cancel()
@@ -151,12 +321,15 @@ func (s *Server) StartGameStream(req *pong.StartGameStreamRequest, stream pong.P
 	if player.GameStream != nil {
 		return fmt.Errorf("game stream is already set for id %s", clientID)
 	}
-	if !s.isF2P && float64(player.BetAmt)/1e11 < s.minBetAmt {
-		return fmt.Errorf("player needs to place bet higher or equal to: %.8f DCR", s.minBetAmt)
+	if !s.isF2P && player.BetAmt < s.minBetAmt {
+		return fmt.Errorf("player needs to place bet higher or equal to: %s DCR", s.minBetAmt)
 	}
 
 	player.GameStream = stream
 	player.Ready = true
+	if player.WR != nil {
+		player.WR.Touch()
+	}
 
 	// Notify all players in the waiting room that this player is ready
 	if player.WR != nil {
@@ -201,11 +374,19 @@ func (s *Server) handleDisconnect(clientID zkidentity.ShortID) {
 
 	// Only process tips if player exists in sessions AND is not in an active game
 	playerSession := s.gameManager.PlayerSessions.GetPlayer(clientID)
+	if playerSession != nil && playerSession.WR != nil && s.shuttingDown.Load() {
+		// Keep the waiting room and its reservation to restore them
+		// after the restart.
+		return
+	}
 	if playerSession != nil {
 		s.gameManager.PlayerSessions.RemovePlayer(clientID)
+		s.releaseRoomStake(playerSession)
 
-		// Check if player is not currently in any game
-		if s.gameManager.GetPlayerGame(clientID) == nil {
+		// Check if player is not currently in any game and their buy-in
+		// isn't held by a running tournament
+		heldByTournament := s.handleTournamentDisconnect(clientID)
+		if s.gameManager.GetPlayerGame(clientID) == nil && !heldByTournament {
 			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 			defer cancel()
 			if err := s.handleReturnUnprocessedTips(ctx, clientID); err != nil {
@@ -227,6 +408,9 @@ func (s *Server) StartNtfnStream(req *pong.StartNtfnStreamRequest, stream pong.P
 	var clientID zkidentity.ShortID
 	clientID.FromString(req.ClientId)
 	s.log.Debugf("StartNtfnStream called by client %s", clientID)
+	if err := s.checkBanned(ctx, clientID); err != nil {
+		return err
+	}
 
 	// Add to active streams
 	s.activeNtfnStreams.Store(clientID, cancel)
@@ -234,29 +418,36 @@ func (s *Server) StartNtfnStream(req *pong.StartNtfnStreamRequest, stream pong.P
 	// Create player session
 	player := s.gameManager.PlayerSessions.CreateSession(clientID)
 	player.NotifierStream = stream
+	if req.Nick != "" {
+		player.Nick = req.Nick
+	}
 
 	s.Lock()
 	s.users[clientID] = player
 	s.Unlock()
+	go s.remindSession(ctx, player)
 
-	// Fetch unprocessed tips
-	totalDcrAmount, _, err := s.handleFetchTotalUnprocessedTips(ctx, clientID)
+	// Fetch the balance of the player
+	s.grantCredits(ctx, clientID, time.Now())
+	funds, err := s.playerFunds(ctx, clientID)
 	if err != nil {
-		s.log.Errorf("Failed to fetch unprocessed tips for client %s: %v", clientID, err)
+		s.log.Errorf("Failed to fetch balance of client %s: %v", clientID, err)
 		return err
 	}
 
 	// Update player's bet amount and notify
-	if player.BetAmt != totalDcrAmount {
-		player.BetAmt = totalDcrAmount
-		s.log.Debugf("Pending payments applied to client %s, total amount: %.8f", clientID, float64(totalDcrAmount)/1e11)
+	if player.BetAmt != funds {
+		player.BetAmt = funds
+		s.log.Debugf("Pending payments applied to client %s, total amount: %s", clientID, funds)
 
 		s.users[clientID].NotifierStream.Send(&pong.NtfnStreamResponse{
 			NotificationType: pong.NotificationType_BET_AMOUNT_UPDATE,
-			BetAmt:           player.BetAmt,
+			BetAmt:           int64(player.BetAmt),
 			PlayerId:         player.ID.String(),
 		})
 	}
+	s.notifyRestoredRoom(player)
+
 	// Wait for disconnection
 	<-ctx.Done()
 	s.log.Debugf("Client %s disconnected", clientID)
@@ -265,25 +456,47 @@ func (s *Server) StartNtfnStream(req *pong.StartNtfnStreamRequest, stream pong.P
 }
 
 func (s *Server) SendInput(ctx context.Context, req *pong.PlayerInput) (*pong.GameUpdate, error) {
+	defer observeSince(s.metrics.sendInput, time.Now())
 	var clientID zkidentity.ShortID
 	clientID.FromString(req.PlayerId)
 	return s.gameManager.HandlePlayerInput(clientID, req)
 }
 
 func (s *Server) ManageWaitingRoom(ctx context.Context, wr *ponggame.WaitingRoom) error {
+	defer s.managedRooms.Delete(wr.ID)
+	defer s.forgetRoom(context.Background(), wr)
+	var stored []byte
 	for {
 		select {
 		case <-ctx.Done():
 			s.log.Infof("Exited ManageWaitingRoom: %s (context cancelled)", wr.ID)
 			return nil
 
-		case <-time.After(time.Second):
+		case now := <-time.After(time.Second):
+			if s.gameManager.GetWaitingRoom(wr.ID) == nil {
+				// Removed without being cancelled, e.g. by its last
+				// player leaving.
+				return nil
+			}
+			waiting, closed := s.checkRestoredRoom(wr, now)
+			if closed {
+				return nil
+			}
+			if !waiting && s.checkRoomTimeouts(wr, now) {
+				return nil
+			}
+			stored = s.persistRoom(ctx, wr, stored)
 			players, ready := wr.ReadyPlayers()
 			if ready {
 				s.log.Infof("Game starting with players: %v and %v", players[0].ID, players[1].ID)
 
+				if ref, ok := s.tournamentRoomMatch(wr.ID); ok {
+					s.gameManager.RemoveWaitingRoom(wr.ID)
+					go s.runTournamentMatch(ctx, ref, players, wr.Rules)
+					return nil
+				}
 				s.gameManager.RemoveWaitingRoom(wr.ID)
-				go s.handleGameLifecycle(ctx, players, wr.ReservedTips) // Start game lifecycle in a goroutine
+				go s.handleGameLifecycle(ctx, wr.ID, players, wr.BetAmount, wr.Rules) // Start game lifecycle in a goroutine
 				return nil
 			}
 		}
@@ -296,6 +509,8 @@ func (s *Server) sendGameUpdates(ctx context.Context, player *ponggame.Player, g
 		case <-ctx.Done():
 			s.handleDisconnect(*player.ID)
 			return
+		case <-game.Done():
+			return
 		case frame, ok := <-player.FrameCh: // Use individual player channel instead of shared game channel
 			if !ok {
 				return // Player's frame channel closed, exit
@@ -305,7 +520,9 @@ func (s *Server) sendGameUpdates(ctx context.Context, player *ponggame.Player, g
 				s.log.Errorf("player %s has no game stream", player.ID)
 				continue
 			}
+			start := time.Now()
 			err := player.GameStream.Send(&pong.GameUpdateBytes{Data: frame})
+			observeSince(s.metrics.frameSend, start)
 			if err != nil {
 				s.handleDisconnect(*player.ID)
 				return
@@ -315,7 +532,11 @@ func (s *Server) sendGameUpdates(ctx context.Context, player *ponggame.Player, g
 }
 
 func (s *Server) Run(ctx context.Context) error {
-	go s.bot.Run(ctx)
+	go s.runBot(ctx)
+	go s.runHealthLoop(ctx)
+	go s.runLeaderboardLoop(ctx)
+	go s.runTournamentLoop(ctx)
+	go s.runPayoutLoop(ctx)
 
 	for {
 		select {
@@ -335,34 +556,20 @@ func (s *Server) Run(ctx context.Context) error {
 
 			s.gameManager.Lock()
 			for _, wr := range s.gameManager.WaitingRooms {
-				if wr.Ctx.Err() == nil { // Only manage rooms with active contexts
-					s.log.Debugf("Managing waiting room: %s", wr.ID)
-					go s.ManageWaitingRoom(wr.Ctx, wr)
+				if wr.Ctx.Err() != nil { // Only manage rooms with active contexts
+					continue
 				}
+				if _, managed := s.managedRooms.LoadOrStore(wr.ID, struct{}{}); managed {
+					continue
+				}
+				s.log.Debugf("Managing waiting room: %s", wr.ID)
+				go s.ManageWaitingRoom(wr.Ctx, wr)
 			}
 			s.gameManager.Unlock()
 		}
 	}
 }
 
-func (s *Server) GetWaitingRooms(ctx context.Context, req *pong.WaitingRoomsRequest) (*pong.WaitingRoomsResponse, error) {
-	s.Lock()
-	defer s.Unlock()
-
-	pongWaitingRooms := make([]*pong.WaitingRoom, len(s.gameManager.WaitingRooms))
-	for i, room := range s.gameManager.WaitingRooms {
-		wr, err := room.Marshal()
-		if err != nil {
-			return nil, err
-		}
-		pongWaitingRooms[i] = wr
-	}
-
-	return &pong.WaitingRoomsResponse{
-		Wr: pongWaitingRooms,
-	}, nil
-}
-
 func (s *Server) JoinWaitingRoom(ctx context.Context, req *pong.JoinWaitingRoomRequest) (*pong.JoinWaitingRoomResponse, error) {
 	var uid zkidentity.ShortID
 	err := uid.FromString(req.ClientId)
@@ -390,26 +597,43 @@ func (s *Server) JoinWaitingRoom(ctx context.Context, req *pong.JoinWaitingRoomR
 	if wr == nil {
 		return nil, fmt.Errorf("waiting room not found: %s", req.RoomId)
 	}
+	if s.tournamentHoldsStake(uid) {
+		return nil, fmt.Errorf("player %s is registered in a tournament", req.ClientId)
+	}
+	if wr.IsBanned(uid) {
+		return nil, fmt.Errorf("player %s was removed from waiting room %s by the host", req.ClientId, req.RoomId)
+	}
+	if wr.IsLocked() {
+		return nil, fmt.Errorf("waiting room %s is locked", req.RoomId)
+	}
+	if wr.IsFull(roomSeats) {
+		return nil, fmt.Errorf("waiting room %s is full", req.RoomId)
+	}
+	if !wr.CanJoin(uid, req.InviteCode) {
+		return nil, fmt.Errorf("waiting room %s is private", req.RoomId)
+	}
+	wr.RLock()
+	betAmt := wr.BetAmount
+	wr.RUnlock()
+	if err := s.checkLimits(ctx, uid, betAmt); err != nil {
+		return nil, err
+	}
 
 	// Fetch and reserve joining player's tips
-	tips, err := s.db.FetchReceivedTipsByUID(ctx, uid, serverdb.StatusUnpaid)
+	tips, err := s.fetchStakeTips(ctx, uid, betAmt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch player tips: %v", err)
+		return nil, err
 	}
-
-	// Calculate total from tips
-	totalBet := int64(0)
-	for _, tip := range tips {
-		totalBet += tip.AmountMatoms
+	if err := s.reserveStake(ctx, wr.ID, uid, betAmt); err != nil {
+		return nil, err
 	}
 
-	// Validate bet amount matches
-	if totalBet != wr.BetAmount {
-		return nil, fmt.Errorf("bet amount mismatch. Available: %.8f, Required: %.8f",
-			float64(totalBet)/1e11, float64(wr.BetAmount)/1e11)
+	// The last seat may have been taken or the bet changed by the host
+	// since the checks above.
+	if err := wr.TakeSeat(player, roomSeats, betAmt); err != nil {
+		s.releaseStake(ctx, wr.ID, uid)
+		return nil, err
 	}
-
-	wr.AddPlayer(player)
 	player.WR = wr
 
 	wr.Lock()
@@ -450,46 +674,62 @@ func (s *Server) CreateWaitingRoom(ctx context.Context, req *pong.CreateWaitingR
 	if hostPlayer == nil {
 		return nil, fmt.Errorf("player not found: %s", req.HostId)
 	}
-	if hostPlayer.BetAmt != req.BetAmt {
-		return nil, fmt.Errorf("server and request mismatch. request amt: %.8f, server amt: %.8f",
-			float64(req.BetAmt)/1e11, float64(hostPlayer.BetAmt)/1e11)
-	}
-	if !s.isF2P && req.BetAmt == 0 {
-		return nil, fmt.Errorf("bet needs to be higher than 0")
-	}
-	if !s.isF2P && float64(req.BetAmt)/1e11 < s.minBetAmt {
-		return nil, fmt.Errorf("bet needs to be higher than %.8f", s.minBetAmt)
+	betAmt := matoms.Amount(req.BetAmt)
+	if err := s.validateBetAmt(betAmt); err != nil {
+		return nil, err
 	}
 	if hostPlayer.WR != nil {
 		return nil, fmt.Errorf("player %s is already in a waiting room", hostID.String())
 	}
+	if s.tournamentHoldsStake(hostID) {
+		return nil, fmt.Errorf("player %s is registered in a tournament", hostID.String())
+	}
+	if err := s.checkLimits(ctx, hostID, betAmt); err != nil {
+		return nil, err
+	}
 
 	s.log.Debugf("creating waiting room. Host ID: %s", hostID)
 
-	// Fetch and reserve unprocessed tips
-	tips, err := s.db.FetchReceivedTipsByUID(ctx, hostID, serverdb.StatusUnpaid)
+	// Fetch unprocessed tips backing the stake
+	tips, err := s.fetchStakeTips(ctx, hostID, betAmt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch unprocessed tips: %v", err)
+		return nil, err
 	}
 
-	// Calculate total from tips
-	totalBet := int64(0)
-	for _, tip := range tips {
-		totalBet += tip.AmountMatoms
+	rules := ponggame.GameRulesFromProto(req.Rules)
+	if err := rules.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Validate bet amount matches
-	if totalBet != req.BetAmt {
-		return nil, fmt.Errorf("bet amount mismatch. Available: %.8f, Requested: %.8f",
-			float64(totalBet)/1e11, float64(req.BetAmt)/1e11)
+	invitees, err := parseInvitees(req.Invitees, hostID)
+	if err != nil {
+		return nil, err
+	}
+	if len(invitees) > 0 && !req.Private {
+		return nil, fmt.Errorf("invitees can only be set on private rooms")
 	}
 
 	// Create waiting room with reserved tips
-	wr, err := ponggame.NewWaitingRoom(hostPlayer, req.BetAmt)
+	wr, err := ponggame.NewWaitingRoom(hostPlayer, betAmt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create waiting room: %v", err)
 	}
 
+	wr.Rules = rules
+
+	var inviteCode string
+	if req.Private {
+		inviteCode, err = wr.MakePrivate()
+		if err != nil {
+			return nil, err
+		}
+		wr.Invite(invitees...)
+	}
+
+	if err := s.reserveStake(ctx, wr.ID, hostID, betAmt); err != nil {
+		return nil, err
+	}
+	s.setRoomRake(wr)
 	wr.Lock()
 	wr.ReservedTips = tips // Store reserved tips
 	wr.Unlock()
@@ -516,24 +756,130 @@ func (s *Server) CreateWaitingRoom(ctx context.Context, req *pong.CreateWaitingR
 		return nil, err
 	}
 
-	s.RLock()
-	for _, user := range s.users {
-		if user.NotifierStream == nil {
-			s.log.Errorf("user %s without NotifierStream", user.ID)
-			continue
+	if req.Private {
+		s.sendWaitingRoomInvites(ctx, hostPlayer, wr, pongWR, invitees)
+	} else {
+		s.RLock()
+		for _, user := range s.users {
+			if user.NotifierStream == nil {
+				s.log.Errorf("user %s without NotifierStream", user.ID)
+				continue
+			}
+			user.NotifierStream.Send(&pong.NtfnStreamResponse{
+				Wr:               pongWR,
+				NotificationType: pong.NotificationType_ON_WR_CREATED,
+			})
 		}
-		user.NotifierStream.Send(&pong.NtfnStreamResponse{
-			Wr:               pongWR,
-			NotificationType: pong.NotificationType_ON_WR_CREATED,
-		})
+		s.RUnlock()
 	}
-	s.RUnlock()
 
 	return &pong.CreateWaitingRoomResponse{
-		Wr: pongWR,
+		Wr:         pongWR,
+		InviteCode: inviteCode,
+	}, nil
+}
+
+// InviteToWaitingRoom lets the host of a private waiting room invite more
+// players to it.
+func (s *Server) InviteToWaitingRoom(ctx context.Context, req *pong.InviteToWaitingRoomRequest) (*pong.InviteToWaitingRoomResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	host := s.gameManager.PlayerSessions.GetPlayer(clientID)
+	if host == nil {
+		return nil, fmt.Errorf("player not found: %s", req.ClientId)
+	}
+
+	wr := s.gameManager.GetWaitingRoom(req.RoomId)
+	if wr == nil {
+		return nil, fmt.Errorf("waiting room not found: %s", req.RoomId)
+	}
+	wr.RLock()
+	isHost := wr.HostID != nil && *wr.HostID == clientID
+	private, inviteCode := wr.Private, wr.InviteCode
+	wr.RUnlock()
+	if !isHost {
+		return nil, fmt.Errorf("only the host can invite players to waiting room %s", req.RoomId)
+	}
+	if !private {
+		return nil, fmt.Errorf("waiting room %s is not private", req.RoomId)
+	}
+
+	invitees, err := parseInvitees(req.Invitees, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if len(invitees) == 0 {
+		return nil, fmt.Errorf("no players to invite")
+	}
+	wr.Invite(invitees...)
+
+	pongWR, err := wr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	s.sendWaitingRoomInvites(ctx, host, wr, pongWR, invitees)
+
+	return &pong.InviteToWaitingRoomResponse{
+		InviteCode: inviteCode,
 	}, nil
 }
 
+// parseInvitees decodes and dedups the invited client ids, dropping the host.
+func parseInvitees(ids []string, hostID zkidentity.ShortID) ([]zkidentity.ShortID, error) {
+	seen := make(map[zkidentity.ShortID]struct{}, len(ids))
+	invitees := make([]zkidentity.ShortID, 0, len(ids))
+	for _, id := range ids {
+		var uid zkidentity.ShortID
+		if err := uid.FromString(id); err != nil {
+			return nil, fmt.Errorf("invalid invitee id %q: %v", id, err)
+		}
+		if uid == hostID {
+			continue
+		}
+		if _, ok := seen[uid]; ok {
+			continue
+		}
+		seen[uid] = struct{}{}
+		invitees = append(invitees, uid)
+	}
+	return invitees, nil
+}
+
+// sendWaitingRoomInvites notifies invited players about a private waiting
+// room, both through their notification stream when connected and through a
+// bisonrelay PM.
+func (s *Server) sendWaitingRoomInvites(ctx context.Context, host *ponggame.Player, wr *ponggame.WaitingRoom, pongWR *pong.WaitingRoom, invitees []zkidentity.ShortID) {
+	wr.RLock()
+	inviteCode := wr.InviteCode
+	wr.RUnlock()
+
+	hostName := host.Nick
+	if hostName == "" {
+		hostName = host.ID.String()
+	}
+
+	for _, uid := range invitees {
+		if player := s.gameManager.PlayerSessions.GetPlayer(uid); player != nil && player.NotifierStream != nil {
+			player.NotifierStream.Send(&pong.NtfnStreamResponse{
+				NotificationType: pong.NotificationType_WR_INVITE,
+				Message:          fmt.Sprintf("%s invited you to a private waiting room", hostName),
+				PlayerId:         host.ID.String(),
+				RoomId:           wr.ID,
+				Wr:               pongWR,
+				InviteCode:       inviteCode,
+			})
+		}
+
+		msg := fmt.Sprintf("%s invited you to a private pong room %s with a bet of %s DCR. Invite code: %s",
+			hostName, wr.ID, wr.BetAmount, inviteCode)
+		if err := s.bot.SendPM(ctx, uid.String(), msg); err != nil {
+			s.log.Warnf("failed to send waiting room invite to %s: %v", uid, err)
+		}
+	}
+}
+
 // LeaveWaitingRoom handles a request from a client to leave a waiting room
 func (s *Server) LeaveWaitingRoom(ctx context.Context, req *pong.LeaveWaitingRoomRequest) (*pong.LeaveWaitingRoomResponse, error) {
 	s.log.Debugf("LeaveWaitingRoom request from client %s for room %s", req.ClientId, req.RoomId)
@@ -564,15 +910,18 @@ func (s *Server) LeaveWaitingRoom(ctx context.Context, req *pong.LeaveWaitingRoo
 		}, nil
 	}
 
+	// If player was the host, hand the room over before they leave.
+	var newHost *ponggame.Player
+	if wr.IsHost(clientID) {
+		newHost = wr.NextHost()
+		if newHost != nil {
+			wr.TransferHost(*newHost.ID)
+		}
+	}
+
 	// Remove the player from the waiting room
 	wr.RemovePlayer(clientID)
-
-	// If player was the host and there are other players, assign a new host
-	if wr.HostID.String() == clientID.String() && len(wr.Players) > 0 {
-		wr.Lock()
-		wr.HostID = wr.Players[0].ID
-		wr.Unlock()
-	}
+	s.releaseStake(ctx, wr.ID, clientID)
 
 	// If the room is now empty, remove it
 	if len(wr.Players) == 0 {
@@ -591,6 +940,11 @@ func (s *Server) LeaveWaitingRoom(ctx context.Context, req *pong.LeaveWaitingRoo
 				})
 			}
 		}
+		if newHost != nil {
+			s.log.Infof("Host left waiting room %s, %s is the new host", wr.ID, newHost.ID)
+			s.notifyRoom(wr, wr.GetPlayers(), pong.NotificationType_HOST_TRANSFERRED,
+				fmt.Sprintf("Host left. %s is now the host", newHost.Nick))
+		}
 	}
 
 	// Reset the player's waiting room reference
@@ -620,6 +974,7 @@ func (s *Server) UnreadyGameStream(ctx context.Context, req *pong.UnreadyGameStr
 	// Check if the player is in a waiting room
 	if player.WR != nil {
 		player.Ready = false
+		player.WR.Touch()
 
 		// First get the cancel function and call it before deleting
 		if cancel, ok := s.activeGameStreams.Load(clientID); ok {
@@ -651,15 +1006,12 @@ func (s *Server) UnreadyGameStream(ctx context.Context, req *pong.UnreadyGameStr
 	return &pong.UnreadyGameStreamResponse{}, nil
 }
 
-// Shutdown forcefully shuts down the server, closing HTTP server, database, waiting rooms, and games.
+// Shutdown forcefully shuts down the server, reporting it not ready and closing
+// games, waiting rooms, the HTTP server and the database.
 func (s *Server) Shutdown(ctx context.Context) error {
-	// Stop HTTP server first
-	if s.httpServer != nil {
-		s.log.Info("Shutting down HTTP server...")
-		if err := s.httpServer.Shutdown(ctx); err != nil {
-			s.log.Errorf("Error shutting down HTTP server: %v", err)
-		}
-	}
+	s.shuttingDown.Store(true)
+	// Report not serving from now on, so clients stop being sent here.
+	s.health.Shutdown()
 
 	// Forcefully terminate all active games
 	s.log.Info("Terminating all active games...")
@@ -703,6 +1055,15 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	s.users = nil
 	s.Unlock()
 
+	// Stop the HTTP server once games are torn down, so /readyz reports
+	// not ready meanwhile.
+	if s.httpServer != nil {
+		s.log.Info("Shutting down HTTP server...")
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			s.log.Errorf("Error shutting down HTTP server: %v", err)
+		}
+	}
+
 	// Close database LAST after all operations are done
 	s.log.Info("Closing database...")
 	if err := s.db.Close(); err != nil {
@@ -731,9 +1092,9 @@ func (s *Server) SignalReadyToPlay(ctx context.Context, req *pong.SignalReadyToP
 	}
 
 	// Mark this player as ready in the game
-	game.Lock()
-	game.PlayersReady[req.ClientId] = true
-	game.Unlock()
+	if err := game.SetPlayerReady(clientID.String(), req.Entropy); err != nil {
+		return nil, fmt.Errorf("invalid ready signal: %w", err)
+	}
 
 	// Notify all players in the game that this player is ready
 	for _, p := range game.Players {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vctt94/bisonbotkit/logging"
 	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/ponggame"
 	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
 	"github.com/vctt94/pong-bisonrelay/server/serverdb"
@@ -26,6 +28,15 @@ type minimalTestBot struct {
 	ackedTipProgress map[uint64]bool
 	ackedTipReceived map[uint64]bool
 	paidTips         map[string]dcrutil.Amount
+	sentPMs          []sentPM
+
+	// payTipErr, if set, fails every PayTip call.
+	payTipErr error
+}
+
+type sentPM struct {
+	nick string
+	msg  string
 }
 
 func newMinimalTestBot() *minimalTestBot {
@@ -59,10 +70,20 @@ func (b *minimalTestBot) AckTipReceived(ctx context.Context, sequenceId uint64)
 func (b *minimalTestBot) PayTip(ctx context.Context, recipient zkidentity.ShortID, amount dcrutil.Amount, priority int32) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if b.payTipErr != nil {
+		return b.payTipErr
+	}
 	b.paidTips[recipient.String()] = amount
 	return nil
 }
 
+func (b *minimalTestBot) SendPM(ctx context.Context, nick string, msg string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sentPMs = append(b.sentPMs, sentPM{nick: nick, msg: msg})
+	return nil
+}
+
 // mockNotifierStream implements PongGame_StartNtfnStreamServer for testing
 type mockNotifierStream struct {
 	grpc.ServerStream
@@ -147,7 +168,10 @@ func setupTestServer(t *testing.T) *Server {
 		},
 		users:              make(map[zkidentity.ShortID]*ponggame.Player),
 		waitingRoomCreated: make(chan struct{}, 1),
+		health:             newHealthServer(),
 	}
+	srv.metrics = newServerMetrics(srv)
+	srv.gameManager.OnFrameDropped = srv.metrics.framesDropped.Inc
 
 	return srv
 }
@@ -289,6 +313,83 @@ func TestJoinWaitingRoom(t *testing.T) {
 	require.NotNil(t, joinResp)
 }
 
+func TestPrivateWaitingRoom(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+
+	ids := make([]zkidentity.ShortID, 3)
+	for i, c := range []string{"1", "2", "3"} {
+		_ = ids[i].FromString(strings.Repeat(c, 64))
+		err := srv.db.StoreUnprocessedTip(ctx, &types.ReceivedTip{
+			Uid:          ids[i][:],
+			AmountMatoms: 50000000000,
+			SequenceId:   uint64(300 + i),
+		})
+		require.NoError(t, err)
+		createTestPlayer(srv, ids[i]).BetAmt = 50000000000
+	}
+	hostID, invitedID, strangerID := ids[0], ids[1], ids[2]
+
+	resp, err := srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{
+		HostId:   hostID.String(),
+		BetAmt:   50000000000,
+		Private:  true,
+		Invitees: []string{invitedID.String()},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.Wr.Private)
+	require.NotEmpty(t, resp.InviteCode)
+
+	// Private rooms are not listed.
+	rooms, err := srv.GetWaitingRooms(ctx, &pong.WaitingRoomsRequest{})
+	require.NoError(t, err)
+	require.Empty(t, rooms.Wr)
+
+	// The invitee is notified through its stream and a PM.
+	invited := srv.gameManager.PlayerSessions.GetPlayer(invitedID)
+	msgs := invited.NotifierStream.(*mockNotifierStream).messages
+	require.Len(t, msgs, 1)
+	require.Equal(t, pong.NotificationType_WR_INVITE, msgs[0].NotificationType)
+	require.Equal(t, resp.InviteCode, msgs[0].InviteCode)
+	bot := srv.bot.(*minimalTestBot)
+	require.Len(t, bot.sentPMs, 1)
+	require.Equal(t, invitedID.String(), bot.sentPMs[0].nick)
+
+	// Strangers need the invite code.
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: strangerID.String(),
+	})
+	require.Error(t, err)
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:     resp.Wr.Id,
+		ClientId:   strangerID.String(),
+		InviteCode: resp.InviteCode,
+	})
+	require.NoError(t, err)
+
+	// The room only has two seats, so the invitee can't join anymore and
+	// keeps its stake.
+	_, err = srv.JoinWaitingRoom(ctx, &pong.JoinWaitingRoomRequest{
+		RoomId:   resp.Wr.Id,
+		ClientId: invitedID.String(),
+	})
+	require.ErrorContains(t, err, "is full")
+	require.Nil(t, srv.gameManager.PlayerSessions.GetPlayer(invitedID).WR)
+	available, reserved, err := srv.fetchPlayerBalance(ctx, invitedID)
+	require.NoError(t, err)
+	require.Equal(t, matoms.Amount(50000000000), available)
+	require.Zero(t, reserved)
+
+	// Only the host may invite more players.
+	_, err = srv.InviteToWaitingRoom(ctx, &pong.InviteToWaitingRoomRequest{
+		ClientId: strangerID.String(),
+		RoomId:   resp.Wr.Id,
+		Invitees: []string{invitedID.String()},
+	})
+	require.Error(t, err)
+}
+
 func TestConcurrentWaitingRoomCreation(t *testing.T) {
 	srv := setupTestServer(t)
 
@@ -366,3 +467,61 @@ func TestGameStreamDisconnection(t *testing.T) {
 	require.NotNil(t, player)
 	require.Equal(t, clientID, *player.ID) // Dereference the pointer
 }
+
+func TestWaitingRoomRemovedNotifications(t *testing.T) {
+	srv := setupTestServer(t)
+	memberID, strangerID := zkidentity.ShortID{1}, zkidentity.ShortID{2}
+	member, stranger := createTestPlayer(srv, memberID), createTestPlayer(srv, strangerID)
+	srv.users[memberID] = member
+	srv.users[strangerID] = stranger
+	removed := func(p *ponggame.Player) int {
+		n := 0
+		for _, msg := range p.NotifierStream.(*mockNotifierStream).messages {
+			if msg.NotificationType == pong.NotificationType_ON_WR_REMOVED {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Only the members of private rooms learn they were removed.
+	srv.handleWaitingRoomRemoved(&pong.WaitingRoom{
+		Id:      "private",
+		Private: true,
+		Players: []*pong.Player{{Uid: memberID.String()}},
+	})
+	require.Equal(t, 1, removed(member))
+	require.Zero(t, removed(stranger))
+
+	srv.handleWaitingRoomRemoved(&pong.WaitingRoom{Id: "public"})
+	require.Equal(t, 2, removed(member))
+	require.Equal(t, 1, removed(stranger))
+}
+
+func TestSeriesUpdateFairness(t *testing.T) {
+	srv := setupTestServer(t)
+	p1ID, p2ID := zkidentity.ShortID{1}, zkidentity.ShortID{2}
+	players := []*ponggame.Player{createTestPlayer(srv, p1ID), createTestPlayer(srv, p2ID)}
+	series, err := ponggame.NewSeries(players, 3)
+	require.NoError(t, err)
+	game, err := srv.gameManager.StartGameWithRules(context.Background(), series.NextPlayers(),
+		ponggame.GameRules{MaxScore: 3, BestOf: 3}, 100)
+	require.NoError(t, err)
+	defer func() {
+		game.Stop(nil)
+		game.Cleanup()
+	}()
+	committed := game.Fairness(false)
+	require.NoError(t, game.SetPlayerReady(p1ID.String(), []byte("p1")))
+	require.NoError(t, game.SetPlayerReady(p2ID.String(), []byte("p2")))
+
+	// Every game of a series reveals its serve seed, not just the last.
+	game.Winner = &p1ID
+	series.RecordGame(game.Winner)
+	srv.notifySeriesUpdate(game, series)
+	msgs := players[0].NotifierStream.(*mockNotifierStream).messages
+	ntfn := msgs[len(msgs)-1]
+	require.Equal(t, pong.NotificationType_SERIES_UPDATE, ntfn.NotificationType)
+	_, err = ponggame.VerifyFairness(committed.ServerSeedHash, []byte("p1"), ntfn.Fairness)
+	require.NoError(t, err)
+}
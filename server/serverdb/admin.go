@@ -0,0 +1,127 @@
+package serverdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoreBan stores or replaces the ban of a player.
+func (b *boltDB) StoreBan(ctx context.Context, ban *Ban) error {
+	data, err := json.Marshal(ban)
+	if err != nil {
+		return err
+	}
+
+	return b.update("StoreBan", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bansBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.Put(ban.UID[:], data)
+	})
+}
+
+// DeleteBan lifts the ban of a player.
+func (b *boltDB) DeleteBan(ctx context.Context, uid zkidentity.ShortID) error {
+	return b.update("DeleteBan", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bansBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.Delete(uid[:])
+	})
+}
+
+func (b *boltDB) FetchBan(ctx context.Context, uid zkidentity.ShortID) (*Ban, error) {
+	var ban *Ban
+	err := b.view("FetchBan", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bansBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		data := bucket.Get(uid[:])
+		if data == nil {
+			return nil
+		}
+		ban = &Ban{}
+		return json.Unmarshal(data, ban)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ban, nil
+}
+
+// FetchBans returns every banned player.
+func (b *boltDB) FetchBans(ctx context.Context) ([]*Ban, error) {
+	var bans []*Ban
+	err := b.view("FetchBans", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bansBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var ban Ban
+			if err := json.Unmarshal(v, &ban); err != nil {
+				return err
+			}
+			bans = append(bans, &ban)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bans, nil
+}
+
+// StoreAdminAction appends an action to the audit trail, assigning its ID.
+func (b *boltDB) StoreAdminAction(ctx context.Context, action *AdminAction) error {
+	return b.update("StoreAdminAction", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(adminActionsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		action.ID = id
+
+		data, err := json.Marshal(action)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(itob(id), data)
+	})
+}
+
+func (b *boltDB) FetchAdminActions(ctx context.Context, since time.Time) ([]*AdminAction, error) {
+	var actions []*AdminAction
+	err := b.view("FetchAdminActions", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(adminActionsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.ForEach(func(_, v []byte) error {
+			var action AdminAction
+			if err := json.Unmarshal(v, &action); err != nil {
+				return err
+			}
+			if action.CreatedAt.Before(since) {
+				return nil
+			}
+			actions = append(actions, &action)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
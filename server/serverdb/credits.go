@@ -0,0 +1,58 @@
+package serverdb
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	bolt "go.etcd.io/bbolt"
+)
+
+// GrantCredits tops the credits of a player up to balance, counting the
+// stakes they have reserved, once per day. Day is the key of the grant
+// period, and players already granted credits for it get nothing. It returns
+// the amount granted.
+func (b *boltDB) GrantCredits(ctx context.Context, uid zkidentity.ShortID, balance matoms.Amount, day string) (matoms.Amount, error) {
+	var granted matoms.Amount
+	err := b.update("GrantCredits", func(tx *bolt.Tx) error {
+		grants := tx.Bucket(creditGrantsBucket)
+		balances := tx.Bucket(ledgerBalancesBucket)
+		if grants == nil || balances == nil {
+			return ErrMainBucketNotFound
+		}
+		if string(grants.Get(uid[:])) == day {
+			return nil
+		}
+
+		held := balanceOf(balances, CreditAccounts.PlayerAccount(uid))
+		prefix := []byte(CreditAccounts.Escrow)
+		suffix := []byte("/" + uid.String())
+		c := balances.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			if bytes.HasSuffix(k, suffix) {
+				held += balanceOf(balances, string(k))
+			}
+		}
+
+		if held < balance {
+			granted = balance - held
+			err := postEntry(tx, &JournalEntry{
+				Kind: EntryGrant,
+				Ref:  day,
+				Postings: []Posting{
+					{Account: GrantsAccount, Amount: -granted},
+					{Account: CreditAccounts.PlayerAccount(uid), Amount: granted},
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return grants.Put(uid[:], []byte(day))
+	})
+	if err != nil {
+		return 0, err
+	}
+	return granted, nil
+}
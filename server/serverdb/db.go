@@ -6,16 +6,28 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	bolt "go.etcd.io/bbolt"
 )
 
 var (
 	receivedTipsBucket    = []byte("receivedTips")
 	sendTipProgressBucket = []byte("sendTipsProgress")
+	matchResultsBucket    = []byte("matchResults")
+	matchSeasonsBucket    = []byte("matchSeasons")
+	playerRatingsBucket   = []byte("playerRatings")
+	seasonsBucket         = []byte("seasons")
+	waitingRoomsBucket    = []byte("waitingRooms")
+	playerLimitsBucket    = []byte("playerLimits")
+	creditGrantsBucket    = []byte("creditGrants")
+	bansBucket            = []byte("bans")
+	adminActionsBucket    = []byte("adminActions")
+	healthBucket          = []byte("health")
 )
 
 // itob converte um uint64 em []byte usando BigEndian.
@@ -47,6 +59,17 @@ func NewBoltDB(dbPath string) (ServerDB, error) {
 		_, err := tx.CreateBucketIfNotExists(sendTipProgressBucket)
 		return err
 	})
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{matchResultsBucket, playerRatingsBucket, seasonsBucket, waitingRoomsBucket, playerLimitsBucket, creditGrantsBucket, bansBucket, adminActionsBucket, healthBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if err := indexMatchResults(tx); err != nil {
+			return err
+		}
+		return createLedger(tx)
+	})
 	if err != nil {
 		db.Close()
 		return nil, err
@@ -54,7 +77,8 @@ func NewBoltDB(dbPath string) (ServerDB, error) {
 	return &boltDB{db: db}, nil
 }
 
-// StoreUnprocessedTip stores a tip under the Uid sub-bucket in the main tips bucket.
+// StoreUnprocessedTip stores a tip under the Uid sub-bucket in the main tips
+// bucket and credits it to the sender in the ledger.
 func (b *boltDB) StoreUnprocessedTip(ctx context.Context, tip *types.ReceivedTip) error {
 	payload := ReceivedTipWrapper{
 		Tip:    tip,
@@ -65,8 +89,12 @@ func (b *boltDB) StoreUnprocessedTip(ctx context.Context, tip *types.ReceivedTip
 	if err != nil {
 		return err
 	}
+	deposit, err := depositEntry(tip)
+	if err != nil {
+		return err
+	}
 
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.update("StoreUnprocessedTip", func(tx *bolt.Tx) error {
 		mainBucket := tx.Bucket(receivedTipsBucket)
 		if mainBucket == nil {
 			return ErrMainBucketNotFound
@@ -83,13 +111,19 @@ func (b *boltDB) StoreUnprocessedTip(ctx context.Context, tip *types.ReceivedTip
 			return ErrDuplicateEntry
 		}
 
-		return userBucket.Put(sequenceId, data)
+		if err := userBucket.Put(sequenceId, data); err != nil {
+			return err
+		}
+		if deposit == nil {
+			return nil
+		}
+		return postEntry(tx, deposit)
 	})
 }
 
 // MarkTipAsSending updates the status of a specific tip to "sending".
 func (b *boltDB) UpdateTipStatus(ctx context.Context, uid []byte, tipID []byte, status TipStatus) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.update("UpdateTipStatus", func(tx *bolt.Tx) error {
 		mainBucket := tx.Bucket(receivedTipsBucket)
 		if mainBucket == nil {
 			return ErrMainBucketNotFound
@@ -126,16 +160,111 @@ func (b *boltDB) UpdateTipStatus(ctx context.Context, uid []byte, tipID []byte,
 	})
 }
 
+// SpendTips spends an amount from the unpaid tips of a player, oldest first.
+// Tips spent in full are moved to status and returned, the last one may be
+// left partially spent.
+func (b *boltDB) SpendTips(ctx context.Context, uid zkidentity.ShortID, amount matoms.Amount, status TipStatus) ([]*types.ReceivedTip, error) {
+	var spent []*types.ReceivedTip
+	err := b.update("SpendTips", func(tx *bolt.Tx) error {
+		mainBucket := tx.Bucket(receivedTipsBucket)
+		if mainBucket == nil {
+			return ErrMainBucketNotFound
+		}
+		userBucket := mainBucket.Bucket(uid[:])
+		if userBucket == nil {
+			return ErrUserBucketNotFound
+		}
+
+		left := amount
+		c := userBucket.Cursor()
+		for k, v := c.First(); k != nil && left > 0; k, v = c.Next() {
+			var wrapper ReceivedTipWrapper
+			if err := json.Unmarshal(v, &wrapper); err != nil {
+				return err
+			}
+			if wrapper.Status != StatusUnpaid {
+				continue
+			}
+			unspent := matoms.Amount(wrapper.Tip.AmountMatoms) - wrapper.Spent
+			if unspent > left {
+				wrapper.Spent += left
+				left = 0
+			} else {
+				wrapper.Status = status
+				left -= unspent
+				spent = append(spent, wrapper.Tip)
+			}
+			data, err := json.Marshal(wrapper)
+			if err != nil {
+				return err
+			}
+			if err := userBucket.Put(k, data); err != nil {
+				return err
+			}
+		}
+		if left > 0 {
+			return fmt.Errorf("%w: unpaid tips of %s are short by %s",
+				ErrInsufficientBalance, uid, left)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spent, nil
+}
+
+// FetchUnspentTips returns the unspent amount of the unpaid tips of every
+// player.
+func (b *boltDB) FetchUnspentTips(ctx context.Context) (map[zkidentity.ShortID]matoms.Amount, error) {
+	unspent := make(map[zkidentity.ShortID]matoms.Amount)
+	err := b.view("FetchUnspentTips", func(tx *bolt.Tx) error {
+		mainBucket := tx.Bucket(receivedTipsBucket)
+		if mainBucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return mainBucket.ForEachBucket(func(uid []byte) error {
+			var userID zkidentity.ShortID
+			copy(userID[:], uid)
+			return mainBucket.Bucket(uid).ForEach(func(_, v []byte) error {
+				var wrapper ReceivedTipWrapper
+				if err := json.Unmarshal(v, &wrapper); err != nil {
+					return err
+				}
+				if wrapper.Status == StatusUnpaid {
+					unspent[userID] += matoms.Amount(wrapper.Tip.AmountMatoms) - wrapper.Spent
+				}
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unspent, nil
+}
+
 // Close closes the bbolt database.
 func (b *boltDB) Close() error {
 	return b.db.Close()
 }
 
+// Ping checks the database is writable by recording the time of the check.
+func (b *boltDB) Ping(ctx context.Context) error {
+	return b.update("Ping", func(tx *bolt.Tx) error {
+		now, err := time.Now().MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(healthBucket).Put([]byte("lastPing"), now)
+	})
+}
+
 // FetchReceivedTipsByUID retrieves all tips with a specific status for a specific user by Uid.
 func (b *boltDB) FetchReceivedTipsByUID(ctx context.Context, uid zkidentity.ShortID, status TipStatus) ([]*types.ReceivedTip, error) {
 	var unprocessedTips []*types.ReceivedTip
 
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view("FetchReceivedTipsByUID", func(tx *bolt.Tx) error {
 		mainBucket := tx.Bucket(receivedTipsBucket)
 		if mainBucket == nil {
 			return ErrTipBucketNotFound
@@ -175,7 +304,7 @@ func (b *boltDB) FetchReceivedTipsByUID(ctx context.Context, uid zkidentity.Shor
 func (b *boltDB) FetchAllReceivedTipsByUID(ctx context.Context, uid zkidentity.ShortID) ([]ReceivedTipWrapper, error) {
 	var allTips []ReceivedTipWrapper
 
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view("FetchAllReceivedTipsByUID", func(tx *bolt.Tx) error {
 		mainBucket := tx.Bucket(receivedTipsBucket)
 		if mainBucket == nil {
 			return errors.New("tip bucket not found")
@@ -207,11 +336,37 @@ func (b *boltDB) FetchAllReceivedTipsByUID(ctx context.Context, uid zkidentity.S
 	return allTips, nil
 }
 
+// FetchReceivedTips retrieves every tip received from any user, regardless
+// of status.
+func (b *boltDB) FetchReceivedTips(ctx context.Context) ([]ReceivedTipWrapper, error) {
+	var allTips []ReceivedTipWrapper
+	err := b.view("FetchReceivedTips", func(tx *bolt.Tx) error {
+		mainBucket := tx.Bucket(receivedTipsBucket)
+		if mainBucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return mainBucket.ForEachBucket(func(uid []byte) error {
+			return mainBucket.Bucket(uid).ForEach(func(_, v []byte) error {
+				var tip ReceivedTipWrapper
+				if err := json.Unmarshal(v, &tip); err != nil {
+					return err
+				}
+				allTips = append(allTips, tip)
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allTips, nil
+}
+
 // FetchUnprocessedTips retrieves all unprocessed tips for all users.
 func (b *boltDB) FetchUnprocessedTips(ctx context.Context) (map[zkidentity.ShortID][]*types.ReceivedTip, error) {
 	unprocessedTips := make(map[zkidentity.ShortID][]*types.ReceivedTip)
 
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view("FetchUnprocessedTips", func(tx *bolt.Tx) error {
 		mainBucket := tx.Bucket(receivedTipsBucket)
 		if mainBucket == nil {
 			return ErrMainBucketNotFound
@@ -256,7 +411,7 @@ func (b *boltDB) FetchTip(ctx context.Context, tipID uint64) (*ReceivedTipWrappe
 	var tip *ReceivedTipWrapper
 	var found bool
 
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view("FetchTip", func(tx *bolt.Tx) error {
 		mainBucket := tx.Bucket(receivedTipsBucket)
 		if mainBucket == nil {
 			return ErrMainBucketNotFound
@@ -296,8 +451,8 @@ func (b *boltDB) FetchTip(ctx context.Context, tipID uint64) (*ReceivedTipWrappe
 	return tip, nil
 }
 
-func (b *boltDB) StoreSendTipProgress(ctx context.Context, winnerUID []byte, totalAmount int64, tips []*types.ReceivedTip, status TipStatus) error {
-	err := b.db.Update(func(tx *bolt.Tx) error {
+func (b *boltDB) StoreSendTipProgress(ctx context.Context, winnerUID []byte, totalAmount matoms.Amount, tips []*types.ReceivedTip, status TipStatus) error {
+	err := b.update("StoreSendTipProgress", func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(sendTipProgressBucket)
 		if bucket == nil {
 			return ErrTipBucketNotFound
@@ -325,28 +480,25 @@ func (b *boltDB) StoreSendTipProgress(ctx context.Context, winnerUID []byte, tot
 	return err
 }
 
-func (b *boltDB) FetchLatestUncompletedTipProgress(ctx context.Context, winnerUID []byte, totalAmount int64) (*TipProgressRecord, error) {
-	var latestRecord *TipProgressRecord
+func (b *boltDB) FetchSendTipProgressByClient(ctx context.Context, clientID []byte) ([]*TipProgressRecord, error) {
+	var results []*TipProgressRecord
 
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view("FetchSendTipProgressByClient", func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(sendTipProgressBucket)
 		if bucket == nil {
 			return ErrTipBucketNotFound
 		}
 
-		return bucket.ForEach(func(k, v []byte) error {
+		return bucket.ForEach(func(_, v []byte) error {
 			var record TipProgressRecord
 			if err := json.Unmarshal(v, &record); err != nil {
 				return err
 			}
 
-			// Check if the record matches the given winnerUID and totalAmount and is uncompleted.
-			if bytes.Equal(record.WinnerUID, winnerUID) && record.TotalAmount == totalAmount && record.Status != StatusPaid {
-				// Update latestRecord if this record is newer.
-				if latestRecord == nil || record.CreatedAt.After(latestRecord.CreatedAt) {
-					tmp := record // create a copy to get its address
-					latestRecord = &tmp
-				}
+			if bytes.Equal(record.WinnerUID, clientID) {
+				// Clone the record to avoid referencing loop variable
+				recordCopy := record
+				results = append(results, &recordCopy)
 			}
 			return nil
 		})
@@ -356,46 +508,79 @@ func (b *boltDB) FetchLatestUncompletedTipProgress(ctx context.Context, winnerUI
 		return nil, err
 	}
 
-	if latestRecord == nil {
-		return nil, ErrTipNotFound
-	}
-
-	return latestRecord, nil
+	return results, nil
 }
 
-func (b *boltDB) FetchSendTipProgressByClient(ctx context.Context, clientID []byte) ([]*TipProgressRecord, error) {
+func (b *boltDB) FetchTipProgressByStatus(ctx context.Context, statuses ...TipStatus) ([]*TipProgressRecord, error) {
 	var results []*TipProgressRecord
-
-	err := b.db.View(func(tx *bolt.Tx) error {
+	err := b.view("FetchTipProgressByStatus", func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(sendTipProgressBucket)
 		if bucket == nil {
 			return ErrTipBucketNotFound
 		}
 
+		// Records are keyed by their sequence id, so they are walked
+		// oldest first.
 		return bucket.ForEach(func(_, v []byte) error {
 			var record TipProgressRecord
 			if err := json.Unmarshal(v, &record); err != nil {
 				return err
 			}
-
-			if bytes.Equal(record.WinnerUID, clientID) {
-				// Clone the record to avoid referencing loop variable
-				recordCopy := record
-				results = append(results, &recordCopy)
+			for _, status := range statuses {
+				if record.Status == status {
+					results = append(results, &record)
+					break
+				}
 			}
 			return nil
 		})
 	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
+func (b *boltDB) FetchTipProgress(ctx context.Context, recordID uint64) (*TipProgressRecord, error) {
+	var record TipProgressRecord
+	err := b.view("FetchTipProgress", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sendTipProgressBucket)
+		if bucket == nil {
+			return ErrTipBucketNotFound
+		}
+		data := bucket.Get(itob(recordID))
+		if data == nil {
+			return ErrTipNotFound
+		}
+		return json.Unmarshal(data, &record)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return &record, nil
+}
 
-	return results, nil
+func (b *boltDB) UpdateTipProgress(ctx context.Context, record *TipProgressRecord) error {
+	return b.update("UpdateTipProgress", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sendTipProgressBucket)
+		if bucket == nil {
+			return ErrTipBucketNotFound
+		}
+
+		key := itob(record.ID)
+		if bucket.Get(key) == nil {
+			return ErrTipNotFound
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(key, data)
+	})
 }
 
 func (b *boltDB) UpdateTipProgressStatus(ctx context.Context, recordID uint64, status TipStatus) error {
-	return b.db.Update(func(tx *bolt.Tx) error {
+	return b.update("UpdateTipProgressStatus", func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(sendTipProgressBucket)
 		if bucket == nil {
 			return ErrTipBucketNotFound
@@ -3,12 +3,14 @@ package serverdb_test
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 	"github.com/vctt94/pong-bisonrelay/server/serverdb"
 )
 
@@ -24,6 +26,10 @@ func testPongServerDBInterface(t *testing.T, db serverdb.ServerDB) {
 		t.Fatalf("Error generating client ID: %v", err)
 	}
 
+	if err := db.Ping(ctx); err != nil {
+		t.Fatalf("Error pinging the database: %v", err)
+	}
+
 	// Create a test tip entry
 	amount := 0.0001
 	amountMatoms := int64(amount * 1e11)
@@ -92,3 +98,108 @@ func TestFSDB(t *testing.T) {
 
 	testPongServerDBInterface(t, db)
 }
+
+func TestSpendTips(t *testing.T) {
+	ctx := context.Background()
+	db, err := serverdb.NewBoltDB(filepath.Join(t.TempDir(), "spend.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize db: %v", err)
+	}
+	defer db.Close()
+
+	var uid zkidentity.ShortID
+	uid[0] = 1
+	for i, amount := range []int64{300, 500} {
+		err := db.StoreUnprocessedTip(ctx, &types.ReceivedTip{Uid: uid[:], AmountMatoms: amount, SequenceId: uint64(i + 1)})
+		if err != nil {
+			t.Fatalf("Failed to store tip: %v", err)
+		}
+	}
+	unspent := func() matoms.Amount {
+		t.Helper()
+		totals, err := db.FetchUnspentTips(ctx)
+		if err != nil {
+			t.Fatalf("Failed to fetch unspent tips: %v", err)
+		}
+		return totals[uid]
+	}
+
+	// Spending less than the oldest tip leaves it unpaid.
+	spent, err := db.SpendTips(ctx, uid, 100, serverdb.StatusSending)
+	if err != nil {
+		t.Fatalf("Failed to spend tips: %v", err)
+	}
+	if len(spent) != 0 || unspent() != 700 {
+		t.Fatalf("Unexpected partial spend: %d tips spent, %d unspent", len(spent), unspent())
+	}
+
+	// The oldest tip is used up first.
+	spent, err = db.SpendTips(ctx, uid, 400, serverdb.StatusSending)
+	if err != nil {
+		t.Fatalf("Failed to spend tips: %v", err)
+	}
+	if len(spent) != 1 || spent[0].SequenceId != 1 || unspent() != 300 {
+		t.Fatalf("Unexpected spend: %d tips spent, %d unspent", len(spent), unspent())
+	}
+	unpaid, err := db.FetchReceivedTipsByUID(ctx, uid, serverdb.StatusUnpaid)
+	if err != nil {
+		t.Fatalf("Failed to fetch unpaid tips: %v", err)
+	}
+	if len(unpaid) != 1 || unpaid[0].SequenceId != 2 {
+		t.Fatalf("Unexpected unpaid tips: %d", len(unpaid))
+	}
+
+	// Tips can't be overspent.
+	if _, err := db.SpendTips(ctx, uid, 301, serverdb.StatusSending); !errors.Is(err, serverdb.ErrInsufficientBalance) {
+		t.Fatalf("Expected insufficient balance error, got %v", err)
+	}
+	if unspent() != 300 {
+		t.Fatalf("Failed spend changed the tips: %d unspent", unspent())
+	}
+}
+
+func TestTipProgressByStatus(t *testing.T) {
+	ctx := context.Background()
+	db, err := serverdb.NewBoltDB(filepath.Join(t.TempDir(), "progress.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize db: %v", err)
+	}
+	defer db.Close()
+
+	var uid zkidentity.ShortID
+	uid[0] = 1
+	for _, status := range []serverdb.TipStatus{serverdb.StatusQueued, serverdb.StatusPaid, serverdb.StatusQueued} {
+		if err := db.StoreSendTipProgress(ctx, uid[:], 1000, nil, status); err != nil {
+			t.Fatalf("Failed to store progress: %v", err)
+		}
+	}
+
+	queued, err := db.FetchTipProgressByStatus(ctx, serverdb.StatusQueued)
+	if err != nil {
+		t.Fatalf("Failed to fetch progress: %v", err)
+	}
+	if len(queued) != 2 || queued[0].ID != 1 || queued[1].ID != 3 {
+		t.Fatalf("Unexpected queued records: %+v", queued)
+	}
+
+	queued[0].Status = serverdb.StatusFailed
+	queued[0].Attempts = 2
+	queued[0].LastError = "offline"
+	if err := db.UpdateTipProgress(ctx, queued[0]); err != nil {
+		t.Fatalf("Failed to update progress: %v", err)
+	}
+	record, err := db.FetchTipProgress(ctx, 1)
+	if err != nil {
+		t.Fatalf("Failed to fetch progress: %v", err)
+	}
+	if record.Status != serverdb.StatusFailed || record.Attempts != 2 || record.LastError != "offline" {
+		t.Fatalf("Unexpected updated record: %+v", record)
+	}
+
+	if _, err := db.FetchTipProgress(ctx, 42); !errors.Is(err, serverdb.ErrTipNotFound) {
+		t.Fatalf("Expected ErrTipNotFound, got %v", err)
+	}
+	if err := db.UpdateTipProgress(ctx, &serverdb.TipProgressRecord{ID: 42}); !errors.Is(err, serverdb.ErrTipNotFound) {
+		t.Fatalf("Expected ErrTipNotFound, got %v", err)
+	}
+}
@@ -3,10 +3,12 @@ package serverdb
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/companyzero/bisonrelay/clientrpc/types"
 	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
 )
 
 var (
@@ -15,6 +17,10 @@ var (
 	ErrUserBucketNotFound = errors.New("user bucket not found")
 	ErrTipNotFound        = errors.New("tip not found")
 	ErrTipBucketNotFound  = errors.New("tip bucket not found")
+	ErrSeasonNotFound     = errors.New("season not found")
+
+	ErrUnbalancedEntry     = errors.New("journal entry is not balanced")
+	ErrInsufficientBalance = errors.New("insufficient balance")
 )
 
 type TipStatus string
@@ -23,33 +29,355 @@ const (
 	StatusUnpaid  TipStatus = "unpaid"
 	StatusSending TipStatus = "sending"
 	StatusPaid    TipStatus = "paid"
+
+	// StatusQueued is the status of payouts waiting to be sent and
+	// StatusFailed the one of payouts that ran out of attempts and need
+	// an admin to look at them.
+	StatusQueued TipStatus = "queued"
+	StatusFailed TipStatus = "failed"
 )
 
 type ReceivedTipWrapper struct {
 	Tip    *types.ReceivedTip
 	Status TipStatus
+	// Spent is the part of an unpaid tip already lost or paid out, in
+	// matoms. Tips leave the unpaid status once fully spent.
+	Spent matoms.Amount `json:",omitempty"`
 }
 
 type TipProgressRecord struct {
 	ID          uint64               `json:"id"`
 	WinnerUID   []byte               `json:"winner_uid"`
-	TotalAmount int64                `json:"total_amount"`
+	TotalAmount matoms.Amount        `json:"total_amount"`
 	Status      TipStatus            `json:"status"`
 	Tips        []*types.ReceivedTip `json:"received_tip"`
 	CreatedAt   time.Time            `json:"created_at"`
+
+	// Attempts is how many times the payout was sent, NextAttempt when
+	// a queued payout may be sent again and LastError why its last
+	// attempt failed.
+	Attempts    int       `json:"attempts,omitempty"`
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	// SentAt is when the payout was last handed to the bot.
+	SentAt time.Time `json:"sent_at,omitempty"`
+	// TimedOut is set on payouts failed because the bot never reported
+	// their outcome, which may still be reported later.
+	TimedOut bool `json:"timed_out,omitempty"`
+	// ProgressSeq is the sequence id of the last tip progress event
+	// applied to the payout, so redelivered events are ignored.
+	ProgressSeq uint64 `json:"progress_seq,omitempty"`
+}
+
+// MatchPlayer is a participant of a stored match.
+type MatchPlayer struct {
+	UID   []byte        `json:"uid"`
+	Nick  string        `json:"nick"`
+	Score int32         `json:"score"`
+	Stake matoms.Amount `json:"stake"`
+}
+
+// MatchResult is the stored outcome of a finished game.
+type MatchResult struct {
+	ID        uint64        `json:"id"`
+	GameID    string        `json:"game_id"`
+	Season    uint32        `json:"season"`
+	WinnerUID []byte        `json:"winner_uid"`
+	Players   []MatchPlayer `json:"players"`
+	TotalBet  matoms.Amount `json:"total_bet"`
+	EndedAt   time.Time     `json:"ended_at"`
+}
+
+// StoredRoomMember is a player of a stored waiting room together with the
+// sequence ids of the tips reserved for their stake.
+type StoredRoomMember struct {
+	UID    []byte   `json:"uid"`
+	Nick   string   `json:"nick"`
+	TipIDs []uint64 `json:"tip_ids"`
+}
+
+// StoredWaitingRoom is a waiting room kept across restarts.
+type StoredWaitingRoom struct {
+	ID         string             `json:"id"`
+	HostUID    []byte             `json:"host_uid"`
+	BetAmt     matoms.Amount      `json:"bet_amt"`
+	MaxScore   int32              `json:"max_score"`
+	BestOf     int32              `json:"best_of"`
+	Private    bool               `json:"private"`
+	InviteCode string             `json:"invite_code,omitempty"`
+	Invited    [][]byte           `json:"invited,omitempty"`
+	Banned     [][]byte           `json:"banned,omitempty"`
+	Locked     bool               `json:"locked"`
+	Members    []StoredRoomMember `json:"members"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// PlayerLimits are the responsible gaming limits a player set on themselves.
+// Zero values mean no limit.
+type PlayerLimits struct {
+	// MaxStake is the most the player may stake in a single game or
+	// tournament.
+	MaxStake matoms.Amount `json:"max_stake,omitempty"`
+	// MaxDailyLoss is the most the player may lose in any 24 hours.
+	MaxDailyLoss matoms.Amount `json:"max_daily_loss,omitempty"`
+	// SessionReminder is how often the player is reminded of how long
+	// they have been connected.
+	SessionReminder time.Duration `json:"session_reminder,omitempty"`
+	// ExcludedUntil is when the self-exclusion of the player ends.
+	ExcludedUntil time.Time `json:"excluded_until,omitempty"`
+
+	// Pending holds looser limits requested by the player, which only
+	// apply after a cool-off.
+	Pending *PendingLimits `json:"pending,omitempty"`
+}
+
+// PendingLimits are looser limits that apply from EffectiveAt.
+type PendingLimits struct {
+	MaxStake     matoms.Amount `json:"max_stake,omitempty"`
+	MaxDailyLoss matoms.Amount `json:"max_daily_loss,omitempty"`
+	EffectiveAt  time.Time     `json:"effective_at"`
+}
+
+// Ban keeps a player from using the server.
+type Ban struct {
+	UID       zkidentity.ShortID `json:"uid"`
+	Reason    string             `json:"reason,omitempty"`
+	BannedBy  string             `json:"banned_by"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// AdminAction is an entry of the audit trail of the actions taken through
+// the admin API.
+type AdminAction struct {
+	ID     uint64 `json:"id"`
+	Admin  string `json:"admin"`
+	Action string `json:"action"`
+	// Target is the game, room, payout or player acted on.
+	Target  string `json:"target,omitempty"`
+	Details string `json:"details,omitempty"`
+	// Error is why the action failed, if it did.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
+// Ledger accounts shared by all players. Deposits and grants are the only
+// accounts allowed to go negative: deposits mirrors every tip received and
+// grants every credit handed out, while payouts collects every amount sent
+// back out.
+const (
+	DepositsAccount = "deposits"
+	GrantsAccount   = "grants"
+	PayoutsAccount  = "payouts"
+	HouseAccount    = "house"
+)
+
+// IsSourceAccount returns whether account is one of the accounts funds enter
+// the ledger through, which may go negative.
+func IsSourceAccount(account string) bool {
+	return account == DepositsAccount || account == GrantsAccount
+}
+
+// Accounts names the ledger accounts players hold and stake a currency in.
+type Accounts struct {
+	// Player and Escrow are the prefixes of the available balances and of
+	// the stakes reserved in games, waiting rooms and tournaments.
+	Player string
+	Escrow string
+}
+
+var (
+	// DCRAccounts hold the DCR players tip the bot.
+	DCRAccounts = Accounts{Player: "player/", Escrow: "escrow/"}
+
+	// CreditAccounts hold the virtual credits of free-to-play servers,
+	// which are granted rather than deposited and never paid out.
+	CreditAccounts = Accounts{Player: "credits/", Escrow: "credit-escrow/"}
+)
+
+// PlayerAccount is the ledger account holding the available balance of a
+// player.
+func (a Accounts) PlayerAccount(uid zkidentity.ShortID) string {
+	return a.Player + uid.String()
+}
+
+// EscrowPrefix is the prefix of the ledger accounts holding the stakes
+// reserved in a game, waiting room or tournament.
+func (a Accounts) EscrowPrefix(escrowID string) string {
+	return a.Escrow + escrowID + "/"
+}
+
+// EscrowAccount is the ledger account holding the stake a player reserved in
+// a game, waiting room or tournament.
+func (a Accounts) EscrowAccount(escrowID string, uid zkidentity.ShortID) string {
+	return a.EscrowPrefix(escrowID) + uid.String()
+}
+
+// PlayerAccount is the ledger account holding the available DCR of a player.
+func PlayerAccount(uid zkidentity.ShortID) string {
+	return DCRAccounts.PlayerAccount(uid)
+}
+
+// EscrowPrefix is the prefix of the ledger accounts holding the DCR stakes
+// reserved in a game, waiting room or tournament.
+func EscrowPrefix(escrowID string) string {
+	return DCRAccounts.EscrowPrefix(escrowID)
+}
+
+// EscrowAccount is the ledger account holding the DCR stake a player
+// reserved in a game, waiting room or tournament.
+func EscrowAccount(escrowID string, uid zkidentity.ShortID) string {
+	return DCRAccounts.EscrowAccount(escrowID, uid)
+}
+
+// EntryKind is the kind of money movement recorded by a journal entry.
+type EntryKind string
+
+const (
+	EntryDeposit    EntryKind = "deposit"
+	EntryReserve    EntryKind = "reserve"
+	EntryRelease    EntryKind = "release"
+	EntrySettle     EntryKind = "settle"
+	EntryPayout     EntryKind = "payout"
+	EntryRefund     EntryKind = "refund"
+	EntryWithdrawal EntryKind = "withdrawal"
+	EntryReversal   EntryKind = "reversal" // payout that could not be queued
+	EntryGrant      EntryKind = "grant"    // credits of free-to-play servers
+)
+
+// Posting moves an amount in matoms into (positive) or out of (negative) a
+// ledger account.
+type Posting struct {
+	Account string        `json:"account"`
+	Amount  matoms.Amount `json:"amount"`
+}
+
+// JournalEntry is a balanced set of postings recording one money movement.
+type JournalEntry struct {
+	ID        uint64    `json:"id"`
+	Kind      EntryKind `json:"kind"`
+	Ref       string    `json:"ref,omitempty"`
+	Postings  []Posting `json:"postings"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks that the entry moves money between at least two accounts
+// and that its postings add up to zero.
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("%w: %s entry needs at least two postings", ErrUnbalancedEntry, e.Kind)
+	}
+	sum := matoms.Amount(0)
+	for _, p := range e.Postings {
+		if p.Account == "" || p.Amount == 0 {
+			return fmt.Errorf("%w: empty posting in %s entry", ErrUnbalancedEntry, e.Kind)
+		}
+		sum += p.Amount
+	}
+	if sum != 0 {
+		return fmt.Errorf("%w: %s entry is off by %d matoms", ErrUnbalancedEntry, e.Kind, sum)
+	}
+	return nil
+}
+
+// PlayerRating is the rating of a player in the current season.
+type PlayerRating struct {
+	UID    []byte  `json:"uid"`
+	Nick   string  `json:"nick"`
+	Rating float64 `json:"rating"`
+	Games  int32   `json:"games"`
+}
+
+// Season tracks the currently running leaderboard season.
+type Season struct {
+	Number         uint32    `json:"number"`
+	StartedAt      time.Time `json:"started_at"`
+	LastWeeklyPost time.Time `json:"last_weekly_post"`
+}
+
+// SeasonStanding is a final leaderboard row of an archived season.
+type SeasonStanding struct {
+	UID           []byte        `json:"uid"`
+	Nick          string        `json:"nick"`
+	Rating        float64       `json:"rating"`
+	NetWinnings   matoms.Amount `json:"net_winnings"`
+	BestStreak    int32         `json:"best_streak"`
+	CurrentStreak int32         `json:"current_streak"`
+	Wins          int32         `json:"wins"`
+	Losses        int32         `json:"losses"`
+}
+
+// SeasonArchive holds the final standings of a finished season.
+type SeasonArchive struct {
+	Number    uint32           `json:"number"`
+	StartedAt time.Time        `json:"started_at"`
+	EndedAt   time.Time        `json:"ended_at"`
+	Standings []SeasonStanding `json:"standings"`
+}
+
+// RateFunc returns the updated ratings of the players of a match given
+// their stored ratings, in the order of the players of the match and nil for
+// players that were never rated.
+type RateFunc func(ratings []*PlayerRating) ([]*PlayerRating, error)
+
 type ServerDB interface {
 	StoreUnprocessedTip(ctx context.Context, tip *types.ReceivedTip) error
 	FetchUnprocessedTips(ctx context.Context) (map[zkidentity.ShortID][]*types.ReceivedTip, error)
 	FetchTip(ctx context.Context, tipID uint64) (*ReceivedTipWrapper, error)
 	FetchReceivedTipsByUID(ctx context.Context, uid zkidentity.ShortID, status TipStatus) ([]*types.ReceivedTip, error)
 	UpdateTipStatus(ctx context.Context, uid []byte, tipID []byte, status TipStatus) error
+	// SpendTips spends an amount from the unpaid tips of a player, oldest
+	// first, and returns the tips it fully spent, which are moved to status.
+	SpendTips(ctx context.Context, uid zkidentity.ShortID, amount matoms.Amount, status TipStatus) ([]*types.ReceivedTip, error)
+	// FetchUnspentTips returns the unspent amount of the unpaid tips of
+	// every player.
+	FetchUnspentTips(ctx context.Context) (map[zkidentity.ShortID]matoms.Amount, error)
 	FetchAllReceivedTipsByUID(ctx context.Context, uid zkidentity.ShortID) ([]ReceivedTipWrapper, error)
+	FetchReceivedTips(ctx context.Context) ([]ReceivedTipWrapper, error)
 
-	StoreSendTipProgress(ctx context.Context, winnerUID []byte, totalAmount int64, tips []*types.ReceivedTip, status TipStatus) error
-	FetchLatestUncompletedTipProgress(ctx context.Context, winnerUID []byte, totalAmount int64) (*TipProgressRecord, error)
+	StoreSendTipProgress(ctx context.Context, winnerUID []byte, totalAmount matoms.Amount, tips []*types.ReceivedTip, status TipStatus) error
 	FetchSendTipProgressByClient(ctx context.Context, clientID []byte) ([]*TipProgressRecord, error)
+	// FetchTipProgressByStatus returns the send progress records in any of
+	// the given statuses, oldest first.
+	FetchTipProgressByStatus(ctx context.Context, statuses ...TipStatus) ([]*TipProgressRecord, error)
+	FetchTipProgress(ctx context.Context, recordID uint64) (*TipProgressRecord, error)
+	UpdateTipProgress(ctx context.Context, record *TipProgressRecord) error
 	UpdateTipProgressStatus(ctx context.Context, recordID uint64, status TipStatus) error
+
+	// StoreMatchResult stores a finished match of the current season and
+	// the ratings rate computes for its players, in one transaction.
+	StoreMatchResult(ctx context.Context, result *MatchResult, rate RateFunc) error
+	FetchMatchResults(ctx context.Context, season uint32, since time.Time) ([]*MatchResult, error)
+	FetchPlayerRatings(ctx context.Context) ([]*PlayerRating, error)
+	FetchCurrentSeason(ctx context.Context) (*Season, error)
+	StoreSeason(ctx context.Context, season *Season) error
+	ArchiveSeason(ctx context.Context, archive *SeasonArchive, next *Season) error
+	FetchSeasonArchive(ctx context.Context, number uint32) (*SeasonArchive, error)
+
+	StoreWaitingRoom(ctx context.Context, room *StoredWaitingRoom) error
+	DeleteWaitingRoom(ctx context.Context, roomID string) error
+	FetchWaitingRooms(ctx context.Context) ([]*StoredWaitingRoom, error)
+
+	FetchPlayerLimits(ctx context.Context, uid zkidentity.ShortID) (*PlayerLimits, error)
+	StorePlayerLimits(ctx context.Context, uid zkidentity.ShortID, limits *PlayerLimits) error
+
+	StoreBan(ctx context.Context, ban *Ban) error
+	DeleteBan(ctx context.Context, uid zkidentity.ShortID) error
+	// FetchBan returns the ban of a player, or nil if they aren't banned.
+	FetchBan(ctx context.Context, uid zkidentity.ShortID) (*Ban, error)
+	FetchBans(ctx context.Context) ([]*Ban, error)
+	StoreAdminAction(ctx context.Context, action *AdminAction) error
+	// FetchAdminActions returns the admin actions taken at or after since,
+	// oldest first.
+	FetchAdminActions(ctx context.Context, since time.Time) ([]*AdminAction, error)
+
+	PostJournalEntry(ctx context.Context, entry *JournalEntry) error
+	SweepAccounts(ctx context.Context, kind EntryKind, ref, prefix, dest string) (matoms.Amount, error)
+	FetchAccountBalance(ctx context.Context, account string) (matoms.Amount, error)
+	FetchAccountBalances(ctx context.Context, prefix string) (map[string]matoms.Amount, error)
+	FetchJournalEntries(ctx context.Context, since time.Time) ([]*JournalEntry, error)
+	CheckLedger(ctx context.Context) error
+	GrantCredits(ctx context.Context, uid zkidentity.ShortID, balance matoms.Amount, day string) (matoms.Amount, error)
+	// Ping returns an error if the database can't be written to.
+	Ping(ctx context.Context) error
 	Close() error
 }
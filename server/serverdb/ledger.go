@@ -0,0 +1,293 @@
+package serverdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	ledgerBalancesBucket = []byte("ledgerBalances")
+	ledgerJournalBucket  = []byte("ledgerJournal")
+)
+
+// openingRef is the ref of the deposits recorded for the unpaid tips found
+// when the ledger is first created.
+const openingRef = "opening"
+
+// createLedger creates the ledger buckets. The unpaid tips of databases
+// created before the ledger existed are recorded as opening deposits.
+func createLedger(tx *bolt.Tx) error {
+	if tx.Bucket(ledgerJournalBucket) != nil {
+		return nil
+	}
+	for _, name := range [][]byte{ledgerBalancesBucket, ledgerJournalBucket} {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+
+	tips := tx.Bucket(receivedTipsBucket)
+	if tips == nil {
+		return ErrMainBucketNotFound
+	}
+	return tips.ForEachBucket(func(uid []byte) error {
+		return tips.Bucket(uid).ForEach(func(_, v []byte) error {
+			var wrapper ReceivedTipWrapper
+			if err := json.Unmarshal(v, &wrapper); err != nil {
+				return err
+			}
+			if wrapper.Status != StatusUnpaid {
+				return nil
+			}
+			entry, err := depositEntry(wrapper.Tip)
+			if err != nil || entry == nil {
+				return err
+			}
+			entry.Ref = openingRef
+			return postEntry(tx, entry)
+		})
+	})
+}
+
+// depositEntry returns the journal entry crediting a received tip to its
+// sender, or nil for empty tips.
+func depositEntry(tip *types.ReceivedTip) (*JournalEntry, error) {
+	if tip.AmountMatoms <= 0 {
+		return nil, nil
+	}
+	var uid zkidentity.ShortID
+	if err := uid.FromBytes(tip.Uid); err != nil {
+		return nil, fmt.Errorf("invalid tip sender: %v", err)
+	}
+	return &JournalEntry{
+		Kind: EntryDeposit,
+		Ref:  strconv.FormatUint(tip.SequenceId, 10),
+		Postings: []Posting{
+			{Account: DepositsAccount, Amount: -matoms.Amount(tip.AmountMatoms)},
+			{Account: PlayerAccount(uid), Amount: matoms.Amount(tip.AmountMatoms)},
+		},
+	}, nil
+}
+
+func balanceOf(bucket *bolt.Bucket, account string) matoms.Amount {
+	v := bucket.Get([]byte(account))
+	if v == nil {
+		return 0
+	}
+	return matoms.Amount(binary.BigEndian.Uint64(v))
+}
+
+// putBalance stores the balance of an account. Empty accounts are dropped so
+// the balances bucket only holds live accounts.
+func putBalance(bucket *bolt.Bucket, account string, balance matoms.Amount) error {
+	if balance == 0 {
+		return bucket.Delete([]byte(account))
+	}
+	return bucket.Put([]byte(account), itob(uint64(balance)))
+}
+
+// postEntry applies a journal entry to the account balances and appends it
+// to the journal.
+func postEntry(tx *bolt.Tx, entry *JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	balances := tx.Bucket(ledgerBalancesBucket)
+	journal := tx.Bucket(ledgerJournalBucket)
+	if balances == nil || journal == nil {
+		return ErrMainBucketNotFound
+	}
+
+	for _, p := range entry.Postings {
+		balance := balanceOf(balances, p.Account) + p.Amount
+		if balance < 0 && !IsSourceAccount(p.Account) {
+			return fmt.Errorf("%w: %s is short by %s", ErrInsufficientBalance,
+				p.Account, -balance)
+		}
+		if err := putBalance(balances, p.Account, balance); err != nil {
+			return err
+		}
+	}
+
+	id, err := journal.NextSequence()
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return journal.Put(itob(id), data)
+}
+
+// PostJournalEntry records a balanced journal entry. The entry is rejected
+// if it would overdraw any account other than deposits and grants.
+func (b *boltDB) PostJournalEntry(ctx context.Context, entry *JournalEntry) error {
+	return b.update("PostJournalEntry", func(tx *bolt.Tx) error {
+		return postEntry(tx, entry)
+	})
+}
+
+// SweepAccounts moves the whole balance of every account starting with
+// prefix to dest in a single journal entry. It returns the amount moved.
+func (b *boltDB) SweepAccounts(ctx context.Context, kind EntryKind, ref, prefix, dest string) (matoms.Amount, error) {
+	var total matoms.Amount
+	err := b.update("SweepAccounts", func(tx *bolt.Tx) error {
+		balances := tx.Bucket(ledgerBalancesBucket)
+		if balances == nil {
+			return ErrMainBucketNotFound
+		}
+
+		entry := &JournalEntry{Kind: kind, Ref: ref}
+		c := balances.Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = c.Next() {
+			amount := matoms.Amount(binary.BigEndian.Uint64(v))
+			entry.Postings = append(entry.Postings, Posting{Account: string(k), Amount: -amount})
+			total += amount
+		}
+		if total == 0 {
+			return nil
+		}
+		entry.Postings = append(entry.Postings, Posting{Account: dest, Amount: total})
+		return postEntry(tx, entry)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// FetchAccountBalance returns the balance of a ledger account.
+func (b *boltDB) FetchAccountBalance(ctx context.Context, account string) (matoms.Amount, error) {
+	var balance matoms.Amount
+	err := b.view("FetchAccountBalance", func(tx *bolt.Tx) error {
+		balances := tx.Bucket(ledgerBalancesBucket)
+		if balances == nil {
+			return ErrMainBucketNotFound
+		}
+		balance = balanceOf(balances, account)
+		return nil
+	})
+	return balance, err
+}
+
+// FetchAccountBalances returns the balances of the non-empty accounts
+// starting with prefix.
+func (b *boltDB) FetchAccountBalances(ctx context.Context, prefix string) (map[string]matoms.Amount, error) {
+	result := make(map[string]matoms.Amount)
+	err := b.view("FetchAccountBalances", func(tx *bolt.Tx) error {
+		balances := tx.Bucket(ledgerBalancesBucket)
+		if balances == nil {
+			return ErrMainBucketNotFound
+		}
+		c := balances.Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = c.Next() {
+			result[string(k)] = matoms.Amount(binary.BigEndian.Uint64(v))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FetchJournalEntries returns the journal entries posted at or after since,
+// in the order they were posted.
+func (b *boltDB) FetchJournalEntries(ctx context.Context, since time.Time) ([]*JournalEntry, error) {
+	var entries []*JournalEntry
+	err := b.view("FetchJournalEntries", func(tx *bolt.Tx) error {
+		journal := tx.Bucket(ledgerJournalBucket)
+		if journal == nil {
+			return ErrMainBucketNotFound
+		}
+		return journal.ForEach(func(_, v []byte) error {
+			var entry JournalEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if entry.CreatedAt.Before(since) {
+				return nil
+			}
+			entries = append(entries, &entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CheckLedger replays the journal and verifies that every entry is balanced,
+// that the replayed balances match the stored ones, that the accounts add up
+// to zero and that only deposits and grants went negative.
+func (b *boltDB) CheckLedger(ctx context.Context) error {
+	return b.view("CheckLedger", func(tx *bolt.Tx) error {
+		balances := tx.Bucket(ledgerBalancesBucket)
+		journal := tx.Bucket(ledgerJournalBucket)
+		if balances == nil || journal == nil {
+			return ErrMainBucketNotFound
+		}
+
+		replayed := make(map[string]matoms.Amount)
+		err := journal.ForEach(func(_, v []byte) error {
+			var entry JournalEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if err := entry.Validate(); err != nil {
+				return fmt.Errorf("entry %d: %w", entry.ID, err)
+			}
+			for _, p := range entry.Postings {
+				replayed[p.Account] += p.Amount
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		total := matoms.Amount(0)
+		err = balances.ForEach(func(k, v []byte) error {
+			account := string(k)
+			balance := matoms.Amount(binary.BigEndian.Uint64(v))
+			if balance < 0 && !IsSourceAccount(account) {
+				return fmt.Errorf("account %s is overdrawn: %d", account, balance)
+			}
+			if replayed[account] != balance {
+				return fmt.Errorf("account %s drifted: stored %d, journal %d",
+					account, balance, replayed[account])
+			}
+			delete(replayed, account)
+			total += balance
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for account, balance := range replayed {
+			if balance != 0 {
+				return fmt.Errorf("account %s drifted: stored 0, journal %d", account, balance)
+			}
+		}
+		if total != 0 {
+			return fmt.Errorf("ledger accounts add up to %d instead of zero", total)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+package serverdb_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/clientrpc/types"
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func TestLedger(t *testing.T) {
+	ctx := context.Background()
+	db, err := serverdb.NewBoltDB(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize db: %v", err)
+	}
+	defer db.Close()
+
+	var alice, bob zkidentity.ShortID
+	alice[0], bob[0] = 1, 2
+	balance := func(account string) matoms.Amount {
+		t.Helper()
+		v, err := db.FetchAccountBalance(ctx, account)
+		if err != nil {
+			t.Fatalf("Failed to fetch balance of %s: %v", account, err)
+		}
+		return v
+	}
+
+	// Storing a tip credits its sender.
+	for i, uid := range []zkidentity.ShortID{alice, bob} {
+		err := db.StoreUnprocessedTip(ctx, &types.ReceivedTip{Uid: uid[:], AmountMatoms: 1000, SequenceId: uint64(i + 1)})
+		if err != nil {
+			t.Fatalf("Failed to store tip: %v", err)
+		}
+	}
+	if got := balance(serverdb.PlayerAccount(alice)); got != 1000 {
+		t.Fatalf("Unexpected balance after deposit: %d", got)
+	}
+	if got := balance(serverdb.DepositsAccount); got != -2000 {
+		t.Fatalf("Unexpected deposits balance: %d", got)
+	}
+
+	// Entries must balance and can't overdraw players.
+	err = db.PostJournalEntry(ctx, &serverdb.JournalEntry{
+		Kind: serverdb.EntryReserve,
+		Postings: []serverdb.Posting{
+			{Account: serverdb.PlayerAccount(alice), Amount: -500},
+			{Account: serverdb.EscrowAccount("g1", alice), Amount: 400},
+		},
+	})
+	if !errors.Is(err, serverdb.ErrUnbalancedEntry) {
+		t.Fatalf("Expected unbalanced entry error, got %v", err)
+	}
+	err = db.PostJournalEntry(ctx, &serverdb.JournalEntry{
+		Kind: serverdb.EntryReserve,
+		Postings: []serverdb.Posting{
+			{Account: serverdb.PlayerAccount(alice), Amount: -1500},
+			{Account: serverdb.EscrowAccount("g1", alice), Amount: 1500},
+		},
+	})
+	if !errors.Is(err, serverdb.ErrInsufficientBalance) {
+		t.Fatalf("Expected insufficient balance error, got %v", err)
+	}
+
+	for _, uid := range []zkidentity.ShortID{alice, bob} {
+		err := db.PostJournalEntry(ctx, &serverdb.JournalEntry{
+			Kind: serverdb.EntryReserve,
+			Ref:  "g1",
+			Postings: []serverdb.Posting{
+				{Account: serverdb.PlayerAccount(uid), Amount: -1000},
+				{Account: serverdb.EscrowAccount("g1", uid), Amount: 1000},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to reserve stake: %v", err)
+		}
+	}
+	escrows, err := db.FetchAccountBalances(ctx, serverdb.EscrowPrefix("g1"))
+	if err != nil {
+		t.Fatalf("Failed to fetch escrows: %v", err)
+	}
+	if len(escrows) != 2 {
+		t.Fatalf("Unexpected escrows: %v", escrows)
+	}
+
+	// Settling sweeps the whole escrow to the winner.
+	settled, err := db.SweepAccounts(ctx, serverdb.EntrySettle, "g1", serverdb.EscrowPrefix("g1"), serverdb.PlayerAccount(alice))
+	if err != nil {
+		t.Fatalf("Failed to settle: %v", err)
+	}
+	if settled != 2000 || balance(serverdb.PlayerAccount(alice)) != 2000 {
+		t.Fatalf("Unexpected settlement: %d", settled)
+	}
+	escrows, err = db.FetchAccountBalances(ctx, serverdb.EscrowPrefix("g1"))
+	if err != nil {
+		t.Fatalf("Failed to fetch escrows: %v", err)
+	}
+	if len(escrows) != 0 {
+		t.Fatalf("Escrow not empty after settlement: %v", escrows)
+	}
+	settled, err = db.SweepAccounts(ctx, serverdb.EntrySettle, "g1", serverdb.EscrowPrefix("g1"), serverdb.PlayerAccount(alice))
+	if err != nil || settled != 0 {
+		t.Fatalf("Sweeping an empty escrow moved %d: %v", settled, err)
+	}
+
+	entries, err := db.FetchJournalEntries(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to fetch journal: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("Unexpected number of journal entries: %d", len(entries))
+	}
+	if entries[4].Kind != serverdb.EntrySettle {
+		t.Fatalf("Unexpected last entry: %+v", entries[4])
+	}
+	if err := db.CheckLedger(ctx); err != nil {
+		t.Fatalf("Ledger check failed: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package serverdb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	bolt "go.etcd.io/bbolt"
+)
+
+// FetchPlayerLimits returns the limits of a player. Players that never set
+// any have empty limits.
+func (b *boltDB) FetchPlayerLimits(ctx context.Context, uid zkidentity.ShortID) (*PlayerLimits, error) {
+	limits := &PlayerLimits{}
+	err := b.view("FetchPlayerLimits", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(playerLimitsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		data := bucket.Get(uid[:])
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, limits)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// StorePlayerLimits stores or replaces the limits of a player.
+func (b *boltDB) StorePlayerLimits(ctx context.Context, uid zkidentity.ShortID, limits *PlayerLimits) error {
+	data, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+
+	return b.update("StorePlayerLimits", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(playerLimitsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.Put(uid[:], data)
+	})
+}
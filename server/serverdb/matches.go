@@ -0,0 +1,313 @@
+package serverdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	currentSeasonKey = []byte("current")
+	seasonArchiveKey = []byte("archive")
+)
+
+// StoreMatchResult stores a finished match of the current season together
+// with the ratings of its players, which rate updates in the same
+// transaction.
+func (b *boltDB) StoreMatchResult(ctx context.Context, result *MatchResult, rate RateFunc) error {
+	return b.update("StoreMatchResult", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(matchResultsBucket)
+		index := tx.Bucket(matchSeasonsBucket)
+		ratingsBucket := tx.Bucket(playerRatingsBucket)
+		if bucket == nil || index == nil || ratingsBucket == nil {
+			return ErrMainBucketNotFound
+		}
+
+		season, err := loadOrStartSeason(tx, result.EndedAt)
+		if err != nil {
+			return err
+		}
+		result.Season = season.Number
+
+		ratings := make([]*PlayerRating, len(result.Players))
+		for i, p := range result.Players {
+			data := ratingsBucket.Get(p.UID)
+			if data == nil {
+				continue
+			}
+			ratings[i] = &PlayerRating{}
+			if err := json.Unmarshal(data, ratings[i]); err != nil {
+				return err
+			}
+		}
+		updated, err := rate(ratings)
+		if err != nil {
+			return err
+		}
+
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		result.ID = id
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put(itob(id), data); err != nil {
+			return err
+		}
+		if err := index.Put(matchSeasonKey(result.Season, id), nil); err != nil {
+			return err
+		}
+
+		for _, rating := range updated {
+			data, err := json.Marshal(rating)
+			if err != nil {
+				return err
+			}
+			if err := ratingsBucket.Put(rating.UID, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FetchMatchResults returns the matches of a season that ended at or after
+// since, in the order they were stored.
+func (b *boltDB) FetchMatchResults(ctx context.Context, season uint32, since time.Time) ([]*MatchResult, error) {
+	var results []*MatchResult
+
+	err := b.view("FetchMatchResults", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(matchResultsBucket)
+		index := tx.Bucket(matchSeasonsBucket)
+		if bucket == nil || index == nil {
+			return ErrMainBucketNotFound
+		}
+
+		prefix := seasonKey(season)
+		c := index.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			data := bucket.Get(k[len(prefix):])
+			if data == nil {
+				continue
+			}
+			var result MatchResult
+			if err := json.Unmarshal(data, &result); err != nil {
+				return err
+			}
+			if result.EndedAt.Before(since) {
+				continue
+			}
+			results = append(results, &result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// indexMatchResults creates the index of match results by season, indexing
+// the results stored before it existed.
+func indexMatchResults(tx *bolt.Tx) error {
+	if tx.Bucket(matchSeasonsBucket) != nil {
+		return nil
+	}
+	index, err := tx.CreateBucket(matchSeasonsBucket)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(matchResultsBucket).ForEach(func(k, v []byte) error {
+		var result MatchResult
+		if err := json.Unmarshal(v, &result); err != nil {
+			return err
+		}
+		return index.Put(matchSeasonKey(result.Season, result.ID), nil)
+	})
+}
+
+func matchSeasonKey(season uint32, id uint64) []byte {
+	return append(seasonKey(season), itob(id)...)
+}
+
+// FetchPlayerRatings returns the ratings of every player of the current season.
+func (b *boltDB) FetchPlayerRatings(ctx context.Context) ([]*PlayerRating, error) {
+	var ratings []*PlayerRating
+
+	err := b.view("FetchPlayerRatings", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(playerRatingsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+
+		return bucket.ForEach(func(_, v []byte) error {
+			var rating PlayerRating
+			if err := json.Unmarshal(v, &rating); err != nil {
+				return err
+			}
+			ratings = append(ratings, &rating)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// FetchCurrentSeason returns the running season, starting season 1 if no
+// season was ever stored.
+func (b *boltDB) FetchCurrentSeason(ctx context.Context) (*Season, error) {
+	var season *Season
+
+	err := b.view("FetchCurrentSeason", func(tx *bolt.Tx) error {
+		var err error
+		season, err = currentSeason(tx)
+		return err
+	})
+	if err == nil && season == nil {
+		err = b.update("FetchCurrentSeason", func(tx *bolt.Tx) error {
+			var err error
+			season, err = loadOrStartSeason(tx, time.Now())
+			return err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// currentSeason returns the running season, or nil if no season was ever
+// stored.
+func currentSeason(tx *bolt.Tx) (*Season, error) {
+	bucket := tx.Bucket(seasonsBucket)
+	if bucket == nil {
+		return nil, ErrMainBucketNotFound
+	}
+	data := bucket.Get(currentSeasonKey)
+	if data == nil {
+		return nil, nil
+	}
+	var season Season
+	if err := json.Unmarshal(data, &season); err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// loadOrStartSeason returns the running season, starting season 1 at start
+// if no season was ever stored.
+func loadOrStartSeason(tx *bolt.Tx, start time.Time) (*Season, error) {
+	season, err := currentSeason(tx)
+	if err != nil || season != nil {
+		return season, err
+	}
+	season = &Season{Number: 1, StartedAt: start}
+	data, err := json.Marshal(season)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Bucket(seasonsBucket).Put(currentSeasonKey, data); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// StoreSeason overwrites the running season.
+func (b *boltDB) StoreSeason(ctx context.Context, season *Season) error {
+	data, err := json.Marshal(season)
+	if err != nil {
+		return err
+	}
+
+	return b.update("StoreSeason", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(seasonsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.Put(currentSeasonKey, data)
+	})
+}
+
+// ArchiveSeason stores the final standings of a season, starts the next one
+// and resets all player ratings.
+func (b *boltDB) ArchiveSeason(ctx context.Context, archive *SeasonArchive, next *Season) error {
+	archiveData, err := json.Marshal(archive)
+	if err != nil {
+		return err
+	}
+	nextData, err := json.Marshal(next)
+	if err != nil {
+		return err
+	}
+
+	return b.update("ArchiveSeason", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(seasonsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+
+		archives, err := bucket.CreateBucketIfNotExists(seasonArchiveKey)
+		if err != nil {
+			return err
+		}
+		if err := archives.Put(seasonKey(archive.Number), archiveData); err != nil {
+			return err
+		}
+		if err := bucket.Put(currentSeasonKey, nextData); err != nil {
+			return err
+		}
+
+		// Ratings start over with every season.
+		if err := tx.DeleteBucket(playerRatingsBucket); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		_, err = tx.CreateBucket(playerRatingsBucket)
+		return err
+	})
+}
+
+// FetchSeasonArchive returns the final standings of a finished season.
+func (b *boltDB) FetchSeasonArchive(ctx context.Context, number uint32) (*SeasonArchive, error) {
+	var archive *SeasonArchive
+
+	err := b.view("FetchSeasonArchive", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(seasonsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+
+		archives := bucket.Bucket(seasonArchiveKey)
+		if archives == nil {
+			return nil
+		}
+		data := archives.Get(seasonKey(number))
+		if data == nil {
+			return nil
+		}
+		archive = &SeasonArchive{}
+		return json.Unmarshal(data, archive)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if archive == nil {
+		return nil, ErrSeasonNotFound
+	}
+	return archive, nil
+}
+
+func seasonKey(number uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, number)
+	return b
+}
@@ -0,0 +1,34 @@
+package serverdb
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// OpDuration observes how long the transactions of database operations
+// take, labelled by the name of the operation.
+var OpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "pong",
+	Subsystem: "db",
+	Name:      "op_duration_seconds",
+	Help:      "Duration of database operations.",
+	Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+}, []string{"op"})
+
+// update runs fn in a read-write transaction, timing it as op.
+func (b *boltDB) update(op string, fn func(*bolt.Tx) error) error {
+	defer observeOp(op, time.Now())
+	return b.db.Update(fn)
+}
+
+// view runs fn in a read-only transaction, timing it as op.
+func (b *boltDB) view(op string, fn func(*bolt.Tx) error) error {
+	defer observeOp(op, time.Now())
+	return b.db.View(fn)
+}
+
+func observeOp(op string, start time.Time) {
+	OpDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,66 @@
+package serverdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoreWaitingRoom stores or replaces a waiting room.
+func (b *boltDB) StoreWaitingRoom(ctx context.Context, room *StoredWaitingRoom) error {
+	data, err := json.Marshal(room)
+	if err != nil {
+		return err
+	}
+
+	return b.update("StoreWaitingRoom", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(waitingRoomsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.Put([]byte(room.ID), data)
+	})
+}
+
+// DeleteWaitingRoom removes a stored waiting room. Deleting a room that is
+// not stored is not an error.
+func (b *boltDB) DeleteWaitingRoom(ctx context.Context, roomID string) error {
+	return b.update("DeleteWaitingRoom", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(waitingRoomsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+		return bucket.Delete([]byte(roomID))
+	})
+}
+
+// FetchWaitingRooms returns the stored waiting rooms, oldest first.
+func (b *boltDB) FetchWaitingRooms(ctx context.Context) ([]*StoredWaitingRoom, error) {
+	var rooms []*StoredWaitingRoom
+
+	err := b.view("FetchWaitingRooms", func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(waitingRoomsBucket)
+		if bucket == nil {
+			return ErrMainBucketNotFound
+		}
+
+		return bucket.ForEach(func(_, v []byte) error {
+			var room StoredWaitingRoom
+			if err := json.Unmarshal(v, &room); err != nil {
+				return err
+			}
+			rooms = append(rooms, &room)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(rooms, func(i, j int) bool {
+		return rooms[i].CreatedAt.Before(rooms[j].CreatedAt)
+	})
+	return rooms, nil
+}
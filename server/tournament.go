@@ -0,0 +1,804 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/vctt94/bisonbotkit/utils"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/ponggame"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+const (
+	// tournamentTick is how often tournaments are started and their
+	// matches scheduled.
+	tournamentTick = 5 * time.Second
+
+	// defaultNoShowTimeout is how long players of a tournament match have
+	// to get ready before the match is forfeited.
+	defaultNoShowTimeout = 5 * time.Minute
+
+	// maxTournamentPlayers caps the players of a tournament.
+	maxTournamentPlayers = 64
+)
+
+// TournamentConfig is the configuration of a tournament created by the
+// operator.
+type TournamentConfig struct {
+	Name       string                `json:"name"`
+	Format     pong.TournamentFormat `json:"format"`
+	BuyIn      matoms.Amount         `json:"buy_in"`
+	MaxPlayers int32                 `json:"max_players"`
+	StartsAt   time.Time             `json:"starts_at"`
+	PrizeSplit []uint32              `json:"prize_split"` // percent per placement
+	Rules      ponggame.GameRules    `json:"rules"`
+
+	// NoShowSecs is how long players have to get ready for a match. It
+	// defaults to defaultNoShowTimeout.
+	NoShowSecs int64 `json:"no_show_secs"`
+}
+
+// tournament is a tournament hosted by the server. Its fields are guarded by
+// the server tournamentsMtx.
+type tournament struct {
+	TournamentConfig
+
+	id      string
+	state   pong.TournamentState
+	players []zkidentity.ShortID // in registration order
+	nicks   map[zkidentity.ShortID]string
+	bracket *ponggame.Bracket
+	matches map[string]*tournamentMatch
+}
+
+// tournamentMatch tracks the scheduling of a bracket match.
+type tournamentMatch struct {
+	roomID   string
+	deadline time.Time
+	started  bool
+}
+
+// tournamentRoom points from a waiting room to the match played in it.
+type tournamentRoom struct {
+	t       *tournament
+	matchID string
+}
+
+func (t *tournament) registered(uid zkidentity.ShortID) bool {
+	for _, id := range t.players {
+		if id == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tournament) prizePool() matoms.Amount {
+	return t.BuyIn * matoms.Amount(len(t.players))
+}
+
+func (t *tournament) marshal() *pong.Tournament {
+	pt := &pong.Tournament{
+		Id:         t.id,
+		Name:       t.Name,
+		Format:     t.Format,
+		State:      t.state,
+		BuyIn:      int64(t.BuyIn),
+		MaxPlayers: t.MaxPlayers,
+		StartsAt:   t.StartsAt.Unix(),
+		PrizeSplit: t.PrizeSplit,
+		Rules:      t.Rules.Marshal(),
+		PrizePool:  int64(t.prizePool()),
+	}
+	for _, uid := range t.players {
+		tp := &pong.TournamentPlayer{
+			PlayerId: uid.String(),
+			Nick:     t.nicks[uid],
+		}
+		if t.bracket != nil {
+			if e := t.bracket.Entrant(uid); e != nil {
+				tp.Seed = e.Seed
+				tp.Losses = e.Losses
+				tp.Eliminated = e.Losses >= t.bracket.MaxLosses
+			}
+		}
+		pt.Players = append(pt.Players, tp)
+	}
+	if t.bracket != nil {
+		pt.Matches = t.bracket.Marshal()
+		for _, pm := range pt.Matches {
+			if tm := t.matches[pm.Id]; tm != nil {
+				pm.RoomId = tm.roomID
+				pm.Deadline = tm.deadline.Unix()
+			}
+		}
+		for _, uid := range t.bracket.Placements() {
+			pt.Placements = append(pt.Placements, uid.String())
+		}
+	}
+	return pt
+}
+
+// CreateTournament opens registration for a new tournament.
+func (s *Server) CreateTournament(cfg TournamentConfig) (*pong.Tournament, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("tournament needs a name")
+	}
+	switch cfg.Format {
+	case pong.TournamentFormat_SINGLE_ELIMINATION, pong.TournamentFormat_DOUBLE_ELIMINATION:
+	default:
+		return nil, fmt.Errorf("unknown tournament format %v", cfg.Format)
+	}
+	if err := s.validateBetAmt(cfg.BuyIn); err != nil {
+		return nil, fmt.Errorf("invalid buy-in: %v", err)
+	}
+	if cfg.MaxPlayers == 0 {
+		cfg.MaxPlayers = maxTournamentPlayers
+	}
+	if cfg.MaxPlayers < 2 || cfg.MaxPlayers > maxTournamentPlayers {
+		return nil, fmt.Errorf("max players must be between 2 and %d", maxTournamentPlayers)
+	}
+	if len(cfg.PrizeSplit) == 0 {
+		cfg.PrizeSplit = []uint32{100}
+	}
+	total := uint32(0)
+	for _, pct := range cfg.PrizeSplit {
+		total += pct
+	}
+	if total != 100 {
+		return nil, fmt.Errorf("prize split must add up to 100%%, got %d%%", total)
+	}
+	if len(cfg.PrizeSplit) > int(cfg.MaxPlayers) {
+		return nil, fmt.Errorf("prize split pays more placements than players")
+	}
+	if cfg.Rules == (ponggame.GameRules{}) {
+		cfg.Rules = ponggame.DefaultGameRules()
+	}
+	if err := cfg.Rules.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.NoShowSecs <= 0 {
+		cfg.NoShowSecs = int64(defaultNoShowTimeout / time.Second)
+	}
+
+	id, err := utils.GenerateRandomString(16)
+	if err != nil {
+		return nil, err
+	}
+	t := &tournament{
+		TournamentConfig: cfg,
+		id:               id,
+		state:            pong.TournamentState_TOURNAMENT_REGISTRATION,
+		nicks:            make(map[zkidentity.ShortID]string),
+		matches:          make(map[string]*tournamentMatch),
+	}
+
+	s.tournamentsMtx.Lock()
+	if s.tournaments == nil {
+		s.tournaments = make(map[string]*tournament)
+	}
+	s.tournaments[id] = t
+	pt := t.marshal()
+	s.tournamentsMtx.Unlock()
+
+	s.log.Infof("Tournament %s (%s) created, starts at %s", cfg.Name, id, cfg.StartsAt)
+	return pt, nil
+}
+
+func (s *Server) ListTournaments(ctx context.Context, req *pong.ListTournamentsRequest) (*pong.ListTournamentsResponse, error) {
+	s.tournamentsMtx.Lock()
+	defer s.tournamentsMtx.Unlock()
+
+	tournaments := make([]*pong.Tournament, 0, len(s.tournaments))
+	for _, t := range s.tournaments {
+		tournaments = append(tournaments, t.marshal())
+	}
+	sort.Slice(tournaments, func(i, j int) bool {
+		return tournaments[i].StartsAt < tournaments[j].StartsAt
+	})
+	return &pong.ListTournamentsResponse{
+		Tournaments: tournaments,
+	}, nil
+}
+
+func (s *Server) GetTournament(ctx context.Context, req *pong.GetTournamentRequest) (*pong.GetTournamentResponse, error) {
+	s.tournamentsMtx.Lock()
+	defer s.tournamentsMtx.Unlock()
+
+	t := s.tournaments[req.TournamentId]
+	if t == nil {
+		return nil, fmt.Errorf("tournament not found: %s", req.TournamentId)
+	}
+	return &pong.GetTournamentResponse{
+		Tournament: t.marshal(),
+	}, nil
+}
+
+// RegisterTournament registers a player in a tournament. The buy-in is
+// reserved from the player's balance and held until the tournament ends.
+func (s *Server) RegisterTournament(ctx context.Context, req *pong.RegisterTournamentRequest) (*pong.RegisterTournamentResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+	player := s.gameManager.PlayerSessions.GetPlayer(clientID)
+	if player == nil {
+		return nil, fmt.Errorf("player not found: %s", req.ClientId)
+	}
+	if err := s.checkAvailable(player); err != nil {
+		return nil, err
+	}
+
+	s.tournamentsMtx.Lock()
+	t := s.tournaments[req.TournamentId]
+	var buyIn matoms.Amount
+	if t != nil {
+		buyIn = t.BuyIn
+	}
+	s.tournamentsMtx.Unlock()
+	if t == nil {
+		return nil, fmt.Errorf("tournament not found: %s", req.TournamentId)
+	}
+
+	if err := s.checkLimits(ctx, clientID, buyIn); err != nil {
+		return nil, err
+	}
+	if _, err := s.fetchStakeTips(ctx, clientID, buyIn); err != nil {
+		return nil, err
+	}
+	if err := s.reserveStake(ctx, t.id, clientID, buyIn); err != nil {
+		return nil, err
+	}
+	reject := func(err error) (*pong.RegisterTournamentResponse, error) {
+		s.tournamentsMtx.Unlock()
+		s.releaseStake(ctx, t.id, clientID)
+		return nil, err
+	}
+
+	s.tournamentsMtx.Lock()
+	if t.state != pong.TournamentState_TOURNAMENT_REGISTRATION {
+		return reject(fmt.Errorf("registration for %s is closed", t.Name))
+	}
+	if s.tournamentOfLocked(clientID) != nil {
+		return reject(fmt.Errorf("player %s is already registered in a tournament", clientID))
+	}
+	if len(t.players) >= int(t.MaxPlayers) {
+		return reject(fmt.Errorf("tournament %s is full", t.Name))
+	}
+	t.players = append(t.players, clientID)
+	t.nicks[clientID] = player.Nick
+	pt := t.marshal()
+	s.tournamentsMtx.Unlock()
+
+	s.log.Infof("Player %s registered in tournament %s", clientID, t.id)
+	s.notifyTournament(t, pong.NotificationType_TOURNAMENT_UPDATE,
+		fmt.Sprintf("%s registered in %s", player.Nick, t.Name), nil)
+
+	return &pong.RegisterTournamentResponse{
+		Tournament: pt,
+	}, nil
+}
+
+// UnregisterTournament removes a player from a tournament that hasn't
+// started, releasing the buy-in.
+func (s *Server) UnregisterTournament(ctx context.Context, req *pong.UnregisterTournamentRequest) (*pong.UnregisterTournamentResponse, error) {
+	var clientID zkidentity.ShortID
+	if err := clientID.FromString(req.ClientId); err != nil {
+		return nil, err
+	}
+
+	s.tournamentsMtx.Lock()
+	t := s.tournaments[req.TournamentId]
+	if t == nil {
+		s.tournamentsMtx.Unlock()
+		return nil, fmt.Errorf("tournament not found: %s", req.TournamentId)
+	}
+	if !t.registered(clientID) {
+		s.tournamentsMtx.Unlock()
+		return nil, fmt.Errorf("player %s is not registered in %s", clientID, t.Name)
+	}
+	if t.state != pong.TournamentState_TOURNAMENT_REGISTRATION {
+		s.tournamentsMtx.Unlock()
+		return nil, fmt.Errorf("tournament %s already started", t.Name)
+	}
+	t.unregisterLocked(clientID)
+	s.tournamentsMtx.Unlock()
+	s.releaseStake(ctx, t.id, clientID)
+
+	s.notifyTournament(t, pong.NotificationType_TOURNAMENT_UPDATE, "A player left the tournament", nil)
+	return &pong.UnregisterTournamentResponse{}, nil
+}
+
+func (t *tournament) unregisterLocked(uid zkidentity.ShortID) {
+	for i, id := range t.players {
+		if id == uid {
+			t.players = append(t.players[:i], t.players[i+1:]...)
+			break
+		}
+	}
+	delete(t.nicks, uid)
+}
+
+// tournamentOfLocked returns the active tournament the player is registered
+// in. Must be called with tournamentsMtx held.
+func (s *Server) tournamentOfLocked(uid zkidentity.ShortID) *tournament {
+	for _, t := range s.tournaments {
+		switch t.state {
+		case pong.TournamentState_TOURNAMENT_REGISTRATION, pong.TournamentState_TOURNAMENT_RUNNING:
+			if t.registered(uid) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// tournamentHoldsStake returns whether a player holds a tournament buy-in.
+func (s *Server) tournamentHoldsStake(uid zkidentity.ShortID) bool {
+	s.tournamentsMtx.Lock()
+	defer s.tournamentsMtx.Unlock()
+	return s.tournamentOfLocked(uid) != nil
+}
+
+// handleTournamentDisconnect drops the registration of a player leaving before
+// the tournament starts. It returns whether the buy-in of the player is still
+// held by a running tournament and must not be refunded.
+func (s *Server) handleTournamentDisconnect(uid zkidentity.ShortID) bool {
+	s.tournamentsMtx.Lock()
+	t := s.tournamentOfLocked(uid)
+	running := t != nil && t.state == pong.TournamentState_TOURNAMENT_RUNNING
+	if t != nil && !running {
+		t.unregisterLocked(uid)
+	}
+	s.tournamentsMtx.Unlock()
+
+	if t != nil && !running {
+		s.releaseStake(context.Background(), t.id, uid)
+		s.notifyTournament(t, pong.NotificationType_TOURNAMENT_UPDATE, "A player left the tournament", nil)
+	}
+	return running
+}
+
+// runTournamentLoop starts tournaments when due and schedules their matches.
+func (s *Server) runTournamentLoop(ctx context.Context) {
+	ticker := time.NewTicker(tournamentTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tickTournaments(ctx, now)
+		}
+	}
+}
+
+func (s *Server) tickTournaments(ctx context.Context, now time.Time) {
+	s.tournamentsMtx.Lock()
+	var started, cancelled, running []*tournament
+	for _, t := range s.tournaments {
+		if t.state == pong.TournamentState_TOURNAMENT_REGISTRATION && !now.Before(t.StartsAt) {
+			if !s.startTournamentLocked(t) {
+				cancelled = append(cancelled, t)
+				continue
+			}
+			started = append(started, t)
+		}
+		if t.state == pong.TournamentState_TOURNAMENT_RUNNING {
+			running = append(running, t)
+		}
+	}
+	s.tournamentsMtx.Unlock()
+
+	for _, t := range cancelled {
+		s.releaseStakes(ctx, t.id)
+		s.notifyTournament(t, pong.NotificationType_TOURNAMENT_ENDED,
+			fmt.Sprintf("%s was cancelled", t.Name), nil)
+	}
+	for _, t := range started {
+		s.notifyTournament(t, pong.NotificationType_TOURNAMENT_UPDATE,
+			fmt.Sprintf("%s started", t.Name), nil)
+	}
+	for _, t := range running {
+		s.scheduleMatches(ctx, t, now)
+	}
+}
+
+// startTournamentLocked closes registration and seeds the bracket. The
+// tournament is cancelled, releasing the buy-ins back to the players'
+// balance, if too few players registered. Must be called with tournamentsMtx
+// held.
+func (s *Server) startTournamentLocked(t *tournament) bool {
+	if len(t.players) < 2 {
+		t.state = pong.TournamentState_TOURNAMENT_CANCELLED
+		s.log.Infof("Tournament %s cancelled: not enough players", t.id)
+		return false
+	}
+
+	seeds := append([]zkidentity.ShortID(nil), t.players...)
+	rand.Shuffle(len(seeds), func(i, j int) { seeds[i], seeds[j] = seeds[j], seeds[i] })
+	bracket, err := ponggame.NewBracket(t.Format, seeds)
+	if err != nil {
+		t.state = pong.TournamentState_TOURNAMENT_CANCELLED
+		s.log.Errorf("Tournament %s cancelled: %v", t.id, err)
+		return false
+	}
+	t.bracket = bracket
+	t.state = pong.TournamentState_TOURNAMENT_RUNNING
+	s.log.Infof("Tournament %s started with %d players", t.id, len(t.players))
+	return true
+}
+
+// scheduleMatches opens a waiting room for every pending match whose players
+// are free and forfeits matches whose players didn't show up in time.
+func (s *Server) scheduleMatches(ctx context.Context, t *tournament, now time.Time) {
+	type pendingMatch struct {
+		id      string
+		players []zkidentity.ShortID
+		tm      *tournamentMatch
+	}
+
+	s.tournamentSchedMtx.Lock()
+	defer s.tournamentSchedMtx.Unlock()
+
+	s.tournamentsMtx.Lock()
+	if t.state != pong.TournamentState_TOURNAMENT_RUNNING {
+		s.tournamentsMtx.Unlock()
+		return
+	}
+	var pending []pendingMatch
+	for _, m := range t.bracket.PendingMatches() {
+		tm := t.matches[m.ID]
+		if tm == nil {
+			tm = &tournamentMatch{deadline: now.Add(time.Duration(t.NoShowSecs) * time.Second)}
+			t.matches[m.ID] = tm
+		}
+		if tm.started {
+			continue
+		}
+		pending = append(pending, pendingMatch{id: m.ID, players: m.Players, tm: tm})
+	}
+	s.tournamentsMtx.Unlock()
+
+	for _, m := range pending {
+		if s.tournamentRoomGone(m.tm) {
+			s.tournamentsMtx.Lock()
+			m.tm.roomID = ""
+			s.tournamentsMtx.Unlock()
+		}
+
+		if !now.Before(m.tm.deadline) {
+			s.forfeitMatch(ctx, t, m.id, m.players, m.tm)
+			continue
+		}
+		if m.tm.roomID != "" {
+			continue
+		}
+
+		host := s.gameManager.PlayerSessions.GetPlayer(m.players[0])
+		guest := s.gameManager.PlayerSessions.GetPlayer(m.players[1])
+		if host == nil || guest == nil || !s.matchPlayerFree(host) || !s.matchPlayerFree(guest) {
+			continue
+		}
+		wr, err := s.createPairedRoom(host, guest, 0, t.Rules, nil)
+		if err != nil {
+			s.log.Errorf("Failed to schedule match %s of tournament %s: %v", m.id, t.id, err)
+			continue
+		}
+		s.tournamentsMtx.Lock()
+		m.tm.roomID = wr.ID
+		if s.tournamentRooms == nil {
+			s.tournamentRooms = make(map[string]tournamentRoom)
+		}
+		s.tournamentRooms[wr.ID] = tournamentRoom{t: t, matchID: m.id}
+		s.tournamentsMtx.Unlock()
+
+		pongWR, err := wr.Marshal()
+		if err != nil {
+			s.log.Errorf("Failed to marshal waiting room: %v", err)
+			continue
+		}
+		for _, player := range []*ponggame.Player{host, guest} {
+			s.notifyTournamentPlayer(t, player, pong.NotificationType_TOURNAMENT_MATCH_READY,
+				fmt.Sprintf("Your match %s of %s is ready. Get ready before %s.",
+					m.id, t.Name, m.tm.deadline.Format(time.Kitchen)), pongWR)
+		}
+	}
+}
+
+// matchPlayerFree returns whether a player can be put in a tournament match
+// waiting room.
+func (s *Server) matchPlayerFree(player *ponggame.Player) bool {
+	return player.WR == nil && s.gameManager.GetPlayerGame(*player.ID) == nil
+}
+
+// tournamentRoomGone returns whether the waiting room of a scheduled match
+// was closed without the match starting, e.g. because a player left.
+func (s *Server) tournamentRoomGone(tm *tournamentMatch) bool {
+	s.tournamentsMtx.Lock()
+	roomID, started := tm.roomID, tm.started
+	s.tournamentsMtx.Unlock()
+	return roomID != "" && !started && s.gameManager.GetWaitingRoom(roomID) == nil
+}
+
+// forfeitMatch decides a match whose players didn't all get ready in time. A
+// player who did get ready wins; otherwise the better seed advances.
+func (s *Server) forfeitMatch(ctx context.Context, t *tournament, matchID string, players []zkidentity.ShortID, tm *tournamentMatch) {
+	winner := players[0]
+	var wr *ponggame.WaitingRoom
+	s.tournamentsMtx.Lock()
+	if tm.started {
+		// The players got ready just in time.
+		s.tournamentsMtx.Unlock()
+		return
+	}
+	if tm.roomID != "" {
+		wr = s.gameManager.GetWaitingRoom(tm.roomID)
+		delete(s.tournamentRooms, tm.roomID)
+	}
+	s.tournamentsMtx.Unlock()
+
+	if wr != nil {
+		ready := 0
+		for _, player := range wr.GetPlayers() {
+			if player.Ready {
+				winner = *player.ID
+				ready++
+			}
+		}
+		if ready != 1 {
+			winner = players[0]
+		}
+		wr.Cancel()
+		s.gameManager.RemoveWaitingRoom(wr.ID)
+		for _, player := range wr.GetPlayers() {
+			player.WR = nil
+		}
+	}
+
+	s.log.Infof("Match %s of tournament %s forfeited, %s advances", matchID, t.id, winner)
+	s.reportTournamentResult(ctx, t, matchID, winner, true)
+}
+
+// tournamentRoomMatch returns the tournament match played in a waiting room,
+// marking it as started.
+func (s *Server) tournamentRoomMatch(roomID string) (tournamentRoom, bool) {
+	s.tournamentsMtx.Lock()
+	defer s.tournamentsMtx.Unlock()
+
+	ref, ok := s.tournamentRooms[roomID]
+	if !ok {
+		return ref, false
+	}
+	delete(s.tournamentRooms, roomID)
+	if tm := ref.t.matches[ref.matchID]; tm != nil {
+		tm.started = true
+	}
+	return ref, true
+}
+
+// runTournamentMatch plays a tournament match and reports its result to the
+// bracket. Nothing is settled until the tournament ends.
+func (s *Server) runTournamentMatch(ctx context.Context, ref tournamentRoom, players []*ponggame.Player, rules ponggame.GameRules) {
+	defer s.resetPlayers(players)
+
+	series, err := ponggame.NewSeries(players, rules.BestOf)
+	if err != nil {
+		s.log.Errorf("Failed to start series: %v", err)
+		return
+	}
+	game := s.playSeries(ctx, series, rules, ref.t.BuyIn)
+	for _, player := range players {
+		delete(s.gameManager.PlayerGameMap, *player.ID)
+	}
+
+	// An undecided match goes to the better seed.
+	s.tournamentsMtx.Lock()
+	var winner zkidentity.ShortID
+	if m := ref.t.bracket.Match(ref.matchID); m != nil {
+		winner = m.Players[0]
+	}
+	s.tournamentsMtx.Unlock()
+	if series.Winner != nil {
+		winner = *series.Winner
+	}
+
+	for _, player := range players {
+		message := fmt.Sprintf("You won match %s of %s.", ref.matchID, ref.t.Name)
+		if *player.ID != winner {
+			message = fmt.Sprintf("You lost match %s of %s.", ref.matchID, ref.t.Name)
+		}
+		if player.NotifierStream == nil {
+			continue
+		}
+		ntfn := &pong.NtfnStreamResponse{
+			NotificationType: pong.NotificationType_GAME_END,
+			Message:          message,
+			Series:           series.Marshal(),
+		}
+		if game != nil {
+			ntfn.GameId = game.Id
+			ntfn.Fairness = game.Fairness(true)
+		}
+		player.NotifierStream.Send(ntfn)
+	}
+
+	s.reportTournamentResult(ctx, ref.t, ref.matchID, winner, false)
+}
+
+// reportTournamentResult records a match result in the bracket, ending the
+// tournament once it has a champion.
+func (s *Server) reportTournamentResult(ctx context.Context, t *tournament, matchID string, winner zkidentity.ShortID, forfeit bool) {
+	s.tournamentsMtx.Lock()
+	if err := t.bracket.ReportResult(matchID, winner, forfeit); err != nil {
+		s.tournamentsMtx.Unlock()
+		s.log.Errorf("Failed to report match %s of tournament %s: %v", matchID, t.id, err)
+		return
+	}
+	delete(t.matches, matchID)
+	done := t.bracket.Done()
+	if done {
+		t.state = pong.TournamentState_TOURNAMENT_FINISHED
+	}
+	s.tournamentsMtx.Unlock()
+
+	if !done {
+		s.notifyTournament(t, pong.NotificationType_TOURNAMENT_UPDATE,
+			fmt.Sprintf("Match %s of %s decided", matchID, t.Name), nil)
+		go s.scheduleMatches(ctx, t, time.Now())
+		return
+	}
+	s.payTournamentPrizes(ctx, t)
+}
+
+// tournamentPrize is the amount paid to a placement of a tournament.
+type tournamentPrize struct {
+	uid    zkidentity.ShortID
+	amount matoms.Amount
+}
+
+// tournamentPrizes splits the prize pool between the placements. Shares of
+// placements nobody reached and rounding leftovers go to the champion.
+func tournamentPrizes(pool matoms.Amount, split []uint32, placements []zkidentity.ShortID) []tournamentPrize {
+	if len(placements) == 0 {
+		return nil
+	}
+	prizes := make([]tournamentPrize, 0, len(split))
+	paid := matoms.Amount(0)
+	for i, pct := range split {
+		if i >= len(placements) {
+			break
+		}
+		amount := pool * matoms.Amount(pct) / 100
+		prizes = append(prizes, tournamentPrize{uid: placements[i], amount: amount})
+		paid += amount
+	}
+	prizes[0].amount += pool - paid
+	return prizes
+}
+
+// payTournamentPrizes settles the buy-ins of a finished tournament by paying
+// the prize split to its top placements.
+func (s *Server) payTournamentPrizes(ctx context.Context, t *tournament) {
+	stakes, err := s.fetchStakes(ctx, t.id)
+	if err != nil {
+		s.log.Errorf("Failed to fetch buy-ins of tournament %s: %v", t.id, err)
+		return
+	}
+	escrows := make(map[string]matoms.Amount, len(stakes))
+	pool := matoms.Amount(0)
+	for uid, stake := range stakes {
+		escrows[s.stakeAccounts().EscrowAccount(t.id, uid)] = stake
+		pool += stake
+	}
+
+	s.tournamentsMtx.Lock()
+	placements := t.bracket.Placements()
+	prizes := tournamentPrizes(pool, t.PrizeSplit, placements)
+	s.tournamentsMtx.Unlock()
+
+	s.log.Infof("Tournament %s finished, champion %s, pool %s", t.id, placements[0], pool)
+
+	shares := make(map[zkidentity.ShortID]matoms.Amount, len(prizes))
+	for _, prize := range prizes {
+		shares[prize.uid] += prize.amount
+	}
+	if err := s.settleShares(ctx, t.id, t.id, escrows, shares, 0); err != nil {
+		s.log.Errorf("Failed to settle buy-ins of tournament %s: %v", t.id, err)
+		return
+	}
+	if !s.isF2P {
+		s.queueTournamentPrizes(ctx, t, prizes, stakes)
+	}
+
+	s.notifyTournament(t, pong.NotificationType_TOURNAMENT_ENDED,
+		fmt.Sprintf("%s is over. Champion: %s", t.Name, s.tournamentNick(t, placements[0])), nil)
+}
+
+// queueTournamentPrizes pays out the prizes of a tournament settled to the
+// winners' balances, spending the buy-ins from the tips backing them.
+func (s *Server) queueTournamentPrizes(ctx context.Context, t *tournament, prizes []tournamentPrize, stakes map[zkidentity.ShortID]matoms.Amount) {
+	paid := make([]matoms.Amount, len(prizes))
+	kept := make(map[zkidentity.ShortID]matoms.Amount, len(prizes))
+	for i, prize := range prizes {
+		if prize.amount == 0 {
+			continue
+		}
+		amount, err := s.recordPayout(ctx, serverdb.EntryPayout, t.id, prize.uid, prize.amount)
+		if err != nil {
+			s.log.Errorf("Failed to record tournament prize of %s: %v", prize.uid, err)
+		}
+		paid[i] = amount
+		kept[prize.uid] += prize.amount - amount
+	}
+	tips := s.spendStakes(ctx, stakes, kept)
+
+	for i, prize := range prizes {
+		if paid[i] == 0 {
+			continue
+		}
+		// The first prize queued carries the buy-ins being settled.
+		if err := s.queuePayout(ctx, prize.uid, paid[i], tips); err != nil {
+			s.log.Errorf("Failed to pay tournament prize to %s: %v", prize.uid, err)
+			continue
+		}
+		tips = nil
+		s.log.Infof("Queued tournament prize to %s: %s", prize.uid, paid[i])
+	}
+}
+
+func (s *Server) tournamentNick(t *tournament, uid zkidentity.ShortID) string {
+	s.tournamentsMtx.Lock()
+	defer s.tournamentsMtx.Unlock()
+	if nick := t.nicks[uid]; nick != "" {
+		return nick
+	}
+	return uid.String()
+}
+
+// notifyTournament sends the tournament state to all of its players.
+func (s *Server) notifyTournament(t *tournament, typ pong.NotificationType, msg string, wr *pong.WaitingRoom) {
+	s.tournamentsMtx.Lock()
+	players := append([]zkidentity.ShortID(nil), t.players...)
+	s.tournamentsMtx.Unlock()
+
+	for _, uid := range players {
+		player := s.gameManager.PlayerSessions.GetPlayer(uid)
+		if player == nil {
+			continue
+		}
+		s.notifyTournamentPlayer(t, player, typ, msg, wr)
+	}
+}
+
+func (s *Server) notifyTournamentPlayer(t *tournament, player *ponggame.Player, typ pong.NotificationType, msg string, wr *pong.WaitingRoom) {
+	if player.NotifierStream == nil {
+		return
+	}
+	s.tournamentsMtx.Lock()
+	pt := t.marshal()
+	s.tournamentsMtx.Unlock()
+	player.NotifierStream.Send(&pong.NtfnStreamResponse{
+		NotificationType: typ,
+		Message:          msg,
+		PlayerId:         player.ID.String(),
+		Tournament:       pt,
+		Wr:               wr,
+	})
+}
+
+// isTournamentRoom returns whether a tournament match is played in the
+// waiting room.
+func (s *Server) isTournamentRoom(roomID string) bool {
+	s.tournamentsMtx.Lock()
+	defer s.tournamentsMtx.Unlock()
+	_, ok := s.tournamentRooms[roomID]
+	return ok
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/companyzero/bisonrelay/zkidentity"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vctt94/pong-bisonrelay/matoms"
+	"github.com/vctt94/pong-bisonrelay/pongrpc/grpc/pong"
+	"github.com/vctt94/pong-bisonrelay/server/serverdb"
+)
+
+func TestTournamentNoShow(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, p2ID := setupChallengePlayers(t, srv)
+	p1 := srv.gameManager.PlayerSessions.GetPlayer(p1ID)
+	p2 := srv.gameManager.PlayerSessions.GetPlayer(p2ID)
+
+	startsAt := time.Now().Add(time.Hour)
+	_, err := srv.CreateTournament(TournamentConfig{Name: "weekend", BuyIn: 50000000000, PrizeSplit: []uint32{60, 30}})
+	require.Error(t, err)
+	pt, err := srv.CreateTournament(TournamentConfig{
+		Name:       "weekend",
+		BuyIn:      50000000000,
+		StartsAt:   startsAt,
+		PrizeSplit: []uint32{100},
+	})
+	require.NoError(t, err)
+
+	for _, uid := range []zkidentity.ShortID{p1ID, p2ID} {
+		_, err := srv.RegisterTournament(ctx, &pong.RegisterTournamentRequest{
+			ClientId:     uid.String(),
+			TournamentId: pt.Id,
+		})
+		require.NoError(t, err)
+	}
+	_, err = srv.RegisterTournament(ctx, &pong.RegisterTournamentRequest{ClientId: p1ID.String(), TournamentId: pt.Id})
+	require.Error(t, err)
+
+	// The buy-in can't be staked anywhere else.
+	_, err = srv.CreateWaitingRoom(ctx, &pong.CreateWaitingRoomRequest{HostId: p1ID.String(), BetAmt: 50000000000})
+	require.Error(t, err)
+
+	srv.tickTournaments(ctx, startsAt)
+	got, err := srv.GetTournament(ctx, &pong.GetTournamentRequest{TournamentId: pt.Id})
+	require.NoError(t, err)
+	require.Equal(t, pong.TournamentState_TOURNAMENT_RUNNING, got.Tournament.State)
+	require.Equal(t, int64(100000000000), got.Tournament.PrizePool)
+	_, err = srv.UnregisterTournament(ctx, &pong.UnregisterTournamentRequest{ClientId: p1ID.String(), TournamentId: pt.Id})
+	require.Error(t, err)
+
+	// Both players are put in the room of their match.
+	require.NotNil(t, p1.WR)
+	require.Equal(t, p1.WR, p2.WR)
+	msgs := p2.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_TOURNAMENT_MATCH_READY, msgs[len(msgs)-1].NotificationType)
+
+	// Only p2 shows up in time.
+	p2.Ready = true
+	srv.tickTournaments(ctx, startsAt.Add(defaultNoShowTimeout))
+
+	got, err = srv.GetTournament(ctx, &pong.GetTournamentRequest{TournamentId: pt.Id})
+	require.NoError(t, err)
+	require.Equal(t, pong.TournamentState_TOURNAMENT_FINISHED, got.Tournament.State)
+	require.Equal(t, []string{p2ID.String(), p1ID.String()}, got.Tournament.Placements)
+	require.True(t, got.Tournament.Matches[0].Forfeit)
+	msgs = p1.NotifierStream.(*mockNotifierStream).messages
+	require.Equal(t, pong.NotificationType_TOURNAMENT_ENDED, msgs[len(msgs)-1].NotificationType)
+
+	srv.processPayouts(ctx, time.Now())
+	bot := srv.bot.(*minimalTestBot)
+	require.Equal(t, dcrutil.Amount(100000000), bot.paidTips[p2ID.String()])
+	tips, err := srv.db.FetchReceivedTipsByUID(ctx, p1ID, serverdb.StatusUnpaid)
+	require.NoError(t, err)
+	require.Empty(t, tips)
+}
+
+func TestTournamentCancelled(t *testing.T) {
+	srv := setupTestServer(t)
+	ctx := context.Background()
+	p1ID, _ := setupChallengePlayers(t, srv)
+
+	startsAt := time.Now()
+	pt, err := srv.CreateTournament(TournamentConfig{Name: "empty", BuyIn: 50000000000, StartsAt: startsAt})
+	require.NoError(t, err)
+	_, err = srv.RegisterTournament(ctx, &pong.RegisterTournamentRequest{ClientId: p1ID.String(), TournamentId: pt.Id})
+	require.NoError(t, err)
+
+	srv.tickTournaments(ctx, startsAt)
+	got, err := srv.GetTournament(ctx, &pong.GetTournamentRequest{TournamentId: pt.Id})
+	require.NoError(t, err)
+	require.Equal(t, pong.TournamentState_TOURNAMENT_CANCELLED, got.Tournament.State)
+	require.False(t, srv.tournamentHoldsStake(p1ID))
+}
+
+func TestTournamentPrizes(t *testing.T) {
+	placements := []zkidentity.ShortID{{1}, {2}}
+	prizes := tournamentPrizes(1001, []uint32{60, 30, 10}, placements)
+	require.Len(t, prizes, 2)
+	// The unclaimed third place share and rounding go to the champion.
+	require.Equal(t, matoms.Amount(701), prizes[0].amount)
+	require.Equal(t, matoms.Amount(300), prizes[1].amount)
+}